@@ -18,10 +18,13 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
 )
 
 func init() {
 	addEnvFlag(configureCmd)
+	addYesFlag(configureCmd)
 }
 
 var configureCmd = &cobra.Command{
@@ -30,6 +33,9 @@ var configureCmd = &cobra.Command{
 	Long:  "Configure the CLI.",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		if flagYes {
+			cr.SetPromptPolicy(cr.PromptAcceptDefaults)
+		}
 		configure()
 	},
 }
@@ -58,7 +58,8 @@ func getPromptValidation(defaults *CliConfig) *cr.PromptValidation {
 			&cr.PromptItemValidation{
 				StructField: "CortexURL",
 				PromptOpts: &cr.PromptOptions{
-					Prompt: "\nCortex operator endpoint",
+					Prompt:             "\nCortex operator endpoint",
+					NonInteractiveHint: "set CORTEX_OPERATOR_ENDPOINT",
 				},
 				StringValidation: cr.GetURLValidation(&cr.URLValidation{
 					Required: true,
@@ -68,7 +69,8 @@ func getPromptValidation(defaults *CliConfig) *cr.PromptValidation {
 			&cr.PromptItemValidation{
 				StructField: "AWSAccessKeyID",
 				PromptOpts: &cr.PromptOptions{
-					Prompt: "AWS Access Key ID",
+					Prompt:             "AWS Access Key ID",
+					NonInteractiveHint: "set AWS_ACCESS_KEY_ID",
 				},
 				StringValidation: &cr.StringValidation{
 					Required: true,
@@ -78,13 +80,15 @@ func getPromptValidation(defaults *CliConfig) *cr.PromptValidation {
 			&cr.PromptItemValidation{
 				StructField: "AWSSecretAccessKey",
 				PromptOpts: &cr.PromptOptions{
-					Prompt:      "AWS Secret Access Key",
-					MaskDefault: true,
-					HideTyping:  true,
+					Prompt:             "AWS Secret Access Key",
+					NonInteractiveHint: "set AWS_SECRET_ACCESS_KEY",
+					MaskDefault:        true,
+					HideTyping:         true,
 				},
 				StringValidation: &cr.StringValidation{
-					Required: true,
-					Default:  defaults.AWSSecretAccessKey,
+					Required:  true,
+					Default:   defaults.AWSSecretAccessKey,
+					Sensitive: true,
 				},
 			},
 		},
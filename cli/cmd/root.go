@@ -28,6 +28,7 @@ import (
 	"github.com/cortexlabs/cortex/pkg/api/resource"
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
 	"github.com/cortexlabs/cortex/pkg/consts"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
 	"github.com/cortexlabs/cortex/pkg/utils/util"
 )
@@ -37,6 +38,7 @@ var cmdStr string
 var flagEnv string
 var flagWatch bool
 var flagAppName string
+var flagYes bool
 
 var configFileExts []string = []string{"json", "yaml", "yml"}
 
@@ -98,6 +100,10 @@ func addAppNameFlag(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&flagAppName, "app", "a", "", "app name")
 }
 
+func addYesFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVarP(&flagYes, "yes", "y", false, "use default values instead of prompting (also enabled by "+cr.YesEnvVar+"=true)")
+}
+
 var resourceTypesHelp = fmt.Sprintf("\nResource Types:\n  %s\n", strings.Join(resource.VisibleTypes.StringList(), "\n  "))
 
 func addResourceTypesToHelp(cmd *cobra.Command) {
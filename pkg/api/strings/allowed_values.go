@@ -0,0 +1,103 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strings
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultMaxAllowedValuesShown is how many allowed values are listed in error messages by default
+const DefaultMaxAllowedValuesShown = 10
+
+var (
+	maxAllowedValuesShownMutex sync.RWMutex
+	maxAllowedValuesShown      = DefaultMaxAllowedValuesShown
+)
+
+// SetMaxAllowedValuesShown sets how many allowed values are listed in error messages (<= 0 lists all of them)
+func SetMaxAllowedValuesShown(max int) {
+	maxAllowedValuesShownMutex.Lock()
+	defer maxAllowedValuesShownMutex.Unlock()
+	maxAllowedValuesShown = max
+}
+
+func getMaxAllowedValuesShown() int {
+	maxAllowedValuesShownMutex.RLock()
+	defer maxAllowedValuesShownMutex.RUnlock()
+	return maxAllowedValuesShown
+}
+
+// UserStrsOrClosest is like UserStrsOr(), but if there are too many allowed values (see SetMaxAllowedValuesShown()),
+// only the ones closest to provided are listed, e.g. `2, 4, 6, ... and 392 more`
+func UserStrsOrClosest(provided interface{}, allowed interface{}) string {
+	max := getMaxAllowedValuesShown()
+	allowedVal := reflect.ValueOf(allowed)
+	if max <= 0 || allowed == nil || allowedVal.Kind() != reflect.Slice || allowedVal.Len() <= max {
+		return UserStrsOr(allowed)
+	}
+
+	distances := make([]float64, allowedVal.Len())
+	indices := make([]int, allowedVal.Len())
+	for i := range indices {
+		indices[i] = i
+		distances[i] = valueDistance(provided, allowedVal.Index(i).Interface())
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return distances[indices[i]] < distances[indices[j]]
+	})
+	closest := indices[:max]
+	sort.Ints(closest)
+
+	strs := make([]string, len(closest))
+	for i, index := range closest {
+		strs[i] = UserStr(allowedVal.Index(index).Interface())
+	}
+	return fmt.Sprintf("%s, ... and %d more", strings.Join(strs, ", "), allowedVal.Len()-max)
+}
+
+// valueDistance is the numeric distance for numbers, and the edit distance (ignoring case) otherwise
+func valueDistance(a interface{}, b interface{}) float64 {
+	aFloat, aOk := toFloat64(a)
+	bFloat, bOk := toFloat64(b)
+	if aOk && bOk {
+		return math.Abs(aFloat - bFloat)
+	}
+	aRunes := []rune(strings.ToLower(fmt.Sprint(a)))
+	bRunes := []rune(strings.ToLower(fmt.Sprint(b)))
+	return float64(editDistance(aRunes, bRunes))
+}
+
+func toFloat64(val interface{}) (float64, bool) {
+	if val == nil {
+		return 0, false
+	}
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
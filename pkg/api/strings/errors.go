@@ -18,6 +18,8 @@ package strings
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 )
 
@@ -31,10 +33,16 @@ var (
 	ErrMustBeEmpty   = "must be empty"
 	ErrCannotBeNull  = "cannot be null"
 
-	ErrRead            = "unable to read"
-	ErrUnzip           = "unable to unzip file"
-	ErrCreateZip       = "unable to create zip file"
-	ErrCantMakeRequest = "unable to make request"
+	ErrRead              = "unable to read"
+	ErrUnzip             = "unable to unzip file"
+	ErrCreateZip         = "unable to create zip file"
+	ErrNotRegularFile    = "not a regular file"
+	ErrMultipleLines     = "file has multiple lines, but a single value was expected"
+	ErrCantMakeRequest   = "unable to make request"
+	ErrPromptInterrupted = "interrupted"
+
+	ErrPromptNotInteractive = "unable to prompt for a value because stdin is not a terminal"
+	ErrPromptTimeout        = "timed out waiting for input"
 
 	ErrMarshalJson      = "invalid json cannot be serialized"
 	ErrUnmarshalJson    = "invalid json"
@@ -61,6 +69,10 @@ func Index(index int) string {
 	return fmt.Sprintf("index %d", index)
 }
 
+func ValidationErrorsHeader(numErrors int) string {
+	return fmt.Sprintf("%d %s:", numErrors, Plural(numErrors, "validation error", "validation errors"))
+}
+
 func MapMustBeDefined(keys ...string) string {
 	if len(keys) == 0 {
 		return fmt.Sprintf("must be defined")
@@ -69,162 +81,542 @@ func MapMustBeDefined(keys ...string) string {
 }
 
 func ErrUnsupportedKey(key interface{}) string {
-	return fmt.Sprintf("key %s is not supported", UserStr(key))
+	msg := fmt.Sprintf("key %s is not supported", UserStr(key))
+	return RenderMessage(MsgUnsupportedKey, msg, key)
 }
 
 func ErrDuplicatedValue(val interface{}) string {
-	return fmt.Sprintf("%s is duplicated", UserStr(val))
+	msg := fmt.Sprintf("%s is duplicated", UserStr(val))
+	return RenderMessage(MsgDuplicatedValue, msg, val)
 }
 
 func ErrInvalidPrimitiveType(provided interface{}, allowedTypes ...PrimitiveType) string {
-	return fmt.Sprintf("%s: invalid type (expected %s)", UserStr(provided), StrsOr(PrimitiveTypes(allowedTypes).StringList()))
+	msg := fmt.Sprintf("%s: invalid type (expected %s)", UserStrTruncated(provided), StrsOr(PrimitiveTypes(allowedTypes).StringList()))
+	return RenderMessage(MsgInvalidPrimitiveType, msg, provided, allowedTypes)
 }
 
 func ErrMustBeGreaterThan(provided interface{}, boundary interface{}) string {
-	return fmt.Sprintf("%s must be greater than %s", UserStr(provided), UserStr(boundary))
+	msg := fmt.Sprintf("%s must be greater than %s", UserStrTruncated(provided), UserStr(boundary))
+	return RenderMessage(MsgMustBeGreaterThan, msg, provided, boundary)
 }
 func ErrMustBeGreaterThanOrEqualTo(provided interface{}, boundary interface{}) string {
-	return fmt.Sprintf("%s must be greater than or equal to %s", UserStr(provided), UserStr(boundary))
+	msg := fmt.Sprintf("%s must be greater than or equal to %s", UserStrTruncated(provided), UserStr(boundary))
+	return RenderMessage(MsgMustBeGreaterThanOrEqualTo, msg, provided, boundary)
 }
 func ErrMustBeLessThan(provided interface{}, boundary interface{}) string {
-	return fmt.Sprintf("%s must be less than %s", UserStr(provided), UserStr(boundary))
+	msg := fmt.Sprintf("%s must be less than %s", UserStrTruncated(provided), UserStr(boundary))
+	return RenderMessage(MsgMustBeLessThan, msg, provided, boundary)
 }
 func ErrMustBeLessThanOrEqualTo(provided interface{}, boundary interface{}) string {
-	return fmt.Sprintf("%s must be less than or equal to %s", UserStr(provided), UserStr(boundary))
+	msg := fmt.Sprintf("%s must be less than or equal to %s", UserStrTruncated(provided), UserStr(boundary))
+	return RenderMessage(MsgMustBeLessThanOrEqualTo, msg, provided, boundary)
 }
 
 func ErrInvalidStr(provided string, allowed ...string) string {
-	return fmt.Sprintf("invalid value (got %s, must be %s)", UserStr(provided), UserStrsOr(allowed))
+	msg := fmt.Sprintf("invalid value (got %s, must be %s)", UserStrTruncated(provided), UserStrsOrClosest(provided, allowed))
+	return RenderMessage(MsgNotAllowedValue, msg, provided, allowed)
+}
+func ErrNotAllowedValue(provided interface{}, allowed interface{}) string {
+	msg := fmt.Sprintf("invalid value (got %s, must be %s)", UserStrTruncated(provided), UserStrsOrClosest(provided, allowed))
+	return RenderMessage(MsgNotAllowedValue, msg, provided, allowed)
+}
+func ErrNotAllowedValueInFile(provided interface{}, allowed interface{}, filePath string) string {
+	msg := fmt.Sprintf("invalid value (got %s, must be one of the values listed in %s: %s)", UserStrTruncated(provided), filePath, UserStrsOrClosest(provided, allowed))
+	return RenderMessage(MsgNotAllowedValue, msg, provided, allowed)
 }
 func ErrInvalidInt(provided int, allowed ...int) string {
-	return fmt.Sprintf("invalid value (got %s, must be %s)", UserStr(provided), UserStrsOr(allowed))
+	msg := fmt.Sprintf("invalid value (got %s, must be %s)", UserStrTruncated(provided), UserStrsOrClosest(provided, allowed))
+	return RenderMessage(MsgNotAllowedValue, msg, provided, allowed)
 }
 func ErrInvalidInt32(provided int32, allowed ...int32) string {
-	return fmt.Sprintf("invalid value (got %s, must be %s)", UserStr(provided), UserStrsOr(allowed))
+	msg := fmt.Sprintf("invalid value (got %s, must be %s)", UserStrTruncated(provided), UserStrsOrClosest(provided, allowed))
+	return RenderMessage(MsgNotAllowedValue, msg, provided, allowed)
 }
 func ErrInvalidInt64(provided int64, allowed ...int64) string {
-	return fmt.Sprintf("invalid value (got %s, must be %s)", UserStr(provided), UserStrsOr(allowed))
+	msg := fmt.Sprintf("invalid value (got %s, must be %s)", UserStrTruncated(provided), UserStrsOrClosest(provided, allowed))
+	return RenderMessage(MsgNotAllowedValue, msg, provided, allowed)
 }
 func ErrInvalidFloat32(provided float32, allowed ...float32) string {
-	return fmt.Sprintf("invalid value (got %s, must be %s)", UserStr(provided), UserStrsOr(allowed))
+	msg := fmt.Sprintf("invalid value (got %s, must be %s)", UserStrTruncated(provided), UserStrsOrClosest(provided, allowed))
+	return RenderMessage(MsgNotAllowedValue, msg, provided, allowed)
 }
 func ErrInvalidFloat64(provided float64, allowed ...float64) string {
-	return fmt.Sprintf("invalid value (got %s, must be %s)", UserStr(provided), UserStrsOr(allowed))
+	msg := fmt.Sprintf("invalid value (got %s, must be %s)", UserStrTruncated(provided), UserStrsOrClosest(provided, allowed))
+	return RenderMessage(MsgNotAllowedValue, msg, provided, allowed)
 }
 
 func ErrMustHavePrefix(provided string, prefix string) string {
-	return fmt.Sprintf("%s must start with %s", UserStr(provided), UserStr(prefix))
+	msg := fmt.Sprintf("%s must start with %s", UserStr(provided), UserStr(prefix))
+	return RenderMessage(MsgMustHavePrefix, msg, provided, prefix)
 }
 func ErrAlphaNumericDashDotUnderscore(provided string) string {
-	return fmt.Sprintf("%s must contain only letters, numbers, underscores, dashes, and periods", UserStr(provided))
+	msg := fmt.Sprintf("%s must contain only letters, numbers, underscores, dashes, and periods", UserStr(provided))
+	return RenderMessage(MsgAlphaNumericDashDotUnderscore, msg, provided)
 }
 func ErrAlphaNumericDashUnderscore(provided string) string {
-	return fmt.Sprintf("%s must contain only letters, numbers, underscores, and dashes", UserStr(provided))
+	msg := fmt.Sprintf("%s must contain only letters, numbers, underscores, and dashes", UserStr(provided))
+	return RenderMessage(MsgAlphaNumericDashUnderscore, msg, provided)
 }
 func ErrDNS1035(provided string) string {
-	return fmt.Sprintf("%s must contain only lower case letters, numbers, and dashes, start with a letter, and cannot end with a dash", UserStr(provided))
+	msg := fmt.Sprintf("%s must contain only lower case letters, numbers, and dashes, start with a letter, and cannot end with a dash", UserStr(provided))
+	return RenderMessage(MsgDNS1035, msg, provided)
+}
+func ErrDNS1123Chars(provided string, allowDots bool) string {
+	msg := fmt.Sprintf("%s must contain only lower case letters, numbers, and dashes", UserStr(provided))
+	if allowDots {
+		msg = fmt.Sprintf("%s must contain only lower case letters, numbers, dashes, and dots", UserStr(provided))
+	}
+	return RenderMessage(MsgDNS1123Chars, msg, provided, allowDots)
+}
+func ErrDNS1123Boundary(provided string) string {
+	msg := fmt.Sprintf("%s must start and end with a lower case letter or number", UserStr(provided))
+	return RenderMessage(MsgDNS1123Boundary, msg, provided)
+}
+func ErrDNS1123SubdomainParts(provided string) string {
+	msg := fmt.Sprintf("each dot-separated part of %s must be non-empty, and must start and end with a lower case letter or number", UserStr(provided))
+	return RenderMessage(MsgDNS1123SubdomainParts, msg, provided)
+}
+func ErrStrTooLong(provided string, maxLength int) string {
+	msg := fmt.Sprintf("%s must be no more than %d %s", UserStr(provided), maxLength, Plural(maxLength, "character", "characters"))
+	return RenderMessage(MsgStrTooLong, msg, provided, maxLength)
+}
+func ErrTooFewElements(length int, minLength int) string {
+	msg := fmt.Sprintf("must contain at least %d %s (got %d)", minLength, Plural(minLength, "element", "elements"), length)
+	return RenderMessage(MsgTooFewElements, msg, length, minLength)
+}
+func ErrTooManyElements(length int, maxLength int) string {
+	msg := fmt.Sprintf("must contain no more than %d %s (got %d)", maxLength, Plural(maxLength, "element", "elements"), length)
+	return RenderMessage(MsgTooManyElements, msg, length, maxLength)
+}
+func ErrListLengthMismatch(length int, countKey string, count int) string {
+	msg := fmt.Sprintf("must contain %d %s, to match %s (got %d)", count, Plural(count, "element", "elements"), countKey, length)
+	return RenderMessage(MsgListLengthMismatch, msg, length, countKey, count)
+}
+func ErrWrongSum(sum float64, target float64) string {
+	msg := fmt.Sprintf("elements must sum to %s (got %s)", Float64(target), Float64(sum))
+	return RenderMessage(MsgWrongSum, msg, sum, target)
+}
+func ErrSumTooSmall(sum int64, minSum int64) string {
+	msg := fmt.Sprintf("elements must sum to at least %d (got %d)", minSum, sum)
+	return RenderMessage(MsgSumTooSmall, msg, sum, minSum)
+}
+func ErrSumTooLarge(sum int64, maxSum int64) string {
+	msg := fmt.Sprintf("elements must sum to at most %d (got %d)", maxSum, sum)
+	return RenderMessage(MsgSumTooLarge, msg, sum, maxSum)
+}
+func ErrCannotNormalizeZeroSum(target float64) string {
+	msg := fmt.Sprintf("elements sum to 0, so they cannot be scaled to sum to %s", Float64(target))
+	return RenderMessage(MsgCannotNormalizeZeroSum, msg, target)
+}
+func ErrAtMostOneTrue(keys []string, trueKeys []string) string {
+	msg := fmt.Sprintf("at most one of %s can be true (%s %s true)", UserStrsOr(keys), UserStrsAnd(trueKeys), Plural(len(trueKeys), "is", "are"))
+	return RenderMessage(MsgAtMostOneTrue, msg, keys, trueKeys)
+}
+func ErrExactlyOneTrue(keys []string) string {
+	msg := fmt.Sprintf("one of %s must be true", UserStrsOr(keys))
+	return RenderMessage(MsgExactlyOneTrue, msg, keys)
+}
+func ErrInvalidChecksum(provided string, description string) string {
+	msg := fmt.Sprintf("%s has an invalid checksum (%s)", UserStr(provided), description)
+	if description == "" {
+		msg = fmt.Sprintf("%s has an invalid checksum", UserStr(provided))
+	}
+	return RenderMessage(MsgInvalidChecksum, msg, provided, description)
+}
+func ErrInvalidNumericBool(provided interface{}) string {
+	msg := fmt.Sprintf("%s is not a valid boolean (expected true, false, 1, or 0)", UserStr(provided))
+	return RenderMessage(MsgInvalidNumericBool, msg, provided)
+}
+func ErrInvalidRatio(provided string, allowColon bool) string {
+	msg := fmt.Sprintf("%s is not a valid ratio (expected a number or a fraction like \"1/4\")", UserStr(provided))
+	if allowColon {
+		msg = fmt.Sprintf("%s is not a valid ratio (expected a number or a fraction like \"1/4\" or \"3:2\")", UserStr(provided))
+	}
+	return RenderMessage(MsgInvalidRatio, msg, provided, allowColon)
+}
+func ErrZeroDenominator(provided string) string {
+	msg := fmt.Sprintf("%s is not a valid ratio (the denominator cannot be 0)", UserStr(provided))
+	return RenderMessage(MsgZeroDenominator, msg, provided)
+}
+func ErrMalformedKVPair(pair string, kvDelimiter string) string {
+	msg := fmt.Sprintf("%s is not a valid key-value pair (expected <key>%s<value>)", UserStr(pair), kvDelimiter)
+	return RenderMessage(MsgMalformedKVPair, msg, pair, kvDelimiter)
+}
+func ErrEmptyKVPairKey(pair string) string {
+	msg := fmt.Sprintf("%s is not a valid key-value pair (the key is empty)", UserStr(pair))
+	return RenderMessage(MsgEmptyKVPairKey, msg, pair)
+}
+func ErrDuplicateKey(key string) string {
+	msg := fmt.Sprintf("key %s is specified more than once", UserStr(key))
+	return RenderMessage(MsgDuplicateKey, msg, key)
+}
+func ErrExplicitPlus(provided string) string {
+	msg := fmt.Sprintf("%s must not have a leading \"+\"", UserStr(provided))
+	return RenderMessage(MsgExplicitPlus, msg, provided)
+}
+func ErrMisplacedGroupingComma(provided interface{}) string {
+	msg := fmt.Sprintf("%s has misplaced grouping commas (expected e.g. \"1,000,000\")", UserStr(provided))
+	return RenderMessage(MsgMisplacedGroupingComma, msg, provided)
+}
+func ErrMisplacedGroupingSeparator(provided interface{}) string {
+	msg := fmt.Sprintf("%s has misplaced grouping separators (expected e.g. \"1,000,000\" or \"1_000_000\")", UserStr(provided))
+	return RenderMessage(MsgMisplacedGroupingSeparator, msg, provided)
+}
+func ErrInvalidTimestamp(provided string, layouts []string) string {
+	msg := fmt.Sprintf("%s is not a valid timestamp (expected the format %s)", UserStr(provided), UserStrsOr(layouts))
+	return RenderMessage(MsgInvalidTimestamp, msg, provided, layouts)
+}
+func ErrInvalidDuration(provided string) string {
+	msg := fmt.Sprintf("%s is not a valid duration (e.g. 45s, 5m, or 1h30m)", UserStr(provided))
+	return RenderMessage(MsgInvalidDuration, msg, provided)
+}
+func ErrTimestampsOutOfOrder(prevIndex int, prev string, provided string, strict bool) string {
+	msg := fmt.Sprintf("%s must not be before %s (the timestamp at index %d)", provided, prev, prevIndex)
+	if strict {
+		msg = fmt.Sprintf("%s must be after %s (the timestamp at index %d)", provided, prev, prevIndex)
+	}
+	return RenderMessage(MsgTimestampsOutOfOrder, msg, prevIndex, prev, provided, strict)
+}
+func ErrMustBePowerOfTwo(provided interface{}, examples []int64) string {
+	msg := fmt.Sprintf("%s must be a power of two (e.g. %s)", UserStr(provided), strings.Join(UserStrs(examples), ", "))
+	if len(examples) == 0 {
+		msg = fmt.Sprintf("%s must be a power of two", UserStr(provided))
+	}
+	return RenderMessage(MsgMustBePowerOfTwo, msg, provided, examples)
+}
+func ErrLeadingZeros(provided interface{}) string {
+	msg := fmt.Sprintf("%s has leading zeros (which are not allowed)", UserStr(provided))
+	return RenderMessage(MsgLeadingZeros, msg, provided)
+}
+func ErrInvalidByteSize(provided string) string {
+	msg := fmt.Sprintf("%s is not a valid size (e.g. 512Mi, 1.5GB, or 1024)", UserStr(provided))
+	return RenderMessage(MsgInvalidByteSize, msg, provided)
+}
+func ErrInvalidByteSizeUnit(provided string, unit string, units []string) string {
+	msg := fmt.Sprintf("%s has an invalid unit (%s); the valid units are %s", UserStr(provided), UserStr(unit), strings.Join(units, ", "))
+	return RenderMessage(MsgInvalidByteSizeUnit, msg, provided, unit, units)
+}
+func ErrByteSizeSignNotAllowed(provided string) string {
+	msg := fmt.Sprintf("%s cannot have a sign (+ or -)", UserStr(provided))
+	return RenderMessage(MsgByteSizeSignNotAllowed, msg, provided)
+}
+func ErrByteSizeRateNotAllowed(provided string) string {
+	msg := fmt.Sprintf("%s must be a size, not a rate (remove the \"/s\" suffix)", UserStr(provided))
+	return RenderMessage(MsgByteSizeRateNotAllowed, msg, provided)
+}
+func ErrByteSizeNotWhole(provided string) string {
+	msg := fmt.Sprintf("%s is not a whole number of bytes", UserStr(provided))
+	return RenderMessage(MsgByteSizeNotWhole, msg, provided)
+}
+func ErrInvalidUTF8(offset int) string {
+	msg := fmt.Sprintf("is not valid UTF-8 (the first invalid byte is at offset %d)", offset)
+	return RenderMessage(MsgInvalidUTF8, msg, offset)
+}
+func ErrControlChar(char rune, offset int) string {
+	msg := fmt.Sprintf("cannot contain control characters (found %U at byte offset %d)", char, offset)
+	return RenderMessage(MsgControlChar, msg, char, offset)
+}
+func ErrUndefinedFieldRef(ref string) string {
+	msg := fmt.Sprintf("%s refers to %s, which is not defined", UserStr("${"+ref+"}"), UserStr(ref))
+	return RenderMessage(MsgUndefinedFieldRef, msg, ref)
+}
+func ErrFieldRefCycle(chain []string) string {
+	msg := fmt.Sprintf("references form a cycle (%s)", strings.Join(chain, " -> "))
+	return RenderMessage(MsgFieldRefCycle, msg, chain)
+}
+func ErrInvalidGlob(provided string, offset int, reason string) string {
+	msg := fmt.Sprintf("%s is not a valid glob pattern (%s at position %d)", UserStr(provided), reason, offset)
+	return RenderMessage(MsgInvalidGlob, msg, provided, offset, reason)
+}
+func ErrGlobNoMatches(provided string) string {
+	msg := fmt.Sprintf("no files match %s", UserStr(provided))
+	return RenderMessage(MsgGlobNoMatches, msg, provided)
+}
+func ErrInvalidCSVList(provided string, reason string) string {
+	msg := fmt.Sprintf("%s is not a valid list (%s)", UserStr(provided), reason)
+	return RenderMessage(MsgInvalidCSVList, msg, provided, reason)
+}
+func ErrCSVListMultipleLines(provided string) string {
+	msg := fmt.Sprintf("%s is not a valid list (quoted lists must be on a single line)", UserStr(provided))
+	return RenderMessage(MsgCSVListMultipleLines, msg, provided)
+}
+func ErrCSVQuotingDelimiter(delimiter string) string {
+	msg := fmt.Sprintf("%s can't be used as a delimiter for quoted lists (a single character other than whitespace, a quote, or a newline is required)", UserStr(delimiter))
+	return RenderMessage(MsgCSVQuotingDelimiter, msg, delimiter)
+}
+func ErrInvalidEndpointHost(provided string, host string) string {
+	msg := fmt.Sprintf("%s: invalid host %s (expected a hostname or IP address)", UserStr(provided), UserStr(host))
+	return RenderMessage(MsgInvalidEndpointHost, msg, provided, host)
+}
+func ErrInvalidEndpointPort(provided string, port string) string {
+	msg := fmt.Sprintf("%s: invalid port %s (expected an integer between 1 and 65535)", UserStr(provided), UserStr(port))
+	return RenderMessage(MsgInvalidEndpointPort, msg, provided, port)
+}
+func ErrEndpointMissingPort(provided string) string {
+	msg := fmt.Sprintf("%s: missing port (expected <host>:<port>)", UserStr(provided))
+	return RenderMessage(MsgEndpointMissingPort, msg, provided)
+}
+func ErrEndpointSchemeNotAllowed(provided string, scheme string, allowed []string) string {
+	msg := fmt.Sprintf("%s: invalid scheme %s (expected %s)", UserStr(provided), UserStr(scheme), UserStrsOr(allowed))
+	if len(allowed) == 0 {
+		msg = fmt.Sprintf("%s: a scheme isn't supported here (expected <host>:<port>)", UserStr(provided))
+	}
+	return RenderMessage(MsgEndpointSchemeNotAllowed, msg, provided, scheme, allowed)
+}
+func ErrInvalidSourceRef(provided string) string {
+	msg := fmt.Sprintf("%s is not a valid reference (expected <scheme>://<ref>)", UserStr(provided))
+	return RenderMessage(MsgInvalidSourceRef, msg, provided)
+}
+func ErrInvalidSourceScheme(provided string) string {
+	msg := fmt.Sprintf("%s is not a valid source scheme", UserStr(provided))
+	return RenderMessage(MsgInvalidSourceScheme, msg, provided)
+}
+func ErrUnknownSourceScheme(provided string, registered []string) string {
+	msg := fmt.Sprintf("unknown source scheme %s (registered schemes: %s)", UserStr(provided), strings.Join(registered, ", "))
+	return RenderMessage(MsgUnknownSourceScheme, msg, provided, registered)
+}
+func ErrDuplicateSourceScheme(provided string) string {
+	msg := fmt.Sprintf("source scheme %s is already registered", UserStr(provided))
+	return RenderMessage(MsgDuplicateSourceScheme, msg, provided)
+}
+func ErrMergeConflict(key string, filePath1 string, type1 interface{}, filePath2 string, type2 interface{}) string {
+	msg := fmt.Sprintf("%s: cannot merge %s from %s with %s from %s", key, type1, filePath1, type2, filePath2)
+	return RenderMessage(MsgMergeConflict, msg, key, filePath1, type1, filePath2, type2)
+}
+func ErrPromptAttemptsExhausted(attempts int) string {
+	msg := fmt.Sprintf("no valid value was provided after %d %s", attempts, Plural(attempts, "attempt", "attempts"))
+	return RenderMessage(MsgPromptAttemptsExhausted, msg, attempts)
+}
+func PromptAttemptsRemaining(remaining int) string {
+	return fmt.Sprintf("(%d %s remaining)", remaining, Plural(remaining, "attempt", "attempts"))
+}
+func ErrInvalidIntOrKeyword(provided interface{}, keywords []string) string {
+	msg := fmt.Sprintf("%s: invalid value (expected an integer or %s)", UserStr(provided), UserStrsOr(keywords))
+	return RenderMessage(MsgInvalidIntOrKeyword, msg, provided, keywords)
+}
+func ErrOrKeywords(errStr string, keywords []string) string {
+	msg := fmt.Sprintf("%s (or %s)", errStr, UserStrsOr(keywords))
+	return RenderMessage(MsgOrKeywords, msg, errStr, keywords)
+}
+func ErrExpectedInteger(provided interface{}) string {
+	msg := fmt.Sprintf("expected an integer; got %s", UserStr(provided))
+	return RenderMessage(MsgExpectedInteger, msg, provided)
+}
+func ErrInvalidIntOrExponent(provided interface{}) string {
+	msg := fmt.Sprintf("%s is not an integer (integers may also be written in exponent notation, e.g. 1e6 or 2.5e3)", UserStr(provided))
+	return RenderMessage(MsgInvalidIntOrExponent, msg, provided)
+}
+func ErrIntOutOfRange(provided interface{}, bits int) string {
+	msg := fmt.Sprintf("%s is out of range for a %d-bit integer", UserStr(provided), bits)
+	return RenderMessage(MsgIntOutOfRange, msg, provided, bits)
+}
+func ErrFloat32OutOfRange(provided interface{}) string {
+	msg := fmt.Sprintf("%s is out of range for a 32-bit float (the largest magnitude is %s)", UserStr(provided), strconv.FormatFloat(math.MaxFloat32, 'g', -1, 32))
+	return RenderMessage(MsgFloat32OutOfRange, msg, provided)
+}
+func WarnFloat32PrecisionLoss(provided interface{}, stored float32) string {
+	return fmt.Sprintf("warning: %s cannot be represented exactly as a 32-bit float, and will be stored as %s", UserStr(provided), Float32(stored))
+}
+func ErrInvalidSelection(provided string, numOptions int) string {
+	msg := fmt.Sprintf("%s is not a valid selection (enter a number from 1 to %d, or one of the listed values)", UserStr(provided), numOptions)
+	return RenderMessage(MsgInvalidSelection, msg, provided, numOptions)
+}
+func ErrLevelTooLow(provided string, minLevel string, levels []string) string {
+	msg := fmt.Sprintf("%s must be at least %s (%s)", UserStr(provided), UserStr(minLevel), strings.Join(levels, " < "))
+	return RenderMessage(MsgLevelTooLow, msg, provided, minLevel, levels)
+}
+func ErrLevelTooHigh(provided string, maxLevel string, levels []string) string {
+	msg := fmt.Sprintf("%s must be at most %s (%s)", UserStr(provided), UserStr(maxLevel), strings.Join(levels, " < "))
+	return RenderMessage(MsgLevelTooHigh, msg, provided, maxLevel, levels)
+}
+func ErrPromptNonInteractive(hint string) string {
+	msg := fmt.Sprintf("running non-interactively (%s)", hint)
+	if hint == "" {
+		msg = "running non-interactively"
+	}
+	return RenderMessage(MsgPromptNonInteractive, msg, hint)
+}
+func ErrPromptNeeded(prompt string, hint string) string {
+	msg := fmt.Sprintf("%s: a value must be provided without prompting (prompts are disabled; %s)", prompt, hint)
+	if hint == "" {
+		msg = fmt.Sprintf("%s: a value must be provided without prompting (prompts are disabled)", prompt)
+	}
+	return RenderMessage(MsgPromptNeeded, msg, prompt, hint)
+}
+func ErrInvalidIntRange(provided string) string {
+	msg := fmt.Sprintf("%s is not a valid range (expected an integer, or a range like \"1-10\")", UserStr(provided))
+	return RenderMessage(MsgInvalidIntRange, msg, provided)
+}
+func ErrIntRangeReversed(provided string) string {
+	msg := fmt.Sprintf("%s is not a valid range (the start cannot be greater than the end)", UserStr(provided))
+	return RenderMessage(MsgIntRangeReversed, msg, provided)
+}
+func ErrInvalidGoIdent(provided string) string {
+	msg := fmt.Sprintf("%s must be a valid identifier: it must contain only letters, numbers, and underscores, and cannot start with a number", UserStr(provided))
+	return RenderMessage(MsgInvalidGoIdent, msg, provided)
+}
+func ErrGoKeyword(provided string) string {
+	msg := fmt.Sprintf("%s must be a valid identifier: it cannot be a Go keyword", UserStr(provided))
+	return RenderMessage(MsgGoKeyword, msg, provided)
+}
+func ErrInvalidEnvVarName(provided string) string {
+	msg := fmt.Sprintf("%s is not a valid environment variable name (it must contain only letters, numbers, and underscores, and cannot start with a number)", UserStr(provided))
+	return RenderMessage(MsgInvalidEnvVarName, msg, provided)
+}
+func ErrReservedEnvVarPrefix(provided string, prefix string) string {
+	msg := fmt.Sprintf("%s cannot start with %s (this prefix is reserved)", UserStr(provided), UserStr(prefix))
+	return RenderMessage(MsgReservedEnvVarPrefix, msg, provided, prefix)
+}
+func ErrInvalidURLEncoding(provided string) string {
+	msg := fmt.Sprintf("%s is not a valid percent-encoded value", UserStr(provided))
+	return RenderMessage(MsgInvalidURLEncoding, msg, provided)
+}
+func ErrInvalidURLEscape(provided string, escape string) string {
+	msg := fmt.Sprintf("%s is not a valid percent-encoded value (%s is not a valid escape sequence)", UserStr(provided), UserStr(escape))
+	return RenderMessage(MsgInvalidURLEscape, msg, provided, escape)
 }
 func ErrInvalidUrl(provided string) string {
-	return fmt.Sprintf("%s is not a valid URL", UserStr(provided))
+	msg := fmt.Sprintf("%s is not a valid URL", UserStr(provided))
+	return RenderMessage(MsgInvalidUrl, msg, provided)
 }
 func ErrInvalidS3aPath(provided string) string {
-	return fmt.Sprintf("%s is not a valid s3a path", UserStr(provided))
+	msg := fmt.Sprintf("%s is not a valid s3a path", UserStr(provided))
+	return RenderMessage(MsgInvalidS3aPath, msg, provided)
 }
 
 func ErrFileDoesNotExist(path string) string {
-	return fmt.Sprintf("%s: file does not exist", path)
+	msg := fmt.Sprintf("%s: file does not exist", path)
+	return RenderMessage(MsgFileDoesNotExist, msg, path)
+}
+
+func ErrFileTooLarge(maxBytes int64) string {
+	msg := fmt.Sprintf("file is too large (the limit is %d bytes)", maxBytes)
+	return RenderMessage(MsgFileTooLarge, msg, maxBytes)
+}
+
+func ErrFileLineOutOfRange(line int, numLines int) string {
+	msg := fmt.Sprintf("line %d is out of range (file has %d %s)", line, numLines, Plural(numLines, "line", "lines"))
+	return RenderMessage(MsgFileLineOutOfRange, msg, line, numLines)
+}
+
+func ErrInvalidFileLine(line int) string {
+	msg := fmt.Sprintf("line %d is not valid (lines are numbered from 1, or from -1 to count from the end)", line)
+	return RenderMessage(MsgInvalidFileLine, msg, line)
 }
 
 func ErrDirDoesNotExist(path string) string {
-	return fmt.Sprintf("%s: directory does not exist", path)
+	msg := fmt.Sprintf("%s: directory does not exist", path)
+	return RenderMessage(MsgDirDoesNotExist, msg, path)
 }
 
 func ErrFileAlreadyExists(path string) string {
-	return fmt.Sprintf("%s: file already exists", path)
+	msg := fmt.Sprintf("%s: file already exists", path)
+	return RenderMessage(MsgFileAlreadyExists, msg, path)
 }
 
 func ErrReadFile(path string) string {
-	return fmt.Sprintf("%s: unable to read file", path)
+	msg := fmt.Sprintf("%s: unable to read file", path)
+	return RenderMessage(MsgReadFile, msg, path)
 }
 
 func ErrReadDir(path string) string {
-	return fmt.Sprintf("%s: unable to read directory", path)
+	msg := fmt.Sprintf("%s: unable to read directory", path)
+	return RenderMessage(MsgReadDir, msg, path)
 }
 
 func ErrReadFormFile(fileName string) string {
-	return fmt.Sprintf("unable to read request form file %s", UserStr(fileName))
+	msg := fmt.Sprintf("unable to read request form file %s", UserStr(fileName))
+	return RenderMessage(MsgReadFormFile, msg, fileName)
 }
 
 func ErrCreateFile(path string) string {
-	return fmt.Sprintf("%s: unable to create file", path)
+	msg := fmt.Sprintf("%s: unable to create file", path)
+	return RenderMessage(MsgCreateFile, msg, path)
 }
 
 func ErrCreateDir(path string) string {
-	return fmt.Sprintf("%s: unable to create directory", path)
+	msg := fmt.Sprintf("%s: unable to create directory", path)
+	return RenderMessage(MsgCreateDir, msg, path)
 }
 
 func ErrWriteFile(path string) string {
-	return fmt.Sprintf("%s: unable to write file", path)
+	msg := fmt.Sprintf("%s: unable to write file", path)
+	return RenderMessage(MsgWriteFile, msg, path)
 }
 
 func ErrCwdDirExists(dirName string) string {
-	return fmt.Sprintf("a directory named %s already exists in your current working directory", UserStr(dirName))
+	msg := fmt.Sprintf("a directory named %s already exists in your current working directory", UserStr(dirName))
+	return RenderMessage(MsgCwdDirExists, msg, dirName)
 }
 
 func ErrDuplicateZipPath(path string) string {
-	return fmt.Sprintf("conflicting path in zip (%s)", UserStr(path))
+	msg := fmt.Sprintf("conflicting path in zip (%s)", UserStr(path))
+	return RenderMessage(MsgDuplicateZipPath, msg, path)
 }
 
 func ErrFailedToConnect(urlStr string) string {
-	return fmt.Sprintf("failed to connect to the operator (%s), run `cortex configure` if you need to update the operator URL", urlStr)
+	msg := fmt.Sprintf("failed to connect to the operator (%s), run `cortex configure` if you need to update the operator URL", urlStr)
+	return RenderMessage(MsgFailedToConnect, msg, urlStr)
 }
 
 func ErrBeMoreSpecific(vals ...string) string {
-	return fmt.Sprintf("please specify %s", UserStrsOr(vals))
+	msg := fmt.Sprintf("please specify %s", UserStrsOr(vals))
+	return RenderMessage(MsgBeMoreSpecific, msg, vals)
 }
 
 func ErrApiNotFound(apiName string) string {
-	return fmt.Sprintf("api %s not found", UserStr(apiName))
+	msg := fmt.Sprintf("api %s not found", UserStr(apiName))
+	return RenderMessage(MsgApiNotFound, msg, apiName)
 }
 
 func ErrUserDataUnavailable(s3Path string) string {
-	return fmt.Sprintf("the file at %s does not exist, or your cluster does not have access to it", s3Path)
+	msg := fmt.Sprintf("the file at %s does not exist, or your cluster does not have access to it", s3Path)
+	return RenderMessage(MsgUserDataUnavailable, msg, s3Path)
 }
 
 func ErrAppNotDeployed(appName string) string {
-	return fmt.Sprintf("app %s is not deployed", UserStr(appName))
+	msg := fmt.Sprintf("app %s is not deployed", UserStr(appName))
+	return RenderMessage(MsgAppNotDeployed, msg, appName)
 }
 
 func ErrApiNotReady(apiName string, status string) string {
-	return fmt.Sprintf("api %s is %s", UserStr(apiName), status)
+	msg := fmt.Sprintf("api %s is %s", UserStr(apiName), status)
+	return RenderMessage(MsgApiNotReady, msg, apiName, status)
 }
 
 func ErrCliAlreadyInAppDir(dirPath string) string {
-	return fmt.Sprintf("your current working directory is already in a cortex app directory (%s)", dirPath)
+	msg := fmt.Sprintf("your current working directory is already in a cortex app directory (%s)", dirPath)
+	return RenderMessage(MsgCliAlreadyInAppDir, msg, dirPath)
 }
 
 func ErrApiVersionMismatch(operatorVersion string, clientVersion string) string {
-	return fmt.Sprintf("API version mismatch (Operator: %s; Client: %s)", operatorVersion, clientVersion)
+	msg := fmt.Sprintf("API version mismatch (Operator: %s; Client: %s)", operatorVersion, clientVersion)
+	return RenderMessage(MsgApiVersionMismatch, msg, operatorVersion, clientVersion)
 }
 
 func ErrFormFileMustBeProvided(fileName string) string {
-	return fmt.Sprintf("request form file %s must be provided", UserStr(fileName))
+	msg := fmt.Sprintf("request form file %s must be provided", UserStr(fileName))
+	return RenderMessage(MsgFormFileMustBeProvided, msg, fileName)
 }
 
 func ErrPathParamMustBeProvided(paramName string) string {
-	return fmt.Sprintf("path param %s must be provided", UserStr(paramName))
+	msg := fmt.Sprintf("path param %s must be provided", UserStr(paramName))
+	return RenderMessage(MsgPathParamMustBeProvided, msg, paramName)
 }
 
 func ErrQueryParamMustBeProvided(paramName string) string {
-	return fmt.Sprintf("query param %s must be provided", UserStr(paramName))
+	msg := fmt.Sprintf("query param %s must be provided", UserStr(paramName))
+	return RenderMessage(MsgQueryParamMustBeProvided, msg, paramName)
 }
 
 func ErrAnyQueryParamMustBeProvided(paramNames ...string) string {
-	return fmt.Sprintf("path params %s must be provided", UserStrsOr(paramNames))
+	msg := fmt.Sprintf("path params %s must be provided", UserStrsOr(paramNames))
+	return RenderMessage(MsgAnyQueryParamMustBeProvided, msg, paramNames)
 }
 
 func ErrUndefinedNameOrType(resourceNameOrType string) string {
-	return fmt.Sprintf("resource name or type %s does not exist", UserStr(resourceNameOrType))
+	msg := fmt.Sprintf("resource name or type %s does not exist", UserStr(resourceNameOrType))
+	return RenderMessage(MsgUndefinedNameOrType, msg, resourceNameOrType)
 }
 
 func CleanYAMLError(err error) string {
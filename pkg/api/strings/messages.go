@@ -0,0 +1,252 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strings
+
+import (
+	"errors"
+	"sync"
+)
+
+// Message codes identify kinds of errors (e.g. for matching errors, or for metrics labels), and are passed to the renderer
+// set by SetMessageRenderer(). Unlike messages, they don't change when messages are reworded. Errors built from the Err*
+// messages have the corresponding code (see Code())
+const (
+	MsgUnknown = "unknown" // the code of errors which don't have one (see Code())
+
+	MsgMustBeDefined              = "must_be_defined"                  // no args
+	MsgMustBeGreaterThan          = "must_be_greater_than"             // args: provided, boundary
+	MsgMustBeGreaterThanOrEqualTo = "must_be_greater_than_or_equal_to" // args: provided, boundary
+	MsgMustBeLessThan             = "must_be_less_than"                // args: provided, boundary
+	MsgMustBeLessThanOrEqualTo    = "must_be_less_than_or_equal_to"    // args: provided, boundary
+	MsgInvalidPrimitiveType       = "invalid_primitive_type"           // args: provided, allowed types ([]PrimitiveType)
+	MsgNotAllowedValue            = "not_allowed_value"                // args: provided, allowed values (a slice)
+
+	// the fixed messages (e.g. ErrPending), which aren't rendered
+	MsgPending                  = "pending"
+	MsgNotFound                 = "not_found"
+	MsgMissing                  = "missing"
+	MsgCannotBeEmpty            = "cannot_be_empty"
+	MsgMustBeEmpty              = "must_be_empty"
+	MsgCannotBeNull             = "cannot_be_null"
+	MsgRead                     = "read"
+	MsgUnzip                    = "unzip"
+	MsgCreateZip                = "create_zip"
+	MsgNotRegularFile           = "not_regular_file"
+	MsgMultipleLines            = "multiple_lines"
+	MsgCantMakeRequest          = "cant_make_request"
+	MsgPromptInterrupted        = "prompt_interrupted"
+	MsgPromptNotInteractive     = "prompt_not_interactive"
+	MsgPromptTimeout            = "prompt_timeout"
+	MsgMarshalJson              = "marshal_json"
+	MsgUnmarshalJson            = "unmarshal_json"
+	MsgUnmarshalYaml            = "unmarshal_yaml"
+	MsgMarshalMsgpack           = "marshal_msgpack"
+	MsgUnmarshalMsgpack         = "unmarshal_msgpack"
+	MsgClassificationTargetType = "classification_target_type"
+	MsgRegressionTargetType     = "regression_target_type"
+	MsgCliNotInAppDir           = "cli_not_in_app_dir"
+	MsgLoadBalancerInitializing = "load_balancer_initializing"
+	MsgUnableToAuthAws          = "unable_to_auth_aws"
+	MsgCortexInstallationBroken = "cortex_installation_broken"
+	MsgUnexpected               = "unexpected"
+	MsgWorkflowAppMismatch      = "workflow_app_mismatch"
+	MsgContextAppMismatch       = "context_app_mismatch"
+	MsgMoreThanOneWorkflow      = "more_than_one_workflow"
+	MsgCannotSetStructField     = "cannot_set_struct_field"
+
+	MsgUnsupportedKey                = "unsupported_key"                   // args: key
+	MsgDuplicatedValue               = "duplicated_value"                  // args: val
+	MsgMustHavePrefix                = "must_have_prefix"                  // args: provided, prefix
+	MsgAlphaNumericDashDotUnderscore = "alpha_numeric_dash_dot_underscore" // args: provided
+	MsgAlphaNumericDashUnderscore    = "alpha_numeric_dash_underscore"     // args: provided
+	MsgDNS1035                       = "dns1035"                           // args: provided
+	MsgDNS1123Chars                  = "dns1123_chars"                     // args: provided, allowDots
+	MsgDNS1123Boundary               = "dns1123_boundary"                  // args: provided
+	MsgDNS1123SubdomainParts         = "dns1123_subdomain_parts"           // args: provided
+	MsgStrTooLong                    = "str_too_long"                      // args: provided, maxLength
+	MsgTooFewElements                = "too_few_elements"                  // args: length, minLength
+	MsgTooManyElements               = "too_many_elements"                 // args: length, maxLength
+	MsgListLengthMismatch            = "list_length_mismatch"              // args: length, countKey, count
+	MsgWrongSum                      = "wrong_sum"                         // args: sum, target
+	MsgSumTooSmall                   = "sum_too_small"                     // args: sum, minSum
+	MsgSumTooLarge                   = "sum_too_large"                     // args: sum, maxSum
+	MsgCannotNormalizeZeroSum        = "cannot_normalize_zero_sum"         // args: target
+	MsgAtMostOneTrue                 = "at_most_one_true"                  // args: keys, trueKeys
+	MsgExactlyOneTrue                = "exactly_one_true"                  // args: keys
+	MsgInvalidChecksum               = "invalid_checksum"                  // args: provided, description
+	MsgInvalidNumericBool            = "invalid_numeric_bool"              // args: provided
+	MsgInvalidRatio                  = "invalid_ratio"                     // args: provided, allowColon
+	MsgZeroDenominator               = "zero_denominator"                  // args: provided
+	MsgMalformedKVPair               = "malformed_kv_pair"                 // args: pair, kvDelimiter
+	MsgEmptyKVPairKey                = "empty_kv_pair_key"                 // args: pair
+	MsgDuplicateKey                  = "duplicate_key"                     // args: key
+	MsgExplicitPlus                  = "explicit_plus"                     // args: provided
+	MsgMisplacedGroupingComma        = "misplaced_grouping_comma"          // args: provided
+	MsgMisplacedGroupingSeparator    = "misplaced_grouping_separator"      // args: provided
+	MsgInvalidTimestamp              = "invalid_timestamp"                 // args: provided, layouts
+	MsgInvalidDuration               = "invalid_duration"                  // args: provided
+	MsgTimestampsOutOfOrder          = "timestamps_out_of_order"           // args: prevIndex, prev, provided, strict
+	MsgMustBePowerOfTwo              = "must_be_power_of_two"              // args: provided, examples
+	MsgLeadingZeros                  = "leading_zeros"                     // args: provided
+	MsgInvalidByteSize               = "invalid_byte_size"                 // args: provided
+	MsgInvalidByteSizeUnit           = "invalid_byte_size_unit"            // args: provided, unit, units
+	MsgByteSizeSignNotAllowed        = "byte_size_sign_not_allowed"        // args: provided
+	MsgByteSizeRateNotAllowed        = "byte_size_rate_not_allowed"        // args: provided
+	MsgByteSizeNotWhole              = "byte_size_not_whole"               // args: provided
+	MsgInvalidUTF8                   = "invalid_utf8"                      // args: offset
+	MsgControlChar                   = "control_char"                      // args: char, offset
+	MsgUndefinedFieldRef             = "undefined_field_ref"               // args: ref
+	MsgFieldRefCycle                 = "field_ref_cycle"                   // args: chain
+	MsgInvalidGlob                   = "invalid_glob"                      // args: provided, offset, reason
+	MsgGlobNoMatches                 = "glob_no_matches"                   // args: provided
+	MsgInvalidCSVList                = "invalid_csv_list"                  // args: provided, reason
+	MsgCSVListMultipleLines          = "csv_list_multiple_lines"           // args: provided
+	MsgCSVQuotingDelimiter           = "csv_quoting_delimiter"             // args: delimiter
+	MsgInvalidEndpointHost           = "invalid_endpoint_host"             // args: provided, host
+	MsgInvalidEndpointPort           = "invalid_endpoint_port"             // args: provided, port
+	MsgEndpointMissingPort           = "endpoint_missing_port"             // args: provided
+	MsgEndpointSchemeNotAllowed      = "endpoint_scheme_not_allowed"       // args: provided, scheme, allowed
+	MsgInvalidSourceRef              = "invalid_source_ref"                // args: provided
+	MsgInvalidSourceScheme           = "invalid_source_scheme"             // args: provided
+	MsgUnknownSourceScheme           = "unknown_source_scheme"             // args: provided, registered
+	MsgDuplicateSourceScheme         = "duplicate_source_scheme"           // args: provided
+	MsgMergeConflict                 = "merge_conflict"                    // args: key, filePath1, type1, filePath2, type2
+	MsgPromptAttemptsExhausted       = "prompt_attempts_exhausted"         // args: attempts
+	MsgInvalidIntOrKeyword           = "invalid_int_or_keyword"            // args: provided, keywords
+	MsgOrKeywords                    = "or_keywords"                       // args: errStr, keywords
+	MsgExpectedInteger               = "expected_integer"                  // args: provided
+	MsgInvalidIntOrExponent          = "invalid_int_or_exponent"           // args: provided
+	MsgIntOutOfRange                 = "int_out_of_range"                  // args: provided, bits
+	MsgFloat32OutOfRange             = "float32_out_of_range"              // args: provided
+	MsgInvalidSelection              = "invalid_selection"                 // args: provided, numOptions
+	MsgLevelTooLow                   = "level_too_low"                     // args: provided, minLevel, levels
+	MsgLevelTooHigh                  = "level_too_high"                    // args: provided, maxLevel, levels
+	MsgPromptNonInteractive          = "prompt_non_interactive"            // args: hint
+	MsgPromptNeeded                  = "prompt_needed"                     // args: prompt, hint
+	MsgInvalidIntRange               = "invalid_int_range"                 // args: provided
+	MsgIntRangeReversed              = "int_range_reversed"                // args: provided
+	MsgInvalidGoIdent                = "invalid_go_ident"                  // args: provided
+	MsgGoKeyword                     = "go_keyword"                        // args: provided
+	MsgInvalidEnvVarName             = "invalid_env_var_name"              // args: provided
+	MsgReservedEnvVarPrefix          = "reserved_env_var_prefix"           // args: provided, prefix
+	MsgInvalidURLEncoding            = "invalid_url_encoding"              // args: provided
+	MsgInvalidURLEscape              = "invalid_url_escape"                // args: provided, escape
+	MsgInvalidUrl                    = "invalid_url"                       // args: provided
+	MsgInvalidS3aPath                = "invalid_s3a_path"                  // args: provided
+	MsgFileDoesNotExist              = "file_does_not_exist"               // args: path
+	MsgFileTooLarge                  = "file_too_large"                    // args: maxBytes
+	MsgFileLineOutOfRange            = "file_line_out_of_range"            // args: line, numLines
+	MsgInvalidFileLine               = "invalid_file_line"                 // args: line
+	MsgDirDoesNotExist               = "dir_does_not_exist"                // args: path
+	MsgFileAlreadyExists             = "file_already_exists"               // args: path
+	MsgReadFile                      = "read_file"                         // args: path
+	MsgReadDir                       = "read_dir"                          // args: path
+	MsgReadFormFile                  = "read_form_file"                    // args: fileName
+	MsgCreateFile                    = "create_file"                       // args: path
+	MsgCreateDir                     = "create_dir"                        // args: path
+	MsgWriteFile                     = "write_file"                        // args: path
+	MsgCwdDirExists                  = "cwd_dir_exists"                    // args: dirName
+	MsgDuplicateZipPath              = "duplicate_zip_path"                // args: path
+	MsgFailedToConnect               = "failed_to_connect"                 // args: urlStr
+	MsgBeMoreSpecific                = "be_more_specific"                  // args: vals
+	MsgApiNotFound                   = "api_not_found"                     // args: apiName
+	MsgUserDataUnavailable           = "user_data_unavailable"             // args: s3Path
+	MsgAppNotDeployed                = "app_not_deployed"                  // args: appName
+	MsgApiNotReady                   = "api_not_ready"                     // args: apiName, status
+	MsgCliAlreadyInAppDir            = "cli_already_in_app_dir"            // args: dirPath
+	MsgApiVersionMismatch            = "api_version_mismatch"              // args: operatorVersion, clientVersion
+	MsgFormFileMustBeProvided        = "form_file_must_be_provided"        // args: fileName
+	MsgPathParamMustBeProvided       = "path_param_must_be_provided"       // args: paramName
+	MsgQueryParamMustBeProvided      = "query_param_must_be_provided"      // args: paramName
+	MsgAnyQueryParamMustBeProvided   = "any_query_param_must_be_provided"  // args: paramNames
+	MsgUndefinedNameOrType           = "undefined_name_or_type"            // args: resourceNameOrType
+)
+
+var (
+	messageRendererMutex sync.RWMutex
+	messageRenderer      func(code string, args ...interface{}) string
+)
+
+// SetMessageRenderer sets a function which renders error messages (e.g. to translate them), given their code (e.g. MsgMustBeDefined).
+// If it returns "", the built-in message is used. Pass nil to remove it
+func SetMessageRenderer(renderer func(code string, args ...interface{}) string) {
+	messageRendererMutex.Lock()
+	defer messageRendererMutex.Unlock()
+	messageRenderer = renderer
+}
+
+// RenderMessage returns the message rendered by the renderer set by SetMessageRenderer(), or defaultMsg
+func RenderMessage(code string, defaultMsg string, args ...interface{}) string {
+	messageRendererMutex.RLock()
+	renderer := messageRenderer
+	messageRendererMutex.RUnlock()
+
+	if renderer != nil {
+		if msg := renderer(code, args...); msg != "" {
+			return msg
+		}
+	}
+	return defaultMsg
+}
+
+// MustBeDefined is ErrMustBeDefined, rendered by the renderer set by SetMessageRenderer()
+func MustBeDefined() string {
+	return RenderMessage(MsgMustBeDefined, ErrMustBeDefined)
+}
+
+// Coder is implemented by errors which have a message code (e.g. the errors returned by errors.WithCode())
+type Coder interface {
+	Code() string
+}
+
+// Code returns the code of the innermost error in err's chain (see errors.Unwrap()) which has one, or MsgUnknown.
+// It returns "" if err is nil
+func Code(err error) string {
+	if err == nil {
+		return ""
+	}
+	code := MsgUnknown
+	for ; err != nil; err = errors.Unwrap(err) {
+		if coder, ok := err.(Coder); ok {
+			code = coder.Code()
+		}
+	}
+	return code
+}
+
+// The error codes from before the message codes, which ErrorCode() returns.
+//
+// Deprecated: use the Msg* codes and Code()
+const (
+	ErrCodeUnknown         = MsgUnknown
+	ErrCodeMustBeDefined   = MsgMustBeDefined
+	ErrCodeInvalidType     = MsgInvalidPrimitiveType
+	ErrCodeOutOfRange      = "out_of_range"
+	ErrCodeNotAllowedValue = MsgNotAllowedValue
+)
+
+// ErrorCode is like Code(), but returns ErrCodeOutOfRange for all of the range codes (e.g. MsgMustBeGreaterThan).
+//
+// Deprecated: use Code()
+func ErrorCode(err error) string {
+	switch code := Code(err); code {
+	case MsgMustBeGreaterThan, MsgMustBeGreaterThanOrEqualTo, MsgMustBeLessThan, MsgMustBeLessThanOrEqualTo:
+		return ErrCodeOutOfRange
+	default:
+		return code
+	}
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strings_test
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+)
+
+// errBuilders lists every Err* message in errors.go, with its code (build is nil for the fixed messages)
+var errBuilders = []struct {
+	name  string
+	code  string
+	build func() string
+}{
+	{"ErrUnsupportedKey", s.MsgUnsupportedKey, func() string { return s.ErrUnsupportedKey("x") }},
+	{"ErrDuplicatedValue", s.MsgDuplicatedValue, func() string { return s.ErrDuplicatedValue("x") }},
+	{"ErrInvalidPrimitiveType", s.MsgInvalidPrimitiveType, func() string { return s.ErrInvalidPrimitiveType("x", s.PrimTypeInt) }},
+	{"ErrMustBeGreaterThan", s.MsgMustBeGreaterThan, func() string { return s.ErrMustBeGreaterThan("x", "x") }},
+	{"ErrMustBeGreaterThanOrEqualTo", s.MsgMustBeGreaterThanOrEqualTo, func() string { return s.ErrMustBeGreaterThanOrEqualTo("x", "x") }},
+	{"ErrMustBeLessThan", s.MsgMustBeLessThan, func() string { return s.ErrMustBeLessThan("x", "x") }},
+	{"ErrMustBeLessThanOrEqualTo", s.MsgMustBeLessThanOrEqualTo, func() string { return s.ErrMustBeLessThanOrEqualTo("x", "x") }},
+	{"ErrInvalidStr", s.MsgNotAllowedValue, func() string { return s.ErrInvalidStr("x", "x") }},
+	{"ErrNotAllowedValue", s.MsgNotAllowedValue, func() string { return s.ErrNotAllowedValue("x", "x") }},
+	{"ErrNotAllowedValueInFile", s.MsgNotAllowedValue, func() string { return s.ErrNotAllowedValueInFile("x", "x", "x") }},
+	{"ErrInvalidInt", s.MsgNotAllowedValue, func() string { return s.ErrInvalidInt(1, 1) }},
+	{"ErrInvalidInt32", s.MsgNotAllowedValue, func() string { return s.ErrInvalidInt32(1, 1) }},
+	{"ErrInvalidInt64", s.MsgNotAllowedValue, func() string { return s.ErrInvalidInt64(1, 1) }},
+	{"ErrInvalidFloat32", s.MsgNotAllowedValue, func() string { return s.ErrInvalidFloat32(1, 1) }},
+	{"ErrInvalidFloat64", s.MsgNotAllowedValue, func() string { return s.ErrInvalidFloat64(1, 1) }},
+	{"ErrMustHavePrefix", s.MsgMustHavePrefix, func() string { return s.ErrMustHavePrefix("x", "x") }},
+	{"ErrAlphaNumericDashDotUnderscore", s.MsgAlphaNumericDashDotUnderscore, func() string { return s.ErrAlphaNumericDashDotUnderscore("x") }},
+	{"ErrAlphaNumericDashUnderscore", s.MsgAlphaNumericDashUnderscore, func() string { return s.ErrAlphaNumericDashUnderscore("x") }},
+	{"ErrDNS1035", s.MsgDNS1035, func() string { return s.ErrDNS1035("x") }},
+	{"ErrDNS1123Chars", s.MsgDNS1123Chars, func() string { return s.ErrDNS1123Chars("x", true) }},
+	{"ErrDNS1123Boundary", s.MsgDNS1123Boundary, func() string { return s.ErrDNS1123Boundary("x") }},
+	{"ErrDNS1123SubdomainParts", s.MsgDNS1123SubdomainParts, func() string { return s.ErrDNS1123SubdomainParts("x") }},
+	{"ErrStrTooLong", s.MsgStrTooLong, func() string { return s.ErrStrTooLong("x", 1) }},
+	{"ErrTooFewElements", s.MsgTooFewElements, func() string { return s.ErrTooFewElements(1, 1) }},
+	{"ErrTooManyElements", s.MsgTooManyElements, func() string { return s.ErrTooManyElements(1, 1) }},
+	{"ErrListLengthMismatch", s.MsgListLengthMismatch, func() string { return s.ErrListLengthMismatch(1, "x", 1) }},
+	{"ErrWrongSum", s.MsgWrongSum, func() string { return s.ErrWrongSum(1, 1) }},
+	{"ErrSumTooSmall", s.MsgSumTooSmall, func() string { return s.ErrSumTooSmall(1, 1) }},
+	{"ErrSumTooLarge", s.MsgSumTooLarge, func() string { return s.ErrSumTooLarge(1, 1) }},
+	{"ErrCannotNormalizeZeroSum", s.MsgCannotNormalizeZeroSum, func() string { return s.ErrCannotNormalizeZeroSum(1) }},
+	{"ErrAtMostOneTrue", s.MsgAtMostOneTrue, func() string { return s.ErrAtMostOneTrue([]string{"a", "b"}, []string{"a", "b"}) }},
+	{"ErrExactlyOneTrue", s.MsgExactlyOneTrue, func() string { return s.ErrExactlyOneTrue([]string{"a", "b"}) }},
+	{"ErrInvalidChecksum", s.MsgInvalidChecksum, func() string { return s.ErrInvalidChecksum("x", "x") }},
+	{"ErrInvalidNumericBool", s.MsgInvalidNumericBool, func() string { return s.ErrInvalidNumericBool("x") }},
+	{"ErrInvalidRatio", s.MsgInvalidRatio, func() string { return s.ErrInvalidRatio("x", true) }},
+	{"ErrZeroDenominator", s.MsgZeroDenominator, func() string { return s.ErrZeroDenominator("x") }},
+	{"ErrMalformedKVPair", s.MsgMalformedKVPair, func() string { return s.ErrMalformedKVPair("x", "x") }},
+	{"ErrEmptyKVPairKey", s.MsgEmptyKVPairKey, func() string { return s.ErrEmptyKVPairKey("x") }},
+	{"ErrDuplicateKey", s.MsgDuplicateKey, func() string { return s.ErrDuplicateKey("x") }},
+	{"ErrExplicitPlus", s.MsgExplicitPlus, func() string { return s.ErrExplicitPlus("x") }},
+	{"ErrMisplacedGroupingComma", s.MsgMisplacedGroupingComma, func() string { return s.ErrMisplacedGroupingComma("x") }},
+	{"ErrMisplacedGroupingSeparator", s.MsgMisplacedGroupingSeparator, func() string { return s.ErrMisplacedGroupingSeparator("x") }},
+	{"ErrInvalidTimestamp", s.MsgInvalidTimestamp, func() string { return s.ErrInvalidTimestamp("x", []string{"a", "b"}) }},
+	{"ErrInvalidDuration", s.MsgInvalidDuration, func() string { return s.ErrInvalidDuration("x") }},
+	{"ErrTimestampsOutOfOrder", s.MsgTimestampsOutOfOrder, func() string { return s.ErrTimestampsOutOfOrder(1, "x", "x", true) }},
+	{"ErrMustBePowerOfTwo", s.MsgMustBePowerOfTwo, func() string { return s.ErrMustBePowerOfTwo("x", []int64{2, 4}) }},
+	{"ErrLeadingZeros", s.MsgLeadingZeros, func() string { return s.ErrLeadingZeros("x") }},
+	{"ErrInvalidByteSize", s.MsgInvalidByteSize, func() string { return s.ErrInvalidByteSize("x") }},
+	{"ErrInvalidByteSizeUnit", s.MsgInvalidByteSizeUnit, func() string { return s.ErrInvalidByteSizeUnit("x", "x", []string{"a", "b"}) }},
+	{"ErrByteSizeSignNotAllowed", s.MsgByteSizeSignNotAllowed, func() string { return s.ErrByteSizeSignNotAllowed("x") }},
+	{"ErrByteSizeRateNotAllowed", s.MsgByteSizeRateNotAllowed, func() string { return s.ErrByteSizeRateNotAllowed("x") }},
+	{"ErrByteSizeNotWhole", s.MsgByteSizeNotWhole, func() string { return s.ErrByteSizeNotWhole("x") }},
+	{"ErrInvalidUTF8", s.MsgInvalidUTF8, func() string { return s.ErrInvalidUTF8(1) }},
+	{"ErrControlChar", s.MsgControlChar, func() string { return s.ErrControlChar('x', 1) }},
+	{"ErrUndefinedFieldRef", s.MsgUndefinedFieldRef, func() string { return s.ErrUndefinedFieldRef("x") }},
+	{"ErrFieldRefCycle", s.MsgFieldRefCycle, func() string { return s.ErrFieldRefCycle([]string{"a", "b"}) }},
+	{"ErrInvalidGlob", s.MsgInvalidGlob, func() string { return s.ErrInvalidGlob("x", 1, "x") }},
+	{"ErrGlobNoMatches", s.MsgGlobNoMatches, func() string { return s.ErrGlobNoMatches("x") }},
+	{"ErrInvalidCSVList", s.MsgInvalidCSVList, func() string { return s.ErrInvalidCSVList("x", "x") }},
+	{"ErrCSVListMultipleLines", s.MsgCSVListMultipleLines, func() string { return s.ErrCSVListMultipleLines("x") }},
+	{"ErrCSVQuotingDelimiter", s.MsgCSVQuotingDelimiter, func() string { return s.ErrCSVQuotingDelimiter("x") }},
+	{"ErrInvalidEndpointHost", s.MsgInvalidEndpointHost, func() string { return s.ErrInvalidEndpointHost("x", "x") }},
+	{"ErrInvalidEndpointPort", s.MsgInvalidEndpointPort, func() string { return s.ErrInvalidEndpointPort("x", "x") }},
+	{"ErrEndpointMissingPort", s.MsgEndpointMissingPort, func() string { return s.ErrEndpointMissingPort("x") }},
+	{"ErrEndpointSchemeNotAllowed", s.MsgEndpointSchemeNotAllowed, func() string { return s.ErrEndpointSchemeNotAllowed("x", "x", []string{"a", "b"}) }},
+	{"ErrInvalidSourceRef", s.MsgInvalidSourceRef, func() string { return s.ErrInvalidSourceRef("x") }},
+	{"ErrInvalidSourceScheme", s.MsgInvalidSourceScheme, func() string { return s.ErrInvalidSourceScheme("x") }},
+	{"ErrUnknownSourceScheme", s.MsgUnknownSourceScheme, func() string { return s.ErrUnknownSourceScheme("x", []string{"a", "b"}) }},
+	{"ErrDuplicateSourceScheme", s.MsgDuplicateSourceScheme, func() string { return s.ErrDuplicateSourceScheme("x") }},
+	{"ErrMergeConflict", s.MsgMergeConflict, func() string { return s.ErrMergeConflict("x", "x", "x", "x", "x") }},
+	{"ErrPromptAttemptsExhausted", s.MsgPromptAttemptsExhausted, func() string { return s.ErrPromptAttemptsExhausted(1) }},
+	{"ErrInvalidIntOrKeyword", s.MsgInvalidIntOrKeyword, func() string { return s.ErrInvalidIntOrKeyword("x", []string{"a", "b"}) }},
+	{"ErrOrKeywords", s.MsgOrKeywords, func() string { return s.ErrOrKeywords("x", []string{"a", "b"}) }},
+	{"ErrExpectedInteger", s.MsgExpectedInteger, func() string { return s.ErrExpectedInteger("x") }},
+	{"ErrInvalidIntOrExponent", s.MsgInvalidIntOrExponent, func() string { return s.ErrInvalidIntOrExponent("x") }},
+	{"ErrIntOutOfRange", s.MsgIntOutOfRange, func() string { return s.ErrIntOutOfRange("x", 1) }},
+	{"ErrFloat32OutOfRange", s.MsgFloat32OutOfRange, func() string { return s.ErrFloat32OutOfRange("x") }},
+	{"ErrInvalidSelection", s.MsgInvalidSelection, func() string { return s.ErrInvalidSelection("x", 1) }},
+	{"ErrLevelTooLow", s.MsgLevelTooLow, func() string { return s.ErrLevelTooLow("x", "x", []string{"a", "b"}) }},
+	{"ErrLevelTooHigh", s.MsgLevelTooHigh, func() string { return s.ErrLevelTooHigh("x", "x", []string{"a", "b"}) }},
+	{"ErrPromptNonInteractive", s.MsgPromptNonInteractive, func() string { return s.ErrPromptNonInteractive("x") }},
+	{"ErrPromptNeeded", s.MsgPromptNeeded, func() string { return s.ErrPromptNeeded("x", "x") }},
+	{"ErrInvalidIntRange", s.MsgInvalidIntRange, func() string { return s.ErrInvalidIntRange("x") }},
+	{"ErrIntRangeReversed", s.MsgIntRangeReversed, func() string { return s.ErrIntRangeReversed("x") }},
+	{"ErrInvalidGoIdent", s.MsgInvalidGoIdent, func() string { return s.ErrInvalidGoIdent("x") }},
+	{"ErrGoKeyword", s.MsgGoKeyword, func() string { return s.ErrGoKeyword("x") }},
+	{"ErrInvalidEnvVarName", s.MsgInvalidEnvVarName, func() string { return s.ErrInvalidEnvVarName("x") }},
+	{"ErrReservedEnvVarPrefix", s.MsgReservedEnvVarPrefix, func() string { return s.ErrReservedEnvVarPrefix("x", "x") }},
+	{"ErrInvalidURLEncoding", s.MsgInvalidURLEncoding, func() string { return s.ErrInvalidURLEncoding("x") }},
+	{"ErrInvalidURLEscape", s.MsgInvalidURLEscape, func() string { return s.ErrInvalidURLEscape("x", "x") }},
+	{"ErrInvalidUrl", s.MsgInvalidUrl, func() string { return s.ErrInvalidUrl("x") }},
+	{"ErrInvalidS3aPath", s.MsgInvalidS3aPath, func() string { return s.ErrInvalidS3aPath("x") }},
+	{"ErrFileDoesNotExist", s.MsgFileDoesNotExist, func() string { return s.ErrFileDoesNotExist("x") }},
+	{"ErrFileTooLarge", s.MsgFileTooLarge, func() string { return s.ErrFileTooLarge(1) }},
+	{"ErrFileLineOutOfRange", s.MsgFileLineOutOfRange, func() string { return s.ErrFileLineOutOfRange(1, 1) }},
+	{"ErrInvalidFileLine", s.MsgInvalidFileLine, func() string { return s.ErrInvalidFileLine(1) }},
+	{"ErrDirDoesNotExist", s.MsgDirDoesNotExist, func() string { return s.ErrDirDoesNotExist("x") }},
+	{"ErrFileAlreadyExists", s.MsgFileAlreadyExists, func() string { return s.ErrFileAlreadyExists("x") }},
+	{"ErrReadFile", s.MsgReadFile, func() string { return s.ErrReadFile("x") }},
+	{"ErrReadDir", s.MsgReadDir, func() string { return s.ErrReadDir("x") }},
+	{"ErrReadFormFile", s.MsgReadFormFile, func() string { return s.ErrReadFormFile("x") }},
+	{"ErrCreateFile", s.MsgCreateFile, func() string { return s.ErrCreateFile("x") }},
+	{"ErrCreateDir", s.MsgCreateDir, func() string { return s.ErrCreateDir("x") }},
+	{"ErrWriteFile", s.MsgWriteFile, func() string { return s.ErrWriteFile("x") }},
+	{"ErrCwdDirExists", s.MsgCwdDirExists, func() string { return s.ErrCwdDirExists("x") }},
+	{"ErrDuplicateZipPath", s.MsgDuplicateZipPath, func() string { return s.ErrDuplicateZipPath("x") }},
+	{"ErrFailedToConnect", s.MsgFailedToConnect, func() string { return s.ErrFailedToConnect("x") }},
+	{"ErrBeMoreSpecific", s.MsgBeMoreSpecific, func() string { return s.ErrBeMoreSpecific("x") }},
+	{"ErrApiNotFound", s.MsgApiNotFound, func() string { return s.ErrApiNotFound("x") }},
+	{"ErrUserDataUnavailable", s.MsgUserDataUnavailable, func() string { return s.ErrUserDataUnavailable("x") }},
+	{"ErrAppNotDeployed", s.MsgAppNotDeployed, func() string { return s.ErrAppNotDeployed("x") }},
+	{"ErrApiNotReady", s.MsgApiNotReady, func() string { return s.ErrApiNotReady("x", "x") }},
+	{"ErrCliAlreadyInAppDir", s.MsgCliAlreadyInAppDir, func() string { return s.ErrCliAlreadyInAppDir("x") }},
+	{"ErrApiVersionMismatch", s.MsgApiVersionMismatch, func() string { return s.ErrApiVersionMismatch("x", "x") }},
+	{"ErrFormFileMustBeProvided", s.MsgFormFileMustBeProvided, func() string { return s.ErrFormFileMustBeProvided("x") }},
+	{"ErrPathParamMustBeProvided", s.MsgPathParamMustBeProvided, func() string { return s.ErrPathParamMustBeProvided("x") }},
+	{"ErrQueryParamMustBeProvided", s.MsgQueryParamMustBeProvided, func() string { return s.ErrQueryParamMustBeProvided("x") }},
+	{"ErrAnyQueryParamMustBeProvided", s.MsgAnyQueryParamMustBeProvided, func() string { return s.ErrAnyQueryParamMustBeProvided("x") }},
+	{"ErrUndefinedNameOrType", s.MsgUndefinedNameOrType, func() string { return s.ErrUndefinedNameOrType("x") }},
+	{"ErrPending", s.MsgPending, nil},
+	{"ErrNotFound", s.MsgNotFound, nil},
+	{"ErrMissing", s.MsgMissing, nil},
+	{"ErrMustBeDefined", s.MsgMustBeDefined, nil},
+	{"ErrCannotBeEmpty", s.MsgCannotBeEmpty, nil},
+	{"ErrMustBeEmpty", s.MsgMustBeEmpty, nil},
+	{"ErrCannotBeNull", s.MsgCannotBeNull, nil},
+	{"ErrRead", s.MsgRead, nil},
+	{"ErrUnzip", s.MsgUnzip, nil},
+	{"ErrCreateZip", s.MsgCreateZip, nil},
+	{"ErrNotRegularFile", s.MsgNotRegularFile, nil},
+	{"ErrMultipleLines", s.MsgMultipleLines, nil},
+	{"ErrCantMakeRequest", s.MsgCantMakeRequest, nil},
+	{"ErrPromptInterrupted", s.MsgPromptInterrupted, nil},
+	{"ErrPromptNotInteractive", s.MsgPromptNotInteractive, nil},
+	{"ErrPromptTimeout", s.MsgPromptTimeout, nil},
+	{"ErrMarshalJson", s.MsgMarshalJson, nil},
+	{"ErrUnmarshalJson", s.MsgUnmarshalJson, nil},
+	{"ErrUnmarshalYaml", s.MsgUnmarshalYaml, nil},
+	{"ErrMarshalMsgpack", s.MsgMarshalMsgpack, nil},
+	{"ErrUnmarshalMsgpack", s.MsgUnmarshalMsgpack, nil},
+	{"ErrClassificationTargetType", s.MsgClassificationTargetType, nil},
+	{"ErrRegressionTargetType", s.MsgRegressionTargetType, nil},
+	{"ErrCliNotInAppDir", s.MsgCliNotInAppDir, nil},
+	{"ErrLoadBalancerInitializing", s.MsgLoadBalancerInitializing, nil},
+	{"ErrUnableToAuthAws", s.MsgUnableToAuthAws, nil},
+	{"ErrCortexInstallationBroken", s.MsgCortexInstallationBroken, nil},
+	{"ErrUnexpected", s.MsgUnexpected, nil},
+	{"ErrWorkflowAppMismatch", s.MsgWorkflowAppMismatch, nil},
+	{"ErrContextAppMismatch", s.MsgContextAppMismatch, nil},
+	{"ErrMoreThanOneWorkflow", s.MsgMoreThanOneWorkflow, nil},
+	{"ErrCannotSetStructField", s.MsgCannotSetStructField, nil},
+}
+
+// errBuilderNames returns the names of the exported Err* functions and variables in errors.go
+func errBuilderNames(t *testing.T) []string {
+	file, err := parser.ParseFile(token.NewFileSet(), "errors.go", nil, 0)
+	require.NoError(t, err)
+	var names []string
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil && strings.HasPrefix(decl.Name.Name, "Err") {
+				names = append(names, decl.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if valueSpec, ok := spec.(*ast.ValueSpec); ok {
+					for _, ident := range valueSpec.Names {
+						if strings.HasPrefix(ident.Name, "Err") {
+							names = append(names, ident.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return names
+}
+
+func TestErrBuilderCodes(t *testing.T) {
+	builderNames := make([]string, len(errBuilders))
+	for i, builder := range errBuilders {
+		builderNames[i] = builder.name
+	}
+	// add new messages to errBuilders (along with a Msg* code)
+	require.ElementsMatch(t, errBuilderNames(t), builderNames)
+
+	s.SetMessageRenderer(func(code string, args ...interface{}) string {
+		return "code: " + code
+	})
+	defer s.SetMessageRenderer(nil)
+
+	for _, builder := range errBuilders {
+		require.NotEmpty(t, builder.code, builder.name)
+		require.NotEqual(t, s.MsgUnknown, builder.code, builder.name)
+		if builder.build != nil {
+			require.Equal(t, "code: "+builder.code, builder.build(), builder.name)
+		}
+	}
+}
+
+type codedError struct {
+	code  string
+	cause error
+}
+
+func (err *codedError) Error() string {
+	return err.code
+}
+
+func (err *codedError) Code() string {
+	return err.code
+}
+
+func (err *codedError) Unwrap() error {
+	return err.cause
+}
+
+func TestCode(t *testing.T) {
+	require.Equal(t, "", s.Code(nil))
+	require.Equal(t, s.MsgUnknown, s.Code(errors.New("plain")))
+
+	err := error(&codedError{code: s.MsgMustBeGreaterThan})
+	require.Equal(t, s.MsgMustBeGreaterThan, s.Code(err))
+
+	err = fmt.Errorf("replicas: %w", err)
+	require.Equal(t, s.MsgMustBeGreaterThan, s.Code(err))
+
+	// the innermost code wins
+	err = &codedError{code: "outer", cause: err}
+	require.Equal(t, s.MsgMustBeGreaterThan, s.Code(err))
+
+	err = &codedError{code: "outer", cause: errors.New("plain")}
+	require.Equal(t, "outer", s.Code(err))
+}
+
+func TestErrorCode(t *testing.T) {
+	require.Equal(t, "", s.ErrorCode(nil))
+	require.Equal(t, s.ErrCodeUnknown, s.ErrorCode(errors.New("plain")))
+
+	for _, code := range []string{s.MsgMustBeGreaterThan, s.MsgMustBeGreaterThanOrEqualTo, s.MsgMustBeLessThan, s.MsgMustBeLessThanOrEqualTo} {
+		err := fmt.Errorf("replicas: %w", &codedError{code: code})
+		require.Equal(t, s.ErrCodeOutOfRange, s.ErrorCode(err), code)
+	}
+
+	require.Equal(t, s.ErrCodeMustBeDefined, s.ErrorCode(&codedError{code: s.MsgMustBeDefined}))
+	require.Equal(t, s.ErrCodeInvalidType, s.ErrorCode(&codedError{code: s.MsgInvalidPrimitiveType}))
+	require.Equal(t, s.ErrCodeNotAllowedValue, s.ErrorCode(&codedError{code: s.MsgNotAllowedValue}))
+	require.Equal(t, s.MsgPending, s.ErrorCode(&codedError{code: s.MsgPending}))
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package strings
 
 import (
+	"fmt"
 	"strings"
 )
 
@@ -54,6 +55,14 @@ func MaskString(str string, numPlain int) string {
 	return strings.Repeat("*", len(str)-numPlain) + str[len(str)-numPlain:]
 }
 
+// Redacted is shown in place of a sensitive value, e.g. "<redacted (len 41)>"
+func Redacted(val interface{}) string {
+	if str, ok := val.(string); ok {
+		return fmt.Sprintf("<redacted (len %d)>", len(str))
+	}
+	return fmt.Sprintf("<redacted (len %d)>", len(fmt.Sprint(val)))
+}
+
 func LongestCommonPrefix(strs ...string) string {
 	if len(strs) == 0 {
 		return ""
@@ -99,6 +108,14 @@ func TrimPrefixIfPresentInAll(strs []string, prefix string) ([]string, bool) {
 	return trimmedStrs, true
 }
 
+// Plural returns singular if n is 1, and plural otherwise, e.g. Plural(n, "error", "errors")
+func Plural(n int, singular string, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
 func StrsOr(strs []string) string {
 	return StrsSentence(strs, "or")
 }
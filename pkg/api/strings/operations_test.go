@@ -17,6 +17,7 @@ limitations under the License.
 package strings_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -56,3 +57,71 @@ func TestLongestCommonPrefix(t *testing.T) {
 	expected = ""
 	require.Equal(t, expected, s.LongestCommonPrefix(strs...))
 }
+
+func TestSuggestClosest(t *testing.T) {
+	regions := []string{"us-east-1", "us-east-2", "us-west-2", "eu-central-1"}
+
+	require.Equal(t, "us-east-1", s.SuggestClosest("us-esat-1", regions))
+	require.Equal(t, "us-west-2", s.SuggestClosest("US-WEST-2", regions))
+	require.Equal(t, "eu-central-1", s.SuggestClosest("eu-centrl-1", regions))
+	require.Equal(t, "", s.SuggestClosest("ap-south-1", regions))
+	require.Equal(t, "", s.SuggestClosest("", regions))
+	require.Equal(t, "", s.SuggestClosest("a", []string{"b"}))
+	require.Equal(t, "", s.SuggestClosest("us-esat-1", nil))
+
+	require.Equal(t, `; did you mean "us-east-1"?`, s.DidYouMean("us-east-1"))
+	require.Equal(t, "", s.DidYouMean(""))
+}
+
+func TestSetMessageRenderer(t *testing.T) {
+	s.SetMessageRenderer(func(code string, args ...interface{}) string {
+		if code == s.MsgMustBeGreaterThan {
+			return fmt.Sprintf("%v doit être supérieur à %v", args...)
+		}
+		return ""
+	})
+	defer s.SetMessageRenderer(nil)
+
+	require.Equal(t, "0 doit être supérieur à 1", s.ErrMustBeGreaterThan(0, 1))
+	require.Equal(t, "0 must be less than 1", s.ErrMustBeLessThan(0, 1))
+	require.Equal(t, s.ErrMustBeDefined, s.MustBeDefined())
+}
+
+func TestPlural(t *testing.T) {
+	require.Equal(t, "errors", s.Plural(0, "error", "errors"))
+	require.Equal(t, "error", s.Plural(1, "error", "errors"))
+	require.Equal(t, "errors", s.Plural(2, "error", "errors"))
+	require.Equal(t, "indices", s.Plural(3, "index", "indices"))
+}
+
+func TestStrsSentence(t *testing.T) {
+	require.Equal(t, "", s.StrsOr(nil))
+	require.Equal(t, "a", s.StrsOr([]string{"a"}))
+	require.Equal(t, "a or b", s.StrsOr([]string{"a", "b"}))
+	require.Equal(t, "a, b, or c", s.StrsOr([]string{"a", "b", "c"}))
+	require.Equal(t, "a, b, and c", s.StrsAnd([]string{"a", "b", "c"}))
+	require.Equal(t, `"a", "b", or "c"`, s.UserStrsOr([]string{"a", "b", "c"}))
+}
+
+// downstream tooling matches some of these messages, so their exact output is pinned here
+func TestPinnedMessages(t *testing.T) {
+	require.Equal(t, "1 validation error:", s.ValidationErrorsHeader(1))
+	require.Equal(t, "3 validation errors:", s.ValidationErrorsHeader(3))
+
+	require.Equal(t, `invalid value (got "d", must be "a", "b", or "c")`, s.ErrInvalidStr("d", "a", "b", "c"))
+	require.Equal(t, `invalid value (got "b", must be "a")`, s.ErrInvalidStr("b", "a"))
+	require.Equal(t, `invalid value (got 3, must be 1 or 2)`, s.ErrNotAllowedValue(3, []int{1, 2}))
+
+	require.Equal(t, "must contain at least 1 element (got 0)", s.ErrTooFewElements(0, 1))
+	require.Equal(t, "must contain at least 2 elements (got 1)", s.ErrTooFewElements(1, 2))
+	require.Equal(t, "must contain no more than 1 element (got 2)", s.ErrTooManyElements(2, 1))
+	require.Equal(t, "must contain no more than 3 elements (got 4)", s.ErrTooManyElements(4, 3))
+
+	require.Equal(t, `one of "s3", "gcs", or "local" must be true`, s.ErrExactlyOneTrue([]string{"s3", "gcs", "local"}))
+	require.Equal(t, `at most one of "s3", "gcs", or "local" can be true ("s3" and "gcs" are true)`, s.ErrAtMostOneTrue([]string{"s3", "gcs", "local"}, []string{"s3", "gcs"}))
+
+	require.Equal(t, `"abc" must be no more than 1 character`, s.ErrStrTooLong("abc", 1))
+	require.Equal(t, "(1 attempt remaining)", s.PromptAttemptsRemaining(1))
+	require.Equal(t, "no valid value was provided after 3 attempts", s.ErrPromptAttemptsExhausted(3))
+	require.Equal(t, "line 2 is out of range (file has 1 line)", s.ErrFileLineOutOfRange(2, 1))
+}
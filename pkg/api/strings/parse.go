@@ -18,6 +18,7 @@ package strings
 
 import (
 	"strconv"
+	"strings"
 )
 
 func ParseBool(valStr string) (bool, bool) {
@@ -44,6 +45,64 @@ func ParseFloat64(valStr string) (float64, bool) {
 	return casted, true
 }
 
+func ParseFloat32Lenient(valStr string) (float32, bool) {
+	return ParseFloat32(strings.TrimSpace(valStr))
+}
+
+func ParseFloat64Lenient(valStr string) (float64, bool) {
+	return ParseFloat64(strings.TrimSpace(valStr))
+}
+
+// StripCommaGrouping removes US-style grouping commas (e.g. "1,000,000"), returning false if any comma is misplaced (e.g. "1,00,0")
+func StripCommaGrouping(valStr string) (string, bool) {
+	return stripGrouping(valStr, ",")
+}
+
+// StripDigitGrouping removes grouping commas or underscores (e.g. "1,000,000" or "1_000_000"), returning false if any
+// separator is misplaced (e.g. "1,00"), or if both kinds are used
+func StripDigitGrouping(valStr string) (string, bool) {
+	if strings.Contains(valStr, ",") && strings.Contains(valStr, "_") {
+		return "", false
+	}
+	if strings.Contains(valStr, "_") {
+		return stripGrouping(valStr, "_")
+	}
+	return stripGrouping(valStr, ",")
+}
+
+func stripGrouping(valStr string, separator string) (string, bool) {
+	if !strings.Contains(valStr, separator) {
+		return valStr, true
+	}
+	sign := ""
+	digits := valStr
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		sign = digits[:1]
+		digits = digits[1:]
+	}
+	groups := strings.Split(digits, separator)
+	for i, group := range groups {
+		if i == 0 && (len(group) < 1 || len(group) > 3) {
+			return "", false
+		}
+		if i > 0 && len(group) != 3 {
+			return "", false
+		}
+		for _, char := range group {
+			if char < '0' || char > '9' {
+				return "", false
+			}
+		}
+	}
+	return sign + strings.Join(groups, ""), true
+}
+
+// HasLeadingZeros returns whether an integer string has superfluous leading zeros (e.g. "007" or "-00", but not "0")
+func HasLeadingZeros(valStr string) bool {
+	digits := strings.TrimLeft(valStr, "+-")
+	return len(digits) > 1 && digits[0] == '0'
+}
+
 func ParseInt(valStr string) (int, bool) {
 	casted, err := strconv.Atoi(valStr)
 	if err != nil {
@@ -52,6 +111,15 @@ func ParseInt(valStr string) (int, bool) {
 	return casted, true
 }
 
+// ParseIntLenient is like ParseInt(), but accepts surrounding whitespace and grouping commas or underscores (see StripDigitGrouping())
+func ParseIntLenient(valStr string) (int, bool) {
+	stripped, ok := StripDigitGrouping(strings.TrimSpace(valStr))
+	if !ok {
+		return 0, false
+	}
+	return ParseInt(stripped)
+}
+
 func ParseInt64(valStr string) (int64, bool) {
 	casted, err := strconv.ParseInt(valStr, 10, 64)
 	if err != nil {
@@ -60,6 +128,15 @@ func ParseInt64(valStr string) (int64, bool) {
 	return casted, true
 }
 
+// ParseInt64Lenient is like ParseInt64(), but accepts surrounding whitespace and grouping commas or underscores (see StripDigitGrouping())
+func ParseInt64Lenient(valStr string) (int64, bool) {
+	stripped, ok := StripDigitGrouping(strings.TrimSpace(valStr))
+	if !ok {
+		return 0, false
+	}
+	return ParseInt64(stripped)
+}
+
 func ParseInt32(valStr string) (int32, bool) {
 	casted, err := strconv.ParseInt(valStr, 10, 32)
 	if err != nil {
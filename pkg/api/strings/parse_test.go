@@ -0,0 +1,83 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strings_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+)
+
+func TestParseIntLenient(t *testing.T) {
+	for str, expected := range map[string]int{
+		"42":           42,
+		"+5":           5,
+		"-5":           -5,
+		" 42 ":         42,
+		"\t+7\n":       7,
+		"1,000":        1000,
+		"-12,345,678":  -12345678,
+		"+1,000":       1000,
+		"1_000":        1000,
+		"1_000_000":    1000000,
+		" -9_999 ":     -9999,
+		"999":          999,
+		"1000":         1000,
+		"0":            0,
+		"100,000,000 ": 100000000,
+	} {
+		val, ok := s.ParseIntLenient(str)
+		require.True(t, ok, str)
+		require.Equal(t, expected, val, str)
+	}
+
+	for _, str := range []string{"", " ", "1,00", "1,00,0", ",100", "100,", "1,,000", "1234,567", "1_00", "_100", "100_", "1,000_000", "1 000", "+-1", "++1", "1.5", "1,0a0", "4 2"} {
+		_, ok := s.ParseIntLenient(str)
+		require.False(t, ok, str)
+	}
+
+	for _, str := range []string{" 42 ", "1,000", "1_000"} {
+		_, ok := s.ParseInt(str)
+		require.False(t, ok, str)
+	}
+
+	val64, ok := s.ParseInt64Lenient(" 9_000_000_000 ")
+	require.True(t, ok)
+	require.Equal(t, int64(9000000000), val64)
+	_, ok = s.ParseInt64Lenient("9,000,00")
+	require.False(t, ok)
+}
+
+func TestParseFloatLenient(t *testing.T) {
+	for str, expected := range map[string]float64{"1.5": 1.5, " 1.5 ": 1.5, "+2": 2, "\t-0.25\n": -0.25} {
+		val, ok := s.ParseFloat64Lenient(str)
+		require.True(t, ok, str)
+		require.Equal(t, expected, val, str)
+	}
+	for _, str := range []string{"", " ", "1 .5", "1,000.5"} {
+		_, ok := s.ParseFloat64Lenient(str)
+		require.False(t, ok, str)
+	}
+	_, ok := s.ParseFloat64(" 1.5 ")
+	require.False(t, ok)
+
+	val32, ok := s.ParseFloat32Lenient(" 0.5 ")
+	require.True(t, ok)
+	require.Equal(t, float32(0.5), val32)
+}
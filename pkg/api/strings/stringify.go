@@ -24,6 +24,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 var emptyTime time.Time
@@ -291,16 +293,57 @@ func ObjFlat(val interface{}) string {
 	return strIndent(val, "", "", "", `"`)
 }
 
+// UserStrMaxLength is the number of characters after which UserStrTruncated() truncates values
+const UserStrMaxLength = 80
+
+// UserStr quotes strings like %q, and escapes control characters in other values (e.g. in nested strings), so that
+// user input can't break up log lines
 func UserStr(val interface{}) string {
-	return strIndent(val, "", "", "", `"`)
+	if str, ok := val.(string); ok {
+		return strconv.Quote(str)
+	}
+	return escapeControlChars(strIndent(val, "", "", "", `"`))
+}
+
+// UserStrTruncated is like UserStr(), but values longer than UserStrMaxLength characters are truncated, and their length
+// is noted (e.g. for a pasted blob)
+func UserStrTruncated(val interface{}) string {
+	if str, ok := val.(string); ok {
+		if length := utf8.RuneCountInString(str); length > UserStrMaxLength {
+			return fmt.Sprintf("%s... (%d characters)", strconv.Quote(string([]rune(str)[:UserStrMaxLength])), length)
+		}
+		return strconv.Quote(str)
+	}
+	userStr := UserStr(val)
+	if length := utf8.RuneCountInString(userStr); length > UserStrMaxLength {
+		return fmt.Sprintf("%s... (%d characters)", string([]rune(userStr)[:UserStrMaxLength]), length)
+	}
+	return userStr
+}
+
+func escapeControlChars(str string) string {
+	if strings.IndexFunc(str, unicode.IsControl) == -1 {
+		return str
+	}
+	var buf strings.Builder
+	for _, char := range str {
+		if unicode.IsControl(char) {
+			quoted := strconv.QuoteRune(char)
+			buf.WriteString(quoted[1 : len(quoted)-1])
+		} else {
+			buf.WriteRune(char)
+		}
+	}
+	return buf.String()
 }
 
 func UserStrValue(val reflect.Value) string {
 	return strIndentValue(val, "", "", "", `"`)
 }
 
+// UserStrStripped doesn't escape or quote strings (e.g. for raw values)
 func UserStrStripped(val interface{}) string {
-	return TrimPrefixAndSuffix(UserStr(val), `"`)
+	return TrimPrefixAndSuffix(strIndent(val, "", "", "", `"`), `"`)
 }
 
 func UserStrs(val interface{}) []string {
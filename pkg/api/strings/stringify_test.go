@@ -17,6 +17,7 @@ limitations under the License.
 package strings_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -257,3 +258,50 @@ func TestObj(t *testing.T) {
 	require.Equal(t, test3SubStrMultiline, s.Obj(&testInterface))
 
 }
+
+func TestUserStr(t *testing.T) {
+	require.Equal(t, `"abc"`, s.UserStr("abc"))
+	require.Equal(t, `"line1\nline2"`, s.UserStr("line1\nline2"))
+	require.Equal(t, `"\x1b[31mred\x1b[0m"`, s.UserStr("\x1b[31mred\x1b[0m"))
+	require.Equal(t, `"say \"hi\""`, s.UserStr(`say "hi"`))
+	require.Equal(t, `"héllo"`, s.UserStr("héllo"))
+	require.Equal(t, "5", s.UserStr(5))
+	require.Equal(t, "1.5", s.UserStr(1.5))
+	require.Equal(t, "true", s.UserStr(true))
+	require.Equal(t, `["a\nb", "c"]`, s.UserStr([]string{"a\nb", "c"}))
+	require.Equal(t, `"a\tb"`, s.UserStr(MyString("a\tb")))
+
+	require.Equal(t, "line1\nline2", s.UserStrStripped("line1\nline2"))
+}
+
+func TestUserStrTruncated(t *testing.T) {
+	require.Equal(t, `"abc"`, s.UserStrTruncated("abc"))
+	require.Equal(t, "12345", s.UserStrTruncated(12345))
+
+	exact := strings.Repeat("a", s.UserStrMaxLength)
+	require.Equal(t, `"`+exact+`"`, s.UserStrTruncated(exact))
+
+	blob := strings.Repeat("x", 10*1024)
+	require.Equal(t, `"`+strings.Repeat("x", s.UserStrMaxLength)+`"... (10240 characters)`, s.UserStrTruncated(blob))
+
+	blob = strings.Repeat("é\n", 5000)
+	truncated := s.UserStrTruncated(blob)
+	require.NotContains(t, truncated, "\n")
+	require.True(t, strings.HasSuffix(truncated, "... (10000 characters)"))
+
+	longList := make([]int, 100)
+	truncated = s.UserStrTruncated(longList)
+	require.Equal(t, s.UserStrMaxLength+len("... (300 characters)"), len(truncated))
+	require.True(t, strings.HasSuffix(truncated, "... (300 characters)"))
+
+	msg := s.ErrInvalidPrimitiveType(strings.Repeat("y", 10*1024), s.PrimTypeInt)
+	require.Less(t, len(msg), 200)
+	require.Contains(t, msg, "(10240 characters)")
+
+	msg = s.ErrInvalidStr("bad\nvalue", "good")
+	require.NotContains(t, msg, "\n")
+	require.Contains(t, msg, `"bad\nvalue"`)
+
+	msg = s.ErrMustBeLessThanOrEqualTo(1000000, 10)
+	require.Equal(t, "1000000 must be less than or equal to 10", msg)
+}
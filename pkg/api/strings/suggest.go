@@ -0,0 +1,88 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package strings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SuggestClosest returns the candidate which is closest to input (by edit distance, ignoring case),
+// or "" if none of them are close enough to be a likely typo
+func SuggestClosest(input string, candidates []string) string {
+	inputRunes := []rune(strings.ToLower(input))
+
+	suggestion := ""
+	bestDistance := -1
+	for _, candidate := range candidates {
+		candidateRunes := []rune(strings.ToLower(candidate))
+		distance := editDistance(inputRunes, candidateRunes)
+
+		maxDistance := len(candidateRunes) / 3
+		if maxDistance < 1 {
+			maxDistance = 1
+		}
+		if distance > maxDistance || distance >= len(inputRunes) {
+			continue
+		}
+		if bestDistance < 0 || distance < bestDistance {
+			suggestion = candidate
+			bestDistance = distance
+		}
+	}
+	return suggestion
+}
+
+// DidYouMean returns e.g. `; did you mean "us-east-1"?`, or "" if suggestion is ""
+func DidYouMean(suggestion string) string {
+	if suggestion == "" {
+		return ""
+	}
+	return fmt.Sprintf("; did you mean %s?", UserStr(suggestion))
+}
+
+// editDistance is the Levenshtein distance between a and b
+func editDistance(a []rune, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(first int, rest ...int) int {
+	min := first
+	for _, val := range rest {
+		if val < min {
+			min = val
+		}
+	}
+	return min
+}
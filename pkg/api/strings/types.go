@@ -37,11 +37,16 @@ var (
 	PrimTypeBool       PrimitiveType = "boolean"
 	PrimTypeBoolList   PrimitiveType = "boolean list"
 
+	PrimTypeTimestamp     PrimitiveType = "timestamp"
+	PrimTypeTimestampList PrimitiveType = "timestamp list"
+	PrimTypeDuration      PrimitiveType = "duration"
+
 	PrimTypeMap     PrimitiveType = "map"
 	PrimTypeMapList PrimitiveType = "list of maps"
 	PrimTypeList    PrimitiveType = "list"
 
 	PrimTypeStringToStringMap PrimitiveType = "map of strings to strings"
+	PrimTypeStringToIntMap    PrimitiveType = "map of strings to integers"
 )
 
 func (ts PrimitiveTypes) StringList() []string {
@@ -56,6 +61,15 @@ func EnvVar(envVarName string) string {
 	return fmt.Sprintf("environment variable \"%s\"", envVarName)
 }
 
+// FromSource returns e.g. ` (from environment variable "MAX_WORKERS")`
+func FromSource(source string) string {
+	return fmt.Sprintf(" (from %s)", source)
+}
+
+func SSMParameter(paramName string) string {
+	return fmt.Sprintf("ssm parameter \"%s\"", paramName)
+}
+
 func DataTypeStrsOr(dataTypes []interface{}) string {
 	dataTypeStrs := make([]string, len(dataTypes))
 	for i, dataType := range dataTypes {
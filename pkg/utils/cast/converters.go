@@ -0,0 +1,82 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cast
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Converter converts a value to a registered target type, returning false if it can't
+type Converter func(interface{}) (interface{}, bool)
+
+var (
+	convertersMutex sync.RWMutex
+	converters      = map[reflect.Type]Converter{}
+)
+
+var (
+	intType     = reflect.TypeOf(0)
+	float64Type = reflect.TypeOf(float64(0))
+	strType     = reflect.TypeOf("")
+)
+
+// RegisterConverter registers fn for converting values to target. Converters for int, float64, and string are consulted
+// by InterfaceToInt(), InterfaceToFloat64(), and InterfaceToStr() when a value can't be converted otherwise (e.g. to read a
+// Quantity struct as an int). Converters for other types (e.g. an ID newtype) are used by Convert(). fn must not call the
+// cast function which consults it with the same value. Only one converter can be registered for each target
+func RegisterConverter(target reflect.Type, fn Converter) error {
+	convertersMutex.Lock()
+	defer convertersMutex.Unlock()
+	if _, ok := converters[target]; ok {
+		return fmt.Errorf("a converter to %s is already registered", target)
+	}
+	converters[target] = fn
+	return nil
+}
+
+// ResetConvertersForTest removes all registered converters
+func ResetConvertersForTest() {
+	convertersMutex.Lock()
+	defer convertersMutex.Unlock()
+	converters = map[reflect.Type]Converter{}
+}
+
+// Convert returns in if it's assignable to target, or otherwise the result of the converter registered for target
+func Convert(in interface{}, target reflect.Type) (interface{}, bool) {
+	if in != nil && reflect.TypeOf(in).AssignableTo(target) {
+		return in, true
+	}
+	return convert(in, target)
+}
+
+// convert applies the converter registered for target, if any. Its result must be assignable to target
+func convert(in interface{}, target reflect.Type) (interface{}, bool) {
+	convertersMutex.RLock()
+	fn, ok := converters[target]
+	convertersMutex.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	converted, ok := fn(in)
+	if !ok || converted == nil || !reflect.TypeOf(converted).AssignableTo(target) {
+		return nil, false
+	}
+	return converted, true
+}
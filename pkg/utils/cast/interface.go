@@ -18,349 +18,221 @@ package cast
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
 )
 
-func InterfaceToInt8(in interface{}) (int8, bool) {
-	var ok bool
-	if in, ok = JSONNumberToInt(in); !ok {
-		return 0, false
-	}
+// MaxDerefDepth is the number of pointers which Deref() follows
+const MaxDerefDepth = 3
 
-	switch casted := in.(type) {
-	case int8:
-		return casted, true
-	case int16:
-		if val := int8(casted); int16(val) == casted {
-			return val, true
+// Deref follows up to MaxDerefDepth pointers, e.g. for values from a partially decoded struct. Nil pointers (including
+// an interface holding a typed nil) are converted to nil. The InterfaceTo*() functions call this on their input
+func Deref(in interface{}) interface{} {
+	for i := 0; i < MaxDerefDepth; i++ {
+		if in == nil {
+			return nil
 		}
-	case int32:
-		if val := int8(casted); int32(val) == casted {
-			return val, true
+		inVal := reflect.ValueOf(in)
+		if inVal.Kind() != reflect.Ptr {
+			return in
 		}
-	case int:
-		if val := int8(casted); int(val) == casted {
-			return val, true
-		}
-	case int64:
-		if val := int8(casted); int64(val) == casted {
-			return val, true
+		if inVal.IsNil() {
+			return nil
 		}
+		in = inVal.Elem().Interface()
 	}
-	return 0, false
+	return in
 }
 
-func InterfaceToInt8Downcast(in interface{}) (int8, bool) {
-	var ok bool
-	if in, ok = JSONNumberToIntOrFloat(in); !ok {
-		return 0, false
-	}
+func InterfaceToInt8(in interface{}) (int8, bool) {
+	return To[int8](in)
+}
 
-	switch casted := in.(type) {
-	case int8:
-		return casted, true
-	case int16:
-		if val := int8(casted); int16(val) == casted {
-			return val, true
-		}
-	case int32:
-		if val := int8(casted); int32(val) == casted {
-			return val, true
-		}
-	case int:
-		if val := int8(casted); int(val) == casted {
-			return val, true
-		}
-	case int64:
-		if val := int8(casted); int64(val) == casted {
-			return val, true
-		}
-	case float32:
-		if val := int8(casted); float32(val) == casted {
-			return val, true
-		}
-	case float64:
-		if val := int8(casted); float64(val) == casted {
-			return val, true
-		}
-	}
-	return 0, false
+func InterfaceToInt8Downcast(in interface{}) (int8, bool) {
+	return ToDowncast[int8](in)
 }
 
 func InterfaceToInt16(in interface{}) (int16, bool) {
-	var ok bool
-	if in, ok = JSONNumberToInt(in); !ok {
-		return 0, false
-	}
-
-	switch casted := in.(type) {
-	case int8:
-		return int16(casted), true
-	case int16:
-		return casted, true
-	case int32:
-		if val := int16(casted); int32(val) == casted {
-			return val, true
-		}
-	case int:
-		if val := int16(casted); int(val) == casted {
-			return val, true
-		}
-	case int64:
-		if val := int16(casted); int64(val) == casted {
-			return val, true
-		}
-	}
-	return 0, false
+	return To[int16](in)
 }
 
 func InterfaceToInt16Downcast(in interface{}) (int16, bool) {
-	var ok bool
-	if in, ok = JSONNumberToIntOrFloat(in); !ok {
-		return 0, false
-	}
-
-	switch casted := in.(type) {
-	case int8:
-		return int16(casted), true
-	case int16:
-		return casted, true
-	case int32:
-		if val := int16(casted); int32(val) == casted {
-			return val, true
-		}
-	case int:
-		if val := int16(casted); int(val) == casted {
-			return val, true
-		}
-	case int64:
-		if val := int16(casted); int64(val) == casted {
-			return val, true
-		}
-	case float32:
-		if val := int16(casted); float32(val) == casted {
-			return val, true
-		}
-	case float64:
-		if val := int16(casted); float64(val) == casted {
-			return val, true
-		}
-	}
-	return 0, false
+	return ToDowncast[int16](in)
 }
 
 func InterfaceToInt32(in interface{}) (int32, bool) {
-	var ok bool
-	if in, ok = JSONNumberToInt(in); !ok {
-		return 0, false
-	}
-
-	switch casted := in.(type) {
-	case int8:
-		return int32(casted), true
-	case int16:
-		return int32(casted), true
-	case int32:
-		return casted, true
-	case int:
-		if val := int32(casted); int(val) == casted {
-			return val, true
-		}
-	case int64:
-		if val := int32(casted); int64(val) == casted {
-			return val, true
-		}
-	}
-	return 0, false
+	return To[int32](in)
 }
 
 func InterfaceToInt32Downcast(in interface{}) (int32, bool) {
-	var ok bool
-	if in, ok = JSONNumberToIntOrFloat(in); !ok {
-		return 0, false
-	}
+	return ToDowncast[int32](in)
+}
 
-	switch casted := in.(type) {
-	case int8:
-		return int32(casted), true
-	case int16:
-		return int32(casted), true
-	case int32:
+func InterfaceToInt(in interface{}) (int, bool) {
+	if casted, ok := To[int](in); ok {
 		return casted, true
-	case int:
-		if val := int32(casted); int(val) == casted {
-			return val, true
-		}
-	case int64:
-		if val := int32(casted); int64(val) == casted {
-			return val, true
-		}
-	case float32:
-		if val := int32(casted); float32(val) == casted {
-			return val, true
-		}
-	case float64:
-		if val := int32(casted); float64(val) == casted {
-			return val, true
-		}
+	}
+	if converted, ok := convert(Deref(in), intType); ok {
+		return converted.(int), true
 	}
 	return 0, false
 }
 
-func InterfaceToInt(in interface{}) (int, bool) {
-	var ok bool
-	if in, ok = JSONNumberToInt(in); !ok {
-		return 0, false
-	}
+func InterfaceToIntDowncast(in interface{}) (int, bool) {
+	return ToDowncast[int](in)
+}
 
-	switch casted := in.(type) {
-	case int8:
-		return int(casted), true
-	case int16:
-		return int(casted), true
-	case int32:
-		return int(casted), true
-	case int:
-		return casted, true
-	case int64:
-		if val := int(casted); int64(val) == casted {
-			return val, true
-		}
+func InterfaceToInt64(in interface{}) (int64, bool) {
+	return To[int64](in)
+}
+
+func InterfaceToInt64Downcast(in interface{}) (int64, bool) {
+	return ToDowncast[int64](in)
+}
+
+// InterfaceToUint64 converts any integer type (or a json.Number) which isn't negative
+func InterfaceToUint64(in interface{}) (uint64, bool) {
+	return To[uint64](in)
+}
+
+// InterfaceToUint64Downcast is like InterfaceToUint64(), but also converts floats with integral values
+func InterfaceToUint64Downcast(in interface{}) (uint64, bool) {
+	return ToDowncast[uint64](in)
+}
+
+func InterfaceToUint(in interface{}) (uint, bool) {
+	return To[uint](in)
+}
+
+func InterfaceToUintDowncast(in interface{}) (uint, bool) {
+	return ToDowncast[uint](in)
+}
+
+// floatToInt64 returns false if f isn't an integer, or is out of range (in which case conversion is implementation-specific)
+func floatToInt64(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= -math.MinInt64 {
+		return 0, false
 	}
-	return 0, false
+	return int64(f), true
 }
 
-func InterfaceToIntDowncast(in interface{}) (int, bool) {
-	var ok bool
-	if in, ok = JSONNumberToIntOrFloat(in); !ok {
+func floatToUint64(f float64) (uint64, bool) {
+	if f != math.Trunc(f) || f < 0 || f >= 2*-math.MinInt64 {
 		return 0, false
 	}
+	return uint64(f), true
+}
 
-	switch casted := in.(type) {
-	case int8:
-		return int(casted), true
-	case int16:
-		return int(casted), true
-	case int32:
-		return int(casted), true
-	case int:
+// Float32Overflow is the smallest magnitude which rounds to infinity when converted to a float32
+var Float32Overflow = math.Ldexp(1, 128) - math.Ldexp(1, 103)
+
+// This will convert any int or float type
+func InterfaceToFloat32(in interface{}) (float32, bool) {
+	return To[float32](in)
+}
+
+// This will convert any int or float type
+func InterfaceToFloat64(in interface{}) (float64, bool) {
+	if casted, ok := To[float64](in); ok {
 		return casted, true
-	case int64:
-		if val := int(casted); int64(val) == casted {
-			return val, true
-		}
-	case float32:
-		if val := int(casted); float32(val) == casted {
-			return val, true
-		}
-	case float64:
-		if val := int(casted); float64(val) == casted {
-			return val, true
-		}
+	}
+	if converted, ok := convert(Deref(in), float64Type); ok {
+		return converted.(float64), true
 	}
 	return 0, false
 }
 
-func InterfaceToInt64(in interface{}) (int64, bool) {
-	var ok bool
-	if in, ok = JSONNumberToInt(in); !ok {
-		return 0, false
+// InterfaceToStr converts strings, and values which a registered converter can convert to a string
+func InterfaceToStr(in interface{}) (string, bool) {
+	in = Deref(in)
+	if casted, ok := in.(string); ok {
+		return casted, true
+	}
+	if converted, ok := convert(in, strType); ok {
+		return converted.(string), true
 	}
+	return "", false
+}
 
+// InterfaceToBoolExtended converts bools, the integers 1 and 0, and the strings accepted by strconv.ParseBool() (e.g. "true", "TRUE", "1", or "f")
+func InterfaceToBoolExtended(in interface{}) (bool, bool) {
+	in = Deref(in)
 	switch casted := in.(type) {
-	case int8:
-		return int64(casted), true
-	case int16:
-		return int64(casted), true
-	case int32:
-		return int64(casted), true
-	case int:
-		return int64(casted), true
-	case int64:
+	case bool:
 		return casted, true
+	case string:
+		val, err := strconv.ParseBool(strings.TrimSpace(casted))
+		return val, err == nil
 	}
-	return 0, false
-}
-
-func InterfaceToInt64Downcast(in interface{}) (int64, bool) {
-	var ok bool
-	if in, ok = JSONNumberToIntOrFloat(in); !ok {
-		return 0, false
+	casted, ok := InterfaceToInt64(in)
+	if !ok {
+		return false, false
+	}
+	switch casted {
+	case 1:
+		return true, true
+	case 0:
+		return false, true
 	}
+	return false, false
+}
 
+// InterfaceToDuration converts time.Durations, strings accepted by time.ParseDuration() (e.g. "45s"), and integers, which
+// are multiplied by bareIntUnit (e.g. time.Second). If bareIntUnit is 0, integers (including integer strings) are rejected
+func InterfaceToDuration(in interface{}, bareIntUnit time.Duration) (time.Duration, bool) {
+	in = Deref(in)
 	switch casted := in.(type) {
-	case int8:
-		return int64(casted), true
-	case int16:
-		return int64(casted), true
-	case int32:
-		return int64(casted), true
-	case int:
-		return int64(casted), true
-	case int64:
+	case time.Duration:
 		return casted, true
-	case float32:
-		if val := int64(casted); float32(val) == casted {
-			return val, true
+	case string:
+		trimmed := strings.TrimSpace(casted)
+		if parsed, err := time.ParseDuration(trimmed); err == nil {
+			return parsed, true
 		}
-	case float64:
-		if val := int64(casted); float64(val) == casted {
-			return val, true
+		if bareIntUnit == 0 {
+			return 0, false
 		}
+		in = json.Number(trimmed)
 	}
-	return 0, false
-}
 
-// This will convert any int or float type
-func InterfaceToFloat32(in interface{}) (float32, bool) {
-	var ok bool
-	if in, ok = JSONNumberToIntOrFloat(in); !ok {
+	if bareIntUnit == 0 {
 		return 0, false
 	}
-
-	switch casted := in.(type) {
-	case int8:
-		return float32(casted), true
-	case int16:
-		return float32(casted), true
-	case int32:
-		return float32(casted), true
-	case int:
-		return float32(casted), true
-	case int64:
-		return float32(casted), true
-	case float32:
-		return casted, true
-	case float64:
-		return float32(casted), true
-	}
-	return 0, false
-}
-
-// This will convert any int or float type
-func InterfaceToFloat64(in interface{}) (float64, bool) {
-	var ok bool
-	if in, ok = JSONNumberToIntOrFloat(in); !ok {
+	casted, ok := InterfaceToInt64(in)
+	if !ok {
 		return 0, false
 	}
+	val := time.Duration(casted) * bareIntUnit
+	if val/bareIntUnit != time.Duration(casted) {
+		return 0, false // overflow
+	}
+	return val, true
+}
 
+// InterfaceToTime converts time.Times (e.g. YAML timestamps), and strings in any of the layouts. loc is used for layouts
+// without a time zone, and defaults to UTC
+func InterfaceToTime(in interface{}, layouts []string, loc *time.Location) (time.Time, bool) {
+	in = Deref(in)
 	switch casted := in.(type) {
-	case int8:
-		return float64(casted), true
-	case int16:
-		return float64(casted), true
-	case int32:
-		return float64(casted), true
-	case int:
-		return float64(casted), true
-	case int64:
-		return float64(casted), true
-	case float32:
-		return float64(casted), true
-	case float64:
+	case time.Time:
 		return casted, true
+	case string:
+		if loc == nil {
+			loc = time.UTC
+		}
+		for _, layout := range layouts {
+			if parsed, err := time.ParseInLocation(layout, casted, loc); err == nil {
+				return parsed, true
+			}
+		}
 	}
-	return 0, false
+	return time.Time{}, false
 }
 
 func JSONNumberToInt(in interface{}) (interface{}, bool) {
@@ -375,6 +247,41 @@ func JSONNumberToInt(in interface{}) (interface{}, bool) {
 	return nil, false
 }
 
+// JSONNumberToIntDowncast is like JSONNumberToInt(), but also converts numbers written as floats
+// (e.g. "1e3" or "2.0") if their values are integral. The conversion is exact, so numbers which can't
+// be represented as a float64 (e.g. "9007199254740993.0") aren't rounded
+func JSONNumberToIntDowncast(in interface{}) (interface{}, bool) {
+	number, ok := in.(json.Number)
+	if !ok {
+		return in, true
+	}
+	inInt, err := number.Int64()
+	if err == nil {
+		return inInt, true
+	}
+	bigInt, ok := jsonNumberToBigInt(number)
+	if !ok || !bigInt.IsInt64() {
+		return nil, false
+	}
+	return bigInt.Int64(), true
+}
+
+// jsonNumberToBigInt returns false if the number isn't integral
+func jsonNumberToBigInt(number json.Number) (*big.Int, bool) {
+	str := number.String()
+	// enough precision to represent every digit exactly, so a fractional part can't be rounded away
+	bigFloat, _, err := big.ParseFloat(str, 10, uint(len(str))*4+64, big.ToNearestEven)
+	if err != nil || bigFloat.IsInf() || !bigFloat.IsInt() {
+		return nil, false
+	}
+	// very small numbers underflow to zero
+	if bigFloat.Sign() == 0 && strings.ContainsAny(strings.SplitN(strings.ToLower(str), "e", 2)[0], "123456789") {
+		return nil, false
+	}
+	bigInt, _ := bigFloat.Int(nil)
+	return bigInt, true
+}
+
 func JSONNumberToIntOrFloat(in interface{}) (interface{}, bool) {
 	number, ok := in.(json.Number)
 	if !ok {
@@ -392,6 +299,7 @@ func JSONNumberToIntOrFloat(in interface{}) (interface{}, bool) {
 }
 
 func InterfaceToInterfaceSlice(in interface{}) ([]interface{}, bool) {
+	in = Deref(in)
 	if in == nil {
 		return nil, true
 	}
@@ -417,6 +325,7 @@ func InterfaceToInterfaceSlice(in interface{}) ([]interface{}, bool) {
 }
 
 func InterfaceToIntSlice(in interface{}) ([]int, bool) {
+	in = Deref(in)
 	if in == nil {
 		return nil, true
 	}
@@ -441,7 +350,58 @@ func InterfaceToIntSlice(in interface{}) ([]int, bool) {
 	return out, true
 }
 
+// InterfaceToIntSliceDowncast is like InterfaceToIntSlice(), but also converts floats with integral values
+func InterfaceToIntSliceDowncast(in interface{}) ([]int, bool) {
+	in = Deref(in)
+	if in == nil {
+		return nil, true
+	}
+
+	inSlice, ok := InterfaceToInterfaceSlice(in)
+	if !ok {
+		return nil, false
+	}
+	out := make([]int, len(inSlice))
+
+	for i, elem := range inSlice {
+		casted, ok := InterfaceToIntDowncast(elem)
+		if !ok {
+			return nil, false
+		}
+		out[i] = casted
+	}
+	return out, true
+}
+
+// InterfaceToIntSliceWithInvalidIndex is like InterfaceToIntSlice(), but if an element can't be converted, its index is returned
+// (otherwise the index is -1). If nilAsEmpty is true, nil is converted to an empty slice
+func InterfaceToIntSliceWithInvalidIndex(in interface{}, nilAsEmpty bool) ([]int, int, bool) {
+	in = Deref(in)
+	if in == nil {
+		if nilAsEmpty {
+			return []int{}, -1, true
+		}
+		return nil, -1, true
+	}
+
+	inSlice, ok := InterfaceToInterfaceSlice(in)
+	if !ok {
+		return nil, -1, false
+	}
+	out := make([]int, len(inSlice))
+
+	for i, elem := range inSlice {
+		casted, ok := InterfaceToInt(elem)
+		if !ok {
+			return nil, i, false
+		}
+		out[i] = casted
+	}
+	return out, -1, true
+}
+
 func InterfaceToInt32Slice(in interface{}) ([]int32, bool) {
+	in = Deref(in)
 	if in == nil {
 		return nil, true
 	}
@@ -467,6 +427,7 @@ func InterfaceToInt32Slice(in interface{}) ([]int32, bool) {
 }
 
 func InterfaceToInt64Slice(in interface{}) ([]int64, bool) {
+	in = Deref(in)
 	if in == nil {
 		return nil, true
 	}
@@ -492,6 +453,7 @@ func InterfaceToInt64Slice(in interface{}) ([]int64, bool) {
 }
 
 func InterfaceToFloat32Slice(in interface{}) ([]float32, bool) {
+	in = Deref(in)
 	if in == nil {
 		return nil, true
 	}
@@ -517,6 +479,7 @@ func InterfaceToFloat32Slice(in interface{}) ([]float32, bool) {
 }
 
 func InterfaceToFloat64Slice(in interface{}) ([]float64, bool) {
+	in = Deref(in)
 	if in == nil {
 		return nil, true
 	}
@@ -541,7 +504,35 @@ func InterfaceToFloat64Slice(in interface{}) ([]float64, bool) {
 	return out, true
 }
 
+// InterfaceToFloat64SliceWithInvalidIndex is like InterfaceToFloat64Slice(), but if an element can't be converted, its index
+// is returned (otherwise the index is -1). If nilAsEmpty is true, nil is converted to an empty slice
+func InterfaceToFloat64SliceWithInvalidIndex(in interface{}, nilAsEmpty bool) ([]float64, int, bool) {
+	in = Deref(in)
+	if in == nil {
+		if nilAsEmpty {
+			return []float64{}, -1, true
+		}
+		return nil, -1, true
+	}
+
+	inSlice, ok := InterfaceToInterfaceSlice(in)
+	if !ok {
+		return nil, -1, false
+	}
+	out := make([]float64, len(inSlice))
+
+	for i, elem := range inSlice {
+		casted, ok := InterfaceToFloat64(elem)
+		if !ok {
+			return nil, i, false
+		}
+		out[i] = casted
+	}
+	return out, -1, true
+}
+
 func InterfaceToStrSlice(in interface{}) ([]string, bool) {
+	in = Deref(in)
 	if in == nil {
 		return nil, true
 	}
@@ -558,7 +549,7 @@ func InterfaceToStrSlice(in interface{}) ([]string, bool) {
 	out := make([]string, len(inSlice))
 
 	for i, elem := range inSlice {
-		casted, ok := elem.(string)
+		casted, ok := Deref(elem).(string)
 		if !ok {
 			return nil, false
 		}
@@ -567,7 +558,35 @@ func InterfaceToStrSlice(in interface{}) ([]string, bool) {
 	return out, true
 }
 
+// InterfaceToStrSliceWithInvalidIndex is like InterfaceToStrSlice(), but if an element isn't a string, its index is returned
+// (otherwise the index is -1). If nilAsEmpty is true, nil is converted to an empty slice
+func InterfaceToStrSliceWithInvalidIndex(in interface{}, nilAsEmpty bool) ([]string, int, bool) {
+	in = Deref(in)
+	if in == nil {
+		if nilAsEmpty {
+			return []string{}, -1, true
+		}
+		return nil, -1, true
+	}
+
+	inSlice, ok := InterfaceToInterfaceSlice(in)
+	if !ok {
+		return nil, -1, false
+	}
+	out := make([]string, len(inSlice))
+
+	for i, elem := range inSlice {
+		casted, ok := Deref(elem).(string)
+		if !ok {
+			return nil, i, false
+		}
+		out[i] = casted
+	}
+	return out, -1, true
+}
+
 func InterfaceToBoolSlice(in interface{}) ([]bool, bool) {
+	in = Deref(in)
 	if in == nil {
 		return nil, true
 	}
@@ -584,7 +603,7 @@ func InterfaceToBoolSlice(in interface{}) ([]bool, bool) {
 	out := make([]bool, len(inSlice))
 
 	for i, elem := range inSlice {
-		casted, ok := elem.(bool)
+		casted, ok := Deref(elem).(bool)
 		if !ok {
 			return nil, false
 		}
@@ -594,6 +613,7 @@ func InterfaceToBoolSlice(in interface{}) ([]bool, bool) {
 }
 
 func InterfaceToStrInterfaceMapSlice(in interface{}) ([]map[string]interface{}, bool) {
+	in = Deref(in)
 	if in == nil {
 		return nil, true
 	}
@@ -620,6 +640,7 @@ func InterfaceToStrInterfaceMapSlice(in interface{}) ([]map[string]interface{},
 }
 
 func InterfaceToInterfaceInterfaceMap(in interface{}) (map[interface{}]interface{}, bool) {
+	in = Deref(in)
 	if in == nil {
 		return nil, true
 	}
@@ -645,6 +666,7 @@ func InterfaceToInterfaceInterfaceMap(in interface{}) (map[interface{}]interface
 }
 
 func InterfaceToStrInterfaceMap(in interface{}) (map[string]interface{}, bool) {
+	in = Deref(in)
 	if in == nil {
 		return nil, true
 	}
@@ -670,7 +692,93 @@ func InterfaceToStrInterfaceMap(in interface{}) (map[string]interface{}, bool) {
 	return out, true
 }
 
+// InterfaceToStrInterfaceMapRecursive is like InterfaceToStrInterfaceMap(), but also converts all nested maps (including
+// those in lists), e.g. the map[interface{}]interface{} maps from YAML. Scalar keys are converted to strings
+func InterfaceToStrInterfaceMapRecursive(in interface{}) (map[string]interface{}, bool) {
+	out, err := InterfaceToStrInterfaceMapRecursiveErr(in)
+	return out, err == nil
+}
+
+var ErrNotMap = errors.New("not a map")
+
+// InvalidMapKeyError is returned by InterfaceToStrInterfaceMapRecursiveErr() for keys which aren't scalars
+type InvalidMapKeyError struct {
+	Path string // the path of the map which contains the key, e.g. "pools[1].labels"
+	Key  interface{}
+}
+
+func (err *InvalidMapKeyError) Error() string {
+	if err.Path == "" {
+		return fmt.Sprintf("map keys must be strings, numbers, or booleans (got %v)", err.Key)
+	}
+	return fmt.Sprintf("%s: map keys must be strings, numbers, or booleans (got %v)", err.Path, err.Key)
+}
+
+// InterfaceToStrInterfaceMapRecursiveErr is like InterfaceToStrInterfaceMapRecursive(), but returns an error: ErrNotMap if in
+// isn't a map, or an *InvalidMapKeyError if a key isn't a scalar
+func InterfaceToStrInterfaceMapRecursiveErr(in interface{}) (map[string]interface{}, error) {
+	in = Deref(in)
+	if in != nil && reflect.TypeOf(in).Kind() != reflect.Map {
+		return nil, ErrNotMap
+	}
+	out, err := strInterfaceMapRecursive(in, "")
+	if err != nil {
+		return nil, err
+	}
+	casted, _ := out.(map[string]interface{})
+	return casted, nil
+}
+
+func strInterfaceMapRecursive(in interface{}, path string) (interface{}, error) {
+	if in == nil {
+		return nil, nil
+	}
+
+	switch reflect.TypeOf(in).Kind() {
+	case reflect.Map:
+		inMap, _ := InterfaceToInterfaceInterfaceMap(in)
+		if inMap == nil {
+			return map[string]interface{}(nil), nil
+		}
+		out := make(map[string]interface{}, len(inMap))
+		for key, value := range inMap {
+			if !IsScalarType(key) {
+				return nil, &InvalidMapKeyError{Path: path, Key: key}
+			}
+			keyStr := fmt.Sprint(key)
+			childPath := keyStr
+			if path != "" {
+				childPath = path + "." + keyStr
+			}
+			casted, err := strInterfaceMapRecursive(value, childPath)
+			if err != nil {
+				return nil, err
+			}
+			out[keyStr] = casted
+		}
+		return out, nil
+
+	case reflect.Slice:
+		inSlice, ok := in.([]interface{})
+		if !ok {
+			return in, nil // only lists from YAML and JSON are converted
+		}
+		out := make([]interface{}, len(inSlice))
+		for i, elem := range inSlice {
+			casted, err := strInterfaceMapRecursive(elem, path+"["+strconv.Itoa(i)+"]")
+			if err != nil {
+				return nil, err
+			}
+			out[i] = casted
+		}
+		return out, nil
+	}
+
+	return in, nil
+}
+
 func InterfaceToStrStrMap(in interface{}) (map[string]string, bool) {
+	in = Deref(in)
 	if in == nil {
 		return nil, true
 	}
@@ -691,7 +799,38 @@ func InterfaceToStrStrMap(in interface{}) (map[string]string, bool) {
 		if !ok {
 			return nil, false
 		}
-		castedVal, ok := value.(string)
+		castedVal, ok := Deref(value).(string)
+		if !ok {
+			return nil, false
+		}
+		out[castedKey] = castedVal
+	}
+	return out, true
+}
+
+func InterfaceToStrIntMap(in interface{}) (map[string]int, bool) {
+	in = Deref(in)
+	if in == nil {
+		return nil, true
+	}
+
+	if intMap, ok := in.(map[string]int); ok {
+		return intMap, true
+	}
+
+	inMap, ok := InterfaceToInterfaceInterfaceMap(in)
+	if !ok {
+		return nil, false
+	}
+
+	out := map[string]int{}
+
+	for key, value := range inMap {
+		castedKey, ok := key.(string)
+		if !ok {
+			return nil, false
+		}
+		castedVal, ok := InterfaceToInt(value)
 		if !ok {
 			return nil, false
 		}
@@ -17,9 +17,15 @@ limitations under the License.
 package cast_test
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
 
 	"github.com/cortexlabs/cortex/pkg/utils/cast"
 )
@@ -62,6 +68,36 @@ func TestInterfaceToFloat64(t *testing.T) {
 	require.False(t, ok)
 }
 
+func TestInterfaceToFloat32(t *testing.T) {
+	var out float32
+	var ok bool
+
+	out, ok = cast.InterfaceToFloat32(float64(math.MaxFloat32))
+	require.True(t, ok)
+	require.Equal(t, float32(math.MaxFloat32), out)
+
+	out, ok = cast.InterfaceToFloat32(float64(-math.MaxFloat32))
+	require.True(t, ok)
+	require.Equal(t, float32(-math.MaxFloat32), out)
+
+	out, ok = cast.InterfaceToFloat32(math.Nextafter(cast.Float32Overflow, 0))
+	require.True(t, ok)
+	require.Equal(t, float32(math.MaxFloat32), out)
+
+	_, ok = cast.InterfaceToFloat32(cast.Float32Overflow)
+	require.False(t, ok)
+
+	_, ok = cast.InterfaceToFloat32(float64(3.5e38))
+	require.False(t, ok)
+
+	_, ok = cast.InterfaceToFloat32(float64(-3.5e38))
+	require.False(t, ok)
+
+	out, ok = cast.InterfaceToFloat32(math.Inf(1))
+	require.True(t, ok)
+	require.True(t, math.IsInf(float64(out), 1))
+}
+
 func TestInterfaceToIntDowncast(t *testing.T) {
 	var out int
 	var ok bool
@@ -111,6 +147,172 @@ func TestInterfaceToInt(t *testing.T) {
 	require.False(t, ok)
 }
 
+func TestInterfaceToInt64Boundaries(t *testing.T) {
+	for _, tc := range []struct {
+		in       interface{}
+		expected int64
+		ok       bool
+		okFloat  bool // whether InterfaceToInt64Downcast succeeds when InterfaceToInt64 doesn't
+	}{
+		{in: int64(math.MaxInt64), expected: math.MaxInt64, ok: true},
+		{in: int64(math.MinInt64), expected: math.MinInt64, ok: true},
+		{in: uint64(math.MaxInt64), expected: math.MaxInt64, ok: true},
+		{in: uint64(math.MaxInt64 + 1)},
+		{in: uint(math.MaxUint64)},
+		{in: uint8(255), expected: 255, ok: true},
+		{in: json.Number("9223372036854775807"), expected: math.MaxInt64, ok: true},
+		{in: json.Number("9223372036854775808")},
+		{in: float64(math.MinInt64), expected: math.MinInt64, okFloat: true},
+		{in: float64(1 << 62), expected: 1 << 62, okFloat: true},
+		{in: float64(math.MaxInt64)}, // rounds up to 2^63
+		{in: float64(1e19)},
+		{in: float64(-1e19)},
+		{in: float32(1 << 40), expected: 1 << 40, okFloat: true},
+		{in: math.Inf(1)},
+		{in: math.NaN()},
+		{in: float64(2.5)},
+	} {
+		out, ok := cast.InterfaceToInt64(tc.in)
+		require.Equal(t, tc.ok, ok, "%T %v", tc.in, tc.in)
+		if ok {
+			require.Equal(t, tc.expected, out)
+		}
+
+		out, ok = cast.InterfaceToInt64Downcast(tc.in)
+		require.Equal(t, tc.ok || tc.okFloat, ok, "%T %v", tc.in, tc.in)
+		if ok {
+			require.Equal(t, tc.expected, out)
+		}
+	}
+}
+
+func TestInterfaceToUint64(t *testing.T) {
+	for _, tc := range []struct {
+		in       interface{}
+		expected uint64
+		ok       bool
+		okFloat  bool // whether InterfaceToUint64Downcast succeeds when InterfaceToUint64 doesn't
+	}{
+		{in: uint64(math.MaxUint64), expected: math.MaxUint64, ok: true},
+		{in: int64(math.MaxInt64), expected: math.MaxInt64, ok: true},
+		{in: int(0), expected: 0, ok: true},
+		{in: int(-1)},
+		{in: int64(math.MinInt64)},
+		{in: json.Number("18446744073709551615"), expected: math.MaxUint64, ok: true},
+		{in: json.Number("18446744073709551616")},
+		{in: json.Number("-1")},
+		{in: json.Number("1e19"), expected: 1e19, okFloat: true},
+		{in: float64(1e19), expected: 1e19, okFloat: true},
+		{in: float64(1 << 64)},
+		{in: float64(-1)},
+		{in: float32(2.5)},
+		{in: "1"},
+	} {
+		out, ok := cast.InterfaceToUint64(tc.in)
+		require.Equal(t, tc.ok, ok, "%T %v", tc.in, tc.in)
+		if ok {
+			require.Equal(t, tc.expected, out)
+		}
+
+		out, ok = cast.InterfaceToUint64Downcast(tc.in)
+		require.Equal(t, tc.ok || tc.okFloat, ok, "%T %v", tc.in, tc.in)
+		if ok {
+			require.Equal(t, tc.expected, out)
+		}
+	}
+
+	out, ok := cast.InterfaceToUint(int32(7))
+	require.True(t, ok)
+	require.Equal(t, uint(7), out)
+
+	_, ok = cast.InterfaceToUint(int32(-7))
+	require.False(t, ok)
+}
+
+func TestSliceWithInvalidIndex(t *testing.T) {
+	ints, index, ok := cast.InterfaceToIntSliceWithInvalidIndex([]interface{}{1, int64(2), 3}, false)
+	require.True(t, ok)
+	require.Equal(t, -1, index)
+	require.Equal(t, []int{1, 2, 3}, ints)
+
+	_, index, ok = cast.InterfaceToIntSliceWithInvalidIndex([]interface{}{1, 2, 3, "4"}, false)
+	require.False(t, ok)
+	require.Equal(t, 3, index)
+
+	_, index, ok = cast.InterfaceToIntSliceWithInvalidIndex(1, false)
+	require.False(t, ok)
+	require.Equal(t, -1, index)
+
+	ints, index, ok = cast.InterfaceToIntSliceWithInvalidIndex(nil, false)
+	require.True(t, ok)
+	require.Nil(t, ints)
+	ints, index, ok = cast.InterfaceToIntSliceWithInvalidIndex(nil, true)
+	require.True(t, ok)
+	require.Equal(t, []int{}, ints)
+
+	floats, index, ok := cast.InterfaceToFloat64SliceWithInvalidIndex([]int{1, 2}, false)
+	require.True(t, ok)
+	require.Equal(t, []float64{1, 2}, floats)
+	_, index, ok = cast.InterfaceToFloat64SliceWithInvalidIndex([]interface{}{1.5, true}, false)
+	require.False(t, ok)
+	require.Equal(t, 1, index)
+
+	strs, index, ok := cast.InterfaceToStrSliceWithInvalidIndex(nil, true)
+	require.True(t, ok)
+	require.Equal(t, []string{}, strs)
+	_, index, ok = cast.InterfaceToStrSliceWithInvalidIndex([]interface{}{"a", 2}, false)
+	require.False(t, ok)
+	require.Equal(t, 1, index)
+
+	ints, ok = cast.InterfaceToIntSliceDowncast([]interface{}{1, 2.0, float32(3)})
+	require.True(t, ok)
+	require.Equal(t, []int{1, 2, 3}, ints)
+	_, ok = cast.InterfaceToIntSliceDowncast([]interface{}{1, 2.5})
+	require.False(t, ok)
+	_, ok = cast.InterfaceToIntSlice([]interface{}{1, 2.0})
+	require.False(t, ok)
+}
+
+func TestInterfaceToStrInterfaceMapRecursive(t *testing.T) {
+	in := map[interface{}]interface{}{
+		"name": "a",
+		1:      true,
+		"pools": []interface{}{
+			map[interface{}]interface{}{"size": 2, true: "yes"},
+		},
+		"labels": map[interface{}]interface{}{"team": map[interface{}]interface{}{"id": 3}},
+	}
+
+	out, ok := cast.InterfaceToStrInterfaceMapRecursive(in)
+	require.True(t, ok)
+	require.Equal(t, map[string]interface{}{
+		"name": "a",
+		"1":    true,
+		"pools": []interface{}{
+			map[string]interface{}{"size": 2, "true": "yes"},
+		},
+		"labels": map[string]interface{}{"team": map[string]interface{}{"id": 3}},
+	}, out)
+
+	in["pools"].([]interface{})[0].(map[interface{}]interface{})["labels"] = map[interface{}]interface{}{
+		"ok":         1,
+		[2]int{1, 2}: "bad",
+	}
+	_, ok = cast.InterfaceToStrInterfaceMapRecursive(in)
+	require.False(t, ok)
+	_, err := cast.InterfaceToStrInterfaceMapRecursiveErr(in)
+	var keyErr *cast.InvalidMapKeyError
+	require.True(t, errors.As(err, &keyErr))
+	require.Equal(t, "pools[0].labels", keyErr.Path)
+
+	_, err = cast.InterfaceToStrInterfaceMapRecursiveErr([]interface{}{1})
+	require.Equal(t, cast.ErrNotMap, err)
+
+	out, ok = cast.InterfaceToStrInterfaceMapRecursive(nil)
+	require.True(t, ok)
+	require.Nil(t, out)
+}
+
 func TestInterfaceToInt8Downcast(t *testing.T) {
 	var out int8
 	var ok bool
@@ -187,3 +389,299 @@ func TestInterfaceToInterfaceInterfaceMap(t *testing.T) {
 	require.True(t, ok)
 	require.Equal(t, expected, casted)
 }
+
+func TestJSONNumbers(t *testing.T) {
+	for _, tc := range []struct {
+		in      json.Number
+		int64   int64
+		ok      bool
+		okFloat bool // whether InterfaceToInt64Downcast succeeds when InterfaceToInt64 doesn't
+	}{
+		{in: "9007199254740993", int64: 9007199254740993, ok: true},
+		{in: "-9007199254740993", int64: -9007199254740993, ok: true},
+		{in: "9007199254740993.0", int64: 9007199254740993, okFloat: true},
+		{in: "9007199254740993e0", int64: 9007199254740993, okFloat: true},
+		{in: "90071992547409930e-1", int64: 9007199254740993, okFloat: true},
+		{in: "9007199254740993.5"},
+		{in: "9007199254740992.000000000000000000000001"},
+		{in: "1e3", int64: 1000, okFloat: true},
+		{in: "-2.0", int64: -2, okFloat: true},
+		{in: "2.5"},
+		{in: "9223372036854775807.0", int64: math.MaxInt64, okFloat: true},
+		{in: "9223372036854775808.0"},
+		{in: "1e19"},
+		{in: "1e400"},
+		{in: "1e-1000000000"},
+		{in: "0e-1000000000", okFloat: true},
+		{in: "abc"},
+	} {
+		out, ok := cast.InterfaceToInt64(tc.in)
+		require.Equal(t, tc.ok, ok, tc.in)
+		if ok {
+			require.Equal(t, tc.int64, out)
+		}
+
+		out, ok = cast.InterfaceToInt64Downcast(tc.in)
+		require.Equal(t, tc.ok || tc.okFloat, ok, tc.in)
+		if ok {
+			require.Equal(t, tc.int64, out)
+		}
+	}
+
+	i8, ok := cast.InterfaceToInt8Downcast(json.Number("1.27e2"))
+	require.True(t, ok)
+	require.Equal(t, int8(127), i8)
+	_, ok = cast.InterfaceToInt8Downcast(json.Number("1.28e2"))
+	require.False(t, ok)
+
+	u, ok := cast.InterfaceToUint64Downcast(json.Number("18446744073709551615.0"))
+	require.True(t, ok)
+	require.Equal(t, uint64(math.MaxUint64), u)
+	_, ok = cast.InterfaceToUint64Downcast(json.Number("-1.0"))
+	require.False(t, ok)
+
+	f, ok := cast.InterfaceToFloat64(json.Number("9007199254740993"))
+	require.True(t, ok)
+	require.Equal(t, float64(9007199254740992), f)
+	_, ok = cast.InterfaceToFloat64(json.Number("1e400"))
+	require.False(t, ok)
+
+	ints, ok := cast.InterfaceToIntSlice([]interface{}{json.Number("9007199254740993"), json.Number("2")})
+	require.True(t, ok)
+	require.Equal(t, []int{9007199254740993, 2}, ints)
+	_, ok = cast.InterfaceToIntSlice([]interface{}{json.Number("2.0")})
+	require.False(t, ok)
+	ints, ok = cast.InterfaceToIntSliceDowncast([]interface{}{json.Number("2.0")})
+	require.True(t, ok)
+	require.Equal(t, []int{2}, ints)
+	_, index, ok := cast.InterfaceToIntSliceWithInvalidIndex([]interface{}{json.Number("1"), json.Number("9223372036854775808")}, false)
+	require.False(t, ok)
+	require.Equal(t, 1, index)
+
+	m, ok := cast.InterfaceToStrInterfaceMapRecursive(map[interface{}]interface{}{"a": json.Number("9007199254740993")})
+	require.True(t, ok)
+	require.Equal(t, json.Number("9007199254740993"), m["a"])
+}
+
+func TestInterfaceToBoolExtended(t *testing.T) {
+	for _, tc := range []struct {
+		in       interface{}
+		expected bool
+	}{
+		{in: true, expected: true},
+		{in: false, expected: false},
+		{in: 1, expected: true},
+		{in: int64(0), expected: false},
+		{in: json.Number("1"), expected: true},
+		{in: "true", expected: true},
+		{in: "TRUE", expected: true},
+		{in: " False ", expected: false},
+		{in: "1", expected: true},
+		{in: "f", expected: false},
+	} {
+		out, ok := cast.InterfaceToBoolExtended(tc.in)
+		require.True(t, ok, "%T %v", tc.in, tc.in)
+		require.Equal(t, tc.expected, out, "%T %v", tc.in, tc.in)
+	}
+
+	for _, in := range []interface{}{2, -1, 1.0, "yes", "2", "", nil} {
+		_, ok := cast.InterfaceToBoolExtended(in)
+		require.False(t, ok, "%T %v", in, in)
+	}
+}
+
+func TestInterfaceToDuration(t *testing.T) {
+	for _, tc := range []struct {
+		in       interface{}
+		unit     time.Duration
+		expected time.Duration
+		ok       bool
+	}{
+		{in: 30, unit: time.Second, expected: 30 * time.Second, ok: true},
+		{in: "45s", unit: time.Second, expected: 45 * time.Second, ok: true},
+		{in: " 1h30m ", expected: 90 * time.Minute, ok: true},
+		{in: "60", unit: time.Millisecond, expected: 60 * time.Millisecond, ok: true},
+		{in: time.Duration(5), expected: 5, ok: true},
+		{in: json.Number("2"), unit: time.Minute, expected: 2 * time.Minute, ok: true},
+		{in: 30},
+		{in: "60"},
+		{in: 1.5, unit: time.Second},
+		{in: int64(math.MaxInt64), unit: time.Second},
+		{in: "soon", unit: time.Second},
+		{in: nil, unit: time.Second},
+	} {
+		out, ok := cast.InterfaceToDuration(tc.in, tc.unit)
+		require.Equal(t, tc.ok, ok, "%T %v", tc.in, tc.in)
+		if ok {
+			require.Equal(t, tc.expected, out)
+		}
+	}
+}
+
+func TestInterfaceToTime(t *testing.T) {
+	var yamlMap map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte("created: 2001-12-14t21:59:43.10-05:00"), &yamlMap))
+	out, ok := cast.InterfaceToTime(yamlMap["created"], nil, nil)
+	require.True(t, ok)
+	require.True(t, time.Date(2001, 12, 15, 2, 59, 43, 100000000, time.UTC).Equal(out))
+
+	out, ok = cast.InterfaceToTime("2020-01-02", []string{time.RFC3339, "2006-01-02"}, nil)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), out)
+
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	out, ok = cast.InterfaceToTime("2020-01-02", []string{"2006-01-02"}, loc)
+	require.True(t, ok)
+	require.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, loc), out)
+
+	_, ok = cast.InterfaceToTime("01/02/2020", []string{"2006-01-02"}, nil)
+	require.False(t, ok)
+	_, ok = cast.InterfaceToTime(1577836800, []string{"2006-01-02"}, nil)
+	require.False(t, ok)
+}
+
+type quantity struct {
+	Value int
+	Unit  string
+}
+
+type resourceID string
+
+func TestRegisterConverter(t *testing.T) {
+	defer cast.ResetConvertersForTest()
+
+	_, ok := cast.InterfaceToInt(quantity{Value: 4})
+	require.False(t, ok)
+
+	require.NoError(t, cast.RegisterConverter(reflect.TypeOf(0), func(in interface{}) (interface{}, bool) {
+		if q, ok := in.(quantity); ok {
+			return q.Value, true
+		}
+		return nil, false
+	}))
+	require.NoError(t, cast.RegisterConverter(reflect.TypeOf(""), func(in interface{}) (interface{}, bool) {
+		if id, ok := in.(resourceID); ok {
+			return string(id), true
+		}
+		return nil, false
+	}))
+	require.NoError(t, cast.RegisterConverter(reflect.TypeOf(resourceID("")), func(in interface{}) (interface{}, bool) {
+		if str, ok := in.(string); ok {
+			return resourceID(str), true
+		}
+		return nil, false
+	}))
+	// a converter which returns the wrong type is ignored
+	require.NoError(t, cast.RegisterConverter(reflect.TypeOf(float64(0)), func(in interface{}) (interface{}, bool) {
+		return "1.5", true
+	}))
+
+	require.Error(t, cast.RegisterConverter(reflect.TypeOf(0), func(in interface{}) (interface{}, bool) { return 0, true }))
+
+	i, ok := cast.InterfaceToInt(quantity{Value: 4})
+	require.True(t, ok)
+	require.Equal(t, 4, i)
+	i, ok = cast.InterfaceToInt(5)
+	require.True(t, ok)
+	require.Equal(t, 5, i)
+	_, ok = cast.InterfaceToInt("4")
+	require.False(t, ok)
+
+	str, ok := cast.InterfaceToStr(resourceID("abc"))
+	require.True(t, ok)
+	require.Equal(t, "abc", str)
+
+	_, ok = cast.InterfaceToFloat64(quantity{Value: 4})
+	require.False(t, ok)
+
+	id, ok := cast.Convert("abc", reflect.TypeOf(resourceID("")))
+	require.True(t, ok)
+	require.Equal(t, resourceID("abc"), id)
+	_, ok = cast.Convert(1, reflect.TypeOf(resourceID("")))
+	require.False(t, ok)
+	_, ok = cast.Convert(1, reflect.TypeOf(int8(0)))
+	require.False(t, ok)
+
+	cast.ResetConvertersForTest()
+	_, ok = cast.InterfaceToInt(quantity{Value: 4})
+	require.False(t, ok)
+}
+
+func TestRegisterConverterConcurrently(t *testing.T) {
+	defer cast.ResetConvertersForTest()
+
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		go func() {
+			errs <- cast.RegisterConverter(reflect.TypeOf(resourceID("")), func(in interface{}) (interface{}, bool) { return nil, false })
+			cast.InterfaceToStr(1)
+		}()
+	}
+	numOK := 0
+	for i := 0; i < 10; i++ {
+		if <-errs == nil {
+			numOK++
+		}
+	}
+	require.Equal(t, 1, numOK)
+}
+
+func TestDeref(t *testing.T) {
+	i := 4
+	f := 1.5
+	str := "abc"
+	b := true
+	pi := &i
+	ppi := &pi
+	pppi := &ppi
+	var nilInt *int
+	var nilInter interface{} = nilInt
+
+	require.Nil(t, cast.Deref(nil))
+	require.Nil(t, cast.Deref(nilInter))
+	require.Nil(t, cast.Deref(&nilInter))
+	require.Equal(t, 4, cast.Deref(pppi))
+	require.Equal(t, &i, cast.Deref(&pppi)) // beyond MaxDerefDepth
+
+	val, ok := cast.InterfaceToInt(&i)
+	require.True(t, ok)
+	require.Equal(t, 4, val)
+	val, ok = cast.InterfaceToInt(pppi)
+	require.True(t, ok)
+	require.Equal(t, 4, val)
+	_, ok = cast.InterfaceToInt(&pppi)
+	require.False(t, ok)
+	_, ok = cast.InterfaceToInt(nilInter)
+	require.False(t, ok)
+
+	floatVal, ok := cast.InterfaceToFloat64(&f)
+	require.True(t, ok)
+	require.Equal(t, 1.5, floatVal)
+	strVal, ok := cast.InterfaceToStr(&str)
+	require.True(t, ok)
+	require.Equal(t, "abc", strVal)
+	boolVal, ok := cast.InterfaceToBoolExtended(&b)
+	require.True(t, ok)
+	require.True(t, boolVal)
+
+	intSlice, ok := cast.InterfaceToIntSlice(&[]interface{}{1, &i})
+	require.True(t, ok)
+	require.Equal(t, []int{1, 4}, intSlice)
+	strSlice, ok := cast.InterfaceToStrSlice([]interface{}{"a", &str})
+	require.True(t, ok)
+	require.Equal(t, []string{"a", "abc"}, strSlice)
+	_, ok = cast.InterfaceToStrSlice([]interface{}{"a", (*string)(nil)})
+	require.False(t, ok)
+
+	var nilSlice *[]int
+	intSlice, ok = cast.InterfaceToIntSlice(nilSlice)
+	require.True(t, ok)
+	require.Nil(t, intSlice)
+
+	strMap, ok := cast.InterfaceToStrInterfaceMap(&map[string]interface{}{"a": 1})
+	require.True(t, ok)
+	require.Equal(t, map[string]interface{}{"a": 1}, strMap)
+	strStrMap, ok := cast.InterfaceToStrStrMap(map[interface{}]interface{}{"a": &str})
+	require.True(t, ok)
+	require.Equal(t, map[string]string{"a": "abc"}, strStrMap)
+}
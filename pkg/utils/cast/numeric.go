@@ -0,0 +1,175 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cast
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Integer is the integer types which To() converts to
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// Float is the float types which To() converts to
+type Float interface {
+	~float32 | ~float64
+}
+
+// Numeric is the types which To() converts to
+type Numeric interface {
+	Integer | Float
+}
+
+// To converts any int, uint, or float type (or a json.Number) to T, if its value fits in T. Floats aren't converted
+// to integer types (see ToDowncast()), and float64s which would round to infinity aren't converted to float32s.
+// Ints are converted to floats even if they can't be represented exactly
+func To[T Numeric](in interface{}) (T, bool) {
+	return to[T](in, false)
+}
+
+// ToDowncast is like To(), but also converts floats to integer types if their values are integral
+func ToDowncast[T Numeric](in interface{}) (T, bool) {
+	return to[T](in, true)
+}
+
+func to[T Numeric](in interface{}, downcast bool) (T, bool) {
+	kind := reflect.TypeOf(T(0)).Kind()
+	in = Deref(in)
+	if number, ok := in.(json.Number); ok {
+		if in, ok = jsonNumberTo(number, kind, downcast); !ok {
+			return 0, false
+		}
+	}
+
+	switch casted := in.(type) {
+	case int8:
+		return fromInt64[T](int64(casted), kind)
+	case int16:
+		return fromInt64[T](int64(casted), kind)
+	case int32:
+		return fromInt64[T](int64(casted), kind)
+	case int:
+		return fromInt64[T](int64(casted), kind)
+	case int64:
+		return fromInt64[T](casted, kind)
+	case uint8:
+		return fromUint64[T](uint64(casted), kind)
+	case uint16:
+		return fromUint64[T](uint64(casted), kind)
+	case uint32:
+		return fromUint64[T](uint64(casted), kind)
+	case uint:
+		return fromUint64[T](uint64(casted), kind)
+	case uint64:
+		return fromUint64[T](casted, kind)
+	case float32:
+		return fromFloat64[T](float64(casted), kind, downcast)
+	case float64:
+		return fromFloat64[T](casted, kind, downcast)
+	}
+	return 0, false
+}
+
+// jsonNumberTo returns number as an int64, uint64, or float64, whichever can be converted to kind
+func jsonNumberTo(number json.Number, kind reflect.Kind, downcast bool) (interface{}, bool) {
+	if isFloatKind(kind) {
+		return JSONNumberToIntOrFloat(number)
+	}
+	if inInt, err := number.Int64(); err == nil {
+		return inInt, true
+	}
+	if isUintKind(kind) {
+		if inUint, err := strconv.ParseUint(number.String(), 10, 64); err == nil {
+			return inUint, true
+		}
+	}
+	if !downcast {
+		return nil, false
+	}
+	bigInt, ok := jsonNumberToBigInt(number)
+	switch {
+	case !ok:
+		return nil, false
+	case bigInt.IsInt64():
+		return bigInt.Int64(), true
+	case bigInt.IsUint64():
+		return bigInt.Uint64(), true
+	}
+	return nil, false
+}
+
+func fromInt64[T Numeric](in int64, kind reflect.Kind) (T, bool) {
+	val := T(in)
+	if isFloatKind(kind) {
+		return val, true
+	}
+	// the sign check catches e.g. -1 wrapping around to math.MaxUint64
+	if int64(val) != in || (val < 0) != (in < 0) {
+		return 0, false
+	}
+	return val, true
+}
+
+func fromUint64[T Numeric](in uint64, kind reflect.Kind) (T, bool) {
+	val := T(in)
+	if isFloatKind(kind) {
+		return val, true
+	}
+	if uint64(val) != in || val < 0 {
+		return 0, false
+	}
+	return val, true
+}
+
+func fromFloat64[T Numeric](in float64, kind reflect.Kind, downcast bool) (T, bool) {
+	switch {
+	case kind == reflect.Float32:
+		if math.Abs(in) >= Float32Overflow && !math.IsInf(in, 0) {
+			return 0, false
+		}
+		return T(in), true
+	case kind == reflect.Float64:
+		return T(in), true
+	case !downcast:
+		return 0, false
+	case isUintKind(kind):
+		if casted, ok := floatToUint64(in); ok {
+			return fromUint64[T](casted, kind)
+		}
+	default:
+		if casted, ok := floatToInt64(in); ok {
+			return fromInt64[T](casted, kind)
+		}
+	}
+	return 0, false
+}
+
+func isFloatKind(kind reflect.Kind) bool {
+	return kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func isUintKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cast_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+)
+
+type replicaCount int
+
+func TestTo(t *testing.T) {
+	u8, ok := cast.To[uint8](255)
+	require.True(t, ok)
+	require.Equal(t, uint8(255), u8)
+	_, ok = cast.To[uint8](256)
+	require.False(t, ok)
+	_, ok = cast.To[uint8](-1)
+	require.False(t, ok)
+	_, ok = cast.To[uint64](int64(-1))
+	require.False(t, ok)
+
+	_, ok = cast.To[int64](uint64(math.MaxUint64))
+	require.False(t, ok)
+	i8, ok := cast.To[int8](uint16(127))
+	require.True(t, ok)
+	require.Equal(t, int8(127), i8)
+	_, ok = cast.To[int8](int64(-129))
+	require.False(t, ok)
+
+	// floats are only converted to integers by ToDowncast()
+	_, ok = cast.To[int](2.0)
+	require.False(t, ok)
+	i, ok := cast.ToDowncast[int](2.0)
+	require.True(t, ok)
+	require.Equal(t, 2, i)
+	_, ok = cast.ToDowncast[int](2.5)
+	require.False(t, ok)
+	_, ok = cast.ToDowncast[uint](-1.0)
+	require.False(t, ok)
+	u, ok := cast.ToDowncast[uint](json.Number("1e3"))
+	require.True(t, ok)
+	require.Equal(t, uint(1000), u)
+
+	f32, ok := cast.To[float32](int64(3))
+	require.True(t, ok)
+	require.Equal(t, float32(3), f32)
+	_, ok = cast.To[float32](math.MaxFloat64)
+	require.False(t, ok)
+	f64, ok := cast.To[float64](json.Number("2.5"))
+	require.True(t, ok)
+	require.Equal(t, 2.5, f64)
+
+	// named types
+	count, ok := cast.To[replicaCount](json.Number("5"))
+	require.True(t, ok)
+	require.Equal(t, replicaCount(5), count)
+
+	// pointers are dereferenced
+	five := int64(5)
+	i, ok = cast.To[int](&five)
+	require.True(t, ok)
+	require.Equal(t, 5, i)
+	var nilPtr *int64
+	_, ok = cast.To[int](nilPtr)
+	require.False(t, ok)
+
+	_, ok = cast.To[int]("5")
+	require.False(t, ok)
+	_, ok = cast.To[int](nil)
+	require.False(t, ok)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"io/ioutil"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// Long AllowedValues lists are checked against a set, which is built the first time the list is used
+const allowedValuesSetMinLen = 16
+
+// allowedValuesSet holds the set which a validation's AllowedValues are checked against, so that it's only built once per
+// validation. It's rebuilt if AllowedValues is replaced. The zero value is ready to use
+type allowedValuesSet struct {
+	built  atomic.Value      // *builtAllowedValuesSet
+	shared *allowedValuesSet // if set, it's used instead (e.g. by the validations which are built from *PtrValidations)
+}
+
+type builtAllowedValuesSet struct {
+	first  interface{} // pointer to the first element of the list which set was built from
+	length int
+	set    interface{}
+}
+
+func (cache *allowedValuesSet) get(first interface{}, length int, buildSet func() interface{}) interface{} {
+	if cache.shared != nil {
+		return cache.shared.get(first, length, buildSet)
+	}
+	if built, ok := cache.built.Load().(*builtAllowedValuesSet); ok && built.first == first && built.length == length {
+		return built.set
+	}
+	set := buildSet()
+	cache.built.Store(&builtAllowedValuesSet{first: first, length: length, set: set})
+	return set
+}
+
+type allowedValuesKey struct {
+	first  interface{} // pointer to the first element of the list
+	length int
+}
+
+var (
+	allowedValuesByFile sync.Map // file path -> []string
+	allowedValuesFiles  sync.Map // allowedValuesKey -> file path
+)
+
+// AllowedValuesFromFile reads AllowedValues from a file with one value per line (blank lines and lines starting with "#" are
+// ignored), e.g. for a long list of region codes. Each file is only read once. Errors for values which aren't allowed
+// mention the file, rather than listing all of its values
+func AllowedValuesFromFile(filePath string) ([]string, error) {
+	if allowedValues, ok := allowedValuesByFile.Load(filePath); ok {
+		return allowedValues.([]string), nil
+	}
+
+	fileBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.WrapCoded(fileError(err), s.MsgRead, filePath, s.ErrRead)
+	}
+
+	allowedValues := []string{}
+	for _, line := range strings.Split(string(fileBytes), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		allowedValues = append(allowedValues, line)
+	}
+
+	loaded, _ := allowedValuesByFile.LoadOrStore(filePath, allowedValues)
+	allowedValues = loaded.([]string)
+	if len(allowedValues) > 0 {
+		allowedValuesFiles.Store(allowedValuesKey{first: &allowedValues[0], length: len(allowedValues)}, filePath)
+	}
+	return allowedValues, nil
+}
+
+// allowedValuesFile returns the file which allowedValues was read from by AllowedValuesFromFile(), if any
+func allowedValuesFile(allowedValues []string) string {
+	if len(allowedValues) == 0 {
+		return ""
+	}
+	filePath, ok := allowedValuesFiles.Load(allowedValuesKey{first: &allowedValues[0], length: len(allowedValues)})
+	if !ok {
+		return ""
+	}
+	return filePath.(string)
+}
+
+// isAllowed returns whether val is one of allowedValues. cache is used for long lists (e.g. &v.allowedValuesSet)
+func isAllowed[T comparable](val T, allowedValues []T, cache *allowedValuesSet) bool {
+	if len(allowedValues) < allowedValuesSetMinLen {
+		for _, allowedValue := range allowedValues {
+			if val == allowedValue {
+				return true
+			}
+		}
+		return false
+	}
+	set := cache.get(&allowedValues[0], len(allowedValues), func() interface{} {
+		set := make(map[T]bool, len(allowedValues))
+		for _, allowedValue := range allowedValues {
+			set[allowedValue] = true
+		}
+		return set
+	}).(map[T]bool)
+	return set[val]
+}
+
+//
+// Musts
+//
+
+func MustAllowedValuesFromFile(filePath string) []string {
+	allowedValues, err := AllowedValuesFromFile(filePath)
+	if err != nil {
+		Fatal(err)
+	}
+	return allowedValues
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+func largeAllowedValues() ([]int, []string) {
+	ints := make([]int, 1000)
+	strs := make([]string, 1000)
+	for i := range ints {
+		ints[i] = i * 2
+		strs[i] = "value" + strconv.Itoa(i*2)
+	}
+	return ints, strs
+}
+
+func TestLargeAllowedValues(t *testing.T) {
+	ints, strs := largeAllowedValues()
+	intValidation := &cr.IntValidation{AllowedValues: ints}
+	strValidation := &cr.StringValidation{AllowedValues: strs}
+
+	for i := 0; i < 2; i++ {
+		val, err := cr.IntFromStr("1998", intValidation)
+		require.NoError(t, err)
+		require.Equal(t, 1998, val)
+
+		_, err = cr.IntFromStr("1999", intValidation)
+		require.EqualError(t, err, s.ErrInvalidInt(1999, ints...))
+
+		str, err := cr.StringFromStr("value1998", strValidation)
+		require.NoError(t, err)
+		require.Equal(t, "value1998", str)
+
+		_, err = cr.StringFromStr("value1999", strValidation)
+		require.EqualError(t, err, s.ErrInvalidStr("value1999", strs...)+s.DidYouMean("value1990"))
+	}
+
+	_, err := cr.IntFromStr("1999", intValidation)
+	require.EqualError(t, err, "invalid value (got 1999, must be 1980, 1982, 1984, 1986, 1988, 1990, 1992, 1994, 1996, 1998, ... and 990 more)")
+	var notAllowedErr *cr.NotAllowedValueError
+	require.True(t, errors.As(err, &notAllowedErr))
+	require.Len(t, notAllowedErr.Allowed, 1000)
+
+	s.SetMaxAllowedValuesShown(3)
+	_, err = cr.StringFromStr("value1999", strValidation)
+	require.EqualError(t, err, `invalid value (got "value1999", must be "value1990", "value1992", "value1994", ... and 997 more); did you mean "value1990"?`)
+	s.SetMaxAllowedValuesShown(0)
+	_, err = cr.IntFromStr("1999", intValidation)
+	require.NotContains(t, err.Error(), "more)")
+	s.SetMaxAllowedValuesShown(s.DefaultMaxAllowedValuesShown)
+
+	// a copy of the validation with a different list must not share the cached set
+	copiedValidation := *intValidation
+	copiedValidation.AllowedValues = append([]int{1999}, ints...)
+	val, err := cr.IntFromStr("1999", &copiedValidation)
+	require.NoError(t, err)
+	require.Equal(t, 1999, val)
+
+	// the set is rebuilt if AllowedValues is replaced
+	intValidation.AllowedValues = append([]int{1999}, ints...)
+	val, err = cr.IntFromStr("1999", intValidation)
+	require.NoError(t, err)
+	require.Equal(t, 1999, val)
+
+	// pointer validations use the same set for each value
+	strPtrValidation := &cr.StringPtrValidation{AllowedValues: strs}
+	for i := 0; i < 2; i++ {
+		strPtr, err := cr.StringPtrFromStr("value1998", strPtrValidation)
+		require.NoError(t, err)
+		require.Equal(t, "value1998", *strPtr)
+
+		_, err = cr.StringPtrFromStr("value1999", strPtrValidation)
+		require.Error(t, err)
+	}
+}
+
+func BenchmarkLargeAllowedValues(b *testing.B) {
+	_, strs := largeAllowedValues()
+	strValidation := &cr.StringValidation{AllowedValues: strs}
+	for i := 0; i < b.N; i++ {
+		cr.StringFromStr(strs[i%len(strs)], strValidation)
+	}
+}
+
+type allowedValuesStruct struct {
+	Region string `json:"region"`
+	Port   int    `json:"port"`
+}
+
+func allowedValuesStructListValidation(ints []int, strs []string) *cr.StructListValidation {
+	return &cr.StructListValidation{
+		StructValidation: &cr.StructValidation{
+			StructFieldValidations: []*cr.StructFieldValidation{
+				{
+					StructField:      "Region",
+					StringValidation: &cr.StringValidation{AllowedValues: strs},
+				},
+				{
+					StructField:   "Port",
+					IntValidation: &cr.IntValidation{AllowedValues: ints},
+				},
+			},
+		},
+	}
+}
+
+func allowedValuesStructList(n int) []interface{} {
+	inter := make([]interface{}, n)
+	for i := range inter {
+		inter[i] = map[string]interface{}{"region": "value" + strconv.Itoa(i*2), "port": i * 2}
+	}
+	return inter
+}
+
+// Struct() copies each field's validation, and the copies use the set which was built for the original
+func TestLargeAllowedValuesStructList(t *testing.T) {
+	ints, strs := largeAllowedValues()
+	v := allowedValuesStructListValidation(ints, strs)
+
+	for i := 0; i < 2; i++ {
+		structs, errs := cr.StructList([]*allowedValuesStruct{}, allowedValuesStructList(200), v)
+		require.Empty(t, errs)
+		require.Len(t, structs, 200)
+		require.Equal(t, &allowedValuesStruct{Region: "value398", Port: 398}, structs.([]*allowedValuesStruct)[199])
+	}
+
+	_, errs := cr.StructList([]*allowedValuesStruct{}, []interface{}{map[string]interface{}{"region": "value1", "port": 3}}, v)
+	require.Len(t, errs, 2)
+
+	// replacing AllowedValues in the original validation rebuilds the set which the copies use
+	v.StructValidation.StructFieldValidations[1].IntValidation.AllowedValues = append([]int{3}, ints...)
+	_, errs = cr.StructList([]*allowedValuesStruct{}, []interface{}{map[string]interface{}{"region": "value0", "port": 3}}, v)
+	require.Empty(t, errs)
+}
+
+func BenchmarkLargeAllowedValuesStructList(b *testing.B) {
+	ints, strs := largeAllowedValues()
+	v := allowedValuesStructListValidation(ints, strs)
+	inter := allowedValuesStructList(200)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cr.StructList([]*allowedValuesStruct{}, inter, v)
+	}
+}
+
+func TestAllowedValuesFromFile(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	_, regions := largeAllowedValues()
+	filePath := filepath.Join(tmpDir, "regions.txt")
+	err = ioutil.WriteFile(filePath, []byte("# valid regions\n"+strings.Join(regions, "\n")+"\n\n"), 0644)
+	require.NoError(t, err)
+
+	allowedValues, err := cr.AllowedValuesFromFile(filePath)
+	require.NoError(t, err)
+	require.Equal(t, regions, allowedValues)
+
+	// the file is only read once
+	err = os.Remove(filePath)
+	require.NoError(t, err)
+	v := &cr.StringValidation{AllowedValues: cr.MustAllowedValuesFromFile(filePath)}
+
+	val, err := cr.StringFromStr("value1998", v)
+	require.NoError(t, err)
+	require.Equal(t, "value1998", val)
+
+	_, err = cr.StringFromStr("value1999", v)
+	require.EqualError(t, err, s.ErrNotAllowedValueInFile("value1999", regions, filePath)+s.DidYouMean("value1990"))
+	require.Contains(t, err.Error(), "must be one of the values listed in "+filePath+": ")
+	require.Contains(t, err.Error(), "... and 990 more")
+
+	_, err = cr.AllowedValuesFromFile(filepath.Join(tmpDir, "missing.txt"))
+	require.Error(t, err)
+}
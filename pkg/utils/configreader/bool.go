@@ -17,41 +17,107 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
+	"strings"
 
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
 )
 
 type BoolValidation struct {
-	Required bool
-	Default  bool
+	Required             bool
+	Default              bool
+	PreserveWhitespace   bool    // don't trim surrounding whitespace (including the trailing newline) from values read from files
+	MaxFileBytes         int64   // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles bool    // allow FromFile readers to read from e.g. pipes and device files
+	AllowNumeric         bool    // accept 1 and 0 (and "1" and "0") as true and fals
+	Extended             bool    // also accept the strings accepted by strconv.ParseBool() (e.g. "true" or "TRUE"), and 1 and 0
+	Sensitive            bool    // replace the value with its length in errors and reports (errors from Validator are shown as-is)
+	Report               *Report // if set, each value which is read is recorded, along with where it came from
 }
 
 func Bool(inter interface{}, v *BoolValidation) (bool, error) {
 	if inter == nil {
-		return false, errors.New(s.ErrCannotBeNull)
+		return false, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+	}
+	if v.Extended {
+		if casted, ok := cast.InterfaceToBoolExtended(inter); ok {
+			return ValidateBool(casted, v)
+		}
+	}
+	if v.AllowNumeric {
+		if casted, ok, err := numericToBool(inter, v.Sensitive); ok {
+			if err != nil {
+				return false, err
+			}
+			return ValidateBool(casted, v)
+		}
 	}
 	casted, castOk := inter.(bool)
 	if !castOk {
-		return false, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeBool))
+		return false, errors.Wrap(&InvalidTypeError{Provided: redactIf(inter, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeBool}})
 	}
 	return ValidateBool(casted, v)
 }
 
+// numericToBool returns whether inter is a number (or "1" or "0"), and if so, whether it's 1 or 0
+func numericToBool(inter interface{}, sensitive bool) (bool, bool, error) {
+	switch inter {
+	case "1":
+		return true, true, nil
+	case "0":
+		return false, true, nil
+	}
+	casted, ok := cast.InterfaceToFloat64(inter)
+	if !ok {
+		return false, false, nil
+	}
+	switch casted {
+	case 1:
+		return true, true, nil
+	case 0:
+		return false, true, nil
+	}
+	return false, true, errors.NewUserCoded(s.MsgInvalidNumericBool, s.ErrInvalidNumericBool(redactIf(inter, sensitive)))
+}
+
 func BoolFromInterfaceMap(key string, iMap map[string]interface{}, v *BoolValidation) (bool, error) {
 	inter, ok := ReadInterfaceMapValue(key, iMap)
 	if !ok {
 		val, err := ValidateBoolMissing(v)
 		if err != nil {
-			return false, errors.Wrap(err, key)
+			return false, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := Bool(inter, v)
 	if err != nil {
-		return false, errors.Wrap(err, key)
+		return false, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
+	return val, nil
+}
+
+// BoolFromInterfaceMapWithRefs is like BoolFromInterfaceMap(), but the value can refer to another field in the map (e.g. "${default_timeout}")
+func BoolFromInterfaceMapWithRefs(key string, iMap map[string]interface{}, v *BoolValidation) (bool, error) {
+	inter, ok, err := ReadInterfaceMapValueWithRefs(key, iMap)
+	if err != nil {
+		return false, errors.WrapKey(err, key)
+	}
+	if !ok {
+		val, err := ValidateBoolMissing(v)
+		if err != nil {
+			return false, errors.WrapKey(err, key)
+		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := Bool(inter, v)
+	if err != nil {
+		return false, errors.WrapKey(err, key)
+	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
 	return val, nil
 }
 
@@ -60,14 +126,16 @@ func BoolFromStrMap(key string, sMap map[string]string, v *BoolValidation) (bool
 	if !ok || valStr == "" {
 		val, err := ValidateBoolMissing(v)
 		if err != nil {
-			return false, errors.Wrap(err, key)
+			return false, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := BoolFromStr(valStr, v)
 	if err != nil {
-		return false, errors.Wrap(err, key)
+		return false, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
 	return val, nil
 }
 
@@ -77,41 +145,58 @@ func BoolFromStr(valStr string, v *BoolValidation) (bool, error) {
 	}
 	casted, castOk := s.ParseBool(valStr)
 	if !castOk {
-		return false, errors.New(s.ErrInvalidPrimitiveType(valStr, s.PrimTypeBool))
+		return false, errors.Wrap(&InvalidTypeError{Provided: redactIf(valStr, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeBool}})
 	}
 	return ValidateBool(casted, v)
 }
 
 func BoolFromEnv(envVarName string, v *BoolValidation) (bool, error) {
-	valStr := ReadEnvVar(envVarName)
+	return DefaultEnv().BoolFromEnv(envVarName, v)
+}
+
+func (env *Env) BoolFromEnv(envVarName string, v *BoolValidation) (bool, error) {
+	valStr := env.ReadEnvVar(envVarName)
 	if valStr == nil || *valStr == "" {
 		val, err := ValidateBoolMissing(v)
 		if err != nil {
-			return false, errors.Wrap(err, s.EnvVar(envVarName))
+			return false, envVarError(err, envVarName)
 		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := BoolFromStr(*valStr, v)
 	if err != nil {
-		return false, errors.Wrap(err, s.EnvVar(envVarName))
+		return false, envVarError(err, envVarName)
 	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
 	return val, nil
 }
 
 func BoolFromFile(filePath string, v *BoolValidation) (bool, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
-	if err != nil || len(valBytes) == 0 {
+	return uncachedFiles.BoolFromFile(filePath, v)
+}
+
+func (cache *FileCache) BoolFromFile(filePath string, v *BoolValidation) (bool, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
+	if err != nil {
+		return false, errors.Wrap(err, filePath)
+	}
+	if valStr == nil || *valStr == "" {
 		val, err := ValidateBoolMissing(v)
 		if err != nil {
-			return false, errors.Wrap(err, filePath)
+			return false, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
-	val, err := BoolFromStr(valStr, v)
+	if err := checkSingleLine(*valStr); err != nil {
+		return false, valueFileError(err, filePath, 0)
+	}
+	val, err := BoolFromStr(*valStr, v)
 	if err != nil {
-		return false, errors.Wrap(err, filePath)
+		return false, valueFileError(err, filePath, 1)
 	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive))
 	return val, nil
 }
 
@@ -123,18 +208,73 @@ func BoolFromEnvOrFile(envVarName string, filePath string, v *BoolValidation) (b
 	return BoolFromFile(filePath, v)
 }
 
+func BoolFromRef(ref string, v *BoolValidation) (bool, error) {
+	valStr, err := ReadRef(ref)
+	if err != nil {
+		return false, errors.Wrap(err, ref)
+	}
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateBoolMissing(v)
+		if err != nil {
+			return false, errors.Wrap(err, ref)
+		}
+		v.Report.record(ref, ValueSourceDefault, ref, redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := BoolFromStr(*valStr, v)
+	if err != nil {
+		return false, errors.Wrap(err, ref)
+	}
+	v.Report.record(ref, ValueSourceRef, ref, redactIf(val, v.Sensitive))
+	return val, nil
+}
+
+func BoolFromSource(source Source, key string, v *BoolValidation) (bool, error) {
+	valStr, found, err := source.Lookup(key)
+	if err != nil {
+		return false, errors.WrapKey(err, key)
+	}
+	if !found || valStr == "" {
+		val, err := ValidateBoolMissing(v)
+		if err != nil {
+			return false, errors.WrapKey(err, key)
+		}
+		v.Report.record(key, ValueSourceDefault, describeSource(source, key), redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := BoolFromStr(valStr, v)
+	if err != nil {
+		return false, errors.WrapKey(errors.WithSource(err, describeSource(source, key)), key)
+	}
+	v.Report.record(key, ValueSourceLookup, describeSource(source, key), redactIf(val, v.Sensitive))
+	return val, nil
+}
+
 func BoolFromPrompt(promptOpts *PromptOptions, v *BoolValidation) (bool, error) {
-	promptOpts.defaultStr = s.Bool(v.Default)
-	valStr := prompt(promptOpts)
-	if valStr == "" {
-		return ValidateBoolMissing(v)
+	promptOpts = promptOpts.withHints(ConstraintHint(v), s.Bool(v.Default))
+	var val bool
+	var source ValueSource
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		if valStr == "" {
+			val, err = ValidateBoolMissing(v)
+		} else if yesNo, ok := parseYesNo(valStr); ok {
+			val, err = ValidateBool(yesNo, v)
+		} else {
+			val, err = BoolFromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(val, v.Sensitive))
 	}
-	return BoolFromStr(valStr, v)
+	return val, err
 }
 
 func ValidateBoolMissing(v *BoolValidation) (bool, error) {
 	if v.Required {
-		return false, errors.New(s.ErrMustBeDefined)
+		return false, errors.Wrap(ErrRequired)
 	}
 	return ValidateBool(v.Default, v)
 }
@@ -143,6 +283,82 @@ func ValidateBool(val bool, v *BoolValidation) (bool, error) {
 	return val, nil
 }
 
+//
+// With raw values
+//
+
+func BoolFromInterfaceMapWithRaw(key string, iMap map[string]interface{}, v *BoolValidation) (bool, string, error) {
+	val, err := BoolFromInterfaceMap(key, iMap, v)
+	raw := ""
+	if inter, ok := ReadInterfaceMapValue(key, iMap); ok {
+		raw = s.UserStrStripped(inter)
+	}
+	return val, raw, err
+}
+
+func BoolFromStrWithRaw(valStr string, v *BoolValidation) (bool, string, error) {
+	val, err := BoolFromStr(valStr, v)
+	return val, valStr, err
+}
+
+func BoolFromEnvWithRaw(envVarName string, v *BoolValidation) (bool, string, error) {
+	return DefaultEnv().BoolFromEnvWithRaw(envVarName, v)
+}
+
+func (env *Env) BoolFromEnvWithRaw(envVarName string, v *BoolValidation) (bool, string, error) {
+	valStr := env.ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateBoolMissing(v)
+		if err != nil {
+			return false, "", envVarError(err, envVarName)
+		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+		return val, "", nil
+	}
+	val, err := BoolFromStr(*valStr, v)
+	if err != nil {
+		return false, *valStr, envVarError(err, envVarName)
+	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+	return val, *valStr, nil
+}
+
+func BoolFromFileWithRaw(filePath string, v *BoolValidation) (bool, string, error) {
+	valStr, err := readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, false)
+	if err != nil {
+		return false, "", errors.Wrap(err, filePath)
+	}
+	if valStr == nil {
+		val, err := ValidateBoolMissing(v)
+		if err != nil {
+			return false, "", valueFileError(err, filePath, 0)
+		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, "", nil
+	}
+	trimmed := *valStr
+	if !v.PreserveWhitespace {
+		trimmed = strings.TrimSpace(trimmed)
+	}
+	if trimmed == "" {
+		val, err := ValidateBoolMissing(v)
+		if err != nil {
+			return false, *valStr, valueFileError(err, filePath, 0)
+		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, *valStr, nil
+	}
+	if err := checkSingleLine(trimmed); err != nil {
+		return false, *valStr, valueFileError(err, filePath, 0)
+	}
+	val, err := BoolFromStr(trimmed, v)
+	if err != nil {
+		return false, *valStr, valueFileError(err, filePath, 1)
+	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive))
+	return val, *valStr, nil
+}
+
 //
 // Musts
 //
@@ -150,7 +366,7 @@ func ValidateBool(val bool, v *BoolValidation) (bool, error) {
 func MustBoolFromEnv(envVarName string, v *BoolValidation) bool {
 	val, err := BoolFromEnv(envVarName, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return val
 }
@@ -158,7 +374,7 @@ func MustBoolFromEnv(envVarName string, v *BoolValidation) bool {
 func MustBoolFromFile(filePath string, v *BoolValidation) bool {
 	val, err := BoolFromFile(filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return val
 }
@@ -166,7 +382,23 @@ func MustBoolFromFile(filePath string, v *BoolValidation) bool {
 func MustBoolFromEnvOrFile(envVarName string, filePath string, v *BoolValidation) bool {
 	val, err := BoolFromEnvOrFile(envVarName, filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
+	}
+	return val
+}
+
+func MustBoolFromRef(ref string, v *BoolValidation) bool {
+	val, err := BoolFromRef(ref, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustBoolFromSource(source Source, key string, v *BoolValidation) bool {
+	val, err := BoolFromSource(source, key, v)
+	if err != nil {
+		Fatal(err)
 	}
 	return val
 }
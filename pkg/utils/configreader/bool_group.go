@@ -0,0 +1,63 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// ValidateAtMostOneTrue errors if more than one of the bool fields named by keys is true (missing fields are false)
+func ValidateAtMostOneTrue(iMap map[string]interface{}, keys ...string) error {
+	trueKeys, err := trueBoolKeys(iMap, keys)
+	if err != nil {
+		return err
+	}
+	if len(trueKeys) > 1 {
+		return errors.NewUserCoded(s.MsgAtMostOneTrue, s.ErrAtMostOneTrue(keys, trueKeys))
+	}
+	return nil
+}
+
+// ValidateExactlyOneTrue errors unless exactly one of the bool fields named by keys is true (missing fields are false)
+func ValidateExactlyOneTrue(iMap map[string]interface{}, keys ...string) error {
+	trueKeys, err := trueBoolKeys(iMap, keys)
+	if err != nil {
+		return err
+	}
+	if len(trueKeys) == 0 {
+		return errors.NewUserCoded(s.MsgExactlyOneTrue, s.ErrExactlyOneTrue(keys))
+	}
+	if len(trueKeys) > 1 {
+		return errors.NewUserCoded(s.MsgAtMostOneTrue, s.ErrAtMostOneTrue(keys, trueKeys))
+	}
+	return nil
+}
+
+func trueBoolKeys(iMap map[string]interface{}, keys []string) ([]string, error) {
+	var trueKeys []string
+	for _, key := range keys {
+		val, err := BoolFromInterfaceMap(key, iMap, &BoolValidation{})
+		if err != nil {
+			return nil, err
+		}
+		if val {
+			trueKeys = append(trueKeys, key)
+		}
+	}
+	return trueKeys, nil
+}
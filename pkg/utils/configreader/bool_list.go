@@ -27,13 +27,14 @@ type BoolListValidation struct {
 	Default    []bool
 	AllowNull  bool
 	AllowEmpty bool
+	Report     *Report // if set, each value which is read is recorded, along with where it came from
 	Validator  func([]bool) ([]bool, error)
 }
 
 func BoolList(inter interface{}, v *BoolListValidation) ([]bool, error) {
 	casted, castOk := cast.InterfaceToBoolSlice(inter)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeBoolList))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeBoolList}})
 	}
 	return ValidateBoolList(casted, v)
 }
@@ -43,20 +44,22 @@ func BoolListFromInterfaceMap(key string, iMap map[string]interface{}, v *BoolLi
 	if !ok {
 		val, err := ValidateBoolListMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", val)
 		return val, nil
 	}
 	val, err := BoolList(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", val)
 	return val, nil
 }
 
 func ValidateBoolListMissing(v *BoolListValidation) ([]bool, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateBoolList(v.Default, v)
 }
@@ -64,18 +67,19 @@ func ValidateBoolListMissing(v *BoolListValidation) ([]bool, error) {
 func ValidateBoolList(val []bool, v *BoolListValidation) ([]bool, error) {
 	if !v.AllowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
 	if !v.AllowEmpty {
 		if val != nil && len(val) == 0 {
-			return nil, errors.New(s.ErrCannotBeEmpty)
+			return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
 		}
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
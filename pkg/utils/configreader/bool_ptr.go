@@ -17,25 +17,44 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
-
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
 )
 
 type BoolPtrValidation struct {
-	Required     bool
-	Default      *bool
-	DisallowNull bool
+	Required             bool
+	Default              *bool
+	DisallowNull         bool
+	PreserveWhitespace   bool    // don't trim surrounding whitespace (including the trailing newline) from values read from files
+	MaxFileBytes         int64   // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles bool    // allow FromFile readers to read from e.g. pipes and device files
+	AllowNumeric         bool    // accept 1 and 0 (and "1" and "0") as true and false
+	Extended             bool    // also accept the strings accepted by strconv.ParseBool() (e.g. "true" or "TRUE"), and 1 and 0
+	Sensitive            bool    // replace the value with its length in errors (errors from Validator are shown as-is)
+	Report               *Report // if set, each value which is read is recorded, along with where it came from
 }
 
 func BoolPtr(inter interface{}, v *BoolPtrValidation) (*bool, error) {
 	if inter == nil {
 		return ValidateBoolPtr(nil, v)
 	}
+	if v.Extended {
+		if casted, ok := cast.InterfaceToBoolExtended(inter); ok {
+			return ValidateBoolPtr(&casted, v)
+		}
+	}
+	if v.AllowNumeric {
+		if casted, ok, err := numericToBool(inter, v.Sensitive); ok {
+			if err != nil {
+				return nil, err
+			}
+			return ValidateBoolPtr(&casted, v)
+		}
+	}
 	casted, castOk := inter.(bool)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeBool))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: redactIf(inter, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeBool}})
 	}
 	return ValidateBoolPtr(&casted, v)
 }
@@ -45,14 +64,16 @@ func BoolPtrFromInterfaceMap(key string, iMap map[string]interface{}, v *BoolPtr
 	if !ok {
 		val, err := ValidateBoolPtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := BoolPtr(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -61,14 +82,16 @@ func BoolPtrFromStrMap(key string, sMap map[string]string, v *BoolPtrValidation)
 	if !ok || valStr == "" {
 		val, err := ValidateBoolPtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := BoolPtrFromStr(valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -78,41 +101,58 @@ func BoolPtrFromStr(valStr string, v *BoolPtrValidation) (*bool, error) {
 	}
 	casted, castOk := s.ParseBool(valStr)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(valStr, s.PrimTypeBool))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: redactIf(valStr, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeBool}})
 	}
 	return ValidateBoolPtr(&casted, v)
 }
 
 func BoolPtrFromEnv(envVarName string, v *BoolPtrValidation) (*bool, error) {
-	valStr := ReadEnvVar(envVarName)
+	return DefaultEnv().BoolPtrFromEnv(envVarName, v)
+}
+
+func (env *Env) BoolPtrFromEnv(envVarName string, v *BoolPtrValidation) (*bool, error) {
+	valStr := env.ReadEnvVar(envVarName)
 	if valStr == nil || *valStr == "" {
 		val, err := ValidateBoolPtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, s.EnvVar(envVarName))
+			return nil, envVarError(err, envVarName)
 		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := BoolPtrFromStr(*valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, s.EnvVar(envVarName))
+		return nil, envVarError(err, envVarName)
 	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
 func BoolPtrFromFile(filePath string, v *BoolPtrValidation) (*bool, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
-	if err != nil || len(valBytes) == 0 {
+	return uncachedFiles.BoolPtrFromFile(filePath, v)
+}
+
+func (cache *FileCache) BoolPtrFromFile(filePath string, v *BoolPtrValidation) (*bool, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
+	if err != nil {
+		return nil, errors.Wrap(err, filePath)
+	}
+	if valStr == nil || *valStr == "" {
 		val, err := ValidateBoolPtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, filePath)
+			return nil, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
-	val, err := BoolPtrFromStr(valStr, v)
+	if err := checkSingleLine(*valStr); err != nil {
+		return nil, valueFileError(err, filePath, 0)
+	}
+	val, err := BoolPtrFromStr(*valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, filePath)
+		return nil, valueFileError(err, filePath, 1)
 	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -125,16 +165,28 @@ func BoolPtrFromEnvOrFile(envVarName string, filePath string, v *BoolPtrValidati
 }
 
 func BoolPtrFromPrompt(promptOpts *PromptOptions, v *BoolPtrValidation) (*bool, error) {
-	valStr := prompt(promptOpts)
-	if valStr == "" {
-		return ValidateBoolPtrMissing(v)
+	promptOpts = promptOpts.withHints(ConstraintHint(v), "")
+	var val *bool
+	var source ValueSource
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		if valStr == "" {
+			val, err = ValidateBoolPtrMissing(v)
+		} else {
+			val, err = BoolPtrFromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(cast.Deref(val), v.Sensitive))
 	}
-	return BoolPtrFromStr(valStr, v)
+	return val, err
 }
 
 func ValidateBoolPtrMissing(v *BoolPtrValidation) (*bool, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateBoolPtr(v.Default, v)
 }
@@ -142,7 +194,7 @@ func ValidateBoolPtrMissing(v *BoolPtrValidation) (*bool, error) {
 func ValidateBoolPtr(val *bool, v *BoolPtrValidation) (*bool, error) {
 	if v.DisallowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
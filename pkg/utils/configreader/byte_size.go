@@ -0,0 +1,280 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// ByteSizeValidation reads sizes like "512Mi", "1.5GB", or "1024" (bytes). Decimal units (K, KB, M, MB, ...) are
+// powers of 1000, and binary units (Ki, KiB, Mi, MiB, ...) are powers of 1024
+type ByteSizeValidation struct {
+	Required             bool
+	Default              int64
+	GreaterThanOrEqualTo *int64
+	LessThanOrEqualTo    *int64
+	AllowSign            bool // accept a leading "+" or "-", e.g. for deltas like "-100Mi"
+	AllowRate            bool // accept a "/s" suffix, e.g. for bandwidth like "5MB/s" (use ParseByteSize() to tell whether it was given)
+	AllowValueUnitMap    bool // also accept maps like {value: 512, unit: Mi} (the unit is optional, and defaults to bytes)
+	Validator            func(int64) (int64, error)
+}
+
+const byteSizeRateSuffix = "/s"
+
+var byteSizeUnits = []string{"B", "K", "KB", "M", "MB", "G", "GB", "T", "TB", "P", "PB", "E", "EB", "Ki", "KiB", "Mi", "MiB", "Gi", "GiB", "Ti", "TiB", "Pi", "PiB", "Ei", "EiB"}
+
+var byteSizeMultipliers = map[string]int64{
+	"":    1,
+	"B":   1,
+	"K":   1e3,
+	"KB":  1e3,
+	"k":   1e3,
+	"kB":  1e3,
+	"M":   1e6,
+	"MB":  1e6,
+	"G":   1e9,
+	"GB":  1e9,
+	"T":   1e12,
+	"TB":  1e12,
+	"P":   1e15,
+	"PB":  1e15,
+	"E":   1e18,
+	"EB":  1e18,
+	"Ki":  1 << 10,
+	"KiB": 1 << 10,
+	"Mi":  1 << 20,
+	"MiB": 1 << 20,
+	"Gi":  1 << 30,
+	"GiB": 1 << 30,
+	"Ti":  1 << 40,
+	"TiB": 1 << 40,
+	"Pi":  1 << 50,
+	"PiB": 1 << 50,
+	"Ei":  1 << 60,
+	"EiB": 1 << 60,
+}
+
+func ByteSize(inter interface{}, v *ByteSizeValidation) (int64, error) {
+	if inter == nil {
+		return 0, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+	}
+	if casted, ok := inter.(string); ok {
+		return ByteSizeFromStr(casted, v)
+	}
+	if v.AllowValueUnitMap {
+		if iMap, ok := cast.InterfaceToStrInterfaceMap(inter); ok {
+			return byteSizeFromValueUnitMap(iMap, v)
+		}
+	}
+	casted, ok := cast.InterfaceToInt64(inter)
+	if !ok {
+		expected := []s.PrimitiveType{s.PrimTypeString, s.PrimTypeInt}
+		if v.AllowValueUnitMap {
+			expected = append(expected, s.PrimTypeMap)
+		}
+		return 0, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: expected})
+	}
+	return ByteSizeFromStr(strconv.FormatInt(casted, 10), v)
+}
+
+// byteSizeFromValueUnitMap reads maps like {value: 512, unit: Mi}
+func byteSizeFromValueUnitMap(iMap map[string]interface{}, v *ByteSizeValidation) (int64, error) {
+	for key := range iMap {
+		if key != "value" && key != "unit" {
+			return 0, errors.NewUserCoded(s.MsgUnsupportedKey, s.ErrUnsupportedKey(key))
+		}
+	}
+
+	valueInter, ok := iMap["value"]
+	if !ok {
+		return 0, errors.WrapKey(errors.Wrap(ErrRequired), "value")
+	}
+	var valueStr string
+	if casted, ok := cast.InterfaceToInt64(valueInter); ok {
+		valueStr = strconv.FormatInt(casted, 10)
+	} else if casted, ok := cast.InterfaceToFloat64(valueInter); ok {
+		valueStr = strconv.FormatFloat(casted, 'f', -1, 64)
+	} else {
+		return 0, errors.WrapKey(errors.Wrap(&InvalidTypeError{Provided: valueInter, Expected: []s.PrimitiveType{s.PrimTypeInt, s.PrimTypeFloat}}), "value")
+	}
+
+	unit := ""
+	if unitInter, ok := iMap["unit"]; ok && unitInter != nil {
+		if unit, ok = unitInter.(string); !ok {
+			return 0, errors.WrapKey(errors.Wrap(&InvalidTypeError{Provided: unitInter, Expected: []s.PrimitiveType{s.PrimTypeString}}), "unit")
+		}
+		unit = strings.TrimSpace(unit)
+		if _, ok := byteSizeMultipliers[strings.TrimSuffix(unit, byteSizeRateSuffix)]; !ok {
+			return 0, errors.WrapKey(errors.NewUserCoded(s.MsgInvalidByteSizeUnit, s.ErrInvalidByteSizeUnit(valueStr+unit, unit, byteSizeUnits)), "unit")
+		}
+	}
+
+	val, _, err := ParseByteSize(valueStr+unit, v)
+	if err != nil {
+		return 0, err
+	}
+	return ValidateByteSize(val, v)
+}
+
+func ByteSizeFromInterfaceMap(key string, iMap map[string]interface{}, v *ByteSizeValidation) (int64, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateByteSizeMissing(v)
+		if err != nil {
+			return 0, errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := ByteSize(inter, v)
+	if err != nil {
+		return 0, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func ByteSizeFromStr(valStr string, v *ByteSizeValidation) (int64, error) {
+	if strings.TrimSpace(valStr) == "" {
+		return ValidateByteSizeMissing(v)
+	}
+	val, _, err := ParseByteSize(valStr, v)
+	if err != nil {
+		return 0, err
+	}
+	return ValidateByteSize(val, v)
+}
+
+func ByteSizeFromEnv(envVarName string, v *ByteSizeValidation) (int64, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateByteSizeMissing(v)
+		if err != nil {
+			return 0, envVarError(err, envVarName)
+		}
+		return val, nil
+	}
+	val, err := ByteSizeFromStr(*valStr, v)
+	if err != nil {
+		return 0, envVarError(err, envVarName)
+	}
+	return val, nil
+}
+
+// ParseByteSize returns the number of bytes, and whether the value was a rate (i.e. had a "/s" suffix).
+// It doesn't apply the range checks or Validator
+func ParseByteSize(valStr string, v *ByteSizeValidation) (int64, bool, error) {
+	str := strings.TrimSpace(valStr)
+
+	negative := false
+	if strings.HasPrefix(str, "+") || strings.HasPrefix(str, "-") {
+		if !v.AllowSign {
+			return 0, false, errors.NewUserCoded(s.MsgByteSizeSignNotAllowed, s.ErrByteSizeSignNotAllowed(valStr))
+		}
+		negative = str[0] == '-'
+		str = str[1:]
+	}
+
+	perSecond := false
+	if strings.HasSuffix(str, byteSizeRateSuffix) {
+		if !v.AllowRate {
+			return 0, false, errors.NewUserCoded(s.MsgByteSizeRateNotAllowed, s.ErrByteSizeRateNotAllowed(valStr))
+		}
+		perSecond = true
+		str = strings.TrimSuffix(str, byteSizeRateSuffix)
+	}
+
+	numEnd := strings.IndexFunc(str, func(char rune) bool {
+		return !(char >= '0' && char <= '9' || char == '.')
+	})
+	if numEnd == -1 {
+		numEnd = len(str)
+	}
+	numStr, unit := str[:numEnd], strings.TrimSpace(str[numEnd:])
+
+	num, ok := new(big.Rat).SetString(numStr)
+	if numStr == "" || strings.Count(numStr, ".") > 1 || !ok {
+		return 0, false, errors.NewUserCoded(s.MsgInvalidByteSize, s.ErrInvalidByteSize(valStr))
+	}
+
+	multiplier, ok := byteSizeMultipliers[unit]
+	if !ok {
+		return 0, false, errors.NewUserCoded(s.MsgInvalidByteSizeUnit, s.ErrInvalidByteSizeUnit(valStr, unit, byteSizeUnits))
+	}
+
+	num.Mul(num, new(big.Rat).SetInt64(multiplier))
+	if !num.IsInt() {
+		return 0, false, errors.NewUserCoded(s.MsgByteSizeNotWhole, s.ErrByteSizeNotWhole(valStr))
+	}
+	if negative {
+		num.Neg(num)
+	}
+	if !num.Num().IsInt64() {
+		return 0, false, errors.NewUserCoded(s.MsgIntOutOfRange, s.ErrIntOutOfRange(valStr, 64))
+	}
+	return num.Num().Int64(), perSecond, nil
+}
+
+func ValidateByteSizeMissing(v *ByteSizeValidation) (int64, error) {
+	if v.Required {
+		return 0, errors.Wrap(ErrRequired)
+	}
+	return ValidateByteSize(v.Default, v)
+}
+
+func ValidateByteSize(val int64, v *ByteSizeValidation) (int64, error) {
+	if v.GreaterThanOrEqualTo != nil {
+		if val < *v.GreaterThanOrEqualTo {
+			return 0, errors.Wrap(&OutOfRangeError{Val: val, Bound: *v.GreaterThanOrEqualTo, Op: ">="})
+		}
+	}
+	if v.LessThanOrEqualTo != nil {
+		if val > *v.LessThanOrEqualTo {
+			return 0, errors.Wrap(&OutOfRangeError{Val: val, Bound: *v.LessThanOrEqualTo, Op: "<="})
+		}
+	}
+
+	if v.Validator != nil {
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
+	}
+	return val, nil
+}
+
+//
+// Musts
+//
+
+func MustByteSizeFromStr(valStr string, v *ByteSizeValidation) int64 {
+	val, err := ByteSizeFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustByteSizeFromEnv(envVarName string, v *ByteSizeValidation) int64 {
+	val, err := ByteSizeFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+var byteSizeUnits = []string{"B", "K", "KB", "M", "MB", "G", "GB", "T", "TB", "P", "PB", "E", "EB", "Ki", "KiB", "Mi", "MiB", "Gi", "GiB", "Ti", "TiB", "Pi", "PiB", "Ei", "EiB"}
+
+func TestByteSize(t *testing.T) {
+	for valStr, expected := range map[string]int64{
+		"1024":    1024,
+		"1024B":   1024,
+		"5K":      5000,
+		"5kB":     5000,
+		"1.5GB":   1500000000,
+		"512Mi":   512 << 20,
+		"512 MiB": 512 << 20,
+		"0.5Ki":   512,
+		"7Ei":     7 << 60,
+	} {
+		val, err := cr.ByteSizeFromStr(valStr, &cr.ByteSizeValidation{})
+		require.NoError(t, err, valStr)
+		require.Equal(t, expected, val, valStr)
+	}
+
+	_, err := cr.ByteSizeFromStr("8Ei", &cr.ByteSizeValidation{})
+	require.EqualError(t, err, s.ErrIntOutOfRange("8Ei", 64))
+
+	val, err := cr.ByteSize(2048, &cr.ByteSizeValidation{})
+	require.NoError(t, err)
+	require.Equal(t, int64(2048), val)
+
+	_, err = cr.ByteSizeFromStr("5XB", &cr.ByteSizeValidation{})
+	require.EqualError(t, err, s.ErrInvalidByteSizeUnit("5XB", "XB", byteSizeUnits))
+
+	_, err = cr.ByteSizeFromStr("Mi", &cr.ByteSizeValidation{})
+	require.EqualError(t, err, s.ErrInvalidByteSize("Mi"))
+
+	_, err = cr.ByteSizeFromStr("1.2.3Mi", &cr.ByteSizeValidation{})
+	require.EqualError(t, err, s.ErrInvalidByteSize("1.2.3Mi"))
+
+	_, err = cr.ByteSizeFromStr("1.5B", &cr.ByteSizeValidation{})
+	require.EqualError(t, err, s.ErrByteSizeNotWhole("1.5B"))
+
+	_, err = cr.ByteSizeFromInterfaceMap("max_size", map[string]interface{}{"max_size": 1.5}, &cr.ByteSizeValidation{})
+	require.Error(t, err)
+
+	_, err = cr.ByteSizeFromStr("2Gi", &cr.ByteSizeValidation{LessThanOrEqualTo: util.Int64Ptr(1 << 30)})
+	require.Error(t, err)
+}
+
+func TestByteSizeSignAndRate(t *testing.T) {
+	// plain sizes are strict
+	_, err := cr.ByteSizeFromStr("-100Mi", &cr.ByteSizeValidation{})
+	require.EqualError(t, err, s.ErrByteSizeSignNotAllowed("-100Mi"))
+	_, err = cr.ByteSizeFromStr("+5MB", &cr.ByteSizeValidation{})
+	require.EqualError(t, err, s.ErrByteSizeSignNotAllowed("+5MB"))
+	_, err = cr.ByteSize(-5, &cr.ByteSizeValidation{})
+	require.EqualError(t, err, s.ErrByteSizeSignNotAllowed("-5"))
+	_, err = cr.ByteSizeFromStr("5MB/s", &cr.ByteSizeValidation{})
+	require.EqualError(t, err, s.ErrByteSizeRateNotAllowed("5MB/s"))
+
+	// signed
+	signed := &cr.ByteSizeValidation{AllowSign: true}
+	val, err := cr.ByteSizeFromStr("-100Mi", signed)
+	require.NoError(t, err)
+	require.Equal(t, int64(-100<<20), val)
+	val, err = cr.ByteSizeFromStr("+5MB", signed)
+	require.NoError(t, err)
+	require.Equal(t, int64(5e6), val)
+	val, err = cr.ByteSize(-5, signed)
+	require.NoError(t, err)
+	require.Equal(t, int64(-5), val)
+	_, err = cr.ByteSizeFromStr("--5MB", signed)
+	require.EqualError(t, err, s.ErrInvalidByteSize("--5MB"))
+	_, err = cr.ByteSizeFromStr("-5MB/s", signed)
+	require.EqualError(t, err, s.ErrByteSizeRateNotAllowed("-5MB/s"))
+
+	// rates
+	rate := &cr.ByteSizeValidation{AllowSign: true, AllowRate: true}
+	val, perSecond, err := cr.ParseByteSize("+5MB/s", rate)
+	require.NoError(t, err)
+	require.Equal(t, int64(5e6), val)
+	require.True(t, perSecond)
+	val, perSecond, err = cr.ParseByteSize("5MB", rate)
+	require.NoError(t, err)
+	require.Equal(t, int64(5e6), val)
+	require.False(t, perSecond)
+	val, err = cr.ByteSizeFromStr("1Gi/s", rate)
+	require.NoError(t, err)
+	require.Equal(t, int64(1<<30), val)
+	_, err = cr.ByteSizeFromStr("5XB/s", rate)
+	require.EqualError(t, err, s.ErrInvalidByteSizeUnit("5XB/s", "XB", byteSizeUnits))
+}
+
+func TestByteSizeValueUnitMap(t *testing.T) {
+	v := &cr.ByteSizeValidation{AllowValueUnitMap: true}
+	iMap := map[string]interface{}{
+		"scalar":       "512Mi",
+		"map":          map[interface{}]interface{}{"value": 512, "unit": "Mi"},
+		"no_unit":      map[string]interface{}{"value": 1024},
+		"fraction":     map[string]interface{}{"value": 1.5, "unit": "GB"},
+		"bad_unit":     map[string]interface{}{"value": 512, "unit": "Xi"},
+		"numeric_unit": map[string]interface{}{"value": 512, "unit": "5Mi"},
+		"bad_value":    map[string]interface{}{"value": "512", "unit": "Mi"},
+		"no_value":     map[string]interface{}{"unit": "Mi"},
+		"extra_key":    map[string]interface{}{"value": 512, "unit": "Mi", "units": "Gi"},
+		"negative":     map[string]interface{}{"value": -512, "unit": "Mi"},
+		"list":         []interface{}{512, "Mi"},
+	}
+
+	for key, expected := range map[string]int64{
+		"scalar":   512 << 20,
+		"map":      512 << 20,
+		"no_unit":  1024,
+		"fraction": 1500000000,
+	} {
+		val, err := cr.ByteSizeFromInterfaceMap(key, iMap, v)
+		require.NoError(t, err, key)
+		require.Equal(t, expected, val, key)
+	}
+
+	_, err := cr.ByteSizeFromInterfaceMap("bad_unit", iMap, v)
+	require.EqualError(t, err, "bad_unit: unit: "+s.ErrInvalidByteSizeUnit("512Xi", "Xi", byteSizeUnits))
+	_, err = cr.ByteSizeFromInterfaceMap("numeric_unit", iMap, v)
+	require.EqualError(t, err, "numeric_unit: unit: "+s.ErrInvalidByteSizeUnit("5125Mi", "5Mi", byteSizeUnits))
+	_, err = cr.ByteSizeFromInterfaceMap("bad_value", iMap, v)
+	require.EqualError(t, err, "bad_value: value: "+s.ErrInvalidPrimitiveType("512", s.PrimTypeInt, s.PrimTypeFloat))
+	_, err = cr.ByteSizeFromInterfaceMap("no_value", iMap, v)
+	require.EqualError(t, err, "no_value: value: "+cr.ErrRequired.Error())
+	_, err = cr.ByteSizeFromInterfaceMap("extra_key", iMap, v)
+	require.EqualError(t, err, "extra_key: "+s.ErrUnsupportedKey("units"))
+	_, err = cr.ByteSizeFromInterfaceMap("negative", iMap, v)
+	require.EqualError(t, err, "negative: "+s.ErrByteSizeSignNotAllowed("-512Mi"))
+	_, err = cr.ByteSizeFromInterfaceMap("list", iMap, v)
+	require.EqualError(t, err, "list: "+s.ErrInvalidPrimitiveType([]interface{}{512, "Mi"}, s.PrimTypeString, s.PrimTypeInt, s.PrimTypeMap))
+
+	// maps are opt-in
+	_, err = cr.ByteSizeFromInterfaceMap("map", iMap, &cr.ByteSizeValidation{})
+	require.Error(t, err)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// configFile is a parsed YAML or JSON file, along with the position of each key and list element in it
+type configFile struct {
+	path      string
+	parsed    interface{}
+	positions map[string]errors.Position // by key path (as rendered by errors.Path.String(), e.g. "pools[1].size")
+}
+
+func readConfigFile(filePath string) (*configFile, error) {
+	fileBytes, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, errors.WrapCoded(fileError(err), s.MsgRead, filePath, s.ErrRead)
+	}
+
+	var parsed interface{}
+	if strings.ToLower(filepath.Ext(filePath)) == ".json" {
+		parsed, err = ReadJSONBytes(fileBytes)
+	} else {
+		parsed, err = ReadYAMLBytes(fileBytes)
+	}
+	if err != nil {
+		return nil, valueFileError(err, filePath, 0)
+	}
+
+	file := &configFile{path: filePath, parsed: parsed, positions: map[string]errors.Position{}}
+	var root yamlv3.Node
+	if yamlv3.Unmarshal(fileBytes, &root) == nil {
+		file.addPositions(&root, nil)
+	}
+
+	// nested maps are converted up front, so that each reader doesn't have to
+	normalized, err := cast.InterfaceToStrInterfaceMapRecursiveErr(parsed)
+	if keyErr, ok := err.(*cast.InvalidMapKeyError); ok {
+		pos, ok := file.positions[keyErr.Path]
+		if !ok {
+			pos = errors.Position{File: filePath}
+		}
+		return nil, errors.WithSource(errors.WithPosition(errors.NewUser(keyErr.Error()), pos), filePath)
+	}
+	if err == nil && parsed != nil {
+		file.parsed = normalized
+	}
+	return file, nil
+}
+
+func (file *configFile) addPositions(node *yamlv3.Node, path errors.Path) {
+	switch node.Kind {
+	case yamlv3.DocumentNode:
+		for _, child := range node.Content {
+			file.addPositions(child, path)
+		}
+	case yamlv3.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			keyNode, valNode := node.Content[i], node.Content[i+1]
+			childPath := append(append(errors.Path{}, path...), errors.PathElement{Key: keyNode.Value})
+			file.positions[childPath.String()] = errors.Position{File: file.path, Line: keyNode.Line, Col: keyNode.Column}
+			file.addPositions(valNode, childPath)
+		}
+	case yamlv3.SequenceNode:
+		for i, elemNode := range node.Content {
+			childPath := append(append(errors.Path{}, path...), errors.PathElement{Index: i, IsIndex: true})
+			file.positions[childPath.String()] = errors.Position{File: file.path, Line: elemNode.Line, Col: elemNode.Column}
+			file.addPositions(elemNode, childPath)
+		}
+	}
+}
+
+// position returns the position of the deepest element of path which is in the file (or just the file, if none of them are)
+func (file *configFile) position(path errors.Path) errors.Position {
+	for i := len(path); i > 0; i-- {
+		if pos, ok := file.positions[path[:i].String()]; ok {
+			return pos
+		}
+	}
+	return errors.Position{File: file.path}
+}
+
+// withPosition adds the position of err's key path to err (or to each of the errors in it, if it's an *ErrorCollector)
+func (file *configFile) withPosition(err error) error {
+	if collected, ok := err.(*ErrorCollector); ok {
+		ec := &ErrorCollector{}
+		for _, collectedErr := range collected.Errors() {
+			ec.Add(file.withPosition(collectedErr))
+		}
+		return ec.Err()
+	}
+	if err == nil {
+		return nil
+	}
+	return errors.WithSource(errors.WithPosition(err, file.position(errors.KeyPath(err))), file.path)
+}
+
+func (file *configFile) interfaceMap() (map[string]interface{}, error) {
+	if file.parsed == nil {
+		return nil, nil
+	}
+	iMap, ok := cast.InterfaceToStrInterfaceMap(file.parsed)
+	if !ok {
+		return nil, valueFileError(errors.Wrap(&InvalidTypeError{Provided: file.parsed, Expected: []s.PrimitiveType{s.PrimTypeMap}}), file.path, 1)
+	}
+	return iMap, nil
+}
+
+// StructFromConfigFile reads a YAML or JSON file (depending on its extension) into dest (see Struct()). Errors include
+// the position of the value which caused them, e.g. "cluster.yaml:14:3: max_replicas: must be less than or equal to 100"
+func StructFromConfigFile(dest interface{}, filePath string, v *StructValidation) []error {
+	file, err := readConfigFile(filePath)
+	if err != nil {
+		return []error{err}
+	}
+	errs := Struct(dest, file.parsed, v)
+	for i, err := range errs {
+		errs[i] = file.withPosition(err)
+	}
+	return errs
+}
+
+// IntFromConfigFile reads key from a YAML or JSON file (depending on its extension); errors include the value's position
+func IntFromConfigFile(filePath string, key string, v *IntValidation) (int, error) {
+	file, err := readConfigFile(filePath)
+	if err != nil {
+		return 0, err
+	}
+	iMap, err := file.interfaceMap()
+	if err != nil {
+		return 0, err
+	}
+	val, err := IntFromInterfaceMap(key, iMap, v)
+	if err != nil {
+		return 0, file.withPosition(err)
+	}
+	return val, nil
+}
+
+// StringFromConfigFile reads key from a YAML or JSON file (depending on its extension); errors include the value's position
+func StringFromConfigFile(filePath string, key string, v *StringValidation) (string, error) {
+	file, err := readConfigFile(filePath)
+	if err != nil {
+		return "", err
+	}
+	iMap, err := file.interfaceMap()
+	if err != nil {
+		return "", err
+	}
+	val, err := StringFromInterfaceMap(key, iMap, v)
+	if err != nil {
+		return "", file.withPosition(err)
+	}
+	return val, nil
+}
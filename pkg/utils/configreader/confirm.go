@@ -0,0 +1,105 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+type ConfirmOptions struct {
+	Question             string
+	DefaultYes           bool
+	RequireExplicit      bool      // ignore the default, so that e.g. destructive operations need a typed answer
+	FailOnNonInteractive bool      // return an error instead of the default when running non-interactively (see IsNonInteractive())
+	MaxAttempts          int       // defaults to DefaultPromptMaxAttempts
+	In                   io.Reader // defaults to stdin
+	Out                  io.Writer // defaults to stdout
+}
+
+// Confirm asks a yes/no question, e.g. "Delete deployment 'iris'? [y/N]"
+func Confirm(question string, defaultYes bool) bool {
+	val, err := ConfirmWithOptions(&ConfirmOptions{
+		Question:   question,
+		DefaultYes: defaultYes,
+	})
+	if err != nil {
+		errors.Panic(err)
+	}
+	return val
+}
+
+func ConfirmWithOptions(opts *ConfirmOptions) (bool, error) {
+	switch GetPromptPolicy() {
+	case PromptAcceptDefaults:
+		return true, nil
+	case PromptFailIfNeeded:
+		return false, errors.NewUserCoded(s.MsgPromptNeeded, s.ErrPromptNeeded(opts.Question, ""))
+	}
+
+	if opts.In == nil && IsNonInteractive() {
+		if opts.FailOnNonInteractive || opts.RequireExplicit {
+			return false, errors.NewUserCoded(s.MsgPromptNotInteractive, s.ErrPromptNotInteractive)
+		}
+		return opts.DefaultYes, nil
+	}
+
+	promptOpts := &PromptOptions{
+		Prompt:      fmt.Sprintf("%s %s", opts.Question, confirmHint(opts)),
+		MaxAttempts: opts.MaxAttempts,
+		In:          opts.In,
+		Out:         opts.Out,
+	}
+
+	var val bool
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		if valStr == "" && !opts.RequireExplicit {
+			val = opts.DefaultYes
+			return nil
+		}
+		var ok bool
+		if val, ok = parseYesNo(valStr); !ok {
+			return errors.NewUserCoded(s.MsgNotAllowedValue, s.ErrInvalidStr(valStr, "y", "yes", "n", "no"))
+		}
+		return nil
+	})
+	return val, err
+}
+
+func confirmHint(opts *ConfirmOptions) string {
+	if opts.RequireExplicit {
+		return "[y/n]"
+	}
+	if opts.DefaultYes {
+		return "[Y/n]"
+	}
+	return "[y/N]"
+}
+
+func parseYesNo(valStr string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(valStr)) {
+	case "y", "yes":
+		return true, true
+	case "n", "no":
+		return false, true
+	}
+	return false, false
+}
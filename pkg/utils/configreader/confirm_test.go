@@ -0,0 +1,44 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+)
+
+func TestConfirmNonInteractive(t *testing.T) {
+	cr.SetNonInteractive(true)
+	defer cr.ResetNonInteractive()
+
+	require.True(t, cr.Confirm("Deploy?", true))
+	require.False(t, cr.Confirm("Delete deployment 'iris'?", false))
+
+	val, err := cr.ConfirmWithOptions(&cr.ConfirmOptions{Question: "Deploy?", DefaultYes: true})
+	require.NoError(t, err)
+	require.True(t, val)
+
+	_, err = cr.ConfirmWithOptions(&cr.ConfirmOptions{Question: "Deploy?", DefaultYes: true, FailOnNonInteractive: true})
+	require.EqualError(t, err, s.ErrPromptNotInteractive)
+
+	_, err = cr.ConfirmWithOptions(&cr.ConfirmOptions{Question: "Delete deployment 'iris'?", RequireExplicit: true})
+	require.EqualError(t, err, s.ErrPromptNotInteractive)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ConstraintHint describes the values which a validation accepts, e.g. "1–64" or "debug|info|warn|error"
+// (an empty string is returned if there is nothing to describe)
+func ConstraintHint(v interface{}) string {
+	switch casted := v.(type) {
+	case *BoolValidation, *BoolPtrValidation:
+		return "y/n"
+	case *LevelValidation:
+		return strings.Join(casted.Levels, "|")
+	}
+
+	value := reflect.ValueOf(v)
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return ""
+	}
+
+	if allowedValues := value.FieldByName("AllowedValues"); allowedValues.IsValid() && allowedValues.Len() > 0 {
+		allowedStrs := make([]string, allowedValues.Len())
+		for i := range allowedStrs {
+			allowedStrs[i] = fmt.Sprint(allowedValues.Index(i).Interface())
+		}
+		return strings.Join(allowedStrs, "|")
+	}
+
+	greaterThan := boundStr(value, "GreaterThan")
+	greaterThanOrEqualTo := boundStr(value, "GreaterThanOrEqualTo")
+	lessThan := boundStr(value, "LessThan")
+	lessThanOrEqualTo := boundStr(value, "LessThanOrEqualTo")
+
+	if greaterThanOrEqualTo != "" && lessThanOrEqualTo != "" && greaterThan == "" && lessThan == "" {
+		return greaterThanOrEqualTo + "–" + lessThanOrEqualTo
+	}
+
+	var bounds []string
+	if greaterThan != "" {
+		bounds = append(bounds, "> "+greaterThan)
+	}
+	if greaterThanOrEqualTo != "" {
+		bounds = append(bounds, ">= "+greaterThanOrEqualTo)
+	}
+	if lessThan != "" {
+		bounds = append(bounds, "< "+lessThan)
+	}
+	if lessThanOrEqualTo != "" {
+		bounds = append(bounds, "<= "+lessThanOrEqualTo)
+	}
+	return strings.Join(bounds, ", ")
+}
+
+// boundStr returns the value of a bound field like GreaterThan, or an empty string if it's not set
+func boundStr(value reflect.Value, fieldName string) string {
+	field := value.FieldByName(fieldName)
+	if !field.IsValid() || field.Kind() != reflect.Ptr || field.IsNil() {
+		return ""
+	}
+	return fmt.Sprint(field.Elem().Interface())
+}
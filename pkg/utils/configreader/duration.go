@@ -0,0 +1,138 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"strings"
+	"time"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+type DurationValidation struct {
+	Required             bool
+	Default              time.Duration
+	BareIntUnit          time.Duration // the unit of integers without a unit (e.g. time.Second for 30); if 0, integers are rejected
+	GreaterThanOrEqualTo *time.Duration
+	LessThanOrEqualTo    *time.Duration
+	Validator            func(time.Duration) (time.Duration, error)
+}
+
+func Duration(inter interface{}, v *DurationValidation) (time.Duration, error) {
+	if inter == nil {
+		return 0, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+	}
+	if casted, ok := inter.(string); ok {
+		return DurationFromStr(casted, v)
+	}
+	casted, ok := cast.InterfaceToDuration(inter, v.BareIntUnit)
+	if !ok {
+		return 0, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeDuration}})
+	}
+	return ValidateDuration(casted, v)
+}
+
+func DurationFromInterfaceMap(key string, iMap map[string]interface{}, v *DurationValidation) (time.Duration, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateDurationMissing(v)
+		if err != nil {
+			return 0, errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := Duration(inter, v)
+	if err != nil {
+		return 0, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func DurationFromStr(valStr string, v *DurationValidation) (time.Duration, error) {
+	if strings.TrimSpace(valStr) == "" {
+		return ValidateDurationMissing(v)
+	}
+	casted, ok := cast.InterfaceToDuration(valStr, v.BareIntUnit)
+	if !ok {
+		return 0, errors.NewUserCoded(s.MsgInvalidDuration, s.ErrInvalidDuration(valStr))
+	}
+	return ValidateDuration(casted, v)
+}
+
+func DurationFromEnv(envVarName string, v *DurationValidation) (time.Duration, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateDurationMissing(v)
+		if err != nil {
+			return 0, envVarError(err, envVarName)
+		}
+		return val, nil
+	}
+	val, err := DurationFromStr(*valStr, v)
+	if err != nil {
+		return 0, envVarError(err, envVarName)
+	}
+	return val, nil
+}
+
+func ValidateDurationMissing(v *DurationValidation) (time.Duration, error) {
+	if v.Required {
+		return 0, errors.Wrap(ErrRequired)
+	}
+	return ValidateDuration(v.Default, v)
+}
+
+func ValidateDuration(val time.Duration, v *DurationValidation) (time.Duration, error) {
+	if v.GreaterThanOrEqualTo != nil {
+		if val < *v.GreaterThanOrEqualTo {
+			return 0, errors.Wrap(&OutOfRangeError{Val: val, Bound: *v.GreaterThanOrEqualTo, Op: ">="})
+		}
+	}
+	if v.LessThanOrEqualTo != nil {
+		if val > *v.LessThanOrEqualTo {
+			return 0, errors.Wrap(&OutOfRangeError{Val: val, Bound: *v.LessThanOrEqualTo, Op: "<="})
+		}
+	}
+
+	if v.Validator != nil {
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
+	}
+	return val, nil
+}
+
+//
+// Musts
+//
+
+func MustDurationFromStr(valStr string, v *DurationValidation) time.Duration {
+	val, err := DurationFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustDurationFromEnv(envVarName string, v *DurationValidation) time.Duration {
+	val, err := DurationFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+)
+
+func TestDuration(t *testing.T) {
+	iMap := map[string]interface{}{
+		"int":      30,
+		"str":      "45s",
+		"int_str":  "60",
+		"typed":    2 * time.Minute,
+		"json":     json.Number("10"),
+		"float":    1.5,
+		"invalid":  "45 parsecs",
+		"overflow": int64(1) << 40,
+	}
+	v := &cr.DurationValidation{BareIntUnit: time.Second}
+
+	for key, expected := range map[string]time.Duration{
+		"int":     30 * time.Second,
+		"str":     45 * time.Second,
+		"int_str": 60 * time.Second,
+		"typed":   2 * time.Minute,
+		"json":    10 * time.Second,
+		"missing": 0,
+	} {
+		val, err := cr.DurationFromInterfaceMap(key, iMap, v)
+		require.NoError(t, err, key)
+		require.Equal(t, expected, val, key)
+	}
+
+	_, err := cr.DurationFromInterfaceMap("invalid", iMap, v)
+	require.EqualError(t, err, "invalid: "+s.ErrInvalidDuration("45 parsecs"))
+
+	_, err = cr.DurationFromInterfaceMap("float", iMap, v)
+	require.EqualError(t, err, "float: "+s.ErrInvalidPrimitiveType(1.5, s.PrimTypeDuration))
+
+	_, err = cr.DurationFromInterfaceMap("overflow", iMap, v)
+	require.Error(t, err)
+
+	// bare integers need a unit
+	_, err = cr.DurationFromInterfaceMap("int", iMap, &cr.DurationValidation{})
+	require.Error(t, err)
+	_, err = cr.DurationFromStr("60", &cr.DurationValidation{})
+	require.EqualError(t, err, s.ErrInvalidDuration("60"))
+
+	max := time.Minute
+	_, err = cr.DurationFromStr("90s", &cr.DurationValidation{LessThanOrEqualTo: &max})
+	require.Error(t, err)
+}
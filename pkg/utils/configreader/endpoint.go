@@ -0,0 +1,218 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+// EndpointValue is a parsed endpoint, e.g. "https://api.example.com:443" or "[::1]:8080"
+type EndpointValue struct {
+	Scheme string // lowercase, and empty if none was provided
+	Host   string // a lowercase hostname, or an IP address (without brackets)
+	Port   int    // 0 if none was provided and there is no DefaultPort
+}
+
+// String returns the normalized endpoint (IPv6 addresses are bracketed)
+func (val EndpointValue) String() string {
+	str := val.Host
+	if val.Port != 0 {
+		str = net.JoinHostPort(val.Host, strconv.Itoa(val.Port))
+	} else if strings.Contains(val.Host, ":") {
+		str = "[" + val.Host + "]"
+	}
+	if val.Scheme != "" {
+		str = val.Scheme + "://" + str
+	}
+	return str
+}
+
+// EndpointValidation reads "<host>:<port>" endpoints, where the host is a hostname or an IP address (IPv6 addresses
+// must be bracketed if there is a port, e.g. "[::1]:8080")
+type EndpointValidation struct {
+	Required       bool
+	Default        string   // parsed like a provided value
+	RequirePort    bool     // a port must be provided (unless there is a DefaultPort)
+	DefaultPort    int      // used if no port is provided
+	AllowedSchemes []string // e.g. "http" or "grpc"; if set, a scheme can be provided (e.g. "http://localhost:8080")
+	Validator      func(EndpointValue) (EndpointValue, error)
+}
+
+func Endpoint(inter interface{}, v *EndpointValidation) (EndpointValue, error) {
+	if inter == nil {
+		return EndpointValue{}, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+	}
+	casted, castOk := cast.InterfaceToStr(inter)
+	if !castOk {
+		return EndpointValue{}, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeString}})
+	}
+	return EndpointFromStr(casted, v)
+}
+
+func EndpointFromInterfaceMap(key string, iMap map[string]interface{}, v *EndpointValidation) (EndpointValue, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateEndpointMissing(v)
+		if err != nil {
+			return EndpointValue{}, errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := Endpoint(inter, v)
+	if err != nil {
+		return EndpointValue{}, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func EndpointFromStr(valStr string, v *EndpointValidation) (EndpointValue, error) {
+	if strings.TrimSpace(valStr) == "" {
+		return ValidateEndpointMissing(v)
+	}
+	val, err := ParseEndpoint(valStr, v)
+	if err != nil {
+		return EndpointValue{}, err
+	}
+	return ValidateEndpoint(val, v)
+}
+
+func EndpointFromEnv(envVarName string, v *EndpointValidation) (EndpointValue, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateEndpointMissing(v)
+		if err != nil {
+			return EndpointValue{}, envVarError(err, envVarName)
+		}
+		return val, nil
+	}
+	val, err := EndpointFromStr(*valStr, v)
+	if err != nil {
+		return EndpointValue{}, envVarError(err, envVarName)
+	}
+	return val, nil
+}
+
+// ParseEndpoint parses and normalizes an endpoint, and applies DefaultPort. It doesn't apply the Validator
+func ParseEndpoint(valStr string, v *EndpointValidation) (EndpointValue, error) {
+	str := strings.TrimSpace(valStr)
+	var val EndpointValue
+
+	if i := strings.Index(str, "://"); i != -1 {
+		val.Scheme = strings.ToLower(str[:i])
+		if !util.IsStrInSlice(val.Scheme, v.AllowedSchemes) {
+			return EndpointValue{}, errors.NewUserCoded(s.MsgEndpointSchemeNotAllowed, s.ErrEndpointSchemeNotAllowed(valStr, str[:i], v.AllowedSchemes))
+		}
+		str = str[i+len("://"):]
+	}
+
+	host, portStr, hasPort, bracketed := splitEndpoint(str)
+
+	if ip := net.ParseIP(host); ip != nil && (!bracketed || ip.To4() == nil) {
+		val.Host = ip.String()
+	} else if !bracketed && util.CheckHostname(host) {
+		val.Host = strings.ToLower(host)
+	} else {
+		return EndpointValue{}, errors.NewUserCoded(s.MsgInvalidEndpointHost, s.ErrInvalidEndpointHost(valStr, host))
+	}
+
+	if hasPort {
+		port, ok := s.ParseInt(portStr)
+		if !ok || port < 1 || port > 65535 {
+			return EndpointValue{}, errors.NewUserCoded(s.MsgInvalidEndpointPort, s.ErrInvalidEndpointPort(valStr, portStr))
+		}
+		val.Port = port
+	} else if v.DefaultPort != 0 {
+		val.Port = v.DefaultPort
+	} else if v.RequirePort {
+		return EndpointValue{}, errors.NewUserCoded(s.MsgEndpointMissingPort, s.ErrEndpointMissingPort(valStr))
+	}
+
+	return val, nil
+}
+
+// splitEndpoint splits "<host>:<port>", "[<ipv6>]:<port>", "[<ipv6>]", or "<host>" (which may be an unbracketed IPv6 address)
+func splitEndpoint(str string) (host string, port string, hasPort bool, bracketed bool) {
+	if strings.HasPrefix(str, "[") {
+		end := strings.Index(str, "]")
+		if end == -1 {
+			return str, "", false, false
+		}
+		rest := str[end+1:]
+		if rest == "" {
+			return str[1:end], "", false, true
+		}
+		if !strings.HasPrefix(rest, ":") {
+			return str, "", false, false
+		}
+		return str[1:end], rest[1:], true, true
+	}
+
+	if strings.Count(str, ":") != 1 {
+		return str, "", false, false
+	}
+	i := strings.Index(str, ":")
+	return str[:i], str[i+1:], true, false
+}
+
+func ValidateEndpointMissing(v *EndpointValidation) (EndpointValue, error) {
+	if v.Required {
+		return EndpointValue{}, errors.Wrap(ErrRequired)
+	}
+	if v.Default == "" {
+		return EndpointValue{}, nil
+	}
+	val, err := ParseEndpoint(v.Default, v)
+	if err != nil {
+		return EndpointValue{}, err
+	}
+	return ValidateEndpoint(val, v)
+}
+
+func ValidateEndpoint(val EndpointValue, v *EndpointValidation) (EndpointValue, error) {
+	if v.Validator != nil {
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
+	}
+	return val, nil
+}
+
+//
+// Musts
+//
+
+func MustEndpointFromStr(valStr string, v *EndpointValidation) EndpointValue {
+	val, err := EndpointFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustEndpointFromEnv(envVarName string, v *EndpointValidation) EndpointValue {
+	val, err := EndpointFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+)
+
+func TestEndpoint(t *testing.T) {
+	v := &cr.EndpointValidation{}
+
+	for str, expected := range map[string]cr.EndpointValue{
+		"localhost:8080":          {Host: "localhost", Port: 8080},
+		" API.Example.com:443 ":   {Host: "api.example.com", Port: 443},
+		"example.com":             {Host: "example.com"},
+		"10.0.0.1:5432":           {Host: "10.0.0.1", Port: 5432},
+		"10.0.0.1":                {Host: "10.0.0.1"},
+		"[::1]:8080":              {Host: "::1", Port: 8080},
+		"[2001:DB8::1]":           {Host: "2001:db8::1"},
+		"2001:db8::1":             {Host: "2001:db8::1"},
+		"my-service.default.svc.": {Host: "my-service.default.svc."},
+	} {
+		val, err := cr.EndpointFromStr(str, v)
+		require.NoError(t, err, str)
+		require.Equal(t, expected, val, str)
+	}
+
+	for str, host := range map[string]string{
+		":8080":           "",
+		"-bad-.com:80":    "-bad-.com",
+		"under_score:80":  "under_score",
+		"[::1:8080":       "[::1:8080",
+		"[::1]8080":       "[::1]8080",
+		"[10.0.0.1]:80":   "10.0.0.1",
+		"[example.com]":   "example.com",
+		"1:2:3":           "1:2:3",
+		"a..b:80":         "a..b",
+		"host name:80":    "host name",
+		"http//host:8080": "http//host",
+	} {
+		_, err := cr.EndpointFromStr(str, v)
+		require.EqualError(t, err, s.ErrInvalidEndpointHost(str, host), str)
+	}
+
+	for str, port := range map[string]string{
+		"localhost:":       "",
+		"localhost:0":      "0",
+		"localhost:65536":  "65536",
+		"localhost:http":   "http",
+		"localhost:80/api": "80/api",
+		"[::1]:-1":         "-1",
+	} {
+		_, err := cr.EndpointFromStr(str, v)
+		require.EqualError(t, err, s.ErrInvalidEndpointPort(str, port), str)
+	}
+
+	_, err := cr.EndpointFromStr("localhost", &cr.EndpointValidation{RequirePort: true})
+	require.EqualError(t, err, s.ErrEndpointMissingPort("localhost"))
+
+	val, err := cr.EndpointFromStr("localhost", &cr.EndpointValidation{RequirePort: true, DefaultPort: 8888})
+	require.NoError(t, err)
+	require.Equal(t, cr.EndpointValue{Host: "localhost", Port: 8888}, val)
+
+	val, err = cr.EndpointFromStr("localhost:9000", &cr.EndpointValidation{DefaultPort: 8888})
+	require.NoError(t, err)
+	require.Equal(t, 9000, val.Port)
+
+	_, err = cr.EndpointFromStr("http://localhost:8080", v)
+	require.EqualError(t, err, s.ErrEndpointSchemeNotAllowed("http://localhost:8080", "http", nil))
+
+	schemeV := &cr.EndpointValidation{AllowedSchemes: []string{"http", "https"}}
+	val, err = cr.EndpointFromStr("HTTPS://[::1]:8443", schemeV)
+	require.NoError(t, err)
+	require.Equal(t, cr.EndpointValue{Scheme: "https", Host: "::1", Port: 8443}, val)
+	val, err = cr.EndpointFromStr("localhost:8080", schemeV)
+	require.NoError(t, err)
+	require.Equal(t, cr.EndpointValue{Host: "localhost", Port: 8080}, val)
+	_, err = cr.EndpointFromStr("grpc://localhost:8080", schemeV)
+	require.EqualError(t, err, s.ErrEndpointSchemeNotAllowed("grpc://localhost:8080", "grpc", schemeV.AllowedSchemes))
+
+	require.Equal(t, "https://[::1]:8443", cr.EndpointValue{Scheme: "https", Host: "::1", Port: 8443}.String())
+	require.Equal(t, "[::1]", cr.EndpointValue{Host: "::1"}.String())
+	require.Equal(t, "localhost:80", cr.EndpointValue{Host: "localhost", Port: 80}.String())
+	require.Equal(t, "localhost", cr.EndpointValue{Host: "localhost"}.String())
+}
+
+func TestEndpointReaders(t *testing.T) {
+	iMap := map[string]interface{}{"db": "db.internal:5432", "port_only": 5432}
+	v := &cr.EndpointValidation{Default: "localhost:5432"}
+
+	val, err := cr.EndpointFromInterfaceMap("db", iMap, v)
+	require.NoError(t, err)
+	require.Equal(t, "db.internal:5432", val.String())
+
+	val, err = cr.EndpointFromInterfaceMap("missing", iMap, v)
+	require.NoError(t, err)
+	require.Equal(t, cr.EndpointValue{Host: "localhost", Port: 5432}, val)
+
+	val, err = cr.EndpointFromInterfaceMap("missing", iMap, &cr.EndpointValidation{})
+	require.NoError(t, err)
+	require.Equal(t, cr.EndpointValue{}, val)
+
+	_, err = cr.EndpointFromInterfaceMap("missing", iMap, &cr.EndpointValidation{Required: true})
+	require.Error(t, err)
+
+	_, err = cr.EndpointFromInterfaceMap("port_only", iMap, v)
+	require.EqualError(t, err, "port_only: "+s.ErrInvalidPrimitiveType(5432, s.PrimTypeString))
+
+	os.Setenv("CR_TEST_ENDPOINT", "[fe80::1]:9090")
+	defer os.Unsetenv("CR_TEST_ENDPOINT")
+	val, err = cr.EndpointFromEnv("CR_TEST_ENDPOINT", v)
+	require.NoError(t, err)
+	require.Equal(t, cr.EndpointValue{Host: "fe80::1", Port: 9090}, val)
+
+	type config struct {
+		Endpoint cr.EndpointValue `json:"endpoint"`
+	}
+	dest := &config{}
+	errs := cr.Struct(dest, map[string]interface{}{"endpoint": "Redis:6379"}, &cr.StructValidation{
+		StructFieldValidations: []*cr.StructFieldValidation{
+			{
+				StructField:        "Endpoint",
+				EndpointValidation: &cr.EndpointValidation{RequirePort: true},
+			},
+		},
+	})
+	require.Empty(t, errs)
+	require.Equal(t, cr.EndpointValue{Host: "redis", Port: 6379}, dest.Endpoint)
+}
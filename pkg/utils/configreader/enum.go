@@ -0,0 +1,167 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"strconv"
+	"strings"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// EnumOption is one of the values of an enum, e.g. "auto" for a mode which can be on, off, or auto
+type EnumOption struct {
+	Name    string      // the canonical spelling, which is listed in errors
+	Aliases []string    // other spellings which are accepted, e.g. "enabled" for "on"
+	Value   interface{} // what's returned when the option is chosen (typically a typed constant)
+}
+
+// EnumValidation reads one of a small set of options. Names and aliases are matched case-insensitively, ignoring surrounding
+// whitespace. YAML parses unquoted on/off/yes/no/true/false as booleans; they're matched as "true" or "false"
+type EnumValidation struct {
+	Required bool
+	Default  string // the name or alias of an option
+	Options  []EnumOption
+}
+
+func Enum(inter interface{}, v *EnumValidation) (interface{}, error) {
+	if inter == nil {
+		return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+	}
+	var valStr string
+	switch casted := inter.(type) {
+	case string:
+		valStr = casted
+	case bool:
+		valStr = strconv.FormatBool(casted)
+	default:
+		casted64, ok := cast.InterfaceToInt64(inter)
+		if !ok {
+			return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeString}})
+		}
+		valStr = strconv.FormatInt(casted64, 10)
+	}
+	return ValidateEnum(valStr, v)
+}
+
+func EnumFromInterfaceMap(key string, iMap map[string]interface{}, v *EnumValidation) (interface{}, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateEnumMissing(v)
+		if err != nil {
+			return nil, errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := Enum(inter, v)
+	if err != nil {
+		return nil, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func EnumFromStr(valStr string, v *EnumValidation) (interface{}, error) {
+	if strings.TrimSpace(valStr) == "" {
+		return ValidateEnumMissing(v)
+	}
+	return ValidateEnum(valStr, v)
+}
+
+func EnumFromEnv(envVarName string, v *EnumValidation) (interface{}, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateEnumMissing(v)
+		if err != nil {
+			return nil, envVarError(err, envVarName)
+		}
+		return val, nil
+	}
+	val, err := EnumFromStr(*valStr, v)
+	if err != nil {
+		return nil, envVarError(err, envVarName)
+	}
+	return val, nil
+}
+
+func ValidateEnumMissing(v *EnumValidation) (interface{}, error) {
+	if v.Required {
+		return nil, errors.Wrap(ErrRequired)
+	}
+	return ValidateEnum(v.Default, v)
+}
+
+func ValidateEnum(val string, v *EnumValidation) (interface{}, error) {
+	normalized := strings.TrimSpace(val)
+	var tokens []string
+	for _, option := range v.Options {
+		for _, token := range append([]string{option.Name}, option.Aliases...) {
+			if strings.EqualFold(token, normalized) {
+				return option.Value, nil
+			}
+			tokens = append(tokens, token)
+		}
+	}
+
+	notAllowedErr := &NotAllowedValueError{Val: val, Allowed: v.OptionNames()}
+	if closest := s.SuggestClosest(normalized, tokens); closest != "" {
+		notAllowedErr.Suggestion = v.optionName(closest)
+	}
+	return nil, errors.Wrap(notAllowedErr)
+}
+
+// OptionNames returns the canonical name of each option
+func (v *EnumValidation) OptionNames() []string {
+	names := make([]string, len(v.Options))
+	for i, option := range v.Options {
+		names[i] = option.Name
+	}
+	return names
+}
+
+// optionName returns the canonical name of the option which token is the name or an alias of
+func (v *EnumValidation) optionName(token string) string {
+	for _, option := range v.Options {
+		for _, candidate := range append([]string{option.Name}, option.Aliases...) {
+			if strings.EqualFold(candidate, token) {
+				return option.Name
+			}
+		}
+	}
+	return ""
+}
+
+//
+// Musts
+//
+
+func MustEnumFromStr(valStr string, v *EnumValidation) interface{} {
+	val, err := EnumFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustEnumFromEnv(envVarName string, v *EnumValidation) interface{} {
+	val, err := EnumFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+)
+
+type Mode int
+
+const (
+	ModeOff Mode = iota
+	ModeOn
+	ModeAuto
+)
+
+var modeValidation = &cr.EnumValidation{
+	Default: "auto",
+	Options: []cr.EnumOption{
+		{Name: "on", Aliases: []string{"true", "enabled"}, Value: ModeOn},
+		{Name: "off", Aliases: []string{"false", "disabled"}, Value: ModeOff},
+		{Name: "auto", Value: ModeAuto},
+	},
+}
+
+func TestEnum(t *testing.T) {
+	val, err := cr.EnumFromStr(" Enabled ", modeValidation)
+	require.NoError(t, err)
+	require.Equal(t, ModeOn, val)
+
+	val, err = cr.EnumFromStr("", modeValidation)
+	require.NoError(t, err)
+	require.Equal(t, ModeAuto, val)
+
+	// YAML parses "off" as false
+	val, err = cr.EnumFromInterfaceMap("mode", cr.MustReadYAMLStrMap("mode: off"), modeValidation)
+	require.NoError(t, err)
+	require.Equal(t, ModeOff, val)
+
+	_, err = cr.EnumFromStr("disabeld", modeValidation)
+	require.EqualError(t, err, s.ErrNotAllowedValue("disabeld", []string{"on", "off", "auto"})+s.DidYouMean("off"))
+
+	_, err = cr.Enum(1.5, modeValidation)
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType(1.5, s.PrimTypeString))
+
+	_, err = cr.EnumFromInterfaceMap("mode", nil, &cr.EnumValidation{Required: true, Options: modeValidation.Options})
+	require.EqualError(t, err, "mode: "+s.MustBeDefined())
+}
+
+type ModeConfig struct {
+	Mode Mode `json:"mode"`
+}
+
+func TestEnumStructField(t *testing.T) {
+	structValidation := &cr.StructValidation{
+		StructFieldValidations: []*cr.StructFieldValidation{
+			{
+				StructField:    "Mode",
+				EnumValidation: modeValidation,
+			},
+		},
+	}
+
+	config := &ModeConfig{}
+	errs := cr.Struct(config, cr.MustReadYAMLStrMap("mode: AUTO"), structValidation)
+	require.Empty(t, errs)
+	require.Equal(t, ModeAuto, config.Mode)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"os"
+	"strings"
+	"sync"
+)
+
+// Env is a source of environment variables. A snapshot (from SnapshotEnv()) is a point-in-time copy
+// of the environment, so related keys read from it are always consistent with each other
+type Env struct {
+	live  bool
+	mutex sync.RWMutex
+	vars  map[string]string
+}
+
+var liveEnv = &Env{live: true}
+
+var (
+	envSnapshotMutex   sync.Mutex
+	envSnapshotEnabled bool
+	envSnapshot        *Env
+)
+
+func SnapshotEnv() *Env {
+	env := &Env{}
+	env.Refresh()
+	return env
+}
+
+// Refresh re-reads the environment into the snapshot (it has no effect on the live environment)
+func (env *Env) Refresh() {
+	if env.live {
+		return
+	}
+
+	vars := make(map[string]string)
+	for _, envVar := range os.Environ() {
+		split := strings.SplitN(envVar, "=", 2)
+		if len(split) == 2 {
+			vars[split[0]] = split[1]
+		}
+	}
+
+	env.mutex.Lock()
+	env.vars = vars
+	env.mutex.Unlock()
+}
+
+func (env *Env) ReadEnvVar(envVarName string) *string {
+	if env.live {
+		envVar, envVarIsSet := os.LookupEnv(envVarName)
+		if envVarIsSet {
+			return &envVar
+		}
+		return nil
+	}
+
+	env.mutex.RLock()
+	envVar, envVarIsSet := env.vars[envVarName]
+	env.mutex.RUnlock()
+	if envVarIsSet {
+		return &envVar
+	}
+	return nil
+}
+
+// SetUseEnvSnapshot controls whether the package-level *FromEnv functions read from the live environment (the default),
+// or from a snapshot which is taken the first time a value is read
+func SetUseEnvSnapshot(enabled bool) {
+	envSnapshotMutex.Lock()
+	defer envSnapshotMutex.Unlock()
+	envSnapshotEnabled = enabled
+	envSnapshot = nil
+}
+
+// DefaultEnv returns the Env used by the package-level *FromEnv functions
+func DefaultEnv() *Env {
+	envSnapshotMutex.Lock()
+	defer envSnapshotMutex.Unlock()
+	if !envSnapshotEnabled {
+		return liveEnv
+	}
+	if envSnapshot == nil {
+		envSnapshot = SnapshotEnv()
+	}
+	return envSnapshot
+}
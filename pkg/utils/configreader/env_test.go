@@ -0,0 +1,73 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+)
+
+func TestSnapshotEnv(t *testing.T) {
+	os.Setenv("CR_TEST_SNAPSHOT_INT", "1")
+	os.Setenv("CR_TEST_SNAPSHOT_STR", "a")
+	defer os.Unsetenv("CR_TEST_SNAPSHOT_INT")
+	defer os.Unsetenv("CR_TEST_SNAPSHOT_STR")
+
+	env := cr.SnapshotEnv()
+	os.Setenv("CR_TEST_SNAPSHOT_INT", "2")
+	os.Unsetenv("CR_TEST_SNAPSHOT_STR")
+
+	num, err := env.IntFromEnv("CR_TEST_SNAPSHOT_INT", &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 1, num)
+
+	str, err := env.StringFromEnv("CR_TEST_SNAPSHOT_STR", &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "a", str)
+
+	env.Refresh()
+
+	num, err = env.IntFromEnv("CR_TEST_SNAPSHOT_INT", &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 2, num)
+
+	_, err = env.StringFromEnv("CR_TEST_SNAPSHOT_STR", &cr.StringValidation{Required: true})
+	require.EqualError(t, err, `CR_TEST_SNAPSHOT_STR: must be defined (from environment variable "CR_TEST_SNAPSHOT_STR")`)
+}
+
+func TestUseEnvSnapshot(t *testing.T) {
+	os.Setenv("CR_TEST_SNAPSHOT_INT", "1")
+	defer os.Unsetenv("CR_TEST_SNAPSHOT_INT")
+
+	cr.SetUseEnvSnapshot(true)
+	defer cr.SetUseEnvSnapshot(false)
+
+	require.Equal(t, 1, cr.MustIntFromEnv("CR_TEST_SNAPSHOT_INT", &cr.IntValidation{}))
+	os.Setenv("CR_TEST_SNAPSHOT_INT", "2")
+	require.Equal(t, 1, cr.MustIntFromEnv("CR_TEST_SNAPSHOT_INT", &cr.IntValidation{}))
+
+	cr.DefaultEnv().Refresh()
+	require.Equal(t, 2, cr.MustIntFromEnv("CR_TEST_SNAPSHOT_INT", &cr.IntValidation{}))
+
+	cr.SetUseEnvSnapshot(false)
+	os.Setenv("CR_TEST_SNAPSHOT_INT", "3")
+	require.Equal(t, 3, cr.MustIntFromEnv("CR_TEST_SNAPSHOT_INT", &cr.IntValidation{}))
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// ErrorCollector accumulates validation errors so that they can all be reported at once, e.g.
+//
+//	ec := &ErrorCollector{}
+//	replicas, err := IntFromInterfaceMap("replicas", iMap, &IntValidation{})
+//	ec.Add(err)
+//	...
+//	return ec.Err()
+//
+// It is also the error returned by Err(), whose message lists each failure (see errors.FormatList())
+type ErrorCollector struct {
+	errs []error
+}
+
+// Add records err (if it's not nil), wrapped with strs (e.g. its key); it returns whether err was recorded.
+// If err is an *ErrorCollector, each of its errors is recorded separately
+func (ec *ErrorCollector) Add(err error, strs ...string) bool {
+	if err == nil {
+		return false
+	}
+	if collected, ok := err.(*ErrorCollector); ok {
+		for _, collectedErr := range collected.errs {
+			ec.errs = append(ec.errs, wrapCollected(collectedErr, strs))
+		}
+		return true
+	}
+	ec.errs = append(ec.errs, wrapCollected(err, strs))
+	return true
+}
+
+func wrapCollected(err error, strs []string) error {
+	if len(strs) == 0 {
+		return err
+	}
+	return errors.Wrap(err, strs...)
+}
+
+// AddAll records each non-nil error in errs, wrapped with strs
+func (ec *ErrorCollector) AddAll(errs []error, strs ...string) bool {
+	ok := false
+	for _, err := range errs {
+		if ec.Add(err, strs...) {
+			ok = true
+		}
+	}
+	return ok
+}
+
+// Errors returns the recorded errors, in the order in which they were added
+func (ec *ErrorCollector) Errors() []error {
+	return ec.errs
+}
+
+func (ec *ErrorCollector) HasErrors() bool {
+	return len(ec.errs) > 0
+}
+
+// Err returns nil if no errors were recorded, and otherwise returns the collector itself
+func (ec *ErrorCollector) Err() error {
+	if !ec.HasErrors() {
+		return nil
+	}
+	return ec
+}
+
+func (ec *ErrorCollector) Error() string {
+	return errors.FormatList(ec.errs)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"encoding/json"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// ErrorJSON is the JSON form of a validation error (see ErrorToJSON())
+type ErrorJSON struct {
+	KeyPath    []string          `json:"key_path,omitempty"`
+	Code       string            `json:"code"`
+	Message    string            `json:"message"`
+	Provided   interface{}       `json:"provided,omitempty"`
+	Bound      interface{}       `json:"bound,omitempty"`
+	Op         string            `json:"op,omitempty"`
+	Expected   []s.PrimitiveType `json:"expected,omitempty"`
+	Allowed    interface{}       `json:"allowed,omitempty"`
+	Suggestion string            `json:"suggestion,omitempty"`
+	File       string            `json:"file,omitempty"`
+	Line       int               `json:"line,omitempty"`
+	Col        int               `json:"col,omitempty"`
+}
+
+// ErrorToJSON serializes err as an ErrorJSON object, or as an array of them if err is an *ErrorCollector
+func ErrorToJSON(err error) ([]byte, error) {
+	var collector *ErrorCollector
+	if errors.As(err, &collector) {
+		return json.Marshal(collector)
+	}
+	return json.Marshal(NewErrorJSON(err))
+}
+
+// NewErrorJSON describes err, which may be wrapped (e.g. with its key). Its code is s.Code(err) (e.g. s.MsgMustBeGreaterThan),
+// which is s.MsgUnknown for errors which weren't built from one of the s.Err* messages
+func NewErrorJSON(err error) ErrorJSON {
+	errJSON := ErrorJSON{
+		KeyPath: errors.KeyPath(err).Strings(),
+		Code:    s.Code(err),
+		Message: err.Error(),
+	}
+
+	if pos, ok := errors.GetPosition(err); ok {
+		errJSON.File = pos.File
+		errJSON.Line = pos.Line
+		errJSON.Col = pos.Col
+	}
+
+	var outOfRangeErr *OutOfRangeError
+	var invalidTypeErr *InvalidTypeError
+	var notAllowedValueErr *NotAllowedValueError
+
+	switch {
+	case errors.As(err, &outOfRangeErr):
+		outOfRangeErr.fillJSON(&errJSON)
+	case errors.As(err, &invalidTypeErr):
+		invalidTypeErr.fillJSON(&errJSON)
+	case errors.As(err, &notAllowedValueErr):
+		notAllowedValueErr.fillJSON(&errJSON)
+	}
+
+	return errJSON
+}
+
+func (err *OutOfRangeError) fillJSON(errJSON *ErrorJSON) {
+	errJSON.Provided = jsonValue(err.Val)
+	errJSON.Bound = jsonValue(err.Bound)
+	errJSON.Op = err.Op
+}
+
+func (err *InvalidTypeError) fillJSON(errJSON *ErrorJSON) {
+	errJSON.Provided = jsonValue(err.Provided)
+	errJSON.Expected = err.Expected
+}
+
+func (err *NotAllowedValueError) fillJSON(errJSON *ErrorJSON) {
+	errJSON.Provided = jsonValue(err.Val)
+	errJSON.Allowed = jsonValue(err.Allowed)
+	errJSON.Suggestion = err.Suggestion
+}
+
+func (err *OutOfRangeError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewErrorJSON(err))
+}
+
+func (err *InvalidTypeError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewErrorJSON(err))
+}
+
+func (err *NotAllowedValueError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(NewErrorJSON(err))
+}
+
+func (ec *ErrorCollector) MarshalJSON() ([]byte, error) {
+	errJSONs := make([]ErrorJSON, len(ec.errs))
+	for i, err := range ec.errs {
+		errJSONs[i] = NewErrorJSON(err)
+	}
+	return json.Marshal(errJSONs)
+}
+
+// jsonValue returns val if it can be serialized, otherwise its string form
+func jsonValue(val interface{}) interface{} {
+	if _, err := json.Marshal(val); err != nil {
+		return s.UserStr(val)
+	}
+	return val
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+func unmarshalErrorJSON(t *testing.T, err error) cr.ErrorJSON {
+	jsonBytes, jsonErr := cr.ErrorToJSON(err)
+	require.NoError(t, jsonErr)
+	var errJSON cr.ErrorJSON
+	require.NoError(t, json.Unmarshal(jsonBytes, &errJSON))
+	return errJSON
+}
+
+func TestErrorToJSON(t *testing.T) {
+	_, err := cr.Float64FromInterfaceMap("target_cpu", map[string]interface{}{"target_cpu": 1.5}, &cr.Float64Validation{LessThanOrEqualTo: util.Float64Ptr(1)})
+	err = errors.WrapKey(err, "autoscaling")
+	require.Equal(t, cr.ErrorJSON{
+		KeyPath:  []string{"autoscaling", "target_cpu"},
+		Code:     s.MsgMustBeLessThanOrEqualTo,
+		Message:  err.Error(),
+		Provided: 1.5,
+		Bound:    float64(1),
+		Op:       "<=",
+	}, unmarshalErrorJSON(t, err))
+
+	jsonBytes, jsonErr := cr.ErrorToJSON(err)
+	require.NoError(t, jsonErr)
+	require.Contains(t, string(jsonBytes), `"key_path":["autoscaling","target_cpu"],"code":"must_be_less_than_or_equal_to"`)
+
+	_, err = cr.IntFromInterfaceMap("replicas", map[string]interface{}{}, &cr.IntValidation{Required: true})
+	require.Equal(t, cr.ErrorJSON{
+		KeyPath: []string{"replicas"},
+		Code:    s.MsgMustBeDefined,
+		Message: "replicas: " + s.ErrMustBeDefined,
+	}, unmarshalErrorJSON(t, err))
+
+	_, err = cr.IntFromInterfaceMap("replicas", map[string]interface{}{"replicas": "two"}, &cr.IntValidation{})
+	require.Equal(t, cr.ErrorJSON{
+		KeyPath:  []string{"replicas"},
+		Code:     s.MsgInvalidPrimitiveType,
+		Message:  err.Error(),
+		Provided: "two",
+		Expected: []s.PrimitiveType{s.PrimTypeInt},
+	}, unmarshalErrorJSON(t, err))
+
+	// values which can't be serialized are converted to strings
+	err = &cr.InvalidTypeError{Provided: make(chan int), Expected: []s.PrimitiveType{s.PrimTypeInt}}
+	require.IsType(t, "", unmarshalErrorJSON(t, err).Provided)
+
+	_, err = cr.StringFromInterfaceMap("region", map[string]interface{}{"region": "mars"}, &cr.StringValidation{AllowedValues: []string{"us-east-1", "us-west-2"}})
+	require.Equal(t, cr.ErrorJSON{
+		KeyPath:  []string{"region"},
+		Code:     s.MsgNotAllowedValue,
+		Message:  err.Error(),
+		Provided: "mars",
+		Allowed:  []interface{}{"us-east-1", "us-west-2"},
+	}, unmarshalErrorJSON(t, err))
+
+	err = errors.New("something went wrong")
+	require.Equal(t, cr.ErrorJSON{
+		Code:    s.MsgUnknown,
+		Message: "something went wrong",
+	}, unmarshalErrorJSON(t, err))
+
+	ec := &cr.ErrorCollector{}
+	ec.Add(errors.WrapKey(errors.Wrap(cr.ErrRequired), "name"))
+	ec.Add(errors.New("something went wrong"))
+	jsonBytes, jsonErr = cr.ErrorToJSON(errors.Wrap(ec.Err(), "api"))
+	require.NoError(t, jsonErr)
+	var errJSONs []cr.ErrorJSON
+	require.NoError(t, json.Unmarshal(jsonBytes, &errJSONs))
+	require.Equal(t, []cr.ErrorJSON{
+		{KeyPath: []string{"name"}, Code: s.MsgMustBeDefined, Message: "name: " + s.ErrMustBeDefined},
+		{Code: s.MsgUnknown, Message: "something went wrong"},
+	}, errJSONs)
+
+	jsonBytes, jsonErr = json.Marshal(&cr.OutOfRangeError{Val: 0, Bound: 0, Op: ">"})
+	require.NoError(t, jsonErr)
+	require.JSONEq(t, `{"code": "must_be_greater_than", "message": "`+s.ErrMustBeGreaterThan(0, 0)+`", "provided": 0, "bound": 0, "op": ">"}`, string(jsonBytes))
+}
+
+func TestCode(t *testing.T) {
+	iMap := map[string]interface{}{"replicas": 0, "name": 5, "mode": "fast"}
+
+	_, err := cr.IntFromInterfaceMap("replicas", iMap, &cr.IntValidation{GreaterThan: util.IntPtr(0)})
+	require.Equal(t, s.MsgMustBeGreaterThan, s.Code(err))
+
+	_, err = cr.StringFromInterfaceMap("name", iMap, &cr.StringValidation{})
+	require.Equal(t, s.MsgInvalidPrimitiveType, s.Code(err))
+
+	_, err = cr.StringFromInterfaceMap("mode", iMap, &cr.StringValidation{AllowedValues: []string{"slow", "medium"}})
+	require.Equal(t, s.MsgNotAllowedValue, s.Code(err))
+
+	_, err = cr.StringFromInterfaceMap("missing", iMap, &cr.StringValidation{Required: true})
+	require.Equal(t, s.MsgMustBeDefined, s.Code(err))
+	require.Equal(t, s.MsgMustBeDefined, s.Code(errors.Wrap(errors.MarkUser(err), "config.yaml")))
+
+	// errors which are built from the other messages have codes too
+	_, err = cr.GlobFromStr("[", &cr.GlobValidation{})
+	require.Equal(t, s.MsgInvalidGlob, s.Code(err))
+
+	_, err = cr.StringFromInterfaceMap("name", map[string]interface{}{"name": "abcd"}, &cr.StringValidation{MaxLength: 3})
+	require.Equal(t, s.MsgStrTooLong, s.Code(err))
+
+	_, err = cr.StringFromInterfaceMap("name", map[string]interface{}{"name": "Api"}, &cr.StringValidation{Dns1123Label: true})
+	require.Equal(t, s.MsgDNS1123Chars, s.Code(err))
+
+	_, err = cr.ReadYAMLBytes([]byte("a: ["))
+	require.Equal(t, s.MsgUnmarshalYaml, s.Code(err))
+
+	require.Equal(t, s.MsgUnknown, s.Code(errors.New("something went wrong")))
+	require.Equal(t, s.Code(cr.ErrRequired), cr.NewErrorJSON(cr.ErrRequired).Code)
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"sync"
+
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+var (
+	fatalHandlerMutex sync.RWMutex
+	fatalHandler      = func(err error) { errors.Panic(err) }
+)
+
+// SetFatalHandler sets the function which the Must* functions call with their (fully wrapped) error, e.g. to call log.Fatal()
+// or os.Exit(). The default is errors.Panic(); if the handler returns, the Must* function still panics. Pass nil to restore the default
+func SetFatalHandler(handler func(err error)) {
+	fatalHandlerMutex.Lock()
+	defer fatalHandlerMutex.Unlock()
+	if handler == nil {
+		handler = func(err error) { errors.Panic(err) }
+	}
+	fatalHandler = handler
+}
+
+// Fatal passes err to the handler set by SetFatalHandler(), and panics if it returns
+func Fatal(err error) {
+	fatalHandlerMutex.RLock()
+	handler := fatalHandler
+	fatalHandlerMutex.RUnlock()
+
+	handler(err)
+	errors.Panic(err)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// FileCache caches the contents of files read by its FromFile readers. A cached file is re-read once
+// its modification time or size changes, or once it is older than the TTL
+type FileCache struct {
+	uncached   bool
+	ttl        time.Duration
+	maxEntries int
+	mutex      sync.Mutex
+	entries    map[string]*fileCacheEntry
+}
+
+type fileCacheEntry struct {
+	contents string
+	modTime  time.Time
+	size     int64
+	cachedAt time.Time
+}
+
+// used by the package-level FromFile readers
+var uncachedFiles = &FileCache{uncached: true}
+
+// NewFileCache creates a FileCache. ttl <= 0 means entries don't expire, and maxEntries <= 0 means there is no limit
+func NewFileCache(ttl time.Duration, maxEntries int) *FileCache {
+	return &FileCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*fileCacheEntry),
+	}
+}
+
+func (cache *FileCache) Purge() {
+	if cache.uncached {
+		return
+	}
+	cache.mutex.Lock()
+	cache.entries = make(map[string]*fileCacheEntry)
+	cache.mutex.Unlock()
+}
+
+func (cache *FileCache) readValueFile(filePath string, maxFileBytes int64, allowNonRegularFiles bool, trimWhitespace bool) (*string, error) {
+	if cache.uncached {
+		return readValueFile(filePath, maxFileBytes, allowNonRegularFiles, trimWhitespace)
+	}
+
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, errors.MarkInternal(errors.Wrap(err))
+	}
+
+	fileInfo, err := os.Stat(absPath)
+	if err != nil || !fileInfo.Mode().IsRegular() {
+		cache.remove(absPath)
+		return readValueFile(absPath, maxFileBytes, allowNonRegularFiles, trimWhitespace)
+	}
+
+	if maxFileBytes <= 0 {
+		maxFileBytes = DefaultMaxFileBytes
+	}
+
+	cache.mutex.Lock()
+	entry, ok := cache.entries[absPath]
+	if ok && (!entry.modTime.Equal(fileInfo.ModTime()) || entry.size != fileInfo.Size() || cache.isExpired(entry)) {
+		delete(cache.entries, absPath)
+		ok = false
+	}
+	cache.mutex.Unlock()
+
+	var contents string
+	if ok {
+		contents = entry.contents
+		if int64(len(contents)) > maxFileBytes {
+			return nil, errors.NewUserCoded(s.MsgFileTooLarge, s.ErrFileTooLarge(maxFileBytes))
+		}
+	} else {
+		contentsPtr, err := readValueFile(absPath, maxFileBytes, allowNonRegularFiles, false)
+		if err != nil || contentsPtr == nil {
+			return nil, err
+		}
+		contents = *contentsPtr
+		// the modification time from before the file was read is stored, so that a concurrent write causes a re-read
+		cache.add(absPath, &fileCacheEntry{
+			contents: contents,
+			modTime:  fileInfo.ModTime(),
+			size:     fileInfo.Size(),
+			cachedAt: time.Now(),
+		})
+	}
+
+	if trimWhitespace {
+		contents = strings.TrimSpace(contents)
+	}
+	return &contents, nil
+}
+
+func (cache *FileCache) isExpired(entry *fileCacheEntry) bool {
+	return cache.ttl > 0 && time.Since(entry.cachedAt) >= cache.ttl
+}
+
+func (cache *FileCache) add(absPath string, entry *fileCacheEntry) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if _, ok := cache.entries[absPath]; !ok && cache.maxEntries > 0 && len(cache.entries) >= cache.maxEntries {
+		cache.evictOldest()
+	}
+	cache.entries[absPath] = entry
+}
+
+func (cache *FileCache) evictOldest() {
+	var oldestPath string
+	var oldestEntry *fileCacheEntry
+	for path, entry := range cache.entries {
+		if oldestEntry == nil || entry.cachedAt.Before(oldestEntry.cachedAt) {
+			oldestPath = path
+			oldestEntry = entry
+		}
+	}
+	delete(cache.entries, oldestPath)
+}
+
+func (cache *FileCache) remove(absPath string) {
+	cache.mutex.Lock()
+	delete(cache.entries, absPath)
+	cache.mutex.Unlock()
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+func TestFileCache(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	filePath := filepath.Join(tmpDir, "workers")
+	err = ioutil.WriteFile(filePath, []byte("4\n"), 0644)
+	require.NoError(t, err)
+
+	cache := cr.NewFileCache(0, 0)
+
+	num, err := cache.IntFromFile(filePath, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 4, num)
+
+	str, err := cache.StringFromFile(filePath, &cr.StringValidation{PreserveWhitespace: true})
+	require.NoError(t, err)
+	require.Equal(t, "4\n", str)
+
+	_, err = cache.IntFromFile(filePath, &cr.IntValidation{MaxFileBytes: 1})
+	require.EqualError(t, err, filePath+": "+s.ErrFileTooLarge(1))
+
+	// a change in size or modification time invalidates the entry
+	err = ioutil.WriteFile(filePath, []byte("16\n"), 0644)
+	require.NoError(t, err)
+	num, err = cache.IntFromFile(filePath, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 16, num)
+
+	err = ioutil.WriteFile(filePath, []byte("32\n"), 0644)
+	require.NoError(t, err)
+	future := time.Now().Add(time.Hour)
+	err = os.Chtimes(filePath, future, future)
+	require.NoError(t, err)
+	num, err = cache.IntFromFile(filePath, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 32, num)
+
+	err = os.Remove(filePath)
+	require.NoError(t, err)
+	num, err = cache.IntFromFile(filePath, &cr.IntValidation{Default: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, num)
+
+	cache.Purge()
+}
+
+func TestFileCacheConcurrent(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	filePaths := writeBootstrapFiles(t, tmpDir, 15)
+	cache := cr.NewFileCache(time.Millisecond, 5)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				index := j % len(filePaths)
+				num, err := cache.IntFromFile(filePaths[index], &cr.IntValidation{})
+				if err != nil || num != index {
+					t.Errorf("%s: got %d (error: %v), expected %d", filePaths[index], num, err, index)
+				}
+				if j%50 == 0 {
+					cache.Purge()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func writeBootstrapFiles(t testing.TB, dir string, numFiles int) []string {
+	filePaths := make([]string, numFiles)
+	for i := range filePaths {
+		filePaths[i] = filepath.Join(dir, "key"+strconv.Itoa(i))
+		err := ioutil.WriteFile(filePaths[i], []byte(strconv.Itoa(i)+"\n"), 0644)
+		require.NoError(t, err)
+	}
+	return filePaths
+}
+
+// reads 15 keys from files, as is done during bootstrapping
+func benchmarkBootstrap(b *testing.B, readInt func(string, *cr.IntValidation) (int, error)) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(b, err)
+	filePaths := writeBootstrapFiles(b, tmpDir, 15)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, filePath := range filePaths {
+			if _, err := readInt(filePath, &cr.IntValidation{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkBootstrapUncached(b *testing.B) {
+	benchmarkBootstrap(b, cr.IntFromFile)
+}
+
+func BenchmarkBootstrapFileCache(b *testing.B) {
+	benchmarkBootstrap(b, cr.NewFileCache(time.Minute, 100).IntFromFile)
+}
@@ -17,12 +17,12 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
+	"math"
+	"strings"
 
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
 	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
-	"github.com/cortexlabs/cortex/pkg/utils/util"
 )
 
 type Float32Validation struct {
@@ -33,34 +33,22 @@ type Float32Validation struct {
 	GreaterThanOrEqualTo *float32
 	LessThan             *float32
 	LessThanOrEqualTo    *float32
+	PreserveWhitespace   bool    // don't trim surrounding whitespace (including the trailing newline) from values read from files
+	MaxFileBytes         int64   // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles bool    // allow FromFile readers to read from e.g. pipes and device files
+	WarnPrecisionLoss    bool    // add a warning to Report if a value can't be represented exactly as a float32
+	Sensitive            bool    // replace the value with its length in errors and reports (errors from Validator are shown as-is)
+	Report               *Report // if set, each value which is read is recorded, along with where it came from
 	Validator            func(float32) (float32, error)
+	allowedValuesSet     allowedValuesSet
 }
 
 func Float32(inter interface{}, v *Float32Validation) (float32, error) {
-	if inter == nil {
-		return 0, errors.New(s.ErrCannotBeNull)
-	}
-	casted, castOk := cast.InterfaceToFloat32(inter)
-	if !castOk {
-		return 0, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeFloat))
-	}
-	return ValidateFloat32(casted, v)
+	return v.value().From(inter)
 }
 
 func Float32FromInterfaceMap(key string, iMap map[string]interface{}, v *Float32Validation) (float32, error) {
-	inter, ok := ReadInterfaceMapValue(key, iMap)
-	if !ok {
-		val, err := ValidateFloat32Missing(v)
-		if err != nil {
-			return 0, errors.Wrap(err, key)
-		}
-		return val, nil
-	}
-	val, err := Float32(inter, v)
-	if err != nil {
-		return 0, errors.Wrap(err, key)
-	}
-	return val, nil
+	return v.value().FromInterfaceMap(key, iMap)
 }
 
 func Float32FromStrMap(key string, sMap map[string]string, v *Float32Validation) (float32, error) {
@@ -68,126 +56,271 @@ func Float32FromStrMap(key string, sMap map[string]string, v *Float32Validation)
 	if !ok || valStr == "" {
 		val, err := ValidateFloat32Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, key)
+			return 0, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := Float32FromStr(valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, key)
+		return 0, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive), float32Warnings(valStr, v.WarnPrecisionLoss, v.Sensitive)...)
 	return val, nil
 }
 
 func Float32FromStr(valStr string, v *Float32Validation) (float32, error) {
-	if valStr == "" {
-		return ValidateFloat32Missing(v)
+	return v.value().FromStr(valStr)
+}
+
+// checkFloat32 returns an error if val is too large to be stored in a float32
+func checkFloat32(val float64, provided interface{}, sensitive bool) error {
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return nil
 	}
-	casted, castOk := s.ParseFloat32(valStr)
-	if !castOk {
-		return 0, errors.New(s.ErrInvalidPrimitiveType(valStr, s.PrimTypeFloat))
+	if math.Abs(val) >= cast.Float32Overflow {
+		return errors.NewUserCoded(s.MsgFloat32OutOfRange, s.ErrFloat32OutOfRange(redactIf(provided, sensitive)))
 	}
-	return ValidateFloat32(casted, v)
+	return nil
+}
+
+// float32Warnings returns a precision loss warning if provided can't be represented exactly as a float32
+// (precision loss isn't reported for sensitive values, since the stored value would be shown)
+func float32Warnings(provided interface{}, warnPrecisionLoss bool, sensitive bool) []string {
+	if !warnPrecisionLoss || sensitive {
+		return nil
+	}
+	var wide float64
+	var ok bool
+	if valStr, isStr := provided.(string); isStr {
+		wide, ok = s.ParseFloat64(valStr)
+	} else {
+		wide, ok = cast.InterfaceToFloat64(provided)
+	}
+	if !ok || math.IsNaN(wide) || math.IsInf(wide, 0) {
+		return nil
+	}
+	if s.Float32(float32(wide)) == s.Float64(wide) {
+		return nil
+	}
+	return []string{s.WarnFloat32PrecisionLoss(provided, float32(wide))}
 }
 
 func Float32FromEnv(envVarName string, v *Float32Validation) (float32, error) {
-	valStr := ReadEnvVar(envVarName)
+	return DefaultEnv().Float32FromEnv(envVarName, v)
+}
+
+func (env *Env) Float32FromEnv(envVarName string, v *Float32Validation) (float32, error) {
+	return v.value().fromEnv(env, envVarName)
+}
+
+func Float32FromFile(filePath string, v *Float32Validation) (float32, error) {
+	return uncachedFiles.Float32FromFile(filePath, v)
+}
+
+func (cache *FileCache) Float32FromFile(filePath string, v *Float32Validation) (float32, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
+	if err != nil {
+		return 0, errors.Wrap(err, filePath)
+	}
 	if valStr == nil || *valStr == "" {
 		val, err := ValidateFloat32Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, s.EnvVar(envVarName))
+			return 0, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
 		return val, nil
 	}
+	if err := checkSingleLine(*valStr); err != nil {
+		return 0, valueFileError(err, filePath, 0)
+	}
 	val, err := Float32FromStr(*valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, s.EnvVar(envVarName))
+		return 0, valueFileError(err, filePath, 1)
 	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive), float32Warnings(*valStr, v.WarnPrecisionLoss, v.Sensitive)...)
 	return val, nil
 }
 
-func Float32FromFile(filePath string, v *Float32Validation) (float32, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
-	if err != nil || len(valBytes) == 0 {
+func Float32FromEnvOrFile(envVarName string, filePath string, v *Float32Validation) (float32, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr != nil && *valStr != "" {
+		return Float32FromEnv(envVarName, v)
+	}
+	return Float32FromFile(filePath, v)
+}
+
+func Float32FromRef(ref string, v *Float32Validation) (float32, error) {
+	valStr, err := ReadRef(ref)
+	if err != nil {
+		return 0, errors.Wrap(err, ref)
+	}
+	if valStr == nil || *valStr == "" {
 		val, err := ValidateFloat32Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, filePath)
+			return 0, errors.Wrap(err, ref)
 		}
+		v.Report.record(ref, ValueSourceDefault, ref, redactIf(val, v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
-	val, err := Float32FromStr(valStr, v)
+	val, err := Float32FromStr(*valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, filePath)
+		return 0, errors.Wrap(err, ref)
 	}
+	v.Report.record(ref, ValueSourceRef, ref, redactIf(val, v.Sensitive), float32Warnings(*valStr, v.WarnPrecisionLoss, v.Sensitive)...)
 	return val, nil
 }
 
-func Float32FromEnvOrFile(envVarName string, filePath string, v *Float32Validation) (float32, error) {
-	valStr := ReadEnvVar(envVarName)
-	if valStr != nil && *valStr != "" {
-		return Float32FromEnv(envVarName, v)
+func Float32FromSource(source Source, key string, v *Float32Validation) (float32, error) {
+	valStr, found, err := source.Lookup(key)
+	if err != nil {
+		return 0, errors.WrapKey(err, key)
 	}
-	return Float32FromFile(filePath, v)
+	if !found || valStr == "" {
+		val, err := ValidateFloat32Missing(v)
+		if err != nil {
+			return 0, errors.WrapKey(err, key)
+		}
+		v.Report.record(key, ValueSourceDefault, describeSource(source, key), redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := Float32FromStr(valStr, v)
+	if err != nil {
+		return 0, errors.WrapKey(errors.WithSource(err, describeSource(source, key)), key)
+	}
+	v.Report.record(key, ValueSourceLookup, describeSource(source, key), redactIf(val, v.Sensitive), float32Warnings(valStr, v.WarnPrecisionLoss, v.Sensitive)...)
+	return val, nil
 }
 
 func Float32FromPrompt(promptOpts *PromptOptions, v *Float32Validation) (float32, error) {
-	promptOpts.defaultStr = s.Float32(v.Default)
-	valStr := prompt(promptOpts)
-	if valStr == "" {
-		return ValidateFloat32Missing(v)
+	promptOpts = promptOpts.withHints(ConstraintHint(v), s.Float32(v.Default))
+	var val float32
+	var source ValueSource
+	var provided string
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		provided = valStr
+		if valStr == "" {
+			val, err = ValidateFloat32Missing(v)
+		} else {
+			val, err = Float32FromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(val, v.Sensitive), float32Warnings(provided, v.WarnPrecisionLoss, v.Sensitive)...)
 	}
-	return Float32FromStr(valStr, v)
+	return val, err
 }
 
 func ValidateFloat32Missing(v *Float32Validation) (float32, error) {
-	if v.Required {
-		return 0, errors.New(s.ErrMustBeDefined)
-	}
-	return ValidateFloat32(v.Default, v)
+	return v.value().ValidateMissing()
 }
 
 func ValidateFloat32(val float32, v *Float32Validation) (float32, error) {
-	err := ValidateFloat32Val(val, v)
-	if err != nil {
-		return 0, err
+	return v.value().Validate(val)
+}
+
+func ValidateFloat32Val(val float32, v *Float32Validation) error {
+	return v.value().validateVal(val)
+}
+
+func (v *Float32Validation) value() *Value[float32] {
+	return &Value[float32]{
+		Required:             v.Required,
+		Default:              v.Default,
+		AllowedValues:        v.AllowedValues,
+		GreaterThan:          v.GreaterThan,
+		GreaterThanOrEqualTo: v.GreaterThanOrEqualTo,
+		LessThan:             v.LessThan,
+		LessThanOrEqualTo:    v.LessThanOrEqualTo,
+		Sensitive:            v.Sensitive,
+		Report:               v.Report,
+		Validator:            v.Validator,
+		allowedValuesSet:     allowedValuesSet{shared: &v.allowedValuesSet},
+		warnings:             v.warningsFor,
 	}
+}
+
+func (v *Float32Validation) warningsFor(provided interface{}) []string {
+	return float32Warnings(provided, v.WarnPrecisionLoss, v.Sensitive)
+}
+
+//
+// With raw values
+//
 
-	if v.Validator != nil {
-		return v.Validator(val)
+func Float32FromInterfaceMapWithRaw(key string, iMap map[string]interface{}, v *Float32Validation) (float32, string, error) {
+	val, err := Float32FromInterfaceMap(key, iMap, v)
+	raw := ""
+	if inter, ok := ReadInterfaceMapValue(key, iMap); ok {
+		raw = s.UserStrStripped(inter)
 	}
-	return val, nil
+	return val, raw, err
 }
 
-func ValidateFloat32Val(val float32, v *Float32Validation) error {
-	if v.GreaterThan != nil {
-		if val <= *v.GreaterThan {
-			return errors.New(s.ErrMustBeGreaterThan(val, *v.GreaterThan))
+func Float32FromStrWithRaw(valStr string, v *Float32Validation) (float32, string, error) {
+	val, err := Float32FromStr(valStr, v)
+	return val, valStr, err
+}
+
+func Float32FromEnvWithRaw(envVarName string, v *Float32Validation) (float32, string, error) {
+	return DefaultEnv().Float32FromEnvWithRaw(envVarName, v)
+}
+
+func (env *Env) Float32FromEnvWithRaw(envVarName string, v *Float32Validation) (float32, string, error) {
+	valStr := env.ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateFloat32Missing(v)
+		if err != nil {
+			return 0, "", envVarError(err, envVarName)
 		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+		return val, "", nil
 	}
-	if v.GreaterThanOrEqualTo != nil {
-		if val < *v.GreaterThanOrEqualTo {
-			return errors.New(s.ErrMustBeGreaterThanOrEqualTo(val, *v.GreaterThanOrEqualTo))
-		}
+	val, err := Float32FromStr(*valStr, v)
+	if err != nil {
+		return 0, *valStr, envVarError(err, envVarName)
 	}
-	if v.LessThan != nil {
-		if val >= *v.LessThan {
-			return errors.New(s.ErrMustBeLessThan(val, *v.LessThan))
-		}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(val, v.Sensitive), float32Warnings(*valStr, v.WarnPrecisionLoss, v.Sensitive)...)
+	return val, *valStr, nil
+}
+
+func Float32FromFileWithRaw(filePath string, v *Float32Validation) (float32, string, error) {
+	valStr, err := readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, false)
+	if err != nil {
+		return 0, "", errors.Wrap(err, filePath)
 	}
-	if v.LessThanOrEqualTo != nil {
-		if val > *v.LessThanOrEqualTo {
-			return errors.New(s.ErrMustBeLessThanOrEqualTo(val, *v.LessThanOrEqualTo))
+	if valStr == nil {
+		val, err := ValidateFloat32Missing(v)
+		if err != nil {
+			return 0, "", valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, "", nil
 	}
-
-	if v.AllowedValues != nil {
-		if !util.IsFloat32InSlice(val, v.AllowedValues) {
-			return errors.New(s.ErrInvalidFloat32(val, v.AllowedValues...))
+	trimmed := *valStr
+	if !v.PreserveWhitespace {
+		trimmed = strings.TrimSpace(trimmed)
+	}
+	if trimmed == "" {
+		val, err := ValidateFloat32Missing(v)
+		if err != nil {
+			return 0, *valStr, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, *valStr, nil
 	}
-
-	return nil
+	if err := checkSingleLine(trimmed); err != nil {
+		return 0, *valStr, valueFileError(err, filePath, 0)
+	}
+	val, err := Float32FromStr(trimmed, v)
+	if err != nil {
+		return 0, *valStr, valueFileError(err, filePath, 1)
+	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive), float32Warnings(trimmed, v.WarnPrecisionLoss, v.Sensitive)...)
+	return val, *valStr, nil
 }
 
 //
@@ -197,7 +330,7 @@ func ValidateFloat32Val(val float32, v *Float32Validation) error {
 func MustFloat32FromEnv(envVarName string, v *Float32Validation) float32 {
 	val, err := Float32FromEnv(envVarName, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return val
 }
@@ -205,7 +338,7 @@ func MustFloat32FromEnv(envVarName string, v *Float32Validation) float32 {
 func MustFloat32FromFile(filePath string, v *Float32Validation) float32 {
 	val, err := Float32FromFile(filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return val
 }
@@ -213,7 +346,23 @@ func MustFloat32FromFile(filePath string, v *Float32Validation) float32 {
 func MustFloat32FromEnvOrFile(envVarName string, filePath string, v *Float32Validation) float32 {
 	val, err := Float32FromEnvOrFile(envVarName, filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
+	}
+	return val
+}
+
+func MustFloat32FromRef(ref string, v *Float32Validation) float32 {
+	val, err := Float32FromRef(ref, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustFloat32FromSource(source Source, key string, v *Float32Validation) float32 {
+	val, err := Float32FromSource(source, key, v)
+	if err != nil {
+		Fatal(err)
 	}
 	return val
 }
@@ -27,13 +27,14 @@ type Float32ListValidation struct {
 	Default    []float32
 	AllowNull  bool
 	AllowEmpty bool
+	Report     *Report // if set, each value which is read is recorded, along with where it came from
 	Validator  func([]float32) ([]float32, error)
 }
 
 func Float32List(inter interface{}, v *Float32ListValidation) ([]float32, error) {
 	casted, castOk := cast.InterfaceToFloat32Slice(inter)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeFloatList))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeFloatList}})
 	}
 	return ValidateFloat32List(casted, v)
 }
@@ -43,20 +44,22 @@ func Float32ListFromInterfaceMap(key string, iMap map[string]interface{}, v *Flo
 	if !ok {
 		val, err := ValidateFloat32ListMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", val)
 		return val, nil
 	}
 	val, err := Float32List(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", val)
 	return val, nil
 }
 
 func ValidateFloat32ListMissing(v *Float32ListValidation) ([]float32, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateFloat32List(v.Default, v)
 }
@@ -64,18 +67,19 @@ func ValidateFloat32ListMissing(v *Float32ListValidation) ([]float32, error) {
 func ValidateFloat32List(val []float32, v *Float32ListValidation) ([]float32, error) {
 	if !v.AllowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
 	if !v.AllowEmpty {
 		if val != nil && len(val) == 0 {
-			return nil, errors.New(s.ErrCannotBeEmpty)
+			return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
 		}
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
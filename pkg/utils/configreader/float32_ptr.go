@@ -17,8 +17,6 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
-
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
 	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
@@ -33,16 +31,27 @@ type Float32PtrValidation struct {
 	GreaterThanOrEqualTo *float32
 	LessThan             *float32
 	LessThanOrEqualTo    *float32
+	PreserveWhitespace   bool    // don't trim surrounding whitespace (including the trailing newline) from values read from files
+	MaxFileBytes         int64   // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles bool    // allow FromFile readers to read from e.g. pipes and device files
+	WarnPrecisionLoss    bool    // add a warning to Report if a value can't be represented exactly as a float32
+	Sensitive            bool    // replace the value with its length in errors (errors from Validator are shown as-is)
+	Report               *Report // if set, each value which is read is recorded, along with where it came from
 	Validator            func(*float32) (*float32, error)
+	allowedValuesSet     allowedValuesSet
 }
 
 func makeFloat32ValValidation(v *Float32PtrValidation) *Float32Validation {
 	return &Float32Validation{
+		Sensitive:            v.Sensitive,
 		AllowedValues:        v.AllowedValues,
 		GreaterThan:          v.GreaterThan,
 		GreaterThanOrEqualTo: v.GreaterThanOrEqualTo,
 		LessThan:             v.LessThan,
 		LessThanOrEqualTo:    v.LessThanOrEqualTo,
+		WarnPrecisionLoss:    v.WarnPrecisionLoss,
+		Report:               v.Report,
+		allowedValuesSet:     allowedValuesSet{shared: &v.allowedValuesSet},
 	}
 }
 
@@ -50,9 +59,14 @@ func Float32Ptr(inter interface{}, v *Float32PtrValidation) (*float32, error) {
 	if inter == nil {
 		return ValidateFloat32Ptr(nil, v)
 	}
+	if wide, ok := cast.InterfaceToFloat64(inter); ok {
+		if err := checkFloat32(wide, inter, v.Sensitive); err != nil {
+			return nil, err
+		}
+	}
 	casted, castOk := cast.InterfaceToFloat32(inter)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeFloat))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: redactIf(inter, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeFloat}})
 	}
 	return ValidateFloat32Ptr(&casted, v)
 }
@@ -62,14 +76,16 @@ func Float32PtrFromInterfaceMap(key string, iMap map[string]interface{}, v *Floa
 	if !ok {
 		val, err := ValidateFloat32PtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := Float32Ptr(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(cast.Deref(val), v.Sensitive), float32Warnings(inter, v.WarnPrecisionLoss, v.Sensitive)...)
 	return val, nil
 }
 
@@ -78,14 +94,16 @@ func Float32PtrFromStrMap(key string, sMap map[string]string, v *Float32PtrValid
 	if !ok || valStr == "" {
 		val, err := ValidateFloat32PtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := Float32PtrFromStr(valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(cast.Deref(val), v.Sensitive), float32Warnings(valStr, v.WarnPrecisionLoss, v.Sensitive)...)
 	return val, nil
 }
 
@@ -93,43 +111,65 @@ func Float32PtrFromStr(valStr string, v *Float32PtrValidation) (*float32, error)
 	if valStr == "" {
 		return ValidateFloat32PtrMissing(v)
 	}
+	if wide, ok := s.ParseFloat64(valStr); ok {
+		if err := checkFloat32(wide, valStr, v.Sensitive); err != nil {
+			return nil, err
+		}
+	}
 	casted, castOk := s.ParseFloat32(valStr)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(valStr, s.PrimTypeFloat))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: redactIf(valStr, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeFloat}})
 	}
 	return ValidateFloat32Ptr(&casted, v)
 }
 
 func Float32PtrFromEnv(envVarName string, v *Float32PtrValidation) (*float32, error) {
-	valStr := ReadEnvVar(envVarName)
+	return DefaultEnv().Float32PtrFromEnv(envVarName, v)
+}
+
+func (env *Env) Float32PtrFromEnv(envVarName string, v *Float32PtrValidation) (*float32, error) {
+	valStr := env.ReadEnvVar(envVarName)
 	if valStr == nil || *valStr == "" {
 		val, err := ValidateFloat32PtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, s.EnvVar(envVarName))
+			return nil, envVarError(err, envVarName)
 		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := Float32PtrFromStr(*valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, s.EnvVar(envVarName))
+		return nil, envVarError(err, envVarName)
 	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(cast.Deref(val), v.Sensitive), float32Warnings(*valStr, v.WarnPrecisionLoss, v.Sensitive)...)
 	return val, nil
 }
 
 func Float32PtrFromFile(filePath string, v *Float32PtrValidation) (*float32, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
-	if err != nil || len(valBytes) == 0 {
+	return uncachedFiles.Float32PtrFromFile(filePath, v)
+}
+
+func (cache *FileCache) Float32PtrFromFile(filePath string, v *Float32PtrValidation) (*float32, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
+	if err != nil {
+		return nil, errors.Wrap(err, filePath)
+	}
+	if valStr == nil || *valStr == "" {
 		val, err := ValidateFloat32PtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, filePath)
+			return nil, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
-	val, err := Float32PtrFromStr(valStr, v)
+	if err := checkSingleLine(*valStr); err != nil {
+		return nil, valueFileError(err, filePath, 0)
+	}
+	val, err := Float32PtrFromStr(*valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, filePath)
+		return nil, valueFileError(err, filePath, 1)
 	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(cast.Deref(val), v.Sensitive), float32Warnings(*valStr, v.WarnPrecisionLoss, v.Sensitive)...)
 	return val, nil
 }
 
@@ -142,16 +182,30 @@ func Float32PtrFromEnvOrFile(envVarName string, filePath string, v *Float32PtrVa
 }
 
 func Float32PtrFromPrompt(promptOpts *PromptOptions, v *Float32PtrValidation) (*float32, error) {
-	valStr := prompt(promptOpts)
-	if valStr == "" {
-		return ValidateFloat32PtrMissing(v)
+	promptOpts = promptOpts.withHints(ConstraintHint(v), "")
+	var val *float32
+	var source ValueSource
+	var provided string
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		provided = valStr
+		if valStr == "" {
+			val, err = ValidateFloat32PtrMissing(v)
+		} else {
+			val, err = Float32PtrFromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(cast.Deref(val), v.Sensitive), float32Warnings(provided, v.WarnPrecisionLoss, v.Sensitive)...)
 	}
-	return Float32PtrFromStr(valStr, v)
+	return val, err
 }
 
 func ValidateFloat32PtrMissing(v *Float32PtrValidation) (*float32, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateFloat32Ptr(v.Default, v)
 }
@@ -159,7 +213,7 @@ func ValidateFloat32PtrMissing(v *Float32PtrValidation) (*float32, error) {
 func ValidateFloat32Ptr(val *float32, v *Float32PtrValidation) (*float32, error) {
 	if v.DisallowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
@@ -171,7 +225,8 @@ func ValidateFloat32Ptr(val *float32, v *Float32PtrValidation) (*float32, error)
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
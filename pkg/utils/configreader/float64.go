@@ -17,49 +17,61 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
+	"strings"
 
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
 	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
-	"github.com/cortexlabs/cortex/pkg/utils/util"
 )
 
 type Float64Validation struct {
-	Required             bool
-	Default              float64
-	AllowedValues        []float64
-	GreaterThan          *float64
-	GreaterThanOrEqualTo *float64
-	LessThan             *float64
-	LessThanOrEqualTo    *float64
-	Validator            func(float64) (float64, error)
+	Required              bool
+	Default               float64
+	DefaultFunc           func() (float64, error) // if set, it's called to compute the default (e.g. from values which have already been read)
+	AllowedValues         []float64
+	GreaterThan           *float64
+	GreaterThanOrEqualTo  *float64
+	LessThan              *float64
+	LessThanOrEqualTo     *float64
+	PreserveWhitespace    bool    // don't trim surrounding whitespace (including the trailing newline) from values read from files
+	MaxFileBytes          int64   // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles  bool    // allow FromFile readers to read from e.g. pipes and device files
+	NormalizeNegativeZero bool    // return -0 as 0
+	DisallowExplicitPlus  bool    // for values read from strings, reject a leading "+" (e.g. "+1.5")
+	Lenient               bool    // accept surrounding whitespace in strings (e.g. " 1.5 ")
+	Sensitive             bool    // replace the value with its length in errors and reports (errors from Validator are shown as-is)
+	Report                *Report // if set, each value which is read is recorded, along with where it came from
+	Validator             func(float64) (float64, error)
+	allowedValuesSet      allowedValuesSet
 }
 
 func Float64(inter interface{}, v *Float64Validation) (float64, error) {
-	if inter == nil {
-		return 0, errors.New(s.ErrCannotBeNull)
-	}
-	casted, castOk := cast.InterfaceToFloat64(inter)
-	if !castOk {
-		return 0, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeFloat))
-	}
-	return ValidateFloat64(casted, v)
+	return v.value().From(inter)
 }
 
 func Float64FromInterfaceMap(key string, iMap map[string]interface{}, v *Float64Validation) (float64, error) {
-	inter, ok := ReadInterfaceMapValue(key, iMap)
+	return v.value().FromInterfaceMap(key, iMap)
+}
+
+// Float64FromInterfaceMapWithRefs is like Float64FromInterfaceMap(), but the value can refer to another field in the map (e.g. "${default_timeout}")
+func Float64FromInterfaceMapWithRefs(key string, iMap map[string]interface{}, v *Float64Validation) (float64, error) {
+	inter, ok, err := ReadInterfaceMapValueWithRefs(key, iMap)
+	if err != nil {
+		return 0, errors.WrapKey(err, key)
+	}
 	if !ok {
 		val, err := ValidateFloat64Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, key)
+			return 0, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := Float64(inter, v)
 	if err != nil {
-		return 0, errors.Wrap(err, key)
+		return 0, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
 	return val, nil
 }
 
@@ -68,126 +80,306 @@ func Float64FromStrMap(key string, sMap map[string]string, v *Float64Validation)
 	if !ok || valStr == "" {
 		val, err := ValidateFloat64Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, key)
+			return 0, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := Float64FromStr(valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, key)
+		return 0, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
 	return val, nil
 }
 
 func Float64FromStr(valStr string, v *Float64Validation) (float64, error) {
-	if valStr == "" {
-		return ValidateFloat64Missing(v)
+	return v.value().FromStr(valStr)
+}
+
+func Float64FromEnv(envVarName string, v *Float64Validation) (float64, error) {
+	return DefaultEnv().Float64FromEnv(envVarName, v)
+}
+
+func (env *Env) Float64FromEnv(envVarName string, v *Float64Validation) (float64, error) {
+	return v.value().fromEnv(env, envVarName)
+}
+
+func Float64FromFile(filePath string, v *Float64Validation) (float64, error) {
+	return uncachedFiles.Float64FromFile(filePath, v)
+}
+
+func (cache *FileCache) Float64FromFile(filePath string, v *Float64Validation) (float64, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
+	if err != nil {
+		return 0, errors.Wrap(err, filePath)
 	}
-	casted, castOk := s.ParseFloat64(valStr)
-	if !castOk {
-		return 0, errors.New(s.ErrInvalidPrimitiveType(valStr, s.PrimTypeFloat))
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateFloat64Missing(v)
+		if err != nil {
+			return 0, valueFileError(err, filePath, 0)
+		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	if err := checkSingleLine(*valStr); err != nil {
+		return 0, valueFileError(err, filePath, 0)
+	}
+	val, err := Float64FromStr(*valStr, v)
+	if err != nil {
+		return 0, valueFileError(err, filePath, 1)
 	}
-	return ValidateFloat64(casted, v)
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive))
+	return val, nil
 }
 
-func Float64FromEnv(envVarName string, v *Float64Validation) (float64, error) {
+func Float64FromEnvOrFile(envVarName string, filePath string, v *Float64Validation) (float64, error) {
 	valStr := ReadEnvVar(envVarName)
+	if valStr != nil && *valStr != "" {
+		return Float64FromEnv(envVarName, v)
+	}
+	return Float64FromFile(filePath, v)
+}
+
+func Float64FromRef(ref string, v *Float64Validation) (float64, error) {
+	valStr, err := ReadRef(ref)
+	if err != nil {
+		return 0, errors.Wrap(err, ref)
+	}
 	if valStr == nil || *valStr == "" {
 		val, err := ValidateFloat64Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, s.EnvVar(envVarName))
+			return 0, errors.Wrap(err, ref)
 		}
+		v.Report.record(ref, ValueSourceDefault, ref, redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := Float64FromStr(*valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, s.EnvVar(envVarName))
+		return 0, errors.Wrap(err, ref)
 	}
+	v.Report.record(ref, ValueSourceRef, ref, redactIf(val, v.Sensitive))
 	return val, nil
 }
 
-func Float64FromFile(filePath string, v *Float64Validation) (float64, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
-	if err != nil || len(valBytes) == 0 {
+func Float64FromSource(source Source, key string, v *Float64Validation) (float64, error) {
+	valStr, found, err := source.Lookup(key)
+	if err != nil {
+		return 0, errors.WrapKey(err, key)
+	}
+	if !found || valStr == "" {
 		val, err := ValidateFloat64Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, filePath)
+			return 0, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, describeSource(source, key), redactIf(val, v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
 	val, err := Float64FromStr(valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, filePath)
+		return 0, errors.WrapKey(errors.WithSource(err, describeSource(source, key)), key)
 	}
+	v.Report.record(key, ValueSourceLookup, describeSource(source, key), redactIf(val, v.Sensitive))
 	return val, nil
 }
 
-func Float64FromEnvOrFile(envVarName string, filePath string, v *Float64Validation) (float64, error) {
-	valStr := ReadEnvVar(envVarName)
-	if valStr != nil && *valStr != "" {
-		return Float64FromEnv(envVarName, v)
+func Float64FromArg(args []string, index int, argName string, v *Float64Validation) (float64, error) {
+	if index < 0 || index >= len(args) {
+		val, err := ValidateFloat64Missing(v)
+		if err != nil {
+			return 0, errors.Wrap(err, argName)
+		}
+		v.Report.record(argName, ValueSourceDefault, "", redactIf(val, v.Sensitive))
+		return val, nil
 	}
-	return Float64FromFile(filePath, v)
+	val, err := Float64FromStr(args[index], v)
+	if err != nil {
+		return 0, errors.Wrap(err, argName)
+	}
+	v.Report.record(argName, ValueSourceArg, "", redactIf(val, v.Sensitive))
+	return val, nil
 }
 
 func Float64FromPrompt(promptOpts *PromptOptions, v *Float64Validation) (float64, error) {
-	promptOpts.defaultStr = s.Float64(v.Default)
-	valStr := prompt(promptOpts)
-	if valStr == "" {
-		return ValidateFloat64Missing(v)
+	defaultVal, defaultErr := v.Default, error(nil)
+	if v.DefaultFunc != nil {
+		// DefaultFunc is only called once, for both the default which is shown and an empty response
+		defaultVal, defaultErr = v.DefaultFunc()
 	}
-	return Float64FromStr(valStr, v)
+	defaultStr := ""
+	if defaultErr == nil {
+		defaultStr = s.Float64(defaultVal)
+	}
+	promptOpts = promptOpts.withHints(ConstraintHint(v), defaultStr)
+	var val float64
+	var source ValueSource
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		if valStr == "" {
+			val, err = validateFloat64Missing(v, defaultVal, defaultErr)
+		} else {
+			val, err = Float64FromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(val, v.Sensitive))
+	}
+	return val, err
 }
 
 func ValidateFloat64Missing(v *Float64Validation) (float64, error) {
+	return v.value().ValidateMissing()
+}
+
+// validateFloat64Missing is the same as ValidateFloat64Missing, with the result of v.DefaultFunc instead of calling it
+func validateFloat64Missing(v *Float64Validation, defaultVal float64, defaultErr error) (float64, error) {
 	if v.Required {
-		return 0, errors.New(s.ErrMustBeDefined)
+		return 0, errors.Wrap(ErrRequired)
+	}
+	if defaultErr != nil {
+		return 0, errors.MarkUser(defaultErr)
 	}
-	return ValidateFloat64(v.Default, v)
+	return ValidateFloat64(defaultVal, v)
 }
 
 func ValidateFloat64(val float64, v *Float64Validation) (float64, error) {
-	err := ValidateFloat64Val(val, v)
-	if err != nil {
-		return 0, err
+	return v.value().Validate(val)
+}
+
+func ValidateFloat64Val(val float64, v *Float64Validation) error {
+	return v.value().validateVal(val)
+}
+
+func (v *Float64Validation) value() *Value[float64] {
+	return &Value[float64]{
+		Required:             v.Required,
+		Default:              v.Default,
+		AllowedValues:        v.AllowedValues,
+		GreaterThan:          v.GreaterThan,
+		GreaterThanOrEqualTo: v.GreaterThanOrEqualTo,
+		LessThan:             v.LessThan,
+		LessThanOrEqualTo:    v.LessThanOrEqualTo,
+		Sensitive:            v.Sensitive,
+		Report:               v.Report,
+		Validator:            v.Validator,
+		allowedValuesSet:     allowedValuesSet{shared: &v.allowedValuesSet},
+		defaultFunc:          v.DefaultFunc,
+		castFunc:             v.castVal,
+		parseFunc:            v.parseVal,
+		normalize:            v.normalizeVal,
 	}
+}
 
-	if v.Validator != nil {
-		return v.Validator(val)
+// castVal converts inter for Float64()
+func (v *Float64Validation) castVal(inter interface{}) (float64, error) {
+	casted, castOk := cast.InterfaceToFloat64(inter)
+	if !castOk {
+		return 0, errors.Wrap(&InvalidTypeError{Provided: redactIf(inter, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeFloat}})
 	}
-	return val, nil
+	return casted, nil
 }
 
-func ValidateFloat64Val(val float64, v *Float64Validation) error {
-	if v.GreaterThan != nil {
-		if val <= *v.GreaterThan {
-			return errors.New(s.ErrMustBeGreaterThan(val, *v.GreaterThan))
+// parseVal parses valStr for Float64FromStr(), returning false if nothing is left once it's normalized (e.g. with Lenient)
+func (v *Float64Validation) parseVal(valStr string) (float64, bool, error) {
+	if v.Lenient {
+		valStr = strings.TrimSpace(valStr)
+		if valStr == "" {
+			return 0, false, nil
 		}
 	}
-	if v.GreaterThanOrEqualTo != nil {
-		if val < *v.GreaterThanOrEqualTo {
-			return errors.New(s.ErrMustBeGreaterThanOrEqualTo(val, *v.GreaterThanOrEqualTo))
-		}
+	casted, castOk := s.ParseFloat64(valStr)
+	if !castOk {
+		return 0, false, errors.Wrap(&InvalidTypeError{Provided: redactIf(valStr, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeFloat}})
 	}
-	if v.LessThan != nil {
-		if val >= *v.LessThan {
-			return errors.New(s.ErrMustBeLessThan(val, *v.LessThan))
-		}
+	if v.DisallowExplicitPlus && strings.HasPrefix(strings.TrimSpace(valStr), "+") {
+		return 0, false, errors.NewUserCoded(s.MsgExplicitPlus, s.ErrExplicitPlus(valStr))
 	}
-	if v.LessThanOrEqualTo != nil {
-		if val > *v.LessThanOrEqualTo {
-			return errors.New(s.ErrMustBeLessThanOrEqualTo(val, *v.LessThanOrEqualTo))
-		}
+	return casted, true, nil
+}
+
+func (v *Float64Validation) normalizeVal(val float64) float64 {
+	if v.NormalizeNegativeZero && val == 0 {
+		val = 0 // -0 == 0, so this replaces -0 with 0
 	}
+	return val
+}
+
+//
+// With raw values
+//
+
+func Float64FromInterfaceMapWithRaw(key string, iMap map[string]interface{}, v *Float64Validation) (float64, string, error) {
+	val, err := Float64FromInterfaceMap(key, iMap, v)
+	raw := ""
+	if inter, ok := ReadInterfaceMapValue(key, iMap); ok {
+		raw = s.UserStrStripped(inter)
+	}
+	return val, raw, err
+}
+
+func Float64FromStrWithRaw(valStr string, v *Float64Validation) (float64, string, error) {
+	val, err := Float64FromStr(valStr, v)
+	return val, valStr, err
+}
+
+func Float64FromEnvWithRaw(envVarName string, v *Float64Validation) (float64, string, error) {
+	return DefaultEnv().Float64FromEnvWithRaw(envVarName, v)
+}
 
-	if v.AllowedValues != nil {
-		if !util.IsFloat64InSlice(val, v.AllowedValues) {
-			return errors.New(s.ErrInvalidFloat64(val, v.AllowedValues...))
+func (env *Env) Float64FromEnvWithRaw(envVarName string, v *Float64Validation) (float64, string, error) {
+	valStr := env.ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateFloat64Missing(v)
+		if err != nil {
+			return 0, "", envVarError(err, envVarName)
 		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+		return val, "", nil
 	}
+	val, err := Float64FromStr(*valStr, v)
+	if err != nil {
+		return 0, *valStr, envVarError(err, envVarName)
+	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+	return val, *valStr, nil
+}
 
-	return nil
+func Float64FromFileWithRaw(filePath string, v *Float64Validation) (float64, string, error) {
+	valStr, err := readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, false)
+	if err != nil {
+		return 0, "", errors.Wrap(err, filePath)
+	}
+	if valStr == nil {
+		val, err := ValidateFloat64Missing(v)
+		if err != nil {
+			return 0, "", valueFileError(err, filePath, 0)
+		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, "", nil
+	}
+	trimmed := *valStr
+	if !v.PreserveWhitespace {
+		trimmed = strings.TrimSpace(trimmed)
+	}
+	if trimmed == "" {
+		val, err := ValidateFloat64Missing(v)
+		if err != nil {
+			return 0, *valStr, valueFileError(err, filePath, 0)
+		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, *valStr, nil
+	}
+	if err := checkSingleLine(trimmed); err != nil {
+		return 0, *valStr, valueFileError(err, filePath, 0)
+	}
+	val, err := Float64FromStr(trimmed, v)
+	if err != nil {
+		return 0, *valStr, valueFileError(err, filePath, 1)
+	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive))
+	return val, *valStr, nil
 }
 
 //
@@ -197,7 +389,7 @@ func ValidateFloat64Val(val float64, v *Float64Validation) error {
 func MustFloat64FromEnv(envVarName string, v *Float64Validation) float64 {
 	val, err := Float64FromEnv(envVarName, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return val
 }
@@ -205,7 +397,7 @@ func MustFloat64FromEnv(envVarName string, v *Float64Validation) float64 {
 func MustFloat64FromFile(filePath string, v *Float64Validation) float64 {
 	val, err := Float64FromFile(filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return val
 }
@@ -213,7 +405,31 @@ func MustFloat64FromFile(filePath string, v *Float64Validation) float64 {
 func MustFloat64FromEnvOrFile(envVarName string, filePath string, v *Float64Validation) float64 {
 	val, err := Float64FromEnvOrFile(envVarName, filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
+	}
+	return val
+}
+
+func MustFloat64FromRef(ref string, v *Float64Validation) float64 {
+	val, err := Float64FromRef(ref, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustFloat64FromSource(source Source, key string, v *Float64Validation) float64 {
+	val, err := Float64FromSource(source, key, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustFloat64FromArg(args []string, index int, argName string, v *Float64Validation) float64 {
+	val, err := Float64FromArg(args, index, argName, v)
+	if err != nil {
+		Fatal(err)
 	}
 	return val
 }
@@ -17,23 +17,35 @@ limitations under the License.
 package configreader
 
 import (
+	"math"
+
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
 	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
 )
 
 type Float64ListValidation struct {
-	Required   bool
-	Default    []float64
-	AllowNull  bool
-	AllowEmpty bool
-	Validator  func([]float64) ([]float64, error)
+	Required     bool
+	Default      []float64
+	AllowNull    bool
+	AllowEmpty   bool
+	SumTo        *float64 // if set, the elements must add up to this value (e.g. 1.0 for probabilities)
+	SumTolerance float64  // how far the sum can be from SumTo, defaults to DefaultSumTolerance
+	Normalize    bool     // if SumTo is set, rescale the elements to sum to it instead of erroring
+	Delimiter    string   // for FromStr and FromEnv readers, defaults to DefaultListDelimiter (see WhitespaceDelimiter)
+	Report       *Report  // if set, each value which is read is recorded, along with where it came from
+	Validator    func([]float64) ([]float64, error)
 }
 
+const DefaultSumTolerance = 1e-9
+
 func Float64List(inter interface{}, v *Float64ListValidation) ([]float64, error) {
-	casted, castOk := cast.InterfaceToFloat64Slice(inter)
+	casted, invalidIndex, castOk := cast.InterfaceToFloat64SliceWithInvalidIndex(inter, false)
+	if invalidIndex >= 0 {
+		return nil, invalidElemTypeError(inter, invalidIndex, s.PrimTypeFloat)
+	}
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeFloatList))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeFloatList}})
 	}
 	return ValidateFloat64List(casted, v)
 }
@@ -43,20 +55,73 @@ func Float64ListFromInterfaceMap(key string, iMap map[string]interface{}, v *Flo
 	if !ok {
 		val, err := ValidateFloat64ListMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", val)
 		return val, nil
 	}
 	val, err := Float64List(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
+	}
+	v.Report.record(key, ValueSourceConfig, "", val)
+	return val, nil
+}
+
+// Float64ListFromInterfaceMapWithCount is like Float64ListFromInterfaceMap(), but the list must have as many elements as the
+// integer at countKey (e.g. "num_shards"). If countKey is missing and countV has no default, the length isn't checked
+func Float64ListFromInterfaceMapWithCount(key string, countKey string, iMap map[string]interface{}, v *Float64ListValidation, countV *IntPtrValidation) ([]float64, error) {
+	count, err := IntPtrFromInterfaceMap(countKey, iMap, countV)
+	if err != nil {
+		return nil, err
+	}
+	val, err := Float64ListFromInterfaceMap(key, iMap, v)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkListCount(len(val), countKey, count); err != nil {
+		return nil, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func Float64ListFromStr(valStr string, v *Float64ListValidation) ([]float64, error) {
+	if valStr == "" {
+		return ValidateFloat64ListMissing(v)
+	}
+	elems := splitList(valStr, v.Delimiter)
+	casted := make([]float64, len(elems))
+	for i, elem := range elems {
+		castedElem, ok := s.ParseFloat64(elem)
+		if !ok {
+			return nil, errors.WrapIndex(errors.Wrap(&InvalidTypeError{Provided: elem, Expected: []s.PrimitiveType{s.PrimTypeFloat}}), i)
+		}
+		casted[i] = castedElem
+	}
+	return ValidateFloat64List(casted, v)
+}
+
+func Float64ListFromEnv(envVarName string, v *Float64ListValidation) ([]float64, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateFloat64ListMissing(v)
+		if err != nil {
+			return nil, envVarError(err, envVarName)
+		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), val)
+		return val, nil
+	}
+	val, err := Float64ListFromStr(*valStr, v)
+	if err != nil {
+		return nil, envVarError(err, envVarName)
 	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), val)
 	return val, nil
 }
 
 func ValidateFloat64ListMissing(v *Float64ListValidation) ([]float64, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateFloat64List(v.Default, v)
 }
@@ -64,18 +129,75 @@ func ValidateFloat64ListMissing(v *Float64ListValidation) ([]float64, error) {
 func ValidateFloat64List(val []float64, v *Float64ListValidation) ([]float64, error) {
 	if !v.AllowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
 	if !v.AllowEmpty {
 		if val != nil && len(val) == 0 {
-			return nil, errors.New(s.ErrCannotBeEmpty)
+			return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
+		}
+	}
+
+	if v.SumTo != nil && len(val) > 0 {
+		var err error
+		val, err = checkFloat64ListSum(val, v)
+		if err != nil {
+			return nil, err
 		}
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
+
+func checkFloat64ListSum(val []float64, v *Float64ListValidation) ([]float64, error) {
+	var sum float64
+	for _, elem := range val {
+		sum += elem
+	}
+
+	tolerance := v.SumTolerance
+	if tolerance <= 0 {
+		tolerance = DefaultSumTolerance
+	}
+	if math.Abs(sum-*v.SumTo) <= tolerance {
+		return val, nil
+	}
+
+	if !v.Normalize {
+		return nil, errors.NewUserCoded(s.MsgWrongSum, s.ErrWrongSum(sum, *v.SumTo))
+	}
+	if sum == 0 {
+		return nil, errors.NewUserCoded(s.MsgCannotNormalizeZeroSum, s.ErrCannotNormalizeZeroSum(*v.SumTo))
+	}
+
+	normalized := make([]float64, len(val))
+	for i, elem := range val {
+		normalized[i] = elem * *v.SumTo / sum
+	}
+	return normalized, nil
+}
+
+//
+// Musts
+//
+
+func MustFloat64ListFromStr(valStr string, v *Float64ListValidation) []float64 {
+	val, err := Float64ListFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustFloat64ListFromEnv(envVarName string, v *Float64ListValidation) []float64 {
+	val, err := Float64ListFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
@@ -17,8 +17,6 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
-
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
 	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
@@ -33,16 +31,24 @@ type Float64PtrValidation struct {
 	GreaterThanOrEqualTo *float64
 	LessThan             *float64
 	LessThanOrEqualTo    *float64
+	PreserveWhitespace   bool    // don't trim surrounding whitespace (including the trailing newline) from values read from files
+	MaxFileBytes         int64   // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles bool    // allow FromFile readers to read from e.g. pipes and device files
+	Sensitive            bool    // replace the value with its length in errors (errors from Validator are shown as-is)
+	Report               *Report // if set, each value which is read is recorded, along with where it came from
 	Validator            func(*float64) (*float64, error)
+	allowedValuesSet     allowedValuesSet
 }
 
 func makeFloat64ValValidation(v *Float64PtrValidation) *Float64Validation {
 	return &Float64Validation{
+		Sensitive:            v.Sensitive,
 		AllowedValues:        v.AllowedValues,
 		GreaterThan:          v.GreaterThan,
 		GreaterThanOrEqualTo: v.GreaterThanOrEqualTo,
 		LessThan:             v.LessThan,
 		LessThanOrEqualTo:    v.LessThanOrEqualTo,
+		allowedValuesSet:     allowedValuesSet{shared: &v.allowedValuesSet},
 	}
 }
 
@@ -52,7 +58,7 @@ func Float64Ptr(inter interface{}, v *Float64PtrValidation) (*float64, error) {
 	}
 	casted, castOk := cast.InterfaceToFloat64(inter)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeFloat))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: redactIf(inter, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeFloat}})
 	}
 	return ValidateFloat64Ptr(&casted, v)
 }
@@ -62,14 +68,16 @@ func Float64PtrFromInterfaceMap(key string, iMap map[string]interface{}, v *Floa
 	if !ok {
 		val, err := ValidateFloat64PtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := Float64Ptr(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -78,14 +86,16 @@ func Float64PtrFromStrMap(key string, sMap map[string]string, v *Float64PtrValid
 	if !ok || valStr == "" {
 		val, err := ValidateFloat64PtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := Float64PtrFromStr(valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -95,41 +105,58 @@ func Float64PtrFromStr(valStr string, v *Float64PtrValidation) (*float64, error)
 	}
 	casted, castOk := s.ParseFloat64(valStr)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(valStr, s.PrimTypeFloat))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: redactIf(valStr, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeFloat}})
 	}
 	return ValidateFloat64Ptr(&casted, v)
 }
 
 func Float64PtrFromEnv(envVarName string, v *Float64PtrValidation) (*float64, error) {
-	valStr := ReadEnvVar(envVarName)
+	return DefaultEnv().Float64PtrFromEnv(envVarName, v)
+}
+
+func (env *Env) Float64PtrFromEnv(envVarName string, v *Float64PtrValidation) (*float64, error) {
+	valStr := env.ReadEnvVar(envVarName)
 	if valStr == nil || *valStr == "" {
 		val, err := ValidateFloat64PtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, s.EnvVar(envVarName))
+			return nil, envVarError(err, envVarName)
 		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := Float64PtrFromStr(*valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, s.EnvVar(envVarName))
+		return nil, envVarError(err, envVarName)
 	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
 func Float64PtrFromFile(filePath string, v *Float64PtrValidation) (*float64, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
-	if err != nil || len(valBytes) == 0 {
+	return uncachedFiles.Float64PtrFromFile(filePath, v)
+}
+
+func (cache *FileCache) Float64PtrFromFile(filePath string, v *Float64PtrValidation) (*float64, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
+	if err != nil {
+		return nil, errors.Wrap(err, filePath)
+	}
+	if valStr == nil || *valStr == "" {
 		val, err := ValidateFloat64PtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, filePath)
+			return nil, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
-	val, err := Float64PtrFromStr(valStr, v)
+	if err := checkSingleLine(*valStr); err != nil {
+		return nil, valueFileError(err, filePath, 0)
+	}
+	val, err := Float64PtrFromStr(*valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, filePath)
+		return nil, valueFileError(err, filePath, 1)
 	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -142,16 +169,28 @@ func Float64PtrFromEnvOrFile(envVarName string, filePath string, v *Float64PtrVa
 }
 
 func Float64PtrFromPrompt(promptOpts *PromptOptions, v *Float64PtrValidation) (*float64, error) {
-	valStr := prompt(promptOpts)
-	if valStr == "" {
-		return ValidateFloat64PtrMissing(v)
+	promptOpts = promptOpts.withHints(ConstraintHint(v), "")
+	var val *float64
+	var source ValueSource
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		if valStr == "" {
+			val, err = ValidateFloat64PtrMissing(v)
+		} else {
+			val, err = Float64PtrFromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(cast.Deref(val), v.Sensitive))
 	}
-	return Float64PtrFromStr(valStr, v)
+	return val, err
 }
 
 func ValidateFloat64PtrMissing(v *Float64PtrValidation) (*float64, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateFloat64Ptr(v.Default, v)
 }
@@ -159,7 +198,7 @@ func ValidateFloat64PtrMissing(v *Float64PtrValidation) (*float64, error) {
 func ValidateFloat64Ptr(val *float64, v *Float64PtrValidation) (*float64, error) {
 	if v.DisallowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
@@ -171,7 +210,8 @@ func ValidateFloat64Ptr(val *float64, v *Float64PtrValidation) (*float64, error)
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
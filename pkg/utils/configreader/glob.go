@@ -0,0 +1,218 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// GlobValidation reads glob patterns (with filepath.Match() syntax, e.g. "data/*.csv")
+type GlobValidation struct {
+	Required     bool
+	Default      string
+	RequireMatch bool // at least one file must match the pattern
+	Validator    func(string) (string, error)
+}
+
+func Glob(inter interface{}, v *GlobValidation) (string, error) {
+	if inter == nil {
+		return "", errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+	}
+	casted, castOk := cast.InterfaceToStr(inter)
+	if !castOk {
+		return "", errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeString}})
+	}
+	return GlobFromStr(casted, v)
+}
+
+func GlobFromInterfaceMap(key string, iMap map[string]interface{}, v *GlobValidation) (string, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateGlobMissing(v)
+		if err != nil {
+			return "", errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := Glob(inter, v)
+	if err != nil {
+		return "", errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func GlobFromStr(valStr string, v *GlobValidation) (string, error) {
+	valStr = strings.TrimSpace(valStr)
+	if valStr == "" {
+		return ValidateGlobMissing(v)
+	}
+	return ValidateGlob(valStr, v)
+}
+
+func GlobFromEnv(envVarName string, v *GlobValidation) (string, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateGlobMissing(v)
+		if err != nil {
+			return "", envVarError(err, envVarName)
+		}
+		return val, nil
+	}
+	val, err := GlobFromStr(*valStr, v)
+	if err != nil {
+		return "", envVarError(err, envVarName)
+	}
+	return val, nil
+}
+
+func ValidateGlobMissing(v *GlobValidation) (string, error) {
+	if v.Required {
+		return "", errors.Wrap(ErrRequired)
+	}
+	if v.Default == "" {
+		return "", nil
+	}
+	return ValidateGlob(v.Default, v)
+}
+
+func ValidateGlob(val string, v *GlobValidation) (string, error) {
+	if offset, reason := globSyntaxError(val); offset != -1 {
+		return "", errors.NewUserCoded(s.MsgInvalidGlob, s.ErrInvalidGlob(val, offset, reason))
+	}
+	if _, err := filepath.Match(val, ""); err != nil {
+		return "", errors.NewUserCoded(s.MsgInvalidGlob, s.ErrInvalidGlob(val, 0, err.Error()))
+	}
+
+	if v.RequireMatch {
+		matches, err := filepath.Glob(val)
+		if err != nil {
+			return "", errors.NewUserCoded(s.MsgInvalidGlob, s.ErrInvalidGlob(val, 0, err.Error()))
+		}
+		if len(matches) == 0 {
+			return "", errors.NewUserCoded(s.MsgGlobNoMatches, s.ErrGlobNoMatches(val))
+		}
+	}
+
+	if v.Validator != nil {
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
+	}
+	return val, nil
+}
+
+// globSyntaxError returns the byte offset of the first syntax error in pattern (following filepath.Match()'s rules)
+// and what's wrong there, or -1 if the pattern is valid. filepath.Match() only reports errors in the part of the pattern
+// which it reaches while matching, so it misses e.g. "*.csv[" when matching against ""
+func globSyntaxError(pattern string) (int, string) {
+	escapes := runtime.GOOS != "windows"
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if escapes {
+				if i == len(pattern)-1 {
+					return i, "trailing \\"
+				}
+				i++
+			}
+		case '[':
+			end, offset, reason := globClassEnd(pattern, i, escapes)
+			if offset != -1 {
+				return offset, reason
+			}
+			i = end
+		}
+	}
+	return -1, ""
+}
+
+// globClassEnd returns the offset of the "]" which ends the character class starting at start
+func globClassEnd(pattern string, start int, escapes bool) (int, int, string) {
+	i := start + 1
+	if i < len(pattern) && pattern[i] == '^' {
+		i++
+	}
+	numRanges := 0
+	for {
+		if i >= len(pattern) {
+			return 0, start, "unterminated ["
+		}
+		if pattern[i] == ']' && numRanges > 0 {
+			return i, -1, ""
+		}
+
+		// the low end of a range (or a single character)
+		next, offset, reason := globClassChar(pattern, i, escapes)
+		if offset != -1 {
+			return 0, offset, reason
+		}
+		i = next
+		if i < len(pattern) && pattern[i] == '-' {
+			if next, offset, reason = globClassChar(pattern, i+1, escapes); offset != -1 {
+				return 0, offset, reason
+			}
+			i = next
+		}
+		numRanges++
+	}
+}
+
+// globClassChar returns the offset after the (possibly escaped) character at i in a character class
+func globClassChar(pattern string, i int, escapes bool) (int, int, string) {
+	if i >= len(pattern) {
+		return 0, i, "unterminated ["
+	}
+	switch pattern[i] {
+	case '-', ']':
+		return 0, i, "unexpected " + pattern[i:i+1]
+	case '\\':
+		if escapes {
+			i++
+			if i >= len(pattern) {
+				return 0, i - 1, "trailing \\"
+			}
+		}
+	}
+	_, size := utf8.DecodeRuneInString(pattern[i:])
+	return i + size, -1, ""
+}
+
+//
+// Musts
+//
+
+func MustGlobFromStr(valStr string, v *GlobValidation) string {
+	val, err := GlobFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustGlobFromEnv(envVarName string, v *GlobValidation) string {
+	val, err := GlobFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
@@ -17,7 +17,12 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
+	"encoding/json"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
 
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
 	"github.com/cortexlabs/cortex/pkg/utils/cast"
@@ -26,40 +31,78 @@ import (
 )
 
 type IntValidation struct {
-	Required             bool
-	Default              int
-	AllowedValues        []int
-	GreaterThan          *int
-	GreaterThanOrEqualTo *int
-	LessThan             *int
-	LessThanOrEqualTo    *int
-	Validator            func(int) (int, error)
+	Required              bool
+	Default               int
+	DefaultFunc           func() (int, error) // if set, it's called to compute the default (e.g. from values which have already been read)
+	AllowedValues         []int
+	GreaterThan           *int
+	GreaterThanOrEqualTo  *int
+	LessThan              *int
+	LessThanOrEqualTo     *int
+	PreserveWhitespace    bool  // don't trim surrounding whitespace (including the trailing newline) from values read from files
+	MaxFileBytes          int64 // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles  bool  // allow FromFile readers to read from e.g. pipes and device files
+	AllowCommaGrouping    bool  // accept US-style grouping commas in strings (e.g. "1,000,000")
+	Lenient               bool  // accept surrounding whitespace, and grouping commas or underscores in strings (e.g. " 1_000_000 ")
+	MustBePowerOfTwo      bool
+	DisallowLeadingZeros  bool     // reject strings like "007" (but not "0"), which may be a typo or meant as octal
+	AllowExponentNotation bool     // accept strings like "1e6" or "2.5e3", if the value is an integer
+	AllowTruncation       bool     // accept floats with fractional parts by truncating them (instead of erroring)
+	UnlimitedTokens       []string // e.g. "unlimited" or "-1" (case-insensitive); UnlimitedValue is returned for these
+	UnlimitedValue        int      // e.g. -1 or math.MaxInt64; it's returned for UnlimitedTokens (and a Default equal to it) without other validation
+	Sensitive             bool     // replace the value with its length in errors and reports (errors from Validator are shown as-is)
+	Report                *Report  // if set, each value which is read is recorded, along with where it came from
+	Validator             func(int) (int, error)
+	allowedValuesSet      allowedValuesSet
 }
 
 func Int(inter interface{}, v *IntValidation) (int, error) {
-	if inter == nil {
-		return 0, errors.New(s.ErrCannotBeNull)
-	}
-	casted, castOk := cast.InterfaceToInt(inter)
-	if !castOk {
-		return 0, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeInt))
-	}
-	return ValidateInt(casted, v)
+	return v.value().From(inter)
 }
 
 func IntFromInterfaceMap(key string, iMap map[string]interface{}, v *IntValidation) (int, error) {
+	return v.value().FromInterfaceMap(key, iMap)
+}
+
+// IntFromInterfaceMapWithFallback is like IntFromInterfaceMap, except that if the key is missing, fallback (if not nil)
+// is used instead of v.Default and v.Required
+func IntFromInterfaceMapWithFallback(key string, iMap map[string]interface{}, v *IntValidation, fallback *int) (int, error) {
 	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateIntMissingWithFallback(v, fallback)
+		if err != nil {
+			return 0, errors.WrapKey(err, key)
+		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := Int(inter, v)
+	if err != nil {
+		return 0, errors.WrapKey(err, key)
+	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
+	return val, nil
+}
+
+// IntFromInterfaceMapWithRefs is like IntFromInterfaceMap(), but the value can refer to another field in the map (e.g. "${default_timeout}")
+func IntFromInterfaceMapWithRefs(key string, iMap map[string]interface{}, v *IntValidation) (int, error) {
+	inter, ok, err := ReadInterfaceMapValueWithRefs(key, iMap)
+	if err != nil {
+		return 0, errors.WrapKey(err, key)
+	}
 	if !ok {
 		val, err := ValidateIntMissing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, key)
+			return 0, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := Int(inter, v)
 	if err != nil {
-		return 0, errors.Wrap(err, key)
+		return 0, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
 	return val, nil
 }
 
@@ -68,58 +111,77 @@ func IntFromStrMap(key string, sMap map[string]string, v *IntValidation) (int, e
 	if !ok || valStr == "" {
 		val, err := ValidateIntMissing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, key)
+			return 0, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := IntFromStr(valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, key)
+		return 0, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
 	return val, nil
 }
 
 func IntFromStr(valStr string, v *IntValidation) (int, error) {
-	if valStr == "" {
-		return ValidateIntMissing(v)
-	}
-	casted, castOk := s.ParseInt(valStr)
-	if !castOk {
-		return 0, errors.New(s.ErrInvalidPrimitiveType(valStr, s.PrimTypeInt))
-	}
-	return ValidateInt(casted, v)
+	return v.value().FromStr(valStr)
 }
 
 func IntFromEnv(envVarName string, v *IntValidation) (int, error) {
-	valStr := ReadEnvVar(envVarName)
+	return DefaultEnv().IntFromEnv(envVarName, v)
+}
+
+func (env *Env) IntFromEnv(envVarName string, v *IntValidation) (int, error) {
+	return v.value().fromEnv(env, envVarName)
+}
+
+func IntFromFile(filePath string, v *IntValidation) (int, error) {
+	return uncachedFiles.IntFromFile(filePath, v)
+}
+
+func (cache *FileCache) IntFromFile(filePath string, v *IntValidation) (int, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
+	if err != nil {
+		return 0, errors.Wrap(err, filePath)
+	}
 	if valStr == nil || *valStr == "" {
 		val, err := ValidateIntMissing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, s.EnvVar(envVarName))
+			return 0, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
 		return val, nil
 	}
+	if err := checkSingleLine(*valStr); err != nil {
+		return 0, valueFileError(err, filePath, 0)
+	}
 	val, err := IntFromStr(*valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, s.EnvVar(envVarName))
+		return 0, valueFileError(err, filePath, 1)
 	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive))
 	return val, nil
 }
 
-func IntFromFile(filePath string, v *IntValidation) (int, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
-	if err != nil || len(valBytes) == 0 {
+func IntFromFileLine(filePath string, line int, v *IntValidation) (int, error) {
+	valStr, err := readFileLine(filePath, line, v.MaxFileBytes, v.AllowNonRegularFiles)
+	if err != nil {
+		return 0, errors.Wrap(err, filePath)
+	}
+	if valStr == nil || *valStr == "" {
 		val, err := ValidateIntMissing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, filePath)
+			return 0, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, errors.Position{File: filePath, Line: line}.String(), redactIf(val, v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
-	val, err := IntFromStr(valStr, v)
+	val, err := IntFromStr(*valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, filePath)
+		return 0, valueFileError(err, filePath, line)
 	}
+	v.Report.record(filePath, ValueSourceFile, errors.Position{File: filePath, Line: line}.String(), redactIf(val, v.Sensitive))
 	return val, nil
 }
 
@@ -131,65 +193,396 @@ func IntFromEnvOrFile(envVarName string, filePath string, v *IntValidation) (int
 	return IntFromFile(filePath, v)
 }
 
+func IntFromRef(ref string, v *IntValidation) (int, error) {
+	valStr, err := ReadRef(ref)
+	if err != nil {
+		return 0, errors.Wrap(err, ref)
+	}
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateIntMissing(v)
+		if err != nil {
+			return 0, errors.Wrap(err, ref)
+		}
+		v.Report.record(ref, ValueSourceDefault, ref, redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := IntFromStr(*valStr, v)
+	if err != nil {
+		return 0, errors.Wrap(err, ref)
+	}
+	v.Report.record(ref, ValueSourceRef, ref, redactIf(val, v.Sensitive))
+	return val, nil
+}
+
+func IntFromSource(source Source, key string, v *IntValidation) (int, error) {
+	valStr, found, err := source.Lookup(key)
+	if err != nil {
+		return 0, errors.WrapKey(err, key)
+	}
+	if !found || valStr == "" {
+		val, err := ValidateIntMissing(v)
+		if err != nil {
+			return 0, errors.WrapKey(err, key)
+		}
+		v.Report.record(key, ValueSourceDefault, describeSource(source, key), redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := IntFromStr(valStr, v)
+	if err != nil {
+		return 0, errors.WrapKey(errors.WithSource(err, describeSource(source, key)), key)
+	}
+	v.Report.record(key, ValueSourceLookup, describeSource(source, key), redactIf(val, v.Sensitive))
+	return val, nil
+}
+
+func IntFromArg(args []string, index int, argName string, v *IntValidation) (int, error) {
+	if index < 0 || index >= len(args) {
+		val, err := ValidateIntMissing(v)
+		if err != nil {
+			return 0, errors.Wrap(err, argName)
+		}
+		v.Report.record(argName, ValueSourceDefault, "", redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := IntFromStr(args[index], v)
+	if err != nil {
+		return 0, errors.Wrap(err, argName)
+	}
+	v.Report.record(argName, ValueSourceArg, "", redactIf(val, v.Sensitive))
+	return val, nil
+}
+
 func IntFromPrompt(promptOpts *PromptOptions, v *IntValidation) (int, error) {
-	promptOpts.defaultStr = s.Int(v.Default)
-	valStr := prompt(promptOpts)
-	if valStr == "" {
-		return ValidateIntMissing(v)
+	defaultVal, defaultErr := v.Default, error(nil)
+	if v.DefaultFunc != nil {
+		// DefaultFunc is only called once, for both the default which is shown and an empty response
+		defaultVal, defaultErr = v.DefaultFunc()
+	}
+	defaultStr := ""
+	if defaultErr == nil {
+		defaultStr = s.Int(defaultVal)
 	}
-	return IntFromStr(valStr, v)
+	promptOpts = promptOpts.withHints(ConstraintHint(v), defaultStr)
+	var val int
+	var source ValueSource
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		if valStr == "" {
+			val, err = validateIntMissing(v, defaultVal, defaultErr)
+		} else {
+			val, err = IntFromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(val, v.Sensitive))
+	}
+	return val, err
 }
 
 func ValidateIntMissing(v *IntValidation) (int, error) {
+	return v.value().ValidateMissing()
+}
+
+// validateIntMissing is the same as ValidateIntMissing, with the result of v.DefaultFunc instead of calling it
+func validateIntMissing(v *IntValidation, defaultVal int, defaultErr error) (int, error) {
 	if v.Required {
-		return 0, errors.New(s.ErrMustBeDefined)
+		return 0, errors.Wrap(ErrRequired)
+	}
+	if defaultErr != nil {
+		return 0, errors.MarkUser(defaultErr)
+	}
+	return ValidateInt(defaultVal, v)
+}
+
+// ValidateIntMissingWithFallback validates fallback if it's not nil, otherwise it's the same as ValidateIntMissing
+func ValidateIntMissingWithFallback(v *IntValidation, fallback *int) (int, error) {
+	if fallback != nil {
+		return ValidateInt(*fallback, v)
 	}
-	return ValidateInt(v.Default, v)
+	return ValidateIntMissing(v)
 }
 
 func ValidateInt(val int, v *IntValidation) (int, error) {
-	err := ValidateIntVal(val, v)
-	if err != nil {
-		return 0, err
+	return v.value().Validate(val)
+}
+
+func ValidateIntVal(val int, v *IntValidation) error {
+	return v.value().validateVal(val)
+}
+
+func (v *IntValidation) value() *Value[int] {
+	return &Value[int]{
+		Required:             v.Required,
+		Default:              v.Default,
+		AllowedValues:        v.AllowedValues,
+		GreaterThan:          v.GreaterThan,
+		GreaterThanOrEqualTo: v.GreaterThanOrEqualTo,
+		LessThan:             v.LessThan,
+		LessThanOrEqualTo:    v.LessThanOrEqualTo,
+		Sensitive:            v.Sensitive,
+		Report:               v.Report,
+		Validator:            v.Validator,
+		allowedValuesSet:     allowedValuesSet{shared: &v.allowedValuesSet},
+		defaultFunc:          v.DefaultFunc,
+		defaultUnchecked:     len(v.UnlimitedTokens) > 0 && v.Default == v.UnlimitedValue,
+		keyword:              v.unlimitedVal,
+		castFunc:             v.castVal,
+		parseFunc:            v.parseVal,
+		check:                v.checkVal,
 	}
+}
 
-	if v.Validator != nil {
-		return v.Validator(val)
+// unlimitedVal returns UnlimitedValue if inter is one of UnlimitedTokens
+func (v *IntValidation) unlimitedVal(inter interface{}) (int, bool) {
+	if len(v.UnlimitedTokens) > 0 && isUnlimitedToken(inter, v.UnlimitedTokens) {
+		return v.UnlimitedValue, true
 	}
-	return val, nil
+	return 0, false
 }
 
-func ValidateIntVal(val int, v *IntValidation) error {
-	if v.GreaterThan != nil {
-		if val <= *v.GreaterThan {
-			return errors.New(s.ErrMustBeGreaterThan(val, *v.GreaterThan))
+// castVal converts inter for Int()
+func (v *IntValidation) castVal(inter interface{}) (int, error) {
+	if len(v.UnlimitedTokens) > 0 {
+		if _, ok := cast.InterfaceToFloat64(inter); !ok {
+			return 0, errors.NewUserCoded(s.MsgInvalidIntOrKeyword, s.ErrInvalidIntOrKeyword(redactIf(inter, v.Sensitive), v.UnlimitedTokens))
+		}
+	}
+	casted, castOk := cast.InterfaceToInt(inter)
+	if !castOk {
+		fromFloat, err := intFromFloat(inter, strconv.IntSize, v.AllowTruncation, v.Sensitive)
+		if err != nil {
+			return 0, err
 		}
+		casted = int(fromFloat)
 	}
-	if v.GreaterThanOrEqualTo != nil {
-		if val < *v.GreaterThanOrEqualTo {
-			return errors.New(s.ErrMustBeGreaterThanOrEqualTo(val, *v.GreaterThanOrEqualTo))
+	return casted, nil
+}
+
+// parseVal parses valStr for IntFromStr(), returning false if nothing is left once it's normalized (e.g. with Lenient)
+func (v *IntValidation) parseVal(valStr string) (int, bool, error) {
+	if v.AllowCommaGrouping {
+		stripped, ok := s.StripCommaGrouping(valStr)
+		if !ok {
+			return 0, false, errors.NewUserCoded(s.MsgMisplacedGroupingComma, s.ErrMisplacedGroupingComma(redactIf(valStr, v.Sensitive)))
 		}
+		valStr = stripped
 	}
-	if v.LessThan != nil {
-		if val >= *v.LessThan {
-			return errors.New(s.ErrMustBeLessThan(val, *v.LessThan))
+	if v.Lenient {
+		stripped, ok := s.StripDigitGrouping(strings.TrimSpace(valStr))
+		if !ok {
+			return 0, false, errors.NewUserCoded(s.MsgMisplacedGroupingSeparator, s.ErrMisplacedGroupingSeparator(redactIf(valStr, v.Sensitive)))
+		}
+		if stripped == "" {
+			return 0, false, nil
 		}
+		valStr = stripped
 	}
-	if v.LessThanOrEqualTo != nil {
-		if val > *v.LessThanOrEqualTo {
-			return errors.New(s.ErrMustBeLessThanOrEqualTo(val, *v.LessThanOrEqualTo))
+	casted, castOk := s.ParseInt(valStr)
+	if !castOk && v.AllowExponentNotation {
+		casted64, err := intFromExponentStr(valStr, strconv.IntSize, v.Sensitive)
+		if err != nil {
+			return 0, false, err
 		}
+		return int(casted64), true, nil
+	}
+	if !castOk && len(v.UnlimitedTokens) > 0 {
+		return 0, false, errors.NewUserCoded(s.MsgInvalidIntOrKeyword, s.ErrInvalidIntOrKeyword(redactIf(valStr, v.Sensitive), v.UnlimitedTokens))
+	}
+	if !castOk {
+		return 0, false, errors.Wrap(&InvalidTypeError{Provided: redactIf(valStr, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeInt}})
+	}
+	if v.DisallowLeadingZeros && s.HasLeadingZeros(valStr) {
+		return 0, false, errors.NewUserCoded(s.MsgLeadingZeros, s.ErrLeadingZeros(redactIf(valStr, v.Sensitive)))
 	}
+	return casted, true, nil
+}
 
-	if v.AllowedValues != nil {
-		if !util.IsIntInSlice(val, v.AllowedValues) {
-			return errors.New(s.ErrInvalidInt(val, v.AllowedValues...))
+// checkVal runs the checks which ValidateIntVal() adds to the range and allowed value checks
+func (v *IntValidation) checkVal(val int) error {
+	if v.MustBePowerOfTwo && !util.IsPowerOfTwo(int64(val)) {
+		if v.Sensitive {
+			return errors.NewUserCoded(s.MsgMustBePowerOfTwo, s.ErrMustBePowerOfTwo(s.Redacted(val), nil))
 		}
+		return errors.NewUserCoded(s.MsgMustBePowerOfTwo, s.ErrMustBePowerOfTwo(val, util.PowersOfTwoNear(int64(val))))
 	}
 
 	return nil
 }
 
+//
+// With raw values
+//
+
+func IntFromInterfaceMapWithRaw(key string, iMap map[string]interface{}, v *IntValidation) (int, string, error) {
+	val, err := IntFromInterfaceMap(key, iMap, v)
+	raw := ""
+	if inter, ok := ReadInterfaceMapValue(key, iMap); ok {
+		raw = s.UserStrStripped(inter)
+	}
+	return val, raw, err
+}
+
+func IntFromStrWithRaw(valStr string, v *IntValidation) (int, string, error) {
+	val, err := IntFromStr(valStr, v)
+	return val, valStr, err
+}
+
+func IntFromEnvWithRaw(envVarName string, v *IntValidation) (int, string, error) {
+	return DefaultEnv().IntFromEnvWithRaw(envVarName, v)
+}
+
+func (env *Env) IntFromEnvWithRaw(envVarName string, v *IntValidation) (int, string, error) {
+	valStr := env.ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateIntMissing(v)
+		if err != nil {
+			return 0, "", envVarError(err, envVarName)
+		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+		return val, "", nil
+	}
+	val, err := IntFromStr(*valStr, v)
+	if err != nil {
+		return 0, *valStr, envVarError(err, envVarName)
+	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+	return val, *valStr, nil
+}
+
+func IntFromFileWithRaw(filePath string, v *IntValidation) (int, string, error) {
+	valStr, err := readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, false)
+	if err != nil {
+		return 0, "", errors.Wrap(err, filePath)
+	}
+	if valStr == nil {
+		val, err := ValidateIntMissing(v)
+		if err != nil {
+			return 0, "", valueFileError(err, filePath, 0)
+		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, "", nil
+	}
+	trimmed := *valStr
+	if !v.PreserveWhitespace {
+		trimmed = strings.TrimSpace(trimmed)
+	}
+	if trimmed == "" {
+		val, err := ValidateIntMissing(v)
+		if err != nil {
+			return 0, *valStr, valueFileError(err, filePath, 0)
+		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, *valStr, nil
+	}
+	if err := checkSingleLine(trimmed); err != nil {
+		return 0, *valStr, valueFileError(err, filePath, 0)
+	}
+	val, err := IntFromStr(trimmed, v)
+	if err != nil {
+		return 0, *valStr, valueFileError(err, filePath, 1)
+	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive))
+	return val, *valStr, nil
+}
+
+// isUnlimitedToken returns whether inter is one of tokens, ignoring case and surrounding whitespace. Ints are compared
+// by their string representation (e.g. for a "-1" token)
+func isUnlimitedToken(inter interface{}, tokens []string) bool {
+	var str string
+	if casted, ok := inter.(string); ok {
+		str = strings.TrimSpace(casted)
+	} else if casted, ok := cast.InterfaceToInt64(inter); ok {
+		str = strconv.FormatInt(casted, 10)
+	} else {
+		return false
+	}
+	for _, token := range tokens {
+		if strings.EqualFold(str, strings.TrimSpace(token)) {
+			return true
+		}
+	}
+	return false
+}
+
+// intFromFloat converts floats for the int readers: integral values are accepted, and fractional values are rejected
+// (or truncated, if allowTruncation). Values of other types are rejected with an InvalidTypeError
+func intFromFloat(inter interface{}, bits int, allowTruncation bool, sensitive bool) (int64, error) {
+	var val float64
+	switch casted := inter.(type) {
+	case float32:
+		val = float64(casted)
+	case float64:
+		val = casted
+	case json.Number:
+		var ok bool
+		if val, ok = cast.InterfaceToFloat64(casted); !ok {
+			return 0, errors.Wrap(&InvalidTypeError{Provided: redactIf(inter, sensitive), Expected: []s.PrimitiveType{s.PrimTypeInt}})
+		}
+	default:
+		return 0, errors.Wrap(&InvalidTypeError{Provided: redactIf(inter, sensitive), Expected: []s.PrimitiveType{s.PrimTypeInt}})
+	}
+
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return 0, errors.NewUserCoded(s.MsgExpectedInteger, s.ErrExpectedInteger(redactIf(inter, sensitive)))
+	}
+
+	casted, ok := cast.InterfaceToInt64Downcast(inter) // exact for json.Numbers
+	if !ok && val != math.Trunc(val) {
+		if !allowTruncation {
+			return 0, errors.NewUserCoded(s.MsgExpectedInteger, s.ErrExpectedInteger(redactIf(inter, sensitive)))
+		}
+		casted, ok = cast.InterfaceToInt64Downcast(math.Trunc(val))
+	}
+	if !ok || bits < 64 && (casted < -1<<(bits-1) || casted > 1<<(bits-1)-1) {
+		return 0, errors.NewUserCoded(s.MsgIntOutOfRange, s.ErrIntOutOfRange(redactIf(inter, sensitive), bits))
+	}
+	return casted, nil
+}
+
+var intExponentRegex = regexp.MustCompile(`^([+-]?(?:\d+\.?\d*|\.\d+))[eE]([+-]?\d+)$`)
+
+// intFromExponentStr parses integers in exponent notation (e.g. "1e6" or "2.5e3") exactly, without rounding through a float
+func intFromExponentStr(valStr string, bits int, sensitive bool) (int64, error) {
+	match := intExponentRegex.FindStringSubmatch(valStr)
+	if match == nil {
+		return 0, errors.NewUserCoded(s.MsgInvalidIntOrExponent, s.ErrInvalidIntOrExponent(redactIf(valStr, sensitive)))
+	}
+	mantissa, ok := new(big.Rat).SetString(match[1])
+	exponent, err := strconv.Atoi(match[2])
+	if !ok || err != nil {
+		return 0, errors.NewUserCoded(s.MsgInvalidIntOrExponent, s.ErrInvalidIntOrExponent(redactIf(valStr, sensitive)))
+	}
+
+	// avoid computing huge powers of 10; the result is 0, out of range, or fractional anyway
+	if mantissa.Sign() != 0 && (exponent > len(valStr)+bits || exponent < -(len(valStr)+bits)) {
+		if exponent > 0 {
+			return 0, errors.NewUserCoded(s.MsgIntOutOfRange, s.ErrIntOutOfRange(redactIf(valStr, sensitive), bits))
+		}
+		return 0, errors.NewUserCoded(s.MsgExpectedInteger, s.ErrExpectedInteger(redactIf(valStr, sensitive)))
+	}
+	if mantissa.Sign() == 0 {
+		return 0, nil
+	}
+
+	scale := new(big.Rat)
+	if exponent >= 0 {
+		scale.SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exponent)), nil))
+	} else {
+		scale.SetFrac(big.NewInt(1), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-exponent)), nil))
+	}
+	val := mantissa.Mul(mantissa, scale)
+	if !val.IsInt() {
+		return 0, errors.NewUserCoded(s.MsgExpectedInteger, s.ErrExpectedInteger(redactIf(valStr, sensitive)))
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	if val.Num().Cmp(limit) >= 0 || val.Num().Cmp(new(big.Int).Neg(limit)) < 0 {
+		return 0, errors.NewUserCoded(s.MsgIntOutOfRange, s.ErrIntOutOfRange(redactIf(valStr, sensitive), bits))
+	}
+	return val.Num().Int64(), nil
+}
+
 //
 // Musts
 //
@@ -197,7 +590,7 @@ func ValidateIntVal(val int, v *IntValidation) error {
 func MustIntFromEnv(envVarName string, v *IntValidation) int {
 	val, err := IntFromEnv(envVarName, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return val
 }
@@ -205,7 +598,15 @@ func MustIntFromEnv(envVarName string, v *IntValidation) int {
 func MustIntFromFile(filePath string, v *IntValidation) int {
 	val, err := IntFromFile(filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
+	}
+	return val
+}
+
+func MustIntFromFileLine(filePath string, line int, v *IntValidation) int {
+	val, err := IntFromFileLine(filePath, line, v)
+	if err != nil {
+		Fatal(err)
 	}
 	return val
 }
@@ -213,7 +614,31 @@ func MustIntFromFile(filePath string, v *IntValidation) int {
 func MustIntFromEnvOrFile(envVarName string, filePath string, v *IntValidation) int {
 	val, err := IntFromEnvOrFile(envVarName, filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
+	}
+	return val
+}
+
+func MustIntFromRef(ref string, v *IntValidation) int {
+	val, err := IntFromRef(ref, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustIntFromSource(source Source, key string, v *IntValidation) int {
+	val, err := IntFromSource(source, key, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustIntFromArg(args []string, index int, argName string, v *IntValidation) int {
+	val, err := IntFromArg(args, index, argName, v)
+	if err != nil {
+		Fatal(err)
 	}
 	return val
 }
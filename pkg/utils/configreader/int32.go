@@ -17,12 +17,10 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
+	"strings"
 
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
-	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
-	"github.com/cortexlabs/cortex/pkg/utils/util"
 )
 
 type Int32Validation struct {
@@ -33,161 +31,255 @@ type Int32Validation struct {
 	GreaterThanOrEqualTo *int32
 	LessThan             *int32
 	LessThanOrEqualTo    *int32
+	PreserveWhitespace   bool    // don't trim surrounding whitespace (including the trailing newline) from values read from files
+	MaxFileBytes         int64   // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles bool    // allow FromFile readers to read from e.g. pipes and device files
+	AllowTruncation      bool    // accept floats with fractional parts by truncating them (instead of erroring)
+	Sensitive            bool    // replace the value with its length in errors and reports (errors from Validator are shown as-is)
+	Report               *Report // if set, each value which is read is recorded, along with where it came from
 	Validator            func(int32) (int32, error)
+	allowedValuesSet     allowedValuesSet
 }
 
 func Int32(inter interface{}, v *Int32Validation) (int32, error) {
-	if inter == nil {
-		return 0, errors.New(s.ErrCannotBeNull)
-	}
-	casted, castOk := cast.InterfaceToInt32(inter)
-	if !castOk {
-		return 0, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeInt))
-	}
-	return ValidateInt32(casted, v)
+	return v.value().From(inter)
 }
 
 func Int32FromInterfaceMap(key string, iMap map[string]interface{}, v *Int32Validation) (int32, error) {
-	inter, ok := ReadInterfaceMapValue(key, iMap)
-	if !ok {
+	return v.value().FromInterfaceMap(key, iMap)
+}
+
+func Int32FromStrMap(key string, sMap map[string]string, v *Int32Validation) (int32, error) {
+	valStr, ok := sMap[key]
+	if !ok || valStr == "" {
 		val, err := ValidateInt32Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, key)
+			return 0, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
 		return val, nil
 	}
-	val, err := Int32(inter, v)
+	val, err := Int32FromStr(valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, key)
+		return 0, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
 	return val, nil
 }
 
-func Int32FromStrMap(key string, sMap map[string]string, v *Int32Validation) (int32, error) {
-	valStr, ok := sMap[key]
-	if !ok || valStr == "" {
+func Int32FromStr(valStr string, v *Int32Validation) (int32, error) {
+	return v.value().FromStr(valStr)
+}
+
+func Int32FromEnv(envVarName string, v *Int32Validation) (int32, error) {
+	return DefaultEnv().Int32FromEnv(envVarName, v)
+}
+
+func (env *Env) Int32FromEnv(envVarName string, v *Int32Validation) (int32, error) {
+	return v.value().fromEnv(env, envVarName)
+}
+
+func Int32FromFile(filePath string, v *Int32Validation) (int32, error) {
+	return uncachedFiles.Int32FromFile(filePath, v)
+}
+
+func (cache *FileCache) Int32FromFile(filePath string, v *Int32Validation) (int32, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
+	if err != nil {
+		return 0, errors.Wrap(err, filePath)
+	}
+	if valStr == nil || *valStr == "" {
 		val, err := ValidateInt32Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, key)
+			return 0, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
 		return val, nil
 	}
-	val, err := Int32FromStr(valStr, v)
+	if err := checkSingleLine(*valStr); err != nil {
+		return 0, valueFileError(err, filePath, 0)
+	}
+	val, err := Int32FromStr(*valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, key)
+		return 0, valueFileError(err, filePath, 1)
 	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive))
 	return val, nil
 }
 
-func Int32FromStr(valStr string, v *Int32Validation) (int32, error) {
-	if valStr == "" {
-		return ValidateInt32Missing(v)
-	}
-	casted, castOk := s.ParseInt32(valStr)
-	if !castOk {
-		return 0, errors.New(s.ErrInvalidPrimitiveType(valStr, s.PrimTypeInt))
+func Int32FromEnvOrFile(envVarName string, filePath string, v *Int32Validation) (int32, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr != nil && *valStr != "" {
+		return Int32FromEnv(envVarName, v)
 	}
-	return ValidateInt32(casted, v)
+	return Int32FromFile(filePath, v)
 }
 
-func Int32FromEnv(envVarName string, v *Int32Validation) (int32, error) {
-	valStr := ReadEnvVar(envVarName)
+func Int32FromRef(ref string, v *Int32Validation) (int32, error) {
+	valStr, err := ReadRef(ref)
+	if err != nil {
+		return 0, errors.Wrap(err, ref)
+	}
 	if valStr == nil || *valStr == "" {
 		val, err := ValidateInt32Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, s.EnvVar(envVarName))
+			return 0, errors.Wrap(err, ref)
 		}
+		v.Report.record(ref, ValueSourceDefault, ref, redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := Int32FromStr(*valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, s.EnvVar(envVarName))
+		return 0, errors.Wrap(err, ref)
 	}
+	v.Report.record(ref, ValueSourceRef, ref, redactIf(val, v.Sensitive))
 	return val, nil
 }
 
-func Int32FromFile(filePath string, v *Int32Validation) (int32, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
-	if err != nil || len(valBytes) == 0 {
+func Int32FromSource(source Source, key string, v *Int32Validation) (int32, error) {
+	valStr, found, err := source.Lookup(key)
+	if err != nil {
+		return 0, errors.WrapKey(err, key)
+	}
+	if !found || valStr == "" {
 		val, err := ValidateInt32Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, filePath)
+			return 0, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, describeSource(source, key), redactIf(val, v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
 	val, err := Int32FromStr(valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, filePath)
+		return 0, errors.WrapKey(errors.WithSource(err, describeSource(source, key)), key)
 	}
+	v.Report.record(key, ValueSourceLookup, describeSource(source, key), redactIf(val, v.Sensitive))
 	return val, nil
 }
 
-func Int32FromEnvOrFile(envVarName string, filePath string, v *Int32Validation) (int32, error) {
-	valStr := ReadEnvVar(envVarName)
-	if valStr != nil && *valStr != "" {
-		return Int32FromEnv(envVarName, v)
-	}
-	return Int32FromFile(filePath, v)
-}
-
 func Int32FromPrompt(promptOpts *PromptOptions, v *Int32Validation) (int32, error) {
-	promptOpts.defaultStr = s.Int32(v.Default)
-	valStr := prompt(promptOpts)
-	if valStr == "" {
-		return ValidateInt32Missing(v)
+	promptOpts = promptOpts.withHints(ConstraintHint(v), s.Int32(v.Default))
+	var val int32
+	var source ValueSource
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		if valStr == "" {
+			val, err = ValidateInt32Missing(v)
+		} else {
+			val, err = Int32FromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(val, v.Sensitive))
 	}
-	return Int32FromStr(valStr, v)
+	return val, err
 }
 
 func ValidateInt32Missing(v *Int32Validation) (int32, error) {
-	if v.Required {
-		return 0, errors.New(s.ErrMustBeDefined)
-	}
-	return ValidateInt32(v.Default, v)
+	return v.value().ValidateMissing()
 }
 
 func ValidateInt32(val int32, v *Int32Validation) (int32, error) {
-	err := ValidateInt32Val(val, v)
-	if err != nil {
-		return 0, err
+	return v.value().Validate(val)
+}
+
+func ValidateInt32Val(val int32, v *Int32Validation) error {
+	return v.value().validateVal(val)
+}
+
+func (v *Int32Validation) value() *Value[int32] {
+	return &Value[int32]{
+		Required:             v.Required,
+		Default:              v.Default,
+		AllowedValues:        v.AllowedValues,
+		GreaterThan:          v.GreaterThan,
+		GreaterThanOrEqualTo: v.GreaterThanOrEqualTo,
+		LessThan:             v.LessThan,
+		LessThanOrEqualTo:    v.LessThanOrEqualTo,
+		Sensitive:            v.Sensitive,
+		Report:               v.Report,
+		Validator:            v.Validator,
+		allowedValuesSet:     allowedValuesSet{shared: &v.allowedValuesSet},
+		allowTruncation:      v.AllowTruncation,
 	}
+}
+
+//
+// With raw values
+//
 
-	if v.Validator != nil {
-		return v.Validator(val)
+func Int32FromInterfaceMapWithRaw(key string, iMap map[string]interface{}, v *Int32Validation) (int32, string, error) {
+	val, err := Int32FromInterfaceMap(key, iMap, v)
+	raw := ""
+	if inter, ok := ReadInterfaceMapValue(key, iMap); ok {
+		raw = s.UserStrStripped(inter)
 	}
-	return val, nil
+	return val, raw, err
 }
 
-func ValidateInt32Val(val int32, v *Int32Validation) error {
-	if v.GreaterThan != nil {
-		if val <= *v.GreaterThan {
-			return errors.New(s.ErrMustBeGreaterThan(val, *v.GreaterThan))
+func Int32FromStrWithRaw(valStr string, v *Int32Validation) (int32, string, error) {
+	val, err := Int32FromStr(valStr, v)
+	return val, valStr, err
+}
+
+func Int32FromEnvWithRaw(envVarName string, v *Int32Validation) (int32, string, error) {
+	return DefaultEnv().Int32FromEnvWithRaw(envVarName, v)
+}
+
+func (env *Env) Int32FromEnvWithRaw(envVarName string, v *Int32Validation) (int32, string, error) {
+	valStr := env.ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateInt32Missing(v)
+		if err != nil {
+			return 0, "", envVarError(err, envVarName)
 		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+		return val, "", nil
 	}
-	if v.GreaterThanOrEqualTo != nil {
-		if val < *v.GreaterThanOrEqualTo {
-			return errors.New(s.ErrMustBeGreaterThanOrEqualTo(val, *v.GreaterThanOrEqualTo))
-		}
+	val, err := Int32FromStr(*valStr, v)
+	if err != nil {
+		return 0, *valStr, envVarError(err, envVarName)
 	}
-	if v.LessThan != nil {
-		if val >= *v.LessThan {
-			return errors.New(s.ErrMustBeLessThan(val, *v.LessThan))
-		}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+	return val, *valStr, nil
+}
+
+func Int32FromFileWithRaw(filePath string, v *Int32Validation) (int32, string, error) {
+	valStr, err := readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, false)
+	if err != nil {
+		return 0, "", errors.Wrap(err, filePath)
 	}
-	if v.LessThanOrEqualTo != nil {
-		if val > *v.LessThanOrEqualTo {
-			return errors.New(s.ErrMustBeLessThanOrEqualTo(val, *v.LessThanOrEqualTo))
+	if valStr == nil {
+		val, err := ValidateInt32Missing(v)
+		if err != nil {
+			return 0, "", valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, "", nil
 	}
-
-	if v.AllowedValues != nil {
-		if !util.IsInt32InSlice(val, v.AllowedValues) {
-			return errors.New(s.ErrInvalidInt32(val, v.AllowedValues...))
+	trimmed := *valStr
+	if !v.PreserveWhitespace {
+		trimmed = strings.TrimSpace(trimmed)
+	}
+	if trimmed == "" {
+		val, err := ValidateInt32Missing(v)
+		if err != nil {
+			return 0, *valStr, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, *valStr, nil
 	}
-
-	return nil
+	if err := checkSingleLine(trimmed); err != nil {
+		return 0, *valStr, valueFileError(err, filePath, 0)
+	}
+	val, err := Int32FromStr(trimmed, v)
+	if err != nil {
+		return 0, *valStr, valueFileError(err, filePath, 1)
+	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive))
+	return val, *valStr, nil
 }
 
 //
@@ -197,7 +289,7 @@ func ValidateInt32Val(val int32, v *Int32Validation) error {
 func MustInt32FromEnv(envVarName string, v *Int32Validation) int32 {
 	val, err := Int32FromEnv(envVarName, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return val
 }
@@ -205,7 +297,7 @@ func MustInt32FromEnv(envVarName string, v *Int32Validation) int32 {
 func MustInt32FromFile(filePath string, v *Int32Validation) int32 {
 	val, err := Int32FromFile(filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return val
 }
@@ -213,7 +305,23 @@ func MustInt32FromFile(filePath string, v *Int32Validation) int32 {
 func MustInt32FromEnvOrFile(envVarName string, filePath string, v *Int32Validation) int32 {
 	val, err := Int32FromEnvOrFile(envVarName, filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
+	}
+	return val
+}
+
+func MustInt32FromRef(ref string, v *Int32Validation) int32 {
+	val, err := Int32FromRef(ref, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustInt32FromSource(source Source, key string, v *Int32Validation) int32 {
+	val, err := Int32FromSource(source, key, v)
+	if err != nil {
+		Fatal(err)
 	}
 	return val
 }
@@ -27,13 +27,16 @@ type Int32ListValidation struct {
 	Default    []int32
 	AllowNull  bool
 	AllowEmpty bool
+	MinSum     *int32
+	MaxSum     *int32
+	Report     *Report // if set, each value which is read is recorded, along with where it came from
 	Validator  func([]int32) ([]int32, error)
 }
 
 func Int32List(inter interface{}, v *Int32ListValidation) ([]int32, error) {
 	casted, castOk := cast.InterfaceToInt32Slice(inter)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeIntList))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeIntList}})
 	}
 	return ValidateInt32List(casted, v)
 }
@@ -43,20 +46,22 @@ func Int32ListFromInterfaceMap(key string, iMap map[string]interface{}, v *Int32
 	if !ok {
 		val, err := ValidateInt32ListMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", val)
 		return val, nil
 	}
 	val, err := Int32List(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", val)
 	return val, nil
 }
 
 func ValidateInt32ListMissing(v *Int32ListValidation) ([]int32, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateInt32List(v.Default, v)
 }
@@ -64,18 +69,32 @@ func ValidateInt32ListMissing(v *Int32ListValidation) ([]int32, error) {
 func ValidateInt32List(val []int32, v *Int32ListValidation) ([]int32, error) {
 	if !v.AllowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
 	if !v.AllowEmpty {
 		if val != nil && len(val) == 0 {
-			return nil, errors.New(s.ErrCannotBeEmpty)
+			return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
+		}
+	}
+
+	if v.MinSum != nil || v.MaxSum != nil {
+		var sum int64
+		for _, elem := range val {
+			sum += int64(elem)
+		}
+		if v.MinSum != nil && sum < int64(*v.MinSum) {
+			return nil, errors.NewUserCoded(s.MsgSumTooSmall, s.ErrSumTooSmall(sum, int64(*v.MinSum)))
+		}
+		if v.MaxSum != nil && sum > int64(*v.MaxSum) {
+			return nil, errors.NewUserCoded(s.MsgSumTooLarge, s.ErrSumTooLarge(sum, int64(*v.MaxSum)))
 		}
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
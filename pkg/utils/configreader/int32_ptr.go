@@ -17,8 +17,6 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
-
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
 	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
@@ -33,16 +31,25 @@ type Int32PtrValidation struct {
 	GreaterThanOrEqualTo *int32
 	LessThan             *int32
 	LessThanOrEqualTo    *int32
+	PreserveWhitespace   bool    // don't trim surrounding whitespace (including the trailing newline) from values read from files
+	MaxFileBytes         int64   // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles bool    // allow FromFile readers to read from e.g. pipes and device files
+	AllowTruncation      bool    // accept floats with fractional parts by truncating them (instead of erroring)
+	Sensitive            bool    // replace the value with its length in errors (errors from Validator are shown as-is)
+	Report               *Report // if set, each value which is read is recorded, along with where it came from
 	Validator            func(*int32) (*int32, error)
+	allowedValuesSet     allowedValuesSet
 }
 
 func makeInt32ValValidation(v *Int32PtrValidation) *Int32Validation {
 	return &Int32Validation{
+		Sensitive:            v.Sensitive,
 		AllowedValues:        v.AllowedValues,
 		GreaterThan:          v.GreaterThan,
 		GreaterThanOrEqualTo: v.GreaterThanOrEqualTo,
 		LessThan:             v.LessThan,
 		LessThanOrEqualTo:    v.LessThanOrEqualTo,
+		allowedValuesSet:     allowedValuesSet{shared: &v.allowedValuesSet},
 	}
 }
 
@@ -52,7 +59,11 @@ func Int32Ptr(inter interface{}, v *Int32PtrValidation) (*int32, error) {
 	}
 	casted, castOk := cast.InterfaceToInt32(inter)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeInt))
+		fromFloat, err := intFromFloat(inter, 32, v.AllowTruncation, v.Sensitive)
+		if err != nil {
+			return nil, err
+		}
+		casted = int32(fromFloat)
 	}
 	return ValidateInt32Ptr(&casted, v)
 }
@@ -62,14 +73,16 @@ func Int32PtrFromInterfaceMap(key string, iMap map[string]interface{}, v *Int32P
 	if !ok {
 		val, err := ValidateInt32PtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := Int32Ptr(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -78,14 +91,16 @@ func Int32PtrFromStrMap(key string, sMap map[string]string, v *Int32PtrValidatio
 	if !ok || valStr == "" {
 		val, err := ValidateInt32PtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := Int32PtrFromStr(valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -95,41 +110,58 @@ func Int32PtrFromStr(valStr string, v *Int32PtrValidation) (*int32, error) {
 	}
 	casted, castOk := s.ParseInt32(valStr)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(valStr, s.PrimTypeInt))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: redactIf(valStr, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeInt}})
 	}
 	return ValidateInt32Ptr(&casted, v)
 }
 
 func Int32PtrFromEnv(envVarName string, v *Int32PtrValidation) (*int32, error) {
-	valStr := ReadEnvVar(envVarName)
+	return DefaultEnv().Int32PtrFromEnv(envVarName, v)
+}
+
+func (env *Env) Int32PtrFromEnv(envVarName string, v *Int32PtrValidation) (*int32, error) {
+	valStr := env.ReadEnvVar(envVarName)
 	if valStr == nil || *valStr == "" {
 		val, err := ValidateInt32PtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, s.EnvVar(envVarName))
+			return nil, envVarError(err, envVarName)
 		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := Int32PtrFromStr(*valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, s.EnvVar(envVarName))
+		return nil, envVarError(err, envVarName)
 	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
 func Int32PtrFromFile(filePath string, v *Int32PtrValidation) (*int32, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
-	if err != nil || len(valBytes) == 0 {
+	return uncachedFiles.Int32PtrFromFile(filePath, v)
+}
+
+func (cache *FileCache) Int32PtrFromFile(filePath string, v *Int32PtrValidation) (*int32, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
+	if err != nil {
+		return nil, errors.Wrap(err, filePath)
+	}
+	if valStr == nil || *valStr == "" {
 		val, err := ValidateInt32PtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, filePath)
+			return nil, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
-	val, err := Int32PtrFromStr(valStr, v)
+	if err := checkSingleLine(*valStr); err != nil {
+		return nil, valueFileError(err, filePath, 0)
+	}
+	val, err := Int32PtrFromStr(*valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, filePath)
+		return nil, valueFileError(err, filePath, 1)
 	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -142,16 +174,28 @@ func Int32PtrFromEnvOrFile(envVarName string, filePath string, v *Int32PtrValida
 }
 
 func Int32PtrFromPrompt(promptOpts *PromptOptions, v *Int32PtrValidation) (*int32, error) {
-	valStr := prompt(promptOpts)
-	if valStr == "" {
-		return ValidateInt32PtrMissing(v)
+	promptOpts = promptOpts.withHints(ConstraintHint(v), "")
+	var val *int32
+	var source ValueSource
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		if valStr == "" {
+			val, err = ValidateInt32PtrMissing(v)
+		} else {
+			val, err = Int32PtrFromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(cast.Deref(val), v.Sensitive))
 	}
-	return Int32PtrFromStr(valStr, v)
+	return val, err
 }
 
 func ValidateInt32PtrMissing(v *Int32PtrValidation) (*int32, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateInt32Ptr(v.Default, v)
 }
@@ -159,7 +203,7 @@ func ValidateInt32PtrMissing(v *Int32PtrValidation) (*int32, error) {
 func ValidateInt32Ptr(val *int32, v *Int32PtrValidation) (*int32, error) {
 	if v.DisallowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
@@ -171,7 +215,8 @@ func ValidateInt32Ptr(val *int32, v *Int32PtrValidation) (*int32, error) {
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
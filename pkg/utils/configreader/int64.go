@@ -17,10 +17,9 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
+	"strings"
 
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
-	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
 	"github.com/cortexlabs/cortex/pkg/utils/util"
 )
@@ -33,163 +32,300 @@ type Int64Validation struct {
 	GreaterThanOrEqualTo *int64
 	LessThan             *int64
 	LessThanOrEqualTo    *int64
+	PreserveWhitespace   bool  // don't trim surrounding whitespace (including the trailing newline) from values read from files
+	MaxFileBytes         int64 // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles bool  // allow FromFile readers to read from e.g. pipes and device files
+	AllowCommaGrouping   bool  // accept US-style grouping commas in strings (e.g. "1,000,000")
+	Lenient              bool  // accept surrounding whitespace, and grouping commas or underscores in strings (e.g. " 1_000_000 ")
+	MustBePowerOfTwo     bool
+	AllowTruncation      bool    // accept floats with fractional parts by truncating them (instead of erroring)
+	Sensitive            bool    // replace the value with its length in errors and reports (errors from Validator are shown as-is)
+	Report               *Report // if set, each value which is read is recorded, along with where it came from
 	Validator            func(int64) (int64, error)
+	allowedValuesSet     allowedValuesSet
 }
 
 func Int64(inter interface{}, v *Int64Validation) (int64, error) {
-	if inter == nil {
-		return 0, errors.New(s.ErrCannotBeNull)
-	}
-	casted, castOk := cast.InterfaceToInt64(inter)
-	if !castOk {
-		return 0, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeInt))
-	}
-	return ValidateInt64(casted, v)
+	return v.value().From(inter)
 }
 
 func Int64FromInterfaceMap(key string, iMap map[string]interface{}, v *Int64Validation) (int64, error) {
-	inter, ok := ReadInterfaceMapValue(key, iMap)
-	if !ok {
+	return v.value().FromInterfaceMap(key, iMap)
+}
+
+func Int64FromStrMap(key string, sMap map[string]string, v *Int64Validation) (int64, error) {
+	valStr, ok := sMap[key]
+	if !ok || valStr == "" {
 		val, err := ValidateInt64Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, key)
+			return 0, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
 		return val, nil
 	}
-	val, err := Int64(inter, v)
+	val, err := Int64FromStr(valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, key)
+		return 0, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
 	return val, nil
 }
 
-func Int64FromStrMap(key string, sMap map[string]string, v *Int64Validation) (int64, error) {
-	valStr, ok := sMap[key]
-	if !ok || valStr == "" {
+func Int64FromStr(valStr string, v *Int64Validation) (int64, error) {
+	return v.value().FromStr(valStr)
+}
+
+func Int64FromEnv(envVarName string, v *Int64Validation) (int64, error) {
+	return DefaultEnv().Int64FromEnv(envVarName, v)
+}
+
+func (env *Env) Int64FromEnv(envVarName string, v *Int64Validation) (int64, error) {
+	return v.value().fromEnv(env, envVarName)
+}
+
+func Int64FromFile(filePath string, v *Int64Validation) (int64, error) {
+	return uncachedFiles.Int64FromFile(filePath, v)
+}
+
+func (cache *FileCache) Int64FromFile(filePath string, v *Int64Validation) (int64, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
+	if err != nil {
+		return 0, errors.Wrap(err, filePath)
+	}
+	if valStr == nil || *valStr == "" {
 		val, err := ValidateInt64Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, key)
+			return 0, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
 		return val, nil
 	}
-	val, err := Int64FromStr(valStr, v)
+	if err := checkSingleLine(*valStr); err != nil {
+		return 0, valueFileError(err, filePath, 0)
+	}
+	val, err := Int64FromStr(*valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, key)
+		return 0, valueFileError(err, filePath, 1)
 	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive))
 	return val, nil
 }
 
-func Int64FromStr(valStr string, v *Int64Validation) (int64, error) {
-	if valStr == "" {
-		return ValidateInt64Missing(v)
-	}
-	casted, castOk := s.ParseInt64(valStr)
-	if !castOk {
-		return 0, errors.New(s.ErrInvalidPrimitiveType(valStr, s.PrimTypeInt))
+func Int64FromEnvOrFile(envVarName string, filePath string, v *Int64Validation) (int64, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr != nil && *valStr != "" {
+		return Int64FromEnv(envVarName, v)
 	}
-	return ValidateInt64(casted, v)
+	return Int64FromFile(filePath, v)
 }
 
-func Int64FromEnv(envVarName string, v *Int64Validation) (int64, error) {
-	valStr := ReadEnvVar(envVarName)
+func Int64FromRef(ref string, v *Int64Validation) (int64, error) {
+	valStr, err := ReadRef(ref)
+	if err != nil {
+		return 0, errors.Wrap(err, ref)
+	}
 	if valStr == nil || *valStr == "" {
 		val, err := ValidateInt64Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, s.EnvVar(envVarName))
+			return 0, errors.Wrap(err, ref)
 		}
+		v.Report.record(ref, ValueSourceDefault, ref, redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := Int64FromStr(*valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, s.EnvVar(envVarName))
+		return 0, errors.Wrap(err, ref)
 	}
+	v.Report.record(ref, ValueSourceRef, ref, redactIf(val, v.Sensitive))
 	return val, nil
 }
 
-func Int64FromFile(filePath string, v *Int64Validation) (int64, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
-	if err != nil || len(valBytes) == 0 {
+func Int64FromSource(source Source, key string, v *Int64Validation) (int64, error) {
+	valStr, found, err := source.Lookup(key)
+	if err != nil {
+		return 0, errors.WrapKey(err, key)
+	}
+	if !found || valStr == "" {
 		val, err := ValidateInt64Missing(v)
 		if err != nil {
-			return 0, errors.Wrap(err, filePath)
+			return 0, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, describeSource(source, key), redactIf(val, v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
 	val, err := Int64FromStr(valStr, v)
 	if err != nil {
-		return 0, errors.Wrap(err, filePath)
+		return 0, errors.WrapKey(errors.WithSource(err, describeSource(source, key)), key)
 	}
+	v.Report.record(key, ValueSourceLookup, describeSource(source, key), redactIf(val, v.Sensitive))
 	return val, nil
 }
 
-func Int64FromEnvOrFile(envVarName string, filePath string, v *Int64Validation) (int64, error) {
-	valStr := ReadEnvVar(envVarName)
-	if valStr != nil && *valStr != "" {
-		return Int64FromEnv(envVarName, v)
-	}
-	return Int64FromFile(filePath, v)
-}
-
 func Int64FromPrompt(promptOpts *PromptOptions, v *Int64Validation) (int64, error) {
-	promptOpts.defaultStr = s.Int64(v.Default)
-	valStr := prompt(promptOpts)
-	if valStr == "" {
-		return ValidateInt64Missing(v)
+	promptOpts = promptOpts.withHints(ConstraintHint(v), s.Int64(v.Default))
+	var val int64
+	var source ValueSource
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		if valStr == "" {
+			val, err = ValidateInt64Missing(v)
+		} else {
+			val, err = Int64FromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(val, v.Sensitive))
 	}
-	return Int64FromStr(valStr, v)
+	return val, err
 }
 
 func ValidateInt64Missing(v *Int64Validation) (int64, error) {
-	if v.Required {
-		return 0, errors.New(s.ErrMustBeDefined)
-	}
-	return ValidateInt64(v.Default, v)
+	return v.value().ValidateMissing()
 }
 
 func ValidateInt64(val int64, v *Int64Validation) (int64, error) {
-	err := ValidateInt64Val(val, v)
-	if err != nil {
-		return 0, err
-	}
+	return v.value().Validate(val)
+}
+
+func ValidateInt64Val(val int64, v *Int64Validation) error {
+	return v.value().validateVal(val)
+}
 
-	if v.Validator != nil {
-		return v.Validator(val)
+func (v *Int64Validation) value() *Value[int64] {
+	return &Value[int64]{
+		Required:             v.Required,
+		Default:              v.Default,
+		AllowedValues:        v.AllowedValues,
+		GreaterThan:          v.GreaterThan,
+		GreaterThanOrEqualTo: v.GreaterThanOrEqualTo,
+		LessThan:             v.LessThan,
+		LessThanOrEqualTo:    v.LessThanOrEqualTo,
+		Sensitive:            v.Sensitive,
+		Report:               v.Report,
+		Validator:            v.Validator,
+		allowedValuesSet:     allowedValuesSet{shared: &v.allowedValuesSet},
+		parseFunc:            v.parseVal,
+		check:                v.checkVal,
+		allowTruncation:      v.AllowTruncation,
 	}
-	return val, nil
 }
 
-func ValidateInt64Val(val int64, v *Int64Validation) error {
-	if v.GreaterThan != nil {
-		if val <= *v.GreaterThan {
-			return errors.New(s.ErrMustBeGreaterThan(val, *v.GreaterThan))
+// parseVal parses valStr for Int64FromStr(), returning false if nothing is left once it's normalized (e.g. with Lenient)
+func (v *Int64Validation) parseVal(valStr string) (int64, bool, error) {
+	if v.AllowCommaGrouping {
+		stripped, ok := s.StripCommaGrouping(valStr)
+		if !ok {
+			return 0, false, errors.NewUserCoded(s.MsgMisplacedGroupingComma, s.ErrMisplacedGroupingComma(redactIf(valStr, v.Sensitive)))
 		}
+		valStr = stripped
 	}
-	if v.GreaterThanOrEqualTo != nil {
-		if val < *v.GreaterThanOrEqualTo {
-			return errors.New(s.ErrMustBeGreaterThanOrEqualTo(val, *v.GreaterThanOrEqualTo))
+	if v.Lenient {
+		stripped, ok := s.StripDigitGrouping(strings.TrimSpace(valStr))
+		if !ok {
+			return 0, false, errors.NewUserCoded(s.MsgMisplacedGroupingSeparator, s.ErrMisplacedGroupingSeparator(redactIf(valStr, v.Sensitive)))
 		}
-	}
-	if v.LessThan != nil {
-		if val >= *v.LessThan {
-			return errors.New(s.ErrMustBeLessThan(val, *v.LessThan))
+		if stripped == "" {
+			return 0, false, nil
 		}
+		valStr = stripped
 	}
-	if v.LessThanOrEqualTo != nil {
-		if val > *v.LessThanOrEqualTo {
-			return errors.New(s.ErrMustBeLessThanOrEqualTo(val, *v.LessThanOrEqualTo))
-		}
+	casted, castOk := s.ParseInt64(valStr)
+	if !castOk {
+		return 0, false, errors.Wrap(&InvalidTypeError{Provided: redactIf(valStr, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeInt}})
 	}
+	return casted, true, nil
+}
 
-	if v.AllowedValues != nil {
-		if !util.IsInt64InSlice(val, v.AllowedValues) {
-			return errors.New(s.ErrInvalidInt64(val, v.AllowedValues...))
+// checkVal runs the checks which ValidateInt64Val() adds to the range and allowed value checks
+func (v *Int64Validation) checkVal(val int64) error {
+	if v.MustBePowerOfTwo && !util.IsPowerOfTwo(val) {
+		if v.Sensitive {
+			return errors.NewUserCoded(s.MsgMustBePowerOfTwo, s.ErrMustBePowerOfTwo(s.Redacted(val), nil))
 		}
+		return errors.NewUserCoded(s.MsgMustBePowerOfTwo, s.ErrMustBePowerOfTwo(val, util.PowersOfTwoNear(val)))
 	}
 
 	return nil
 }
 
+//
+// With raw values
+//
+
+func Int64FromInterfaceMapWithRaw(key string, iMap map[string]interface{}, v *Int64Validation) (int64, string, error) {
+	val, err := Int64FromInterfaceMap(key, iMap, v)
+	raw := ""
+	if inter, ok := ReadInterfaceMapValue(key, iMap); ok {
+		raw = s.UserStrStripped(inter)
+	}
+	return val, raw, err
+}
+
+func Int64FromStrWithRaw(valStr string, v *Int64Validation) (int64, string, error) {
+	val, err := Int64FromStr(valStr, v)
+	return val, valStr, err
+}
+
+func Int64FromEnvWithRaw(envVarName string, v *Int64Validation) (int64, string, error) {
+	return DefaultEnv().Int64FromEnvWithRaw(envVarName, v)
+}
+
+func (env *Env) Int64FromEnvWithRaw(envVarName string, v *Int64Validation) (int64, string, error) {
+	valStr := env.ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateInt64Missing(v)
+		if err != nil {
+			return 0, "", envVarError(err, envVarName)
+		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+		return val, "", nil
+	}
+	val, err := Int64FromStr(*valStr, v)
+	if err != nil {
+		return 0, *valStr, envVarError(err, envVarName)
+	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+	return val, *valStr, nil
+}
+
+func Int64FromFileWithRaw(filePath string, v *Int64Validation) (int64, string, error) {
+	valStr, err := readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, false)
+	if err != nil {
+		return 0, "", errors.Wrap(err, filePath)
+	}
+	if valStr == nil {
+		val, err := ValidateInt64Missing(v)
+		if err != nil {
+			return 0, "", valueFileError(err, filePath, 0)
+		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, "", nil
+	}
+	trimmed := *valStr
+	if !v.PreserveWhitespace {
+		trimmed = strings.TrimSpace(trimmed)
+	}
+	if trimmed == "" {
+		val, err := ValidateInt64Missing(v)
+		if err != nil {
+			return 0, *valStr, valueFileError(err, filePath, 0)
+		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, *valStr, nil
+	}
+	if err := checkSingleLine(trimmed); err != nil {
+		return 0, *valStr, valueFileError(err, filePath, 0)
+	}
+	val, err := Int64FromStr(trimmed, v)
+	if err != nil {
+		return 0, *valStr, valueFileError(err, filePath, 1)
+	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive))
+	return val, *valStr, nil
+}
+
 //
 // Musts
 //
@@ -197,7 +333,7 @@ func ValidateInt64Val(val int64, v *Int64Validation) error {
 func MustInt64FromEnv(envVarName string, v *Int64Validation) int64 {
 	val, err := Int64FromEnv(envVarName, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return val
 }
@@ -205,7 +341,7 @@ func MustInt64FromEnv(envVarName string, v *Int64Validation) int64 {
 func MustInt64FromFile(filePath string, v *Int64Validation) int64 {
 	val, err := Int64FromFile(filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return val
 }
@@ -213,7 +349,23 @@ func MustInt64FromFile(filePath string, v *Int64Validation) int64 {
 func MustInt64FromEnvOrFile(envVarName string, filePath string, v *Int64Validation) int64 {
 	val, err := Int64FromEnvOrFile(envVarName, filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
+	}
+	return val
+}
+
+func MustInt64FromRef(ref string, v *Int64Validation) int64 {
+	val, err := Int64FromRef(ref, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustInt64FromSource(source Source, key string, v *Int64Validation) int64 {
+	val, err := Int64FromSource(source, key, v)
+	if err != nil {
+		Fatal(err)
 	}
 	return val
 }
@@ -27,13 +27,16 @@ type Int64ListValidation struct {
 	Default    []int64
 	AllowNull  bool
 	AllowEmpty bool
+	MinSum     *int64
+	MaxSum     *int64
+	Report     *Report // if set, each value which is read is recorded, along with where it came from
 	Validator  func([]int64) ([]int64, error)
 }
 
 func Int64List(inter interface{}, v *Int64ListValidation) ([]int64, error) {
 	casted, castOk := cast.InterfaceToInt64Slice(inter)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeIntList))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeIntList}})
 	}
 	return ValidateInt64List(casted, v)
 }
@@ -43,20 +46,22 @@ func Int64ListFromInterfaceMap(key string, iMap map[string]interface{}, v *Int64
 	if !ok {
 		val, err := ValidateInt64ListMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", val)
 		return val, nil
 	}
 	val, err := Int64List(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", val)
 	return val, nil
 }
 
 func ValidateInt64ListMissing(v *Int64ListValidation) ([]int64, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateInt64List(v.Default, v)
 }
@@ -64,18 +69,32 @@ func ValidateInt64ListMissing(v *Int64ListValidation) ([]int64, error) {
 func ValidateInt64List(val []int64, v *Int64ListValidation) ([]int64, error) {
 	if !v.AllowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
 	if !v.AllowEmpty {
 		if val != nil && len(val) == 0 {
-			return nil, errors.New(s.ErrCannotBeEmpty)
+			return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
+		}
+	}
+
+	if v.MinSum != nil || v.MaxSum != nil {
+		var sum int64
+		for _, elem := range val {
+			sum += elem
+		}
+		if v.MinSum != nil && sum < *v.MinSum {
+			return nil, errors.NewUserCoded(s.MsgSumTooSmall, s.ErrSumTooSmall(sum, *v.MinSum))
+		}
+		if v.MaxSum != nil && sum > *v.MaxSum {
+			return nil, errors.NewUserCoded(s.MsgSumTooLarge, s.ErrSumTooLarge(sum, *v.MaxSum))
 		}
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
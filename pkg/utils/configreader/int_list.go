@@ -20,20 +20,32 @@ import (
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
 	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
 )
 
 type IntListValidation struct {
-	Required   bool
-	Default    []int
-	AllowNull  bool
-	AllowEmpty bool
-	Validator  func([]int) ([]int, error)
+	Required         bool
+	Default          []int
+	AllowNull        bool
+	AllowEmpty       bool
+	DisallowDups     bool
+	MinLength        int
+	MaxLength        int    // 0 means there is no limit
+	MinSum           *int   // checked after ElementValidator
+	MaxSum           *int   // checked after ElementValidator
+	Delimiter        string // for FromStr and FromEnv readers, defaults to DefaultListDelimiter (see WhitespaceDelimiter)
+	ElementValidator func(int) (int, error)
+	Report           *Report // if set, each value which is read is recorded, along with where it came from
+	Validator        func([]int) ([]int, error)
 }
 
 func IntList(inter interface{}, v *IntListValidation) ([]int, error) {
-	casted, castOk := cast.InterfaceToIntSlice(inter)
+	casted, invalidIndex, castOk := cast.InterfaceToIntSliceWithInvalidIndex(inter, false)
+	if invalidIndex >= 0 {
+		return nil, invalidElemTypeError(inter, invalidIndex, s.PrimTypeInt)
+	}
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeIntList))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeIntList}})
 	}
 	return ValidateIntList(casted, v)
 }
@@ -43,20 +55,73 @@ func IntListFromInterfaceMap(key string, iMap map[string]interface{}, v *IntList
 	if !ok {
 		val, err := ValidateIntListMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", val)
 		return val, nil
 	}
 	val, err := IntList(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
+	}
+	v.Report.record(key, ValueSourceConfig, "", val)
+	return val, nil
+}
+
+// IntListFromInterfaceMapWithCount is like IntListFromInterfaceMap(), but the list must have as many elements as the
+// integer at countKey (e.g. "num_shards"). If countKey is missing and countV has no default, the length isn't checked
+func IntListFromInterfaceMapWithCount(key string, countKey string, iMap map[string]interface{}, v *IntListValidation, countV *IntPtrValidation) ([]int, error) {
+	count, err := IntPtrFromInterfaceMap(countKey, iMap, countV)
+	if err != nil {
+		return nil, err
+	}
+	val, err := IntListFromInterfaceMap(key, iMap, v)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkListCount(len(val), countKey, count); err != nil {
+		return nil, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func IntListFromStr(valStr string, v *IntListValidation) ([]int, error) {
+	if valStr == "" {
+		return ValidateIntListMissing(v)
+	}
+	elems := splitList(valStr, v.Delimiter)
+	casted := make([]int, len(elems))
+	for i, elem := range elems {
+		castedElem, ok := s.ParseInt(elem)
+		if !ok {
+			return nil, errors.WrapIndex(errors.Wrap(&InvalidTypeError{Provided: elem, Expected: []s.PrimitiveType{s.PrimTypeInt}}), i)
+		}
+		casted[i] = castedElem
+	}
+	return ValidateIntList(casted, v)
+}
+
+func IntListFromEnv(envVarName string, v *IntListValidation) ([]int, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateIntListMissing(v)
+		if err != nil {
+			return nil, envVarError(err, envVarName)
+		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), val)
+		return val, nil
+	}
+	val, err := IntListFromStr(*valStr, v)
+	if err != nil {
+		return nil, envVarError(err, envVarName)
 	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), val)
 	return val, nil
 }
 
 func ValidateIntListMissing(v *IntListValidation) ([]int, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateIntList(v.Default, v)
 }
@@ -64,18 +129,79 @@ func ValidateIntListMissing(v *IntListValidation) ([]int, error) {
 func ValidateIntList(val []int, v *IntListValidation) ([]int, error) {
 	if !v.AllowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
 	if !v.AllowEmpty {
 		if val != nil && len(val) == 0 {
-			return nil, errors.New(s.ErrCannotBeEmpty)
+			return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
+		}
+	}
+
+	if val != nil {
+		if len(val) < v.MinLength {
+			return nil, errors.NewUserCoded(s.MsgTooFewElements, s.ErrTooFewElements(len(val), v.MinLength))
+		}
+		if v.MaxLength > 0 && len(val) > v.MaxLength {
+			return nil, errors.NewUserCoded(s.MsgTooManyElements, s.ErrTooManyElements(len(val), v.MaxLength))
+		}
+	}
+
+	if v.ElementValidator != nil && val != nil {
+		validated := make([]int, len(val))
+		for i, elem := range val {
+			validatedElem, err := v.ElementValidator(elem)
+			if err != nil {
+				return nil, errors.WrapIndex(errors.MarkUser(err), i)
+			}
+			validated[i] = validatedElem
+		}
+		val = validated
+	}
+
+	if v.DisallowDups {
+		if dups := util.FindDuplicateInts(val); len(dups) > 0 {
+			return nil, errors.NewUserCoded(s.MsgDuplicatedValue, s.ErrDuplicatedValue(dups[0]))
+		}
+	}
+
+	if v.MinSum != nil || v.MaxSum != nil {
+		var sum int64
+		for _, elem := range val {
+			sum += int64(elem)
+		}
+		if v.MinSum != nil && sum < int64(*v.MinSum) {
+			return nil, errors.NewUserCoded(s.MsgSumTooSmall, s.ErrSumTooSmall(sum, int64(*v.MinSum)))
+		}
+		if v.MaxSum != nil && sum > int64(*v.MaxSum) {
+			return nil, errors.NewUserCoded(s.MsgSumTooLarge, s.ErrSumTooLarge(sum, int64(*v.MaxSum)))
 		}
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
+
+//
+// Musts
+//
+
+func MustIntListFromStr(valStr string, v *IntListValidation) []int {
+	val, err := IntListFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustIntListFromEnv(envVarName string, v *IntListValidation) []int {
+	val, err := IntListFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+type IntMapValidation struct {
+	Required         bool
+	Default          map[string]int
+	AllowNull        bool
+	AllowEmpty       bool
+	MinSum           *int // checked after ElementValidator
+	MaxSum           *int // checked after ElementValidator
+	ElementValidator func(int) (int, error)
+	Validator        func(map[string]int) (map[string]int, error)
+}
+
+func IntMap(inter interface{}, v *IntMapValidation) (map[string]int, error) {
+	casted, castOk := cast.InterfaceToStrIntMap(inter)
+	if !castOk {
+		return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeStringToIntMap}})
+	}
+	return ValidateIntMap(casted, v)
+}
+
+func IntMapFromInterfaceMap(key string, iMap map[string]interface{}, v *IntMapValidation) (map[string]int, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateIntMapMissing(v)
+		if err != nil {
+			return nil, errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := IntMap(inter, v)
+	if err != nil {
+		return nil, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func ValidateIntMapMissing(v *IntMapValidation) (map[string]int, error) {
+	if v.Required {
+		return nil, errors.Wrap(ErrRequired)
+	}
+	return ValidateIntMap(v.Default, v)
+}
+
+func ValidateIntMap(val map[string]int, v *IntMapValidation) (map[string]int, error) {
+	if !v.AllowNull {
+		if val == nil {
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+		}
+	}
+
+	if !v.AllowEmpty {
+		if val != nil && len(val) == 0 {
+			return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
+		}
+	}
+
+	if v.ElementValidator != nil && val != nil {
+		validated := make(map[string]int, len(val))
+		for _, key := range util.IntMapSortedKeys(val) {
+			validatedElem, err := v.ElementValidator(val[key])
+			if err != nil {
+				return nil, errors.WrapKey(errors.MarkUser(err), key)
+			}
+			validated[key] = validatedElem
+		}
+		val = validated
+	}
+
+	if v.MinSum != nil || v.MaxSum != nil {
+		var sum int64
+		for _, elem := range val {
+			sum += int64(elem)
+		}
+		if v.MinSum != nil && sum < int64(*v.MinSum) {
+			return nil, errors.NewUserCoded(s.MsgSumTooSmall, s.ErrSumTooSmall(sum, int64(*v.MinSum)))
+		}
+		if v.MaxSum != nil && sum > int64(*v.MaxSum) {
+			return nil, errors.NewUserCoded(s.MsgSumTooLarge, s.ErrSumTooLarge(sum, int64(*v.MaxSum)))
+		}
+	}
+
+	if v.Validator != nil {
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
+	}
+	return val, nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// IntOrKeywordValue holds either an int, or a keyword (e.g. "auto")
+type IntOrKeywordValue struct {
+	Int     int
+	Keyword string // set if a keyword was provided instead of an int
+}
+
+func (val IntOrKeywordValue) IsKeyword() bool {
+	return val.Keyword != ""
+}
+
+type IntOrKeywordValidation struct {
+	Required      bool
+	Default       IntOrKeywordValue
+	Keywords      []string       // e.g. "auto"
+	IntValidation *IntValidation // constraints for int values (Required and Default are ignored)
+	keywordsSet   allowedValuesSet
+}
+
+func IntOrKeyword(inter interface{}, v *IntOrKeywordValidation) (IntOrKeywordValue, error) {
+	if inter == nil {
+		return IntOrKeywordValue{}, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+	}
+	if keyword, ok := inter.(string); ok && isAllowed(keyword, v.Keywords, &v.keywordsSet) {
+		return ValidateIntOrKeyword(IntOrKeywordValue{Keyword: keyword}, v)
+	}
+	casted, castOk := cast.InterfaceToInt(inter)
+	if !castOk {
+		return IntOrKeywordValue{}, errors.NewUserCoded(s.MsgInvalidIntOrKeyword, s.ErrInvalidIntOrKeyword(inter, v.Keywords))
+	}
+	return ValidateIntOrKeyword(IntOrKeywordValue{Int: casted}, v)
+}
+
+func IntOrKeywordFromInterfaceMap(key string, iMap map[string]interface{}, v *IntOrKeywordValidation) (IntOrKeywordValue, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateIntOrKeywordMissing(v)
+		if err != nil {
+			return IntOrKeywordValue{}, errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := IntOrKeyword(inter, v)
+	if err != nil {
+		return IntOrKeywordValue{}, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func IntOrKeywordFromStr(valStr string, v *IntOrKeywordValidation) (IntOrKeywordValue, error) {
+	if isAllowed(valStr, v.Keywords, &v.keywordsSet) {
+		return ValidateIntOrKeyword(IntOrKeywordValue{Keyword: valStr}, v)
+	}
+	casted, castOk := s.ParseInt(valStr)
+	if !castOk {
+		return IntOrKeywordValue{}, errors.NewUserCoded(s.MsgInvalidIntOrKeyword, s.ErrInvalidIntOrKeyword(valStr, v.Keywords))
+	}
+	return ValidateIntOrKeyword(IntOrKeywordValue{Int: casted}, v)
+}
+
+func IntOrKeywordFromEnv(envVarName string, v *IntOrKeywordValidation) (IntOrKeywordValue, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateIntOrKeywordMissing(v)
+		if err != nil {
+			return IntOrKeywordValue{}, envVarError(err, envVarName)
+		}
+		return val, nil
+	}
+	val, err := IntOrKeywordFromStr(*valStr, v)
+	if err != nil {
+		return IntOrKeywordValue{}, envVarError(err, envVarName)
+	}
+	return val, nil
+}
+
+func ValidateIntOrKeywordMissing(v *IntOrKeywordValidation) (IntOrKeywordValue, error) {
+	if v.Required {
+		return IntOrKeywordValue{}, errors.Wrap(ErrRequired)
+	}
+	return ValidateIntOrKeyword(v.Default, v)
+}
+
+func ValidateIntOrKeyword(val IntOrKeywordValue, v *IntOrKeywordValidation) (IntOrKeywordValue, error) {
+	if val.IsKeyword() {
+		if !isAllowed(val.Keyword, v.Keywords, &v.keywordsSet) {
+			return IntOrKeywordValue{}, errors.NewUserCoded(s.MsgInvalidIntOrKeyword, s.ErrInvalidIntOrKeyword(val.Keyword, v.Keywords))
+		}
+		return val, nil
+	}
+
+	if v.IntValidation == nil {
+		return val, nil
+	}
+	intValidation := *v.IntValidation
+	intValidation.Required = false
+	intValidation.allowedValuesSet = allowedValuesSet{shared: &v.IntValidation.allowedValuesSet}
+	casted, err := ValidateInt(val.Int, &intValidation)
+	if err != nil {
+		return IntOrKeywordValue{}, errors.NewUserCoded(s.MsgOrKeywords, s.ErrOrKeywords(err.Error(), v.Keywords))
+	}
+	return IntOrKeywordValue{Int: casted}, nil
+}
+
+//
+// Musts
+//
+
+func MustIntOrKeywordFromStr(valStr string, v *IntOrKeywordValidation) IntOrKeywordValue {
+	val, err := IntOrKeywordFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustIntOrKeywordFromEnv(envVarName string, v *IntOrKeywordValidation) IntOrKeywordValue {
+	val, err := IntOrKeywordFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+func TestIntOrKeyword(t *testing.T) {
+	v := &cr.IntOrKeywordValidation{
+		Keywords: []string{"auto"},
+		IntValidation: &cr.IntValidation{
+			GreaterThan: util.IntPtr(0),
+		},
+		Default: cr.IntOrKeywordValue{Keyword: "auto"},
+	}
+
+	val, err := cr.IntOrKeywordFromStr("auto", v)
+	require.NoError(t, err)
+	require.True(t, val.IsKeyword())
+	require.Equal(t, "auto", val.Keyword)
+
+	val, err = cr.IntOrKeywordFromStr("4", v)
+	require.NoError(t, err)
+	require.False(t, val.IsKeyword())
+	require.Equal(t, 4, val.Int)
+
+	_, err = cr.IntOrKeywordFromStr("0", v)
+	require.EqualError(t, err, s.ErrOrKeywords(s.ErrMustBeGreaterThan(0, 0), []string{"auto"}))
+
+	_, err = cr.IntOrKeywordFromStr("max", v)
+	require.EqualError(t, err, s.ErrInvalidIntOrKeyword("max", []string{"auto"}))
+
+	iMap := cr.MustReadYAMLStrMap("workers: 8\nthreads: auto\nbatch: 1.5")
+
+	val, err = cr.IntOrKeywordFromInterfaceMap("workers", iMap, v)
+	require.NoError(t, err)
+	require.Equal(t, cr.IntOrKeywordValue{Int: 8}, val)
+
+	val, err = cr.IntOrKeywordFromInterfaceMap("threads", iMap, v)
+	require.NoError(t, err)
+	require.Equal(t, cr.IntOrKeywordValue{Keyword: "auto"}, val)
+
+	val, err = cr.IntOrKeywordFromInterfaceMap("missing", iMap, v)
+	require.NoError(t, err)
+	require.Equal(t, cr.IntOrKeywordValue{Keyword: "auto"}, val)
+
+	_, err = cr.IntOrKeywordFromInterfaceMap("batch", iMap, v)
+	require.EqualError(t, err, "batch: "+s.ErrInvalidIntOrKeyword(1.5, []string{"auto"}))
+}
@@ -17,7 +17,7 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
+	"strconv"
 
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
 	"github.com/cortexlabs/cortex/pkg/utils/cast"
@@ -33,16 +33,25 @@ type IntPtrValidation struct {
 	GreaterThanOrEqualTo *int
 	LessThan             *int
 	LessThanOrEqualTo    *int
+	PreserveWhitespace   bool    // don't trim surrounding whitespace (including the trailing newline) from values read from files
+	MaxFileBytes         int64   // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles bool    // allow FromFile readers to read from e.g. pipes and device files
+	AllowTruncation      bool    // accept floats with fractional parts by truncating them (instead of erroring)
+	Sensitive            bool    // replace the value with its length in errors (errors from Validator are shown as-is)
+	Report               *Report // if set, each value which is read is recorded, along with where it came from
 	Validator            func(*int) (*int, error)
+	allowedValuesSet     allowedValuesSet
 }
 
 func makeIntValValidation(v *IntPtrValidation) *IntValidation {
 	return &IntValidation{
+		Sensitive:            v.Sensitive,
 		AllowedValues:        v.AllowedValues,
 		GreaterThan:          v.GreaterThan,
 		GreaterThanOrEqualTo: v.GreaterThanOrEqualTo,
 		LessThan:             v.LessThan,
 		LessThanOrEqualTo:    v.LessThanOrEqualTo,
+		allowedValuesSet:     allowedValuesSet{shared: &v.allowedValuesSet},
 	}
 }
 
@@ -52,7 +61,11 @@ func IntPtr(inter interface{}, v *IntPtrValidation) (*int, error) {
 	}
 	casted, castOk := cast.InterfaceToInt(inter)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeInt))
+		fromFloat, err := intFromFloat(inter, strconv.IntSize, v.AllowTruncation, v.Sensitive)
+		if err != nil {
+			return nil, err
+		}
+		casted = int(fromFloat)
 	}
 	return ValidateIntPtr(&casted, v)
 }
@@ -62,14 +75,16 @@ func IntPtrFromInterfaceMap(key string, iMap map[string]interface{}, v *IntPtrVa
 	if !ok {
 		val, err := ValidateIntPtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := IntPtr(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -78,14 +93,16 @@ func IntPtrFromStrMap(key string, sMap map[string]string, v *IntPtrValidation) (
 	if !ok || valStr == "" {
 		val, err := ValidateIntPtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := IntPtrFromStr(valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -95,41 +112,58 @@ func IntPtrFromStr(valStr string, v *IntPtrValidation) (*int, error) {
 	}
 	casted, castOk := s.ParseInt(valStr)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(valStr, s.PrimTypeInt))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: redactIf(valStr, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeInt}})
 	}
 	return ValidateIntPtr(&casted, v)
 }
 
 func IntPtrFromEnv(envVarName string, v *IntPtrValidation) (*int, error) {
-	valStr := ReadEnvVar(envVarName)
+	return DefaultEnv().IntPtrFromEnv(envVarName, v)
+}
+
+func (env *Env) IntPtrFromEnv(envVarName string, v *IntPtrValidation) (*int, error) {
+	valStr := env.ReadEnvVar(envVarName)
 	if valStr == nil || *valStr == "" {
 		val, err := ValidateIntPtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, s.EnvVar(envVarName))
+			return nil, envVarError(err, envVarName)
 		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := IntPtrFromStr(*valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, s.EnvVar(envVarName))
+		return nil, envVarError(err, envVarName)
 	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
 func IntPtrFromFile(filePath string, v *IntPtrValidation) (*int, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
-	if err != nil || len(valBytes) == 0 {
+	return uncachedFiles.IntPtrFromFile(filePath, v)
+}
+
+func (cache *FileCache) IntPtrFromFile(filePath string, v *IntPtrValidation) (*int, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
+	if err != nil {
+		return nil, errors.Wrap(err, filePath)
+	}
+	if valStr == nil || *valStr == "" {
 		val, err := ValidateIntPtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, filePath)
+			return nil, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
-	val, err := IntPtrFromStr(valStr, v)
+	if err := checkSingleLine(*valStr); err != nil {
+		return nil, valueFileError(err, filePath, 0)
+	}
+	val, err := IntPtrFromStr(*valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, filePath)
+		return nil, valueFileError(err, filePath, 1)
 	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -142,16 +176,28 @@ func IntPtrFromEnvOrFile(envVarName string, filePath string, v *IntPtrValidation
 }
 
 func IntPtrFromPrompt(promptOpts *PromptOptions, v *IntPtrValidation) (*int, error) {
-	valStr := prompt(promptOpts)
-	if valStr == "" {
-		return ValidateIntPtrMissing(v)
+	promptOpts = promptOpts.withHints(ConstraintHint(v), "")
+	var val *int
+	var source ValueSource
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		if valStr == "" {
+			val, err = ValidateIntPtrMissing(v)
+		} else {
+			val, err = IntPtrFromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(cast.Deref(val), v.Sensitive))
 	}
-	return IntPtrFromStr(valStr, v)
+	return val, err
 }
 
 func ValidateIntPtrMissing(v *IntPtrValidation) (*int, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateIntPtr(v.Default, v)
 }
@@ -159,7 +205,7 @@ func ValidateIntPtrMissing(v *IntPtrValidation) (*int, error) {
 func ValidateIntPtr(val *int, v *IntPtrValidation) (*int, error) {
 	if v.DisallowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
@@ -171,7 +217,8 @@ func ValidateIntPtr(val *int, v *IntPtrValidation) (*int, error) {
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
@@ -0,0 +1,237 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"sort"
+	"strings"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// IntRange is an inclusive range of ints
+type IntRange struct {
+	Low  int
+	High int
+}
+
+// IntRanges is a sorted list of non-overlapping, non-adjacent ranges
+type IntRanges []IntRange
+
+func (ranges IntRanges) Contains(val int) bool {
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].High >= val })
+	return i < len(ranges) && ranges[i].Low <= val
+}
+
+// Ints returns every int in the ranges, in ascending order
+func (ranges IntRanges) Ints() []int {
+	var ints []int
+	for _, intRange := range ranges {
+		for val := intRange.Low; val <= intRange.High; val++ {
+			ints = append(ints, val)
+		}
+	}
+	return ints
+}
+
+// IntRangeListValidation is for lists of ints and ranges, e.g. "80,443,8000-9000"
+type IntRangeListValidation struct {
+	Required             bool
+	Default              IntRanges
+	AllowEmpty           bool
+	GreaterThanOrEqualTo *int // applies to every value in every range
+	LessThanOrEqualTo    *int // applies to every value in every range
+	Validator            func(IntRanges) (IntRanges, error)
+}
+
+// IntRangeList accepts a string (e.g. "80,8000-9000"), an int, or a list of strings and ints
+func IntRangeList(inter interface{}, v *IntRangeListValidation) (IntRanges, error) {
+	if inter == nil {
+		return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+	}
+
+	var segments []string
+	if casted, ok := inter.(string); ok {
+		segments = strings.Split(casted, ",")
+	} else if casted, ok := cast.InterfaceToInt(inter); ok {
+		segments = []string{s.Int(casted)}
+	} else if casted, ok := cast.InterfaceToInterfaceSlice(inter); ok {
+		for _, elem := range casted {
+			if elemStr, ok := elem.(string); ok {
+				segments = append(segments, strings.Split(elemStr, ",")...)
+			} else if elemInt, ok := cast.InterfaceToInt(elem); ok {
+				segments = append(segments, s.Int(elemInt))
+			} else {
+				return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeString, s.PrimTypeInt, s.PrimTypeList}})
+			}
+		}
+	} else {
+		return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeString, s.PrimTypeInt, s.PrimTypeList}})
+	}
+
+	ranges, err := parseIntRangeSegments(segments)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateIntRangeList(ranges, v)
+}
+
+func IntRangeListFromInterfaceMap(key string, iMap map[string]interface{}, v *IntRangeListValidation) (IntRanges, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateIntRangeListMissing(v)
+		if err != nil {
+			return nil, errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := IntRangeList(inter, v)
+	if err != nil {
+		return nil, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func IntRangeListFromStr(valStr string, v *IntRangeListValidation) (IntRanges, error) {
+	if valStr == "" {
+		return ValidateIntRangeListMissing(v)
+	}
+	ranges, err := parseIntRangeSegments(strings.Split(valStr, ","))
+	if err != nil {
+		return nil, err
+	}
+	return ValidateIntRangeList(ranges, v)
+}
+
+func IntRangeListFromEnv(envVarName string, v *IntRangeListValidation) (IntRanges, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateIntRangeListMissing(v)
+		if err != nil {
+			return nil, envVarError(err, envVarName)
+		}
+		return val, nil
+	}
+	val, err := IntRangeListFromStr(*valStr, v)
+	if err != nil {
+		return nil, envVarError(err, envVarName)
+	}
+	return val, nil
+}
+
+func ValidateIntRangeListMissing(v *IntRangeListValidation) (IntRanges, error) {
+	if v.Required {
+		return nil, errors.Wrap(ErrRequired)
+	}
+	return ValidateIntRangeList(v.Default, v)
+}
+
+func ValidateIntRangeList(val IntRanges, v *IntRangeListValidation) (IntRanges, error) {
+	if !v.AllowEmpty && len(val) == 0 {
+		return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
+	}
+
+	for _, intRange := range val {
+		if v.GreaterThanOrEqualTo != nil && intRange.Low < *v.GreaterThanOrEqualTo {
+			return nil, errors.Wrap(&OutOfRangeError{Val: intRange.Low, Bound: *v.GreaterThanOrEqualTo, Op: ">="})
+		}
+		if v.LessThanOrEqualTo != nil && intRange.High > *v.LessThanOrEqualTo {
+			return nil, errors.Wrap(&OutOfRangeError{Val: intRange.High, Bound: *v.LessThanOrEqualTo, Op: "<="})
+		}
+	}
+
+	if v.Validator != nil {
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
+	}
+	return val, nil
+}
+
+// parseIntRangeSegments parses segments like "80" and "8000-9000", and merges them into sorted, non-overlapping ranges
+func parseIntRangeSegments(segments []string) (IntRanges, error) {
+	var ranges IntRanges
+	for _, segment := range segments {
+		segment = strings.TrimSpace(segment)
+		if segment == "" {
+			continue
+		}
+		intRange, err := parseIntRangeSegment(segment)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, intRange)
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Low < ranges[j].Low })
+
+	var merged IntRanges
+	for _, intRange := range ranges {
+		last := len(merged) - 1
+		if last >= 0 && intRange.Low <= merged[last].High+1 {
+			if intRange.High > merged[last].High {
+				merged[last].High = intRange.High
+			}
+			continue
+		}
+		merged = append(merged, intRange)
+	}
+	return merged, nil
+}
+
+func parseIntRangeSegment(segment string) (IntRange, error) {
+	// the search starts after the first character, so that the low end can be negative
+	split := strings.Index(segment[1:], "-") + 1
+	if split == 0 {
+		val, ok := s.ParseInt(segment)
+		if !ok {
+			return IntRange{}, errors.NewUserCoded(s.MsgInvalidIntRange, s.ErrInvalidIntRange(segment))
+		}
+		return IntRange{Low: val, High: val}, nil
+	}
+
+	low, lowOk := s.ParseInt(strings.TrimSpace(segment[:split]))
+	high, highOk := s.ParseInt(strings.TrimSpace(segment[split+1:]))
+	if !lowOk || !highOk {
+		return IntRange{}, errors.NewUserCoded(s.MsgInvalidIntRange, s.ErrInvalidIntRange(segment))
+	}
+	if low > high {
+		return IntRange{}, errors.NewUserCoded(s.MsgIntRangeReversed, s.ErrIntRangeReversed(segment))
+	}
+	return IntRange{Low: low, High: high}, nil
+}
+
+//
+// Musts
+//
+
+func MustIntRangeListFromStr(valStr string, v *IntRangeListValidation) IntRanges {
+	val, err := IntRangeListFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustIntRangeListFromEnv(envVarName string, v *IntRangeListValidation) IntRanges {
+	val, err := IntRangeListFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+func TestIntRangeList(t *testing.T) {
+	v := &cr.IntRangeListValidation{
+		GreaterThanOrEqualTo: util.IntPtr(1),
+		LessThanOrEqualTo:    util.IntPtr(65535),
+	}
+
+	ranges, err := cr.IntRangeListFromStr("8000-9000, 443,80,8500-9100,9101", v)
+	require.NoError(t, err)
+	require.Equal(t, cr.IntRanges{{80, 80}, {443, 443}, {8000, 9101}}, ranges)
+	require.True(t, ranges.Contains(80))
+	require.True(t, ranges.Contains(8000))
+	require.True(t, ranges.Contains(9101))
+	require.False(t, ranges.Contains(81))
+	require.False(t, ranges.Contains(9102))
+	require.False(t, ranges.Contains(1))
+
+	ranges, err = cr.IntRangeListFromStr("5,1-3,3,2", v)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3, 5}, ranges.Ints())
+
+	_, err = cr.IntRangeListFromStr("80,8000-x", v)
+	require.EqualError(t, err, s.ErrInvalidIntRange("8000-x"))
+
+	_, err = cr.IntRangeListFromStr("80,9000-8000", v)
+	require.EqualError(t, err, s.ErrIntRangeReversed("9000-8000"))
+
+	_, err = cr.IntRangeListFromStr("0-10", v)
+	require.EqualError(t, err, s.ErrMustBeGreaterThanOrEqualTo(0, 1))
+
+	_, err = cr.IntRangeListFromStr("60000-70000", v)
+	require.EqualError(t, err, s.ErrMustBeLessThanOrEqualTo(70000, 65535))
+
+	ranges, err = cr.IntRangeList(-5, &cr.IntRangeListValidation{})
+	require.NoError(t, err)
+	require.Equal(t, cr.IntRanges{{-5, -5}}, ranges)
+
+	ranges, err = cr.IntRangeList("-10--5", &cr.IntRangeListValidation{})
+	require.NoError(t, err)
+	require.Equal(t, cr.IntRanges{{-10, -5}}, ranges)
+
+	configData := cr.MustReadYAMLStrMap(`
+    ports: [80, "443", "8000-8080"]
+    `)
+	ranges, err = cr.IntRangeListFromInterfaceMap("ports", configData, v)
+	require.NoError(t, err)
+	require.Equal(t, cr.IntRanges{{80, 80}, {443, 443}, {8000, 8080}}, ranges)
+
+	_, err = cr.IntRangeListFromInterfaceMap("ports", map[string]interface{}{"ports": true}, v)
+	require.EqualError(t, err, "ports: "+s.ErrInvalidPrimitiveType(true, s.PrimTypeString, s.PrimTypeInt, s.PrimTypeList))
+
+	_, err = cr.IntRangeListFromStr(" , ", v)
+	require.EqualError(t, err, s.ErrCannotBeEmpty)
+}
@@ -37,20 +37,20 @@ func InterfaceFromInterfaceMap(key string, iMap map[string]interface{}, v *Inter
 	if !ok {
 		val, err := ValidateInterfaceMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
 		return val, nil
 	}
 	val, err := ValidateInterface(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
 	return val, nil
 }
 
 func ValidateInterfaceMissing(v *InterfaceValidation) (interface{}, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateInterface(v.Default, v)
 }
@@ -58,12 +58,13 @@ func ValidateInterfaceMissing(v *InterfaceValidation) (interface{}, error) {
 func ValidateInterface(val interface{}, v *InterfaceValidation) (interface{}, error) {
 	if !v.AllowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
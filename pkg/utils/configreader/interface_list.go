@@ -0,0 +1,111 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// InterfaceListValidation is for lists whose elements can have different types (use ElementValidator to type-switch on them)
+type InterfaceListValidation struct {
+	Required         bool
+	Default          []interface{}
+	AllowNull        bool
+	AllowEmpty       bool
+	MinLength        int
+	MaxLength        int  // 0 means there is no limit
+	CoerceToList     bool // a non-list value is read as a single-element list
+	ElementValidator func(interface{}) (interface{}, error)
+	Validator        func([]interface{}) ([]interface{}, error)
+}
+
+func InterfaceList(inter interface{}, v *InterfaceListValidation) ([]interface{}, error) {
+	casted, castOk := cast.InterfaceToInterfaceSlice(inter)
+	if !castOk {
+		if !v.CoerceToList {
+			return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeList}})
+		}
+		casted = []interface{}{inter}
+	}
+	return ValidateInterfaceList(casted, v)
+}
+
+func InterfaceListFromInterfaceMap(key string, iMap map[string]interface{}, v *InterfaceListValidation) ([]interface{}, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateInterfaceListMissing(v)
+		if err != nil {
+			return nil, errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := InterfaceList(inter, v)
+	if err != nil {
+		return nil, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func ValidateInterfaceListMissing(v *InterfaceListValidation) ([]interface{}, error) {
+	if v.Required {
+		return nil, errors.Wrap(ErrRequired)
+	}
+	return ValidateInterfaceList(v.Default, v)
+}
+
+func ValidateInterfaceList(val []interface{}, v *InterfaceListValidation) ([]interface{}, error) {
+	if !v.AllowNull {
+		if val == nil {
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+		}
+	}
+
+	if !v.AllowEmpty {
+		if val != nil && len(val) == 0 {
+			return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
+		}
+	}
+
+	if val != nil {
+		if len(val) < v.MinLength {
+			return nil, errors.NewUserCoded(s.MsgTooFewElements, s.ErrTooFewElements(len(val), v.MinLength))
+		}
+		if v.MaxLength > 0 && len(val) > v.MaxLength {
+			return nil, errors.NewUserCoded(s.MsgTooManyElements, s.ErrTooManyElements(len(val), v.MaxLength))
+		}
+	}
+
+	if v.ElementValidator != nil && val != nil {
+		validated := make([]interface{}, len(val))
+		for i, elem := range val {
+			validatedElem, err := v.ElementValidator(elem)
+			if err != nil {
+				return nil, errors.WrapIndex(errors.MarkUser(err), i)
+			}
+			validated[i] = validatedElem
+		}
+		val = validated
+	}
+
+	if v.Validator != nil {
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
+	}
+	return val, nil
+}
@@ -24,20 +24,21 @@ import (
 )
 
 type InterfaceMapValidation struct {
-	Required          bool
-	AllowNull         bool
-	AllowEmpty        bool
-	ScalarsOnly       bool
-	StringLeavesOnly  bool
-	AllowedLeafValues []string
-	Default           map[string]interface{}
-	Validator         func(map[string]interface{}) (map[string]interface{}, error)
+	Required             bool
+	AllowNull            bool
+	AllowEmpty           bool
+	ScalarsOnly          bool
+	StringLeavesOnly     bool
+	AllowedLeafValues    []string
+	Default              map[string]interface{}
+	Validator            func(map[string]interface{}) (map[string]interface{}, error)
+	allowedLeafValuesSet allowedValuesSet
 }
 
 func InterfaceMap(inter interface{}, v *InterfaceMapValidation) (map[string]interface{}, error) {
 	casted, castOk := cast.InterfaceToStrInterfaceMap(inter)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeMap))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeMap}})
 	}
 	return ValidateInterfaceMap(casted, v)
 }
@@ -47,20 +48,20 @@ func InterfaceMapFromInterfaceMap(key string, iMap map[string]interface{}, v *In
 	if !ok {
 		val, err := ValidateInterfaceMapMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
 		return val, nil
 	}
 	val, err := InterfaceMap(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
 	return val, nil
 }
 
 func ValidateInterfaceMapMissing(v *InterfaceMapValidation) (map[string]interface{}, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateInterfaceMap(v.Default, v)
 }
@@ -68,20 +69,20 @@ func ValidateInterfaceMapMissing(v *InterfaceMapValidation) (map[string]interfac
 func ValidateInterfaceMap(val map[string]interface{}, v *InterfaceMapValidation) (map[string]interface{}, error) {
 	if !v.AllowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
 	if !v.AllowEmpty {
 		if val != nil && len(val) == 0 {
-			return nil, errors.New(s.ErrCannotBeEmpty)
+			return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
 		}
 	}
 
 	if v.ScalarsOnly {
 		for k, v := range val {
 			if !cast.IsScalarType(v) {
-				return nil, errors.New(k, s.ErrInvalidPrimitiveType(v, s.PrimTypeString, s.PrimTypeInt, s.PrimTypeFloat, s.PrimTypeBool))
+				return nil, errors.NewUserCoded(s.MsgInvalidPrimitiveType, k, s.ErrInvalidPrimitiveType(v, s.PrimTypeString, s.PrimTypeInt, s.PrimTypeFloat, s.PrimTypeBool))
 			}
 		}
 	}
@@ -99,14 +100,15 @@ func ValidateInterfaceMap(val map[string]interface{}, v *InterfaceMapValidation)
 			return nil, err
 		}
 		for _, leafVal := range leafVals {
-			if !util.IsStrInSlice(leafVal, v.AllowedLeafValues) {
-				return nil, errors.New(s.ErrInvalidStr(leafVal, v.AllowedLeafValues...))
+			if !isAllowed(leafVal, v.AllowedLeafValues, &v.allowedLeafValuesSet) {
+				return nil, errors.NewUserCoded(s.MsgNotAllowedValue, s.ErrInvalidStr(leafVal, v.AllowedLeafValues...))
 			}
 		}
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
@@ -0,0 +1,153 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"strings"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// LevelValue is one of an ordered list of levels, e.g. a log level
+type LevelValue struct {
+	Level string // the canonical spelling from LevelValidation.Levels
+	Rank  int    // the index of Level in LevelValidation.Levels
+}
+
+type LevelValidation struct {
+	Required bool
+	Default  string
+	Levels   []string // ordered from lowest to highest, e.g. "debug", "info", "warn", "error" (matched case-insensitively)
+	MinLevel string
+	MaxLevel string
+}
+
+func Level(inter interface{}, v *LevelValidation) (LevelValue, error) {
+	if inter == nil {
+		return LevelValue{}, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+	}
+	casted, castOk := inter.(string)
+	if !castOk {
+		return LevelValue{}, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeString}})
+	}
+	return ValidateLevel(casted, v)
+}
+
+func LevelFromInterfaceMap(key string, iMap map[string]interface{}, v *LevelValidation) (LevelValue, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateLevelMissing(v)
+		if err != nil {
+			return LevelValue{}, errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := Level(inter, v)
+	if err != nil {
+		return LevelValue{}, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func LevelFromStr(valStr string, v *LevelValidation) (LevelValue, error) {
+	if valStr == "" {
+		return ValidateLevelMissing(v)
+	}
+	return ValidateLevel(valStr, v)
+}
+
+func LevelFromEnv(envVarName string, v *LevelValidation) (LevelValue, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateLevelMissing(v)
+		if err != nil {
+			return LevelValue{}, envVarError(err, envVarName)
+		}
+		return val, nil
+	}
+	val, err := LevelFromStr(*valStr, v)
+	if err != nil {
+		return LevelValue{}, envVarError(err, envVarName)
+	}
+	return val, nil
+}
+
+func ValidateLevelMissing(v *LevelValidation) (LevelValue, error) {
+	if v.Required {
+		return LevelValue{}, errors.Wrap(ErrRequired)
+	}
+	return ValidateLevel(v.Default, v)
+}
+
+func ValidateLevel(val string, v *LevelValidation) (LevelValue, error) {
+	rank := levelRank(val, v.Levels)
+	if rank < 0 {
+		return LevelValue{}, errors.NewUserCoded(s.MsgNotAllowedValue, s.ErrInvalidStr(val, v.Levels...)+s.DidYouMean(s.SuggestClosest(val, v.Levels)))
+	}
+
+	if v.MinLevel != "" {
+		minRank := levelRank(v.MinLevel, v.Levels)
+		if minRank < 0 {
+			return LevelValue{}, errors.NewUserCoded(s.MsgNotAllowedValue, s.ErrInvalidStr(v.MinLevel, v.Levels...))
+		}
+		if rank < minRank {
+			return LevelValue{}, errors.NewUserCoded(s.MsgLevelTooLow, s.ErrLevelTooLow(val, v.Levels[minRank], v.Levels))
+		}
+	}
+
+	if v.MaxLevel != "" {
+		maxRank := levelRank(v.MaxLevel, v.Levels)
+		if maxRank < 0 {
+			return LevelValue{}, errors.NewUserCoded(s.MsgNotAllowedValue, s.ErrInvalidStr(v.MaxLevel, v.Levels...))
+		}
+		if rank > maxRank {
+			return LevelValue{}, errors.NewUserCoded(s.MsgLevelTooHigh, s.ErrLevelTooHigh(val, v.Levels[maxRank], v.Levels))
+		}
+	}
+
+	return LevelValue{Level: v.Levels[rank], Rank: rank}, nil
+}
+
+func levelRank(level string, levels []string) int {
+	for i, candidate := range levels {
+		if strings.EqualFold(candidate, level) {
+			return i
+		}
+	}
+	return -1
+}
+
+//
+// Musts
+//
+
+func MustLevelFromStr(valStr string, v *LevelValidation) LevelValue {
+	val, err := LevelFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustLevelFromEnv(envVarName string, v *LevelValidation) LevelValue {
+	val, err := LevelFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+)
+
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+func TestLevel(t *testing.T) {
+	v := &cr.LevelValidation{
+		Default:  "info",
+		Levels:   logLevels,
+		MinLevel: "info",
+	}
+
+	val, err := cr.LevelFromStr("WARN", v)
+	require.NoError(t, err)
+	require.Equal(t, cr.LevelValue{Level: "warn", Rank: 2}, val)
+
+	val, err = cr.LevelFromStr("", v)
+	require.NoError(t, err)
+	require.Equal(t, cr.LevelValue{Level: "info", Rank: 1}, val)
+
+	_, err = cr.LevelFromStr("debug", v)
+	require.EqualError(t, err, s.ErrLevelTooLow("debug", "info", logLevels))
+	require.Contains(t, err.Error(), `must be at least "info" (debug < info < warn < error)`)
+
+	_, err = cr.LevelFromStr("trace", v)
+	require.EqualError(t, err, s.ErrInvalidStr("trace", logLevels...))
+
+	v.MaxLevel = "warn"
+	_, err = cr.LevelFromInterfaceMap("log_level", map[string]interface{}{"log_level": "error"}, v)
+	require.EqualError(t, err, "log_level: "+s.ErrLevelTooHigh("error", "warn", logLevels))
+
+	_, err = cr.Level(2, v)
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType(2, s.PrimTypeString))
+}
+
+type LevelConfig struct {
+	LogLevel cr.LevelValue `json:"log_level"`
+}
+
+func TestLevelStructField(t *testing.T) {
+	structValidation := &cr.StructValidation{
+		StructFieldValidations: []*cr.StructFieldValidation{
+			{
+				StructField: "LogLevel",
+				LevelValidation: &cr.LevelValidation{
+					Default: "info",
+					Levels:  logLevels,
+				},
+			},
+		},
+	}
+
+	config := &LevelConfig{}
+	errs := cr.Struct(config, cr.MustReadYAMLStrMap("log_level: error"), structValidation)
+	require.Empty(t, errs)
+	require.Equal(t, cr.LevelValue{Level: "error", Rank: 3}, config.LogLevel)
+}
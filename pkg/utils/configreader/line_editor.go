@@ -0,0 +1,101 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"golang.org/x/crypto/ssh/terminal"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// lineEditorIO connects a terminal.Terminal to the prompt's input and output
+type lineEditorIO struct {
+	readByte    func() (byte, error)
+	out         io.Writer
+	interrupted bool
+}
+
+func (rw *lineEditorIO) Read(buf []byte) (int, error) {
+	if len(buf) == 0 {
+		return 0, nil
+	}
+	char, err := rw.readByte()
+	if err != nil {
+		return 0, err
+	}
+	if char == 3 { // ctrl-c (terminal.Terminal treats it the same as ctrl-d)
+		rw.interrupted = true
+	}
+	buf[0] = char
+	return 1, nil
+}
+
+func (rw *lineEditorIO) Write(buf []byte) (int, error) {
+	return rw.out.Write(buf)
+}
+
+// the editor shared by prompts with KeepHistory set, so that earlier entries can be recalled with the up arrow
+var (
+	historyEditorMutex sync.Mutex
+	historyEditorIO    = &lineEditorIO{}
+	historyEditor      = terminal.NewTerminal(historyEditorIO, "")
+)
+
+// readEditedLine reads a line from a terminal with support for arrow keys, backspace, and other readline-style editing
+func readEditedLine(fd int, readByte func() (byte, error), out io.Writer, prompt string, keepHistory bool) (string, error) {
+	rw := &lineEditorIO{readByte: readByte, out: out}
+	var editor *terminal.Terminal
+	if keepHistory {
+		historyEditorMutex.Lock()
+		defer historyEditorMutex.Unlock()
+		*historyEditorIO = *rw
+		rw = historyEditorIO
+		editor = historyEditor
+		editor.SetPrompt(prompt)
+	} else {
+		editor = terminal.NewTerminal(rw, prompt)
+	}
+
+	if width, height, err := terminal.GetSize(fd); err == nil && width > 0 {
+		editor.SetSize(width, height)
+	}
+
+	restore, err := makeRaw(fd, out)
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+
+	line, err := editor.ReadLine()
+	if err == nil || err == terminal.ErrPasteIndicator {
+		return line, nil
+	}
+
+	fmt.Fprint(out, "\r\n")
+	if rw.interrupted {
+		return "", errors.NewUserCoded(s.MsgPromptInterrupted, s.ErrPromptInterrupted)
+	}
+	if err == ErrPromptTimeout {
+		return "", err
+	}
+	return "", errors.MarkInternal(errors.Wrap(err))
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"fmt"
+	"strings"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// StringListFromPrompt prompts for one element at a time ("Tag #1", "Tag #2", ...) until an empty line is entered
+// (or for a comma-separated list if promptOpts.SingleLine is set)
+func StringListFromPrompt(promptOpts *PromptOptions, v *StringListValidation) ([]string, error) {
+	var val []string
+	addElement := func(elemStr string) error {
+		if v.ElementValidator != nil {
+			var err error
+			if elemStr, err = v.ElementValidator(elemStr); err != nil {
+				return errors.MarkUser(err)
+			}
+		}
+		val = append(val, elemStr)
+		return nil
+	}
+	finish := func() error {
+		var err error
+		if len(val) == 0 {
+			val, err = ValidateStringListMissing(v)
+		} else {
+			val, err = ValidateStringList(val, v)
+		}
+		return err
+	}
+	reset := func() { val = nil }
+
+	err := promptList(promptOpts, strings.Join(v.Default, ", "), v.MaxLength, addElement, finish, reset)
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// IntListFromPrompt is the same as StringListFromPrompt, for ints
+func IntListFromPrompt(promptOpts *PromptOptions, v *IntListValidation) ([]int, error) {
+	var val []int
+	addElement := func(elemStr string) error {
+		elem, ok := s.ParseInt(elemStr)
+		if !ok {
+			return errors.Wrap(&InvalidTypeError{Provided: elemStr, Expected: []s.PrimitiveType{s.PrimTypeInt}})
+		}
+		if v.ElementValidator != nil {
+			var err error
+			if elem, err = v.ElementValidator(elem); err != nil {
+				return errors.MarkUser(err)
+			}
+		}
+		val = append(val, elem)
+		return nil
+	}
+	finish := func() error {
+		var err error
+		if len(val) == 0 {
+			val, err = ValidateIntListMissing(v)
+		} else {
+			val, err = ValidateIntList(val, v)
+		}
+		return err
+	}
+	reset := func() { val = nil }
+
+	defaultStrs := make([]string, len(v.Default))
+	for i, elem := range v.Default {
+		defaultStrs[i] = s.Int(elem)
+	}
+
+	err := promptList(promptOpts, strings.Join(defaultStrs, ", "), v.MaxLength, addElement, finish, reset)
+	if err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// promptList collects elements with addElement and then calls finish to validate the whole list.
+// Element errors re-prompt for the element; if the list is invalid, it can be re-entered once
+func promptList(opts *PromptOptions, defaultStr string, maxLength int, addElement func(string) error, finish func() error, reset func()) error {
+	if opts.SingleLine {
+		opts = opts.withHints(opts.constraintHint, defaultStr)
+		return promptWithRetries(opts, func(valStr string) error {
+			reset()
+			if valStr != "" {
+				for i, elemStr := range strings.Split(valStr, ",") {
+					if err := addElement(strings.TrimSpace(elemStr)); err != nil {
+						return errors.WrapIndex(err, i)
+					}
+				}
+			}
+			return finish()
+		})
+	}
+
+	interactive := GetPromptPolicy() == PromptInteractive && (opts.In != nil || !IsNonInteractive())
+
+	for attempt := 1; ; attempt++ {
+		reset()
+		for numElements := 0; maxLength <= 0 || numElements < maxLength; numElements++ {
+			elemOpts := *opts
+			elemOpts.Prompt = fmt.Sprintf("%s #%d", opts.Prompt, numElements+1)
+			elemOpts.defaultStr = ""
+
+			done := false
+			err := promptWithRetries(&elemOpts, func(valStr string) error {
+				if valStr == "" {
+					done = true
+					return nil
+				}
+				return addElement(valStr)
+			})
+			if err != nil {
+				return err
+			}
+			if done {
+				break
+			}
+		}
+
+		err := finish()
+		if err == nil || attempt > 1 || !interactive {
+			return err
+		}
+		fmt.Fprintln(opts.out(), opts.style(opts.ErrorStyler, err.Error()+" (please enter the list again)"))
+	}
+}
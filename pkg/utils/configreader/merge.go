@@ -0,0 +1,226 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+type ListStrategy int
+
+const (
+	ListReplace ListStrategy = iota // lists in later files replace lists in earlier files
+	ListConcat                      // lists in later files are appended to lists in earlier files
+)
+
+type MergeOptions struct {
+	ListStrategy ListStrategy
+}
+
+// MergeYAMLDir deep-merges the .yaml and .yml files in dir (in lexical order, later files take precedence)
+func MergeYAMLDir(dir string, opts *MergeOptions) (map[string]interface{}, error) {
+	if opts == nil {
+		opts = &MergeOptions{}
+	}
+
+	filePaths, err := util.ListDir(dir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := map[string]interface{}{}
+	sources := map[string]string{} // key path -> file which set it
+
+	for _, filePath := range filePaths {
+		ext := strings.ToLower(filepath.Ext(filePath))
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		if !util.IsFile(filePath) {
+			continue
+		}
+
+		yamlBytes, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, errors.MarkInternal(errors.WrapCoded(err, s.MsgRead, filePath, s.ErrRead))
+		}
+		parsed, err := ReadYAMLBytes(yamlBytes)
+		if err != nil {
+			return nil, errors.Wrap(err, filePath)
+		}
+		if parsed == nil {
+			continue
+		}
+		parsedMap, ok := cast.InterfaceToStrInterfaceMap(parsed)
+		if !ok {
+			return nil, errors.NewUserCoded(s.MsgInvalidPrimitiveType, filePath, s.ErrInvalidPrimitiveType(parsed, s.PrimTypeMap))
+		}
+
+		if err := mergeInterfaceMap(merged, parsedMap, "", filePath, sources, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return merged, nil
+}
+
+// MergeInterfaceMaps deep-merges maps, with later maps taking precedence over earlier ones. Nested maps are merged
+// key by key; any other value (including lists) in a later map replaces the earlier value. The input maps are not modified
+func MergeInterfaceMaps(maps ...map[string]interface{}) map[string]interface{} {
+	merged := map[string]interface{}{}
+	for _, src := range maps {
+		overlayInterfaceMap(merged, src)
+	}
+	return merged
+}
+
+func overlayInterfaceMap(dest map[string]interface{}, src map[string]interface{}) {
+	for key, srcVal := range src {
+		if srcMap, ok := srcVal.(map[interface{}]interface{}); ok {
+			if casted, ok := cast.InterfaceToStrInterfaceMap(srcMap); ok {
+				srcVal = casted
+			}
+		}
+
+		srcMap, srcIsMap := srcVal.(map[string]interface{})
+		destMap, destIsMap := dest[key].(map[string]interface{})
+		if srcIsMap && destIsMap {
+			overlayInterfaceMap(destMap, srcMap)
+			continue
+		}
+
+		dest[key] = copyMergeValue(srcVal)
+	}
+}
+
+func mergeInterfaceMap(dest map[string]interface{}, src map[string]interface{}, keyPath string, filePath string, sources map[string]string, opts *MergeOptions) error {
+	for key, srcVal := range src {
+		childKeyPath := key
+		if keyPath != "" {
+			childKeyPath = keyPath + "." + key
+		}
+
+		if srcMap, ok := srcVal.(map[interface{}]interface{}); ok {
+			casted, ok := cast.InterfaceToStrInterfaceMap(srcMap)
+			if !ok {
+				return errors.NewUserCoded(s.MsgInvalidPrimitiveType, filePath, childKeyPath, s.ErrInvalidPrimitiveType(srcVal, s.PrimTypeMap))
+			}
+			srcVal = casted
+		}
+
+		destVal, exists := dest[key]
+		if !exists || destVal == nil || srcVal == nil {
+			dest[key] = copyMergeValue(srcVal)
+			sources[childKeyPath] = filePath
+			continue
+		}
+
+		destType := mergePrimType(destVal)
+		srcType := mergePrimType(srcVal)
+		if !mergeTypesCompatible(destType, srcType) {
+			return errors.NewUserCoded(s.MsgMergeConflict, s.ErrMergeConflict(childKeyPath, mergeSource(childKeyPath, sources), destType, filePath, srcType))
+		}
+
+		switch casted := srcVal.(type) {
+		case map[string]interface{}:
+			if err := mergeInterfaceMap(dest[key].(map[string]interface{}), casted, childKeyPath, filePath, sources, opts); err != nil {
+				return err
+			}
+		case []interface{}:
+			if opts.ListStrategy == ListConcat {
+				dest[key] = append(dest[key].([]interface{}), casted...)
+			} else {
+				dest[key] = copyMergeValue(casted)
+			}
+		default:
+			dest[key] = srcVal
+		}
+		sources[childKeyPath] = filePath
+	}
+	return nil
+}
+
+// returns the file which set keyPath, or which set its closest parent
+func mergeSource(keyPath string, sources map[string]string) string {
+	for {
+		if filePath, ok := sources[keyPath]; ok {
+			return filePath
+		}
+		lastDot := strings.LastIndex(keyPath, ".")
+		if lastDot == -1 {
+			return ""
+		}
+		keyPath = keyPath[:lastDot]
+	}
+}
+
+// maps are copied so that merging into them doesn't modify the parsed input
+func copyMergeValue(val interface{}) interface{} {
+	switch casted := val.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(casted))
+		for key, child := range casted {
+			if childMap, ok := child.(map[interface{}]interface{}); ok {
+				if strMap, ok := cast.InterfaceToStrInterfaceMap(childMap); ok {
+					child = strMap
+				}
+			}
+			copied[key] = copyMergeValue(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(casted))
+		copy(copied, casted)
+		return copied
+	}
+	return val
+}
+
+func mergePrimType(val interface{}) s.PrimitiveType {
+	switch val.(type) {
+	case map[string]interface{}:
+		return s.PrimTypeMap
+	case []interface{}:
+		return s.PrimTypeList
+	case string:
+		return s.PrimTypeString
+	case bool:
+		return s.PrimTypeBool
+	case int, int32, int64:
+		return s.PrimTypeInt
+	case float32, float64:
+		return s.PrimTypeFloat
+	}
+	return ""
+}
+
+func mergeTypesCompatible(type1 s.PrimitiveType, type2 s.PrimitiveType) bool {
+	isNumber := func(primType s.PrimitiveType) bool {
+		return primType == s.PrimTypeInt || primType == s.PrimTypeFloat
+	}
+	if isNumber(type1) && isNumber(type2) {
+		return true
+	}
+	return type1 == type2
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+func writeYAMLFiles(t *testing.T, dir string, files map[string]string) {
+	for name, contents := range files {
+		err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0644)
+		require.NoError(t, err)
+	}
+}
+
+func TestMergeYAMLDir(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	writeYAMLFiles(t, tmpDir, map[string]string{
+		"1-base.yaml": `
+cluster:
+  name: dev
+  nodes: 2
+  instance_types: [m5.large]
+  tags:
+    team: ml
+log_level: info
+`,
+		"2-prod.yml": `
+cluster:
+  name: prod
+  nodes: 2.5
+  instance_types: [m5.xlarge]
+  tags:
+    env: prod
+`,
+		"3-secrets.yaml": `
+api_key: abc
+`,
+		"notes.txt":    `cluster: 1`,
+		"4-empty.yaml": ``,
+	})
+
+	merged, err := cr.MergeYAMLDir(tmpDir, nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"cluster": map[string]interface{}{
+			"name":           "prod",
+			"nodes":          2.5,
+			"instance_types": []interface{}{"m5.xlarge"},
+			"tags": map[string]interface{}{
+				"team": "ml",
+				"env":  "prod",
+			},
+		},
+		"log_level": "info",
+		"api_key":   "abc",
+	}, merged)
+
+	name, err := cr.StringFromInterfaceMap("log_level", merged, &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "info", name)
+
+	merged, err = cr.MergeYAMLDir(tmpDir, &cr.MergeOptions{ListStrategy: cr.ListConcat})
+	require.NoError(t, err)
+	instanceTypes := merged["cluster"].(map[string]interface{})["instance_types"]
+	require.Equal(t, []interface{}{"m5.large", "m5.xlarge"}, instanceTypes)
+
+	writeYAMLFiles(t, tmpDir, map[string]string{
+		"5-override.yaml": `
+cluster:
+  nodes: two
+`,
+	})
+	_, err = cr.MergeYAMLDir(tmpDir, nil)
+	require.EqualError(t, err, s.ErrMergeConflict("cluster.nodes",
+		filepath.Join(tmpDir, "2-prod.yml"), s.PrimTypeFloat,
+		filepath.Join(tmpDir, "5-override.yaml"), s.PrimTypeString))
+}
+
+func TestMergeYAMLDirNestedConflict(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	writeYAMLFiles(t, tmpDir, map[string]string{
+		"a.yaml": "cluster:\n  tags:\n    team: ml\n",
+		"b.yaml": "cluster:\n  tags:\n    team: [ml]\n",
+	})
+
+	_, err = cr.MergeYAMLDir(tmpDir, nil)
+	require.EqualError(t, err, s.ErrMergeConflict("cluster.tags.team",
+		filepath.Join(tmpDir, "a.yaml"), s.PrimTypeString,
+		filepath.Join(tmpDir, "b.yaml"), s.PrimTypeList))
+}
+
+func TestMergeInterfaceMaps(t *testing.T) {
+	base := map[string]interface{}{
+		"cluster": map[string]interface{}{
+			"name":           "dev",
+			"nodes":          2,
+			"instance_types": []interface{}{"m5.large"},
+			"tags":           map[interface{}]interface{}{"team": "ml"},
+		},
+		"log_level": "info",
+	}
+	env := map[string]interface{}{
+		"cluster": map[string]interface{}{
+			"name":           "prod",
+			"instance_types": []interface{}{"m5.xlarge"},
+			"tags":           map[string]interface{}{"env": "prod"},
+		},
+	}
+	user := map[string]interface{}{
+		"cluster":   map[string]interface{}{"nodes": 5},
+		"log_level": map[string]interface{}{"default": "debug"},
+	}
+
+	merged := cr.MergeInterfaceMaps(base, env, user)
+	require.Equal(t, map[string]interface{}{
+		"cluster": map[string]interface{}{
+			"name":           "prod",
+			"nodes":          5,
+			"instance_types": []interface{}{"m5.xlarge"},
+			"tags":           map[string]interface{}{"team": "ml", "env": "prod"},
+		},
+		"log_level": map[string]interface{}{"default": "debug"},
+	}, merged)
+
+	// the inputs are not modified
+	require.Equal(t, "dev", base["cluster"].(map[string]interface{})["name"])
+	require.Equal(t, map[interface{}]interface{}{"team": "ml"}, base["cluster"].(map[string]interface{})["tags"])
+
+	nodes, err := cr.IntFromInterfaceMap("nodes", merged["cluster"].(map[string]interface{}), &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 5, nodes)
+
+	require.Equal(t, map[string]interface{}{}, cr.MergeInterfaceMaps())
+}
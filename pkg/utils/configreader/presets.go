@@ -0,0 +1,89 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+// Presets holds constructors for commonly used validations. Each call returns a new validation,
+// so the result can be modified without affecting other callers
+var Presets presets
+
+type presets struct{}
+
+const k8sNameMaxLength = 63
+
+func (presets) Port() *IntValidation {
+	return &IntValidation{
+		GreaterThan:       util.IntPtr(0),
+		LessThanOrEqualTo: util.IntPtr(65535),
+	}
+}
+
+func (presets) Percentage() *Float64Validation {
+	return &Float64Validation{
+		GreaterThanOrEqualTo: util.Float64Ptr(0),
+		LessThanOrEqualTo:    util.Float64Ptr(100),
+	}
+}
+
+// K8sName validates names of kubernetes resources (DNS-1035 labels)
+func (presets) K8sName() *StringValidation {
+	return &StringValidation{
+		Dns1035:   true,
+		MaxLength: k8sNameMaxLength,
+	}
+}
+
+// K8sLabel validates names which kubernetes requires to be DNS-1123 labels (e.g. namespaces)
+func (presets) K8sLabel() *StringValidation {
+	return &StringValidation{
+		Dns1123Label: true,
+	}
+}
+
+// K8sSubdomain validates names which kubernetes requires to be DNS-1123 subdomains (e.g. secrets and config maps)
+func (presets) K8sSubdomain() *StringValidation {
+	return &StringValidation{
+		Dns1123Subdomain: true,
+	}
+}
+
+// K8sResourceName is an alias for K8sSubdomain(), named for the common case: most kubernetes resources (e.g. deployments,
+// services, and config maps) must have DNS-1123 subdomain names. Some resources are stricter (see K8sName() and K8sLabel())
+func (presets) K8sResourceName() *StringValidation {
+	return Presets.K8sSubdomain()
+}
+
+// GoIdent validates names which are used as identifiers in generated code or templates (ASCII letters, numbers, and
+// underscores, not starting with a number, and not a Go keyword)
+func (presets) GoIdent() *StringValidation {
+	return &StringValidation{
+		Validator: func(val string) (string, error) {
+			if util.IsGoKeyword(val) {
+				return "", errors.NewUserCoded(s.MsgGoKeyword, s.ErrGoKeyword(val))
+			}
+			if !util.CheckGoIdent(val) {
+				return "", errors.NewUserCoded(s.MsgInvalidGoIdent, s.ErrInvalidGoIdent(val))
+			}
+			return val, nil
+		},
+	}
+}
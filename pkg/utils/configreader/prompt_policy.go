@@ -0,0 +1,66 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"os"
+	"sync"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+)
+
+// YesEnvVar can be set to "true" to use the PromptAcceptDefaults policy (unless SetPromptPolicy() was called)
+const YesEnvVar = "CORTEX_YES"
+
+type PromptPolicy int
+
+const (
+	PromptInteractive    PromptPolicy = iota // prompts are shown (unless running non-interactively, see IsNonInteractive())
+	PromptAcceptDefaults                     // prompts use their defaults without being shown, and confirmations return true
+	PromptFailIfNeeded                       // prompts and confirmations return an error instead of being shown
+)
+
+var (
+	promptPolicyMutex    sync.Mutex
+	promptPolicyOverride *PromptPolicy
+)
+
+// SetPromptPolicy controls how the FromPrompt readers and ConfirmWithOptions() behave. It takes precedence over YesEnvVar
+func SetPromptPolicy(policy PromptPolicy) {
+	promptPolicyMutex.Lock()
+	defer promptPolicyMutex.Unlock()
+	promptPolicyOverride = &policy
+}
+
+// ResetPromptPolicy undoes SetPromptPolicy
+func ResetPromptPolicy() {
+	promptPolicyMutex.Lock()
+	defer promptPolicyMutex.Unlock()
+	promptPolicyOverride = nil
+}
+
+func GetPromptPolicy() PromptPolicy {
+	promptPolicyMutex.Lock()
+	defer promptPolicyMutex.Unlock()
+	if promptPolicyOverride != nil {
+		return *promptPolicyOverride
+	}
+	if yes, ok := s.ParseBool(os.Getenv(YesEnvVar)); ok && yes {
+		return PromptAcceptDefaults
+	}
+	return PromptInteractive
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+// menus with more options than this can be filtered by typing a prefix
+const selectFilterThreshold = 15
+
+// StringFromPromptSelect prompts with a numbered menu of v.AllowedValues (if there are none, it's the same as StringFromPrompt)
+func StringFromPromptSelect(promptOpts *PromptOptions, v *StringValidation) (string, error) {
+	if len(v.AllowedValues) == 0 {
+		return StringFromPrompt(promptOpts, v)
+	}
+
+	defaultIndex := -1
+	for i, allowed := range v.AllowedValues {
+		if !v.Required && allowed == v.Default {
+			defaultIndex = i
+		}
+	}
+
+	var val string
+	err := promptSelect(promptOpts, v.AllowedValues, defaultIndex, func(valStr string) error {
+		var err error
+		if valStr == "" {
+			val, err = ValidateStringMissing(v)
+		} else {
+			val, err = StringFromStr(valStr, v)
+		}
+		return err
+	})
+	return val, err
+}
+
+// IntFromPromptSelect prompts with a numbered menu of v.AllowedValues (if there are none, it's the same as IntFromPrompt)
+func IntFromPromptSelect(promptOpts *PromptOptions, v *IntValidation) (int, error) {
+	if len(v.AllowedValues) == 0 {
+		return IntFromPrompt(promptOpts, v)
+	}
+
+	options := make([]string, len(v.AllowedValues))
+	defaultIndex := -1
+	for i, allowed := range v.AllowedValues {
+		options[i] = s.Int(allowed)
+		if !v.Required && allowed == v.Default {
+			defaultIndex = i
+		}
+	}
+
+	var val int
+	err := promptSelect(promptOpts, options, defaultIndex, func(valStr string) error {
+		var err error
+		if valStr == "" {
+			val, err = ValidateIntMissing(v)
+		} else {
+			val, err = IntFromStr(valStr, v)
+		}
+		return err
+	})
+	return val, err
+}
+
+// promptSelect resolves the user's selection (a menu number or a literal option) before calling parse()
+func promptSelect(opts *PromptOptions, options []string, defaultIndex int, parse func(string) error) error {
+	indices := make([]int, len(options))
+	for i := range options {
+		indices[i] = i
+	}
+	renderSelectMenu(opts.out(), options, indices, defaultIndex)
+
+	// the default is marked in the menu instead, and the options are the only constraint which matters
+	opts = opts.withHints("", "")
+
+	return promptWithRetries(opts, func(selection string) error {
+		selection = strings.TrimSpace(selection)
+		if selection == "" {
+			if defaultIndex >= 0 {
+				return parse(options[defaultIndex])
+			}
+			return parse("")
+		}
+
+		if num, ok := s.ParseInt(selection); ok && num >= 1 && num <= len(options) {
+			return parse(options[num-1])
+		}
+
+		if util.IsStrInSlice(selection, options) {
+			return parse(selection)
+		}
+
+		if len(options) > selectFilterThreshold {
+			var matches []int
+			for i, option := range options {
+				if strings.HasPrefix(option, selection) {
+					matches = append(matches, i)
+				}
+			}
+			if len(matches) > 0 {
+				renderSelectMenu(opts.out(), options, matches, defaultIndex)
+				return errPromptAgain
+			}
+		}
+
+		return errors.NewUserCoded(s.MsgInvalidSelection, s.ErrInvalidSelection(selection, len(options)))
+	})
+}
+
+// renderSelectMenu prints the options at the given indices, numbered by their position in the full list
+func renderSelectMenu(out io.Writer, options []string, indices []int, defaultIndex int) {
+	numWidth := len(s.Int(len(options)))
+	for _, i := range indices {
+		line := fmt.Sprintf("  %*d) %s", numWidth, i+1, options[i])
+		if i == defaultIndex {
+			line += " (default)"
+		}
+		fmt.Fprintln(out, line)
+	}
+}
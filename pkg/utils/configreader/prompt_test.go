@@ -0,0 +1,627 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/configreader/prompttest"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+func TestPromptTimeout(t *testing.T) {
+	cr.SetNonInteractive(false)
+	defer cr.ResetNonInteractive()
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer w.Close()
+
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+
+	_, err = cr.StringFromPrompt(&cr.PromptOptions{Prompt: "Name", Timeout: 20 * time.Millisecond}, &cr.StringValidation{})
+	require.Equal(t, cr.ErrPromptTimeout, err)
+	require.EqualError(t, err, s.ErrPromptTimeout)
+
+	val, err := cr.StringFromPrompt(&cr.PromptOptions{
+		Prompt:                     "Name",
+		Timeout:                    20 * time.Millisecond,
+		FallBackToDefaultOnTimeout: true,
+	}, &cr.StringValidation{Default: "iris"})
+	require.NoError(t, err)
+	require.Equal(t, "iris", val)
+
+	_, err = cr.IntFromPrompt(&cr.PromptOptions{
+		Prompt:                     "Replicas",
+		Timeout:                    20 * time.Millisecond,
+		FallBackToDefaultOnTimeout: true,
+	}, &cr.IntValidation{Required: true})
+	require.EqualError(t, err, s.ErrMustBeDefined)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = cr.StringFromPrompt(&cr.PromptOptions{Prompt: "Name", Ctx: ctx}, &cr.StringValidation{})
+	require.Equal(t, cr.ErrPromptTimeout, errors.Cause(err))
+
+	// input which arrives after a timeout is read by the next prompt
+	_, err = w.WriteString("3\n")
+	require.NoError(t, err)
+	num, err := cr.IntFromPrompt(&cr.PromptOptions{Prompt: "Replicas", Timeout: time.Second}, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 3, num)
+
+	// the same applies to PromptOptions.In, even if the next prompt has no timeout
+	inR, inW := io.Pipe()
+	defer inW.Close()
+	_, err = cr.StringFromPrompt(&cr.PromptOptions{Prompt: "Name", In: inR, Out: prompttest.NewOutput(), Timeout: 20 * time.Millisecond}, &cr.StringValidation{})
+	require.Equal(t, cr.ErrPromptTimeout, err)
+	go inW.Write([]byte("4\n5\n"))
+	num, err = cr.IntFromPrompt(&cr.PromptOptions{Prompt: "Replicas", In: inR, Out: prompttest.NewOutput()}, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 4, num)
+	num, err = cr.IntFromPrompt(&cr.PromptOptions{Prompt: "Replicas", In: inR, Out: prompttest.NewOutput()}, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 5, num)
+}
+
+func TestPromptNonInteractive(t *testing.T) {
+	cr.SetNonInteractive(true)
+	defer cr.ResetNonInteractive()
+
+	val, err := cr.StringFromPrompt(&cr.PromptOptions{Prompt: "Region"}, &cr.StringValidation{Default: "us-west-2"})
+	require.NoError(t, err)
+	require.Equal(t, "us-west-2", val)
+
+	val, err = cr.StringFromPrompt(&cr.PromptOptions{Prompt: "Region"}, &cr.StringValidation{Required: true, Default: "us-west-2"})
+	require.NoError(t, err)
+	require.Equal(t, "us-west-2", val)
+
+	num, err := cr.IntFromPromptSelect(&cr.PromptOptions{Prompt: "Replicas"}, &cr.IntValidation{AllowedValues: []int{1, 2, 3}, Default: 2})
+	require.NoError(t, err)
+	require.Equal(t, 2, num)
+
+	_, err = cr.StringFromPrompt(&cr.PromptOptions{Prompt: "API key"}, &cr.StringValidation{Required: true})
+	require.EqualError(t, err, s.ErrPromptNonInteractive("")+": "+s.ErrMustBeDefined)
+	// the error is the same as for a missing value, with the note added
+	require.True(t, errors.Is(err, cr.ErrRequired))
+	require.Equal(t, s.Code(cr.ErrRequired), s.Code(err))
+
+	_, err = cr.StringFromPrompt(&cr.PromptOptions{
+		Prompt:             "API key",
+		NonInteractiveHint: "set CORTEX_API_KEY or pass --api-key",
+	}, &cr.StringValidation{Required: true})
+	require.EqualError(t, err, "running non-interactively (set CORTEX_API_KEY or pass --api-key): must be defined")
+
+	type Config struct {
+		Region string
+	}
+	config := &Config{}
+	err = cr.ReadPrompt(config, &cr.PromptValidation{
+		PromptItemValidations: []*cr.PromptItemValidation{
+			{
+				StructField:      "Region",
+				PromptOpts:       &cr.PromptOptions{Prompt: "Region"},
+				StringValidation: &cr.StringValidation{Required: true},
+			},
+		},
+	})
+	require.EqualError(t, err, s.ErrPromptNonInteractive("")+": "+s.ErrMustBeDefined)
+
+	cr.ResetNonInteractive()
+	os.Setenv(cr.NonInteractiveEnvVar, "true")
+	defer os.Unsetenv(cr.NonInteractiveEnvVar)
+	require.True(t, cr.IsNonInteractive())
+	cr.SetNonInteractive(false)
+	require.False(t, cr.IsNonInteractive())
+}
+
+func TestPromptScriptedInput(t *testing.T) {
+	out := prompttest.NewOutput()
+	opts := &cr.PromptOptions{
+		Prompt: "Replicas",
+		In:     prompttest.ScriptedInput([]string{"abc", "0", "3"}),
+		Out:    out,
+	}
+	num, err := cr.IntFromPrompt(opts, &cr.IntValidation{GreaterThan: util.IntPtr(0), Default: 1})
+	require.NoError(t, err)
+	require.Equal(t, 3, num)
+	out.RequireInOrder(t,
+		"Replicas (> 0) [1]", s.ErrInvalidPrimitiveType("abc", s.PrimTypeInt),
+		"Replicas (> 0) [1]", s.ErrMustBeGreaterThan(0, 0),
+		"Replicas (> 0) [1]",
+	)
+
+	// an empty line is the default
+	val, err := cr.StringFromPrompt(&cr.PromptOptions{
+		Prompt: "Region",
+		In:     prompttest.ScriptedInput([]string{""}),
+		Out:    prompttest.NewOutput(),
+	}, &cr.StringValidation{Default: "us-west-2"})
+	require.NoError(t, err)
+	require.Equal(t, "us-west-2", val)
+
+	_, err = cr.StringFromPrompt(&cr.PromptOptions{
+		Prompt:      "Region",
+		MaxAttempts: 2,
+		In:          prompttest.ScriptedInput([]string{"", ""}),
+		Out:         prompttest.NewOutput(),
+	}, &cr.StringValidation{Required: true})
+	require.EqualError(t, err, s.ErrPromptAttemptsExhausted(2)+": "+s.ErrMustBeDefined)
+
+	// hidden prompts don't show the default unless it's masked
+	out = prompttest.NewOutput()
+	secret, err := cr.StringFromPrompt(&cr.PromptOptions{
+		Prompt:      "Secret",
+		HideTyping:  true,
+		MaskDefault: true,
+		In:          prompttest.ScriptedInput([]string{"hunter2"}),
+		Out:         out,
+	}, &cr.StringValidation{Default: "abcdefgh1234"})
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", secret)
+	require.NotContains(t, out.String(), "abcdefgh1234")
+	out.RequireInOrder(t, "Secret [********1234]")
+}
+
+func TestPromptSelect(t *testing.T) {
+	regions := []string{"us-east-1", "us-east-2", "us-west-1", "us-west-2"}
+
+	out := prompttest.NewOutput()
+	val, err := cr.StringFromPromptSelect(&cr.PromptOptions{
+		Prompt: "Region",
+		In:     prompttest.ScriptedInput([]string{"9", "2"}),
+		Out:    out,
+	}, &cr.StringValidation{AllowedValues: regions, Default: "us-west-2"})
+	require.NoError(t, err)
+	require.Equal(t, "us-east-2", val)
+	out.RequireInOrder(t,
+		"1) us-east-1", "2) us-east-2", "3) us-west-1", "4) us-west-2 (default)",
+		"Region", s.ErrInvalidSelection("9", 4),
+		"Region",
+	)
+
+	val, err = cr.StringFromPromptSelect(&cr.PromptOptions{
+		Prompt: "Region",
+		In:     prompttest.ScriptedInput([]string{"us-west-1"}),
+		Out:    prompttest.NewOutput(),
+	}, &cr.StringValidation{AllowedValues: regions})
+	require.NoError(t, err)
+	require.Equal(t, "us-west-1", val)
+
+	val, err = cr.StringFromPromptSelect(&cr.PromptOptions{
+		Prompt: "Region",
+		In:     prompttest.ScriptedInput([]string{""}),
+		Out:    prompttest.NewOutput(),
+	}, &cr.StringValidation{AllowedValues: regions, Default: "us-west-2"})
+	require.NoError(t, err)
+	require.Equal(t, "us-west-2", val)
+
+	num, err := cr.IntFromPromptSelect(&cr.PromptOptions{
+		Prompt: "Replicas",
+		In:     prompttest.ScriptedInput([]string{"3"}),
+		Out:    prompttest.NewOutput(),
+	}, &cr.IntValidation{AllowedValues: []int{1, 2, 4, 8}})
+	require.NoError(t, err)
+	require.Equal(t, 4, num)
+
+	// long menus can be filtered by prefix, which doesn't use up an attempt
+	var zones []string
+	for _, region := range regions {
+		for _, zone := range []string{"a", "b", "c", "d"} {
+			zones = append(zones, region+zone)
+		}
+	}
+	out = prompttest.NewOutput()
+	val, err = cr.StringFromPromptSelect(&cr.PromptOptions{
+		Prompt:      "Zone",
+		MaxAttempts: 1,
+		In:          prompttest.ScriptedInput([]string{"us-west-2", "16"}),
+		Out:         out,
+	}, &cr.StringValidation{AllowedValues: zones})
+	require.NoError(t, err)
+	require.Equal(t, "us-west-2d", val)
+	out.RequireInOrder(t, "1) us-east-1a", "16) us-west-2d", "Zone", "13) us-west-2a", "16) us-west-2d", "Zone")
+	filteredMenu := strings.SplitN(out.String(), "Enter a value: ", 2)[1]
+	require.NotContains(t, filteredMenu, "us-east")
+}
+
+func TestConfirmScriptedInput(t *testing.T) {
+	out := prompttest.NewOutput()
+	val, err := cr.ConfirmWithOptions(&cr.ConfirmOptions{
+		Question: "Delete deployment 'iris'?",
+		In:       prompttest.ScriptedInput([]string{"maybe", "YES"}),
+		Out:      out,
+	})
+	require.NoError(t, err)
+	require.True(t, val)
+	out.RequireInOrder(t, "Delete deployment 'iris'? [y/N]", s.ErrInvalidStr("maybe", "y", "yes", "n", "no"))
+
+	val, err = cr.ConfirmWithOptions(&cr.ConfirmOptions{
+		Question:   "Deploy?",
+		DefaultYes: true,
+		In:         prompttest.ScriptedInput([]string{""}),
+		Out:        prompttest.NewOutput(),
+	})
+	require.NoError(t, err)
+	require.True(t, val)
+
+	_, err = cr.ConfirmWithOptions(&cr.ConfirmOptions{
+		Question:        "Delete?",
+		RequireExplicit: true,
+		MaxAttempts:     1,
+		In:              prompttest.ScriptedInput([]string{""}),
+		Out:             prompttest.NewOutput(),
+	})
+	require.EqualError(t, err, s.ErrInvalidStr("", "y", "yes", "n", "no"))
+}
+
+func TestPromptMultiLine(t *testing.T) {
+	pem := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----"
+
+	out := prompttest.NewOutput()
+	val, err := cr.StringFromPrompt(&cr.PromptOptions{
+		Prompt:    "Certificate",
+		MultiLine: true,
+		In:        prompttest.ScriptedInput(append(strings.Split(pem, "\n"), "EOF", "next")),
+		Out:       out,
+	}, &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, pem, val)
+	out.RequireInOrder(t, "Certificate", "end with EOF on its own line")
+
+	// an EOF also ends the input
+	val, err = cr.StringFromPrompt(&cr.PromptOptions{
+		Prompt:                  "Certificate",
+		MultiLine:               true,
+		PreserveTrailingNewline: true,
+		In:                      strings.NewReader(pem),
+		Out:                     prompttest.NewOutput(),
+	}, &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, pem+"\n", val)
+
+	val, err = cr.StringFromPrompt(&cr.PromptOptions{
+		Prompt:      "Config",
+		MultiLine:   true,
+		EndSentinel: ".",
+		In:          prompttest.ScriptedInput([]string{"a: 1", "b: 2", "."}),
+		Out:         prompttest.NewOutput(),
+	}, &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "a: 1\nb: 2", val)
+
+	// the default is used if nothing is entered, and only its first line is shown
+	out = prompttest.NewOutput()
+	val, err = cr.StringFromPrompt(&cr.PromptOptions{
+		Prompt:    "Certificate",
+		MultiLine: true,
+		In:        prompttest.ScriptedInput([]string{"EOF"}),
+		Out:       out,
+	}, &cr.StringValidation{Default: pem})
+	require.NoError(t, err)
+	require.Equal(t, pem, val)
+	out.RequireInOrder(t, "Certificate [-----BEGIN CERTIFICATE-----…]")
+	require.NotContains(t, out.String(), "MIIB")
+}
+
+func TestPromptConstraintHint(t *testing.T) {
+	require.Equal(t, "1–64", cr.ConstraintHint(&cr.IntValidation{GreaterThanOrEqualTo: util.IntPtr(1), LessThanOrEqualTo: util.IntPtr(64)}))
+	require.Equal(t, "> 0, <= 1", cr.ConstraintHint(&cr.Float64Validation{GreaterThan: util.Float64Ptr(0), LessThanOrEqualTo: util.Float64Ptr(1)}))
+	require.Equal(t, "debug|info|warn|error", cr.ConstraintHint(&cr.StringValidation{AllowedValues: []string{"debug", "info", "warn", "error"}}))
+	require.Equal(t, "y/n", cr.ConstraintHint(&cr.BoolValidation{}))
+	require.Equal(t, "", cr.ConstraintHint(&cr.StringValidation{}))
+
+	out := prompttest.NewOutput()
+	num, err := cr.IntFromPrompt(&cr.PromptOptions{
+		Prompt: "Worker count",
+		In:     prompttest.ScriptedInput([]string{""}),
+		Out:    out,
+	}, &cr.IntValidation{GreaterThanOrEqualTo: util.IntPtr(1), LessThanOrEqualTo: util.IntPtr(64), Default: 4})
+	require.NoError(t, err)
+	require.Equal(t, 4, num)
+	out.RequireInOrder(t, "Worker count (1–64) [4]")
+
+	out = prompttest.NewOutput()
+	_, err = cr.StringFromPrompt(&cr.PromptOptions{
+		Prompt:          "Log level",
+		HideConstraints: true,
+		In:              prompttest.ScriptedInput([]string{""}),
+		Out:             out,
+	}, &cr.StringValidation{AllowedValues: []string{"debug", "info"}, Default: "info"})
+	require.NoError(t, err)
+	require.NotContains(t, out.String(), "debug|info")
+	out.RequireInOrder(t, "Log level [info]")
+}
+
+func TestPromptRetryMessage(t *testing.T) {
+	out := prompttest.NewOutput()
+	_, err := cr.IntFromPrompt(&cr.PromptOptions{
+		Prompt:      "Port",
+		MaxAttempts: 3,
+		In:          prompttest.ScriptedInput([]string{"4000", "abc", "5000"}),
+		Out:         out,
+	}, &cr.IntValidation{AllowedValues: []int{80, 443, 8080}, Default: 80})
+	require.EqualError(t, err, s.ErrPromptAttemptsExhausted(3)+": "+s.ErrInvalidInt(5000, 80, 443, 8080))
+	out.RequireInOrder(t,
+		"Port", s.ErrInvalidInt(4000, 80, 443, 8080), "(2 attempts remaining)",
+		"Port", s.ErrInvalidPrimitiveType("abc", s.PrimTypeInt), "(1 attempt remaining)",
+		"Port",
+	)
+
+	// an invalid entry never falls back to the default
+	num, err := cr.IntFromPrompt(&cr.PromptOptions{
+		Prompt:      "Port",
+		MaxAttempts: 1,
+		In:          prompttest.ScriptedInput([]string{"4000"}),
+		Out:         prompttest.NewOutput(),
+	}, &cr.IntValidation{AllowedValues: []int{80, 443, 8080}, Default: 80})
+	require.Error(t, err)
+	require.Equal(t, 0, num)
+
+	out = prompttest.NewOutput()
+	num, err = cr.IntFromPrompt(&cr.PromptOptions{
+		Prompt: "Port",
+		In:     prompttest.ScriptedInput([]string{"4000", "443"}),
+		Out:    out,
+		RetryMessageFunc: func(err error, remaining int) string {
+			return fmt.Sprintf("!! %s (%d left)", err.Error(), remaining)
+		},
+	}, &cr.IntValidation{AllowedValues: []int{80, 443, 8080}})
+	require.NoError(t, err)
+	require.Equal(t, 443, num)
+	out.RequireInOrder(t, "!! "+s.ErrInvalidInt(4000, 80, 443, 8080)+" (2 left)")
+}
+
+func TestPromptStylers(t *testing.T) {
+	bold := func(str string) string { return "<b>" + str + "</b>" }
+	dim := func(str string) string { return "<dim>" + str + "</dim>" }
+	red := func(str string) string { return "<red>" + str + "</red>" }
+
+	promptOpts := func(out *prompttest.Output) *cr.PromptOptions {
+		return &cr.PromptOptions{
+			Prompt:        "Replicas",
+			In:            prompttest.ScriptedInput([]string{"abc", ""}),
+			Out:           out,
+			PromptStyler:  bold,
+			DefaultStyler: dim,
+			ErrorStyler:   red,
+		}
+	}
+
+	// the output isn't a terminal
+	out := prompttest.NewOutput()
+	_, err := cr.IntFromPrompt(promptOpts(out), &cr.IntValidation{Default: 1})
+	require.NoError(t, err)
+	require.NotContains(t, out.String(), "<")
+	out.RequireInOrder(t, "Replicas [1]")
+
+	cr.SetPromptColors(true)
+	defer cr.ResetPromptColors()
+
+	out = prompttest.NewOutput()
+	num, err := cr.IntFromPrompt(promptOpts(out), &cr.IntValidation{Default: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, num)
+	out.RequireInOrder(t,
+		"<b>Replicas</b> <dim>[1]</dim>",
+		"<red>"+s.ErrInvalidPrimitiveType("abc", s.PrimTypeInt),
+		"<b>Replicas</b> <dim>[1]</dim>",
+	)
+
+	// returned errors are never styled
+	_, err = cr.IntFromPrompt(&cr.PromptOptions{
+		Prompt:      "Replicas",
+		MaxAttempts: 1,
+		In:          prompttest.ScriptedInput([]string{"abc"}),
+		Out:         prompttest.NewOutput(),
+		ErrorStyler: red,
+	}, &cr.IntValidation{})
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType("abc", s.PrimTypeInt))
+}
+
+func TestPromptPolicy(t *testing.T) {
+	os.Setenv(cr.YesEnvVar, "true")
+	require.Equal(t, cr.PromptAcceptDefaults, cr.GetPromptPolicy())
+	os.Unsetenv(cr.YesEnvVar)
+	require.Equal(t, cr.PromptInteractive, cr.GetPromptPolicy())
+
+	cr.SetPromptPolicy(cr.PromptAcceptDefaults)
+	defer cr.ResetPromptPolicy()
+
+	out := prompttest.NewOutput()
+	num, err := cr.IntFromPrompt(&cr.PromptOptions{
+		Prompt: "Replicas",
+		In:     prompttest.ScriptedInput([]string{"3"}),
+		Out:    out,
+	}, &cr.IntValidation{Default: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, num)
+	require.Empty(t, out.String())
+
+	_, err = cr.StringFromPrompt(&cr.PromptOptions{Prompt: "API key"}, &cr.StringValidation{Required: true})
+	require.EqualError(t, err, "API key: "+s.ErrMustBeDefined)
+
+	require.True(t, cr.Confirm("Delete deployment?", false))
+
+	cr.SetPromptPolicy(cr.PromptFailIfNeeded)
+
+	_, err = cr.IntFromPrompt(&cr.PromptOptions{Prompt: "Replicas", NonInteractiveHint: "pass --replicas"}, &cr.IntValidation{Default: 1})
+	require.EqualError(t, err, s.ErrPromptNeeded("Replicas", "pass --replicas"))
+
+	_, err = cr.ConfirmWithOptions(&cr.ConfirmOptions{Question: "Delete deployment?"})
+	require.EqualError(t, err, s.ErrPromptNeeded("Delete deployment?", ""))
+}
+
+func TestPromptLineEditingFallback(t *testing.T) {
+	// line editing is only used when stdin and stdout are terminals
+	num, err := cr.IntFromPrompt(&cr.PromptOptions{
+		Prompt:      "Replicas",
+		LineEditing: true,
+		KeepHistory: true,
+		In:          prompttest.ScriptedInput([]string{"abc", "2"}),
+		Out:         prompttest.NewOutput(),
+	}, &cr.IntValidation{Default: 1})
+	require.NoError(t, err)
+	require.Equal(t, 2, num)
+}
+
+func TestListFromPrompt(t *testing.T) {
+	out := prompttest.NewOutput()
+	tags, err := cr.StringListFromPrompt(&cr.PromptOptions{
+		Prompt: "Tag",
+		In:     prompttest.ScriptedInput([]string{"a", "B", "b", "a", "", "x", "y", ""}),
+		Out:    out,
+	}, &cr.StringListValidation{
+		DisallowDups: true,
+		MinLength:    2,
+		ElementValidator: func(tag string) (string, error) {
+			if tag != strings.ToLower(tag) {
+				return "", errors.New("must be lower case")
+			}
+			return tag, nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"x", "y"}, tags)
+	out.RequireInOrder(t,
+		"Tag #1", "Tag #2", "must be lower case", "Tag #2", "Tag #3", "Tag #4",
+		s.ErrDuplicatedValue("a")+" (please enter the list again)",
+		"Tag #1", "Tag #2", "Tag #3",
+	)
+
+	// the list can only be re-entered once
+	_, err = cr.StringListFromPrompt(&cr.PromptOptions{
+		Prompt: "Tag",
+		In:     prompttest.ScriptedInput([]string{"a", "", "a", ""}),
+		Out:    prompttest.NewOutput(),
+	}, &cr.StringListValidation{MinLength: 2})
+	require.EqualError(t, err, s.ErrTooFewElements(1, 2))
+
+	// an empty list uses the default
+	ports, err := cr.IntListFromPrompt(&cr.PromptOptions{
+		Prompt: "Port",
+		In:     prompttest.ScriptedInput([]string{""}),
+		Out:    prompttest.NewOutput(),
+	}, &cr.IntListValidation{Default: []int{80, 443}})
+	require.NoError(t, err)
+	require.Equal(t, []int{80, 443}, ports)
+
+	// prompting stops at MaxLength
+	ports, err = cr.IntListFromPrompt(&cr.PromptOptions{
+		Prompt: "Port",
+		In:     prompttest.ScriptedInput([]string{"abc", "80", "443"}),
+		Out:    prompttest.NewOutput(),
+	}, &cr.IntListValidation{MaxLength: 2})
+	require.NoError(t, err)
+	require.Equal(t, []int{80, 443}, ports)
+
+	out = prompttest.NewOutput()
+	ports, err = cr.IntListFromPrompt(&cr.PromptOptions{
+		Prompt:     "Ports",
+		SingleLine: true,
+		In:         prompttest.ScriptedInput([]string{"80, abc", "80, 80", "80, 8080"}),
+		Out:        out,
+	}, &cr.IntListValidation{DisallowDups: true, Default: []int{80}})
+	require.NoError(t, err)
+	require.Equal(t, []int{80, 8080}, ports)
+	out.RequireInOrder(t,
+		"Ports [80]", "1: "+s.ErrInvalidPrimitiveType("abc", s.PrimTypeInt),
+		"Ports [80]", s.ErrDuplicatedValue(80),
+	)
+}
+
+func TestPromptInjectedIO(t *testing.T) {
+	scripted := func(prompt string, lines ...string) (*cr.PromptOptions, *prompttest.Output) {
+		out := prompttest.NewOutput()
+		return &cr.PromptOptions{Prompt: prompt, In: prompttest.ScriptedInput(lines), Out: out}, out
+	}
+
+	opts, out := scripted("Enabled", "maybe", "yes")
+	boolVal, err := cr.BoolFromPrompt(opts, &cr.BoolValidation{})
+	require.NoError(t, err)
+	require.True(t, boolVal)
+	out.RequireInOrder(t, "Enabled", "Enabled")
+
+	opts, _ = scripted("Enabled", "")
+	boolPtr, err := cr.BoolPtrFromPrompt(opts, &cr.BoolPtrValidation{})
+	require.NoError(t, err)
+	require.Nil(t, boolPtr)
+
+	opts, _ = scripted("Ratio", "0.25")
+	float32Val, err := cr.Float32FromPrompt(opts, &cr.Float32Validation{})
+	require.NoError(t, err)
+	require.Equal(t, float32(0.25), float32Val)
+
+	opts, _ = scripted("Ratio", "0.5")
+	float32Ptr, err := cr.Float32PtrFromPrompt(opts, &cr.Float32PtrValidation{})
+	require.NoError(t, err)
+	require.Equal(t, float32(0.5), *float32Ptr)
+
+	opts, out = scripted("Ratio", "1.5", "0.75")
+	float64Val, err := cr.Float64FromPrompt(opts, &cr.Float64Validation{LessThanOrEqualTo: util.Float64Ptr(1)})
+	require.NoError(t, err)
+	require.Equal(t, 0.75, float64Val)
+	out.RequireInOrder(t, "Ratio", s.ErrMustBeLessThanOrEqualTo(1.5, 1), "Ratio")
+
+	opts, _ = scripted("Ratio", "0.125")
+	float64Ptr, err := cr.Float64PtrFromPrompt(opts, &cr.Float64PtrValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 0.125, *float64Ptr)
+
+	opts, _ = scripted("Port", "8080")
+	int32Val, err := cr.Int32FromPrompt(opts, &cr.Int32Validation{})
+	require.NoError(t, err)
+	require.Equal(t, int32(8080), int32Val)
+
+	opts, _ = scripted("Port", "443")
+	int32Ptr, err := cr.Int32PtrFromPrompt(opts, &cr.Int32PtrValidation{})
+	require.NoError(t, err)
+	require.Equal(t, int32(443), *int32Ptr)
+
+	opts, _ = scripted("Bytes", "9000000000")
+	int64Val, err := cr.Int64FromPrompt(opts, &cr.Int64Validation{})
+	require.NoError(t, err)
+	require.Equal(t, int64(9000000000), int64Val)
+
+	opts, _ = scripted("Bytes", "", "1")
+	int64Ptr, err := cr.Int64PtrFromPrompt(opts, &cr.Int64PtrValidation{Required: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), *int64Ptr)
+
+	opts, _ = scripted("Replicas", "2")
+	intPtr, err := cr.IntPtrFromPrompt(opts, &cr.IntPtrValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 2, *intPtr)
+
+	opts, _ = scripted("Name", "iris")
+	strPtr, err := cr.StringPtrFromPrompt(opts, &cr.StringPtrValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "iris", *strPtr)
+}
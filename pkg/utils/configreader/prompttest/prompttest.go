@@ -0,0 +1,71 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package prompttest provides helpers for testing code which uses the configreader FromPrompt readers
+// (set PromptOptions.In to a ScriptedInput, and PromptOptions.Out to an Output)
+package prompttest
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ScriptedInput returns a reader which provides each line as the input to one prompt
+func ScriptedInput(lines []string) io.Reader {
+	if len(lines) == 0 {
+		return strings.NewReader("")
+	}
+	return strings.NewReader(strings.Join(lines, "\n") + "\n")
+}
+
+// Output captures everything written by prompts
+type Output struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func NewOutput() *Output {
+	return &Output{}
+}
+
+func (output *Output) Write(p []byte) (int, error) {
+	output.mutex.Lock()
+	defer output.mutex.Unlock()
+	return output.buf.Write(p)
+}
+
+func (output *Output) String() string {
+	output.mutex.Lock()
+	defer output.mutex.Unlock()
+	return output.buf.String()
+}
+
+// RequireInOrder fails the test unless each of expected was written, in order
+func (output *Output) RequireInOrder(t testing.TB, expected ...string) {
+	t.Helper()
+	rendered := output.String()
+	remaining := rendered
+	for _, str := range expected {
+		index := strings.Index(remaining, str)
+		if index < 0 {
+			t.Fatalf("expected %q in prompt output (in order), but it was not found\n\nprompt output:\n%s", str, rendered)
+		}
+		remaining = remaining[index+len(str):]
+	}
+}
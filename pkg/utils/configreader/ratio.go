@@ -0,0 +1,181 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"strings"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// RatioValue is a fraction such as "1/4" (a plain number n is read as n/1)
+type RatioValue struct {
+	Numerator   float64
+	Denominator float64
+}
+
+func (r RatioValue) Float64() float64 {
+	return r.Numerator / r.Denominator
+}
+
+type RatioValidation struct {
+	Required             bool
+	Default              string // e.g. "1/4"
+	AllowColon           bool   // also accept "a:b"
+	GreaterThan          *float64
+	GreaterThanOrEqualTo *float64
+	LessThan             *float64
+	LessThanOrEqualTo    *float64
+}
+
+func Ratio(inter interface{}, v *RatioValidation) (RatioValue, error) {
+	if inter == nil {
+		return RatioValue{}, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+	}
+	if casted, castOk := inter.(string); castOk {
+		return RatioFromStr(casted, v)
+	}
+	casted, castOk := cast.InterfaceToFloat64(inter)
+	if !castOk {
+		return RatioValue{}, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeString, s.PrimTypeFloat}})
+	}
+	return ValidateRatio(RatioValue{Numerator: casted, Denominator: 1}, v)
+}
+
+func RatioFromInterfaceMap(key string, iMap map[string]interface{}, v *RatioValidation) (RatioValue, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateRatioMissing(v)
+		if err != nil {
+			return RatioValue{}, errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := Ratio(inter, v)
+	if err != nil {
+		return RatioValue{}, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func RatioFromStr(valStr string, v *RatioValidation) (RatioValue, error) {
+	if valStr == "" {
+		return ValidateRatioMissing(v)
+	}
+	casted, err := parseRatio(valStr, v.AllowColon)
+	if err != nil {
+		return RatioValue{}, err
+	}
+	return ValidateRatio(casted, v)
+}
+
+func RatioFromEnv(envVarName string, v *RatioValidation) (RatioValue, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateRatioMissing(v)
+		if err != nil {
+			return RatioValue{}, envVarError(err, envVarName)
+		}
+		return val, nil
+	}
+	val, err := RatioFromStr(*valStr, v)
+	if err != nil {
+		return RatioValue{}, envVarError(err, envVarName)
+	}
+	return val, nil
+}
+
+func ValidateRatioMissing(v *RatioValidation) (RatioValue, error) {
+	if v.Required {
+		return RatioValue{}, errors.Wrap(ErrRequired)
+	}
+	if v.Default == "" {
+		return RatioValue{}, nil
+	}
+	casted, err := parseRatio(v.Default, v.AllowColon)
+	if err != nil {
+		return RatioValue{}, err
+	}
+	return ValidateRatio(casted, v)
+}
+
+func ValidateRatio(val RatioValue, v *RatioValidation) (RatioValue, error) {
+	err := ValidateFloat64Val(val.Float64(), &Float64Validation{
+		GreaterThan:          v.GreaterThan,
+		GreaterThanOrEqualTo: v.GreaterThanOrEqualTo,
+		LessThan:             v.LessThan,
+		LessThanOrEqualTo:    v.LessThanOrEqualTo,
+	})
+	if err != nil {
+		return RatioValue{}, err
+	}
+	return val, nil
+}
+
+func parseRatio(valStr string, allowColon bool) (RatioValue, error) {
+	sep := "/"
+	if allowColon && !strings.Contains(valStr, "/") {
+		sep = ":"
+	}
+
+	split := strings.Split(valStr, sep)
+	if len(split) == 1 {
+		num, ok := s.ParseFloat64(strings.TrimSpace(valStr))
+		if !ok {
+			return RatioValue{}, errors.NewUserCoded(s.MsgInvalidRatio, s.ErrInvalidRatio(valStr, allowColon))
+		}
+		return RatioValue{Numerator: num, Denominator: 1}, nil
+	}
+	if len(split) != 2 {
+		return RatioValue{}, errors.NewUserCoded(s.MsgInvalidRatio, s.ErrInvalidRatio(valStr, allowColon))
+	}
+
+	num, ok := s.ParseFloat64(strings.TrimSpace(split[0]))
+	if !ok {
+		return RatioValue{}, errors.NewUserCoded(s.MsgInvalidRatio, s.ErrInvalidRatio(valStr, allowColon))
+	}
+	denom, ok := s.ParseFloat64(strings.TrimSpace(split[1]))
+	if !ok {
+		return RatioValue{}, errors.NewUserCoded(s.MsgInvalidRatio, s.ErrInvalidRatio(valStr, allowColon))
+	}
+	if denom == 0 {
+		return RatioValue{}, errors.NewUserCoded(s.MsgZeroDenominator, s.ErrZeroDenominator(valStr))
+	}
+	return RatioValue{Numerator: num, Denominator: denom}, nil
+}
+
+//
+// Musts
+//
+
+func MustRatioFromStr(valStr string, v *RatioValidation) RatioValue {
+	val, err := RatioFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustRatioFromEnv(envVarName string, v *RatioValidation) RatioValue {
+	val, err := RatioFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
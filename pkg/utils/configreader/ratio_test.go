@@ -0,0 +1,69 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+func TestRatio(t *testing.T) {
+	v := &cr.RatioValidation{
+		Default:              "1/2",
+		GreaterThanOrEqualTo: util.Float64Ptr(0),
+		LessThanOrEqualTo:    util.Float64Ptr(1),
+	}
+
+	val, err := cr.RatioFromStr("1/4", v)
+	require.NoError(t, err)
+	require.Equal(t, cr.RatioValue{Numerator: 1, Denominator: 4}, val)
+	require.Equal(t, 0.25, val.Float64())
+
+	val, err = cr.RatioFromStr("", v)
+	require.NoError(t, err)
+	require.Equal(t, 0.5, val.Float64())
+
+	val, err = cr.Ratio(0.75, v)
+	require.NoError(t, err)
+	require.Equal(t, cr.RatioValue{Numerator: 0.75, Denominator: 1}, val)
+
+	_, err = cr.RatioFromStr("3/2", v)
+	require.EqualError(t, err, s.ErrMustBeLessThanOrEqualTo(1.5, float64(1)))
+
+	_, err = cr.RatioFromStr("1/0", v)
+	require.EqualError(t, err, s.ErrZeroDenominator("1/0"))
+
+	_, err = cr.RatioFromStr("1:4", v)
+	require.EqualError(t, err, s.ErrInvalidRatio("1:4", false))
+
+	_, err = cr.RatioFromStr("1/2/3", v)
+	require.EqualError(t, err, s.ErrInvalidRatio("1/2/3", false))
+
+	v.AllowColon = true
+	v.LessThanOrEqualTo = nil
+	val, err = cr.RatioFromInterfaceMap("split", map[string]interface{}{"split": "3 : 2"}, v)
+	require.NoError(t, err)
+	require.Equal(t, 1.5, val.Float64())
+
+	_, err = cr.RatioFromInterfaceMap("split", map[string]interface{}{"split": "3:0"}, v)
+	require.EqualError(t, err, "split: "+s.ErrZeroDenominator("3:0"))
+}
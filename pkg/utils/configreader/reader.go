@@ -18,11 +18,16 @@ package configreader
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"reflect"
+	"regexp"
 	"strings"
+	"time"
 
 	input "github.com/tcnksm/go-input"
 	yaml "gopkg.in/yaml.v2"
@@ -61,9 +66,22 @@ type StructFieldValidation struct {
 	Float64Validation             *Float64Validation
 	Float64PtrValidation          *Float64PtrValidation
 	Float64ListValidation         *Float64ListValidation
+	IntOrKeywordValidation        *IntOrKeywordValidation
+	LevelValidation               *LevelValidation
+	EnumValidation                *EnumValidation
+	ByteSizeValidation            *ByteSizeValidation
+	IntRangeListValidation        *IntRangeListValidation
+	RatioValidation               *RatioValidation
+	TimestampValidation           *TimestampValidation
+	TimestampListValidation       *TimestampListValidation
+	DurationValidation            *DurationValidation
+	GlobValidation                *GlobValidation
+	EndpointValidation            *EndpointValidation
 	StringMapValidation           *StringMapValidation
+	IntMapValidation              *IntMapValidation
 	InterfaceMapValidation        *InterfaceMapValidation
 	InterfaceMapListValidation    *InterfaceMapListValidation
+	InterfaceListValidation       *InterfaceListValidation
 	InterfaceValidation           *InterfaceValidation
 	StructValidation              *StructValidation
 	StructListValidation          *StructListValidation
@@ -82,6 +100,8 @@ type StructValidation struct {
 	DefualtNil             bool // If this struct is nested and it's key is not defined, set it to nil instead of defaults or erroring (e.g. if any subfields are required)
 	ShortCircuit           bool
 	AllowExtraFields       bool
+	PostValidate           func(interface{}) error // called with dest after all fields are read without errors (for checks which span multiple fields)
+	CollectErrors          bool                    // return a single *ErrorCollector which lists every error (including in nested structs), instead of separate errors; ShortCircuit is ignored
 }
 
 type StructListValidation struct {
@@ -104,6 +124,7 @@ type InterfaceStructValidation struct {
 type InterfaceStructType struct {
 	Type                   interface{} // e.g. (*MyType)(nil)
 	StructFieldValidations []*StructFieldValidation
+	PostValidate           func(interface{}) error
 }
 
 type InterfaceStructListValidation struct {
@@ -114,19 +135,31 @@ type InterfaceStructListValidation struct {
 }
 
 func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
+	if v.CollectErrors {
+		collectedValidation := *v
+		collectedValidation.CollectErrors = false
+		collectedValidation.ShortCircuit = false
+		ec := &ErrorCollector{}
+		ec.AddAll(Struct(dest, inter, &collectedValidation))
+		if err := ec.Err(); err != nil {
+			return []error{err}
+		}
+		return nil
+	}
+
 	allowedFields := []string{}
 	allErrs := []error{}
 	var ok bool
 
 	if inter == nil {
 		if !v.AllowNull {
-			return []error{errors.New(s.ErrCannotBeNull)}
+			return []error{errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)}
 		}
 	}
 
 	interMap, ok := cast.InterfaceToStrInterfaceMap(inter)
 	if !ok {
-		return []error{errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeMap))}
+		return []error{errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeMap}})}
 	}
 
 	for _, structFieldValidation := range v.StructFieldValidations {
@@ -143,14 +176,16 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 
 		if structFieldValidation.StringValidation != nil {
 			validation := *structFieldValidation.StringValidation
+			validation.allowedValuesSet = allowedValuesSet{shared: &structFieldValidation.StringValidation.allowedValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = StringFromInterfaceMap(key, interMap, &validation)
 			if err == nil && structFieldValidation.Parser != nil {
 				val, err = structFieldValidation.Parser(val.(string))
-				err = errors.Wrap(err, key)
+				err = errors.WrapKey(err, key)
 			}
 		} else if structFieldValidation.StringPtrValidation != nil {
 			validation := *structFieldValidation.StringPtrValidation
+			validation.allowedValuesSet = allowedValuesSet{shared: &structFieldValidation.StringPtrValidation.allowedValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = StringPtrFromInterfaceMap(key, interMap, &validation)
 			if err == nil && structFieldValidation.Parser != nil {
@@ -165,7 +200,7 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 						val = valPtrValue.Interface()
 					} else {
 						val = nil
-						err = errors.Wrap(err, key)
+						err = errors.WrapKey(err, key)
 					}
 				}
 			}
@@ -187,10 +222,12 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 			val, err = BoolListFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.IntValidation != nil {
 			validation := *structFieldValidation.IntValidation
+			validation.allowedValuesSet = allowedValuesSet{shared: &structFieldValidation.IntValidation.allowedValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = IntFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.IntPtrValidation != nil {
 			validation := *structFieldValidation.IntPtrValidation
+			validation.allowedValuesSet = allowedValuesSet{shared: &structFieldValidation.IntPtrValidation.allowedValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = IntPtrFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.IntListValidation != nil {
@@ -199,10 +236,12 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 			val, err = IntListFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.Int32Validation != nil {
 			validation := *structFieldValidation.Int32Validation
+			validation.allowedValuesSet = allowedValuesSet{shared: &structFieldValidation.Int32Validation.allowedValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = Int32FromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.Int32PtrValidation != nil {
 			validation := *structFieldValidation.Int32PtrValidation
+			validation.allowedValuesSet = allowedValuesSet{shared: &structFieldValidation.Int32PtrValidation.allowedValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = Int32PtrFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.Int32ListValidation != nil {
@@ -211,10 +250,12 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 			val, err = Int32ListFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.Int64Validation != nil {
 			validation := *structFieldValidation.Int64Validation
+			validation.allowedValuesSet = allowedValuesSet{shared: &structFieldValidation.Int64Validation.allowedValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = Int64FromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.Int64PtrValidation != nil {
 			validation := *structFieldValidation.Int64PtrValidation
+			validation.allowedValuesSet = allowedValuesSet{shared: &structFieldValidation.Int64PtrValidation.allowedValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = Int64PtrFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.Int64ListValidation != nil {
@@ -223,18 +264,22 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 			val, err = Int64ListFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.Float32Validation != nil {
 			validation := *structFieldValidation.Float32Validation
+			validation.allowedValuesSet = allowedValuesSet{shared: &structFieldValidation.Float32Validation.allowedValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = Float32FromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.Float32PtrValidation != nil {
 			validation := *structFieldValidation.Float32PtrValidation
+			validation.allowedValuesSet = allowedValuesSet{shared: &structFieldValidation.Float32PtrValidation.allowedValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = Float32PtrFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.Float64Validation != nil {
 			validation := *structFieldValidation.Float64Validation
+			validation.allowedValuesSet = allowedValuesSet{shared: &structFieldValidation.Float64Validation.allowedValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = Float64FromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.Float64PtrValidation != nil {
 			validation := *structFieldValidation.Float64PtrValidation
+			validation.allowedValuesSet = allowedValuesSet{shared: &structFieldValidation.Float64PtrValidation.allowedValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = Float64PtrFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.Float32ListValidation != nil {
@@ -245,18 +290,72 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 			validation := *structFieldValidation.Float64ListValidation
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = Float64ListFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.IntOrKeywordValidation != nil {
+			validation := *structFieldValidation.IntOrKeywordValidation
+			validation.keywordsSet = allowedValuesSet{shared: &structFieldValidation.IntOrKeywordValidation.keywordsSet}
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = IntOrKeywordFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.LevelValidation != nil {
+			validation := *structFieldValidation.LevelValidation
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = LevelFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.EnumValidation != nil {
+			validation := *structFieldValidation.EnumValidation
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = EnumFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.ByteSizeValidation != nil {
+			validation := *structFieldValidation.ByteSizeValidation
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = ByteSizeFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.RatioValidation != nil {
+			validation := *structFieldValidation.RatioValidation
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = RatioFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.TimestampValidation != nil {
+			validation := *structFieldValidation.TimestampValidation
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = TimestampFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.DurationValidation != nil {
+			validation := *structFieldValidation.DurationValidation
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = DurationFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.GlobValidation != nil {
+			validation := *structFieldValidation.GlobValidation
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = GlobFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.EndpointValidation != nil {
+			validation := *structFieldValidation.EndpointValidation
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = EndpointFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.TimestampListValidation != nil {
+			validation := *structFieldValidation.TimestampListValidation
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = TimestampListFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.IntRangeListValidation != nil {
+			validation := *structFieldValidation.IntRangeListValidation
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = IntRangeListFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.IntMapValidation != nil {
+			validation := *structFieldValidation.IntMapValidation
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = IntMapFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.StringMapValidation != nil {
 			validation := *structFieldValidation.StringMapValidation
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = StringMapFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.InterfaceMapValidation != nil {
 			validation := *structFieldValidation.InterfaceMapValidation
+			validation.allowedLeafValuesSet = allowedValuesSet{shared: &structFieldValidation.InterfaceMapValidation.allowedLeafValuesSet}
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = InterfaceMapFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.InterfaceMapListValidation != nil {
 			validation := *structFieldValidation.InterfaceMapListValidation
 			updateValidation(&validation, dest, structFieldValidation)
 			val, err = InterfaceMapListFromInterfaceMap(key, interMap, &validation)
+		} else if structFieldValidation.InterfaceListValidation != nil {
+			validation := *structFieldValidation.InterfaceListValidation
+			updateValidation(&validation, dest, structFieldValidation)
+			val, err = InterfaceListFromInterfaceMap(key, interMap, &validation)
 		} else if structFieldValidation.InterfaceValidation != nil {
 			validation := *structFieldValidation.InterfaceValidation
 			updateValidation(&validation, dest, structFieldValidation)
@@ -264,10 +363,11 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 		} else if structFieldValidation.StructValidation != nil {
 			validation := *structFieldValidation.StructValidation
 			updateValidation(&validation, dest, structFieldValidation)
+			validation.CollectErrors = false // nested errors are collected by the outermost struct
 			nestedType := reflect.ValueOf(dest).Elem().FieldByName(structFieldValidation.StructField).Type()
 			interMapVal, ok := ReadInterfaceMapValue(key, interMap)
 			if !ok && validation.Required {
-				err = errors.New(key, s.ErrMustBeDefined)
+				err = errors.WrapKey(ErrRequired, key)
 			} else if !ok && validation.DefualtNil {
 				val = nil
 			} else {
@@ -276,20 +376,25 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 				}
 				val = reflect.New(nestedType.Elem()).Interface()
 				errs = Struct(val, interMapVal, &validation)
-				errs = errors.WrapMultiple(errs, key)
+				errs = errors.WrapKeyMultiple(errs, key)
 			}
 
 		} else if structFieldValidation.StructListValidation != nil {
 			validation := *structFieldValidation.StructListValidation
 			updateValidation(&validation, dest, structFieldValidation)
+			if validation.StructValidation != nil && validation.StructValidation.CollectErrors {
+				elemValidation := *validation.StructValidation
+				elemValidation.CollectErrors = false
+				validation.StructValidation = &elemValidation
+			}
 			nestedType := reflect.ValueOf(dest).Elem().FieldByName(structFieldValidation.StructField).Type()
 			interMapVal, ok := ReadInterfaceMapValue(key, interMap)
 			if !ok && validation.Required {
-				err = errors.New(key, s.ErrMustBeDefined)
+				err = errors.WrapKey(ErrRequired, key)
 			} else {
 				val = reflect.Indirect(reflect.New(nestedType)).Interface()
 				val, errs = StructList(val, interMapVal, &validation)
-				errs = errors.WrapMultiple(errs, key)
+				errs = errors.WrapKeyMultiple(errs, key)
 			}
 
 		} else if structFieldValidation.InterfaceStructValidation != nil {
@@ -297,10 +402,10 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 			updateValidation(&validation, dest, structFieldValidation)
 			interMapVal, ok := ReadInterfaceMapValue(key, interMap)
 			if !ok && validation.Required {
-				err = errors.New(key, s.ErrMustBeDefined)
+				err = errors.WrapKey(ErrRequired, key)
 			} else {
 				val, errs = InterfaceStruct(interMapVal, &validation)
-				errs = errors.WrapMultiple(errs, key)
+				errs = errors.WrapKeyMultiple(errs, key)
 			}
 
 		} else if structFieldValidation.InterfaceStructListValidation != nil {
@@ -309,11 +414,11 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 			nestedType := reflect.ValueOf(dest).Elem().FieldByName(structFieldValidation.StructField).Type()
 			interMapVal, ok := ReadInterfaceMapValue(key, interMap)
 			if !ok && validation.Required {
-				err = errors.New(key, s.ErrMustBeDefined)
+				err = errors.WrapKey(ErrRequired, key)
 			} else {
 				val = reflect.Indirect(reflect.New(nestedType)).Interface()
 				val, errs = InterfaceStructList(val, interMapVal, &validation)
-				errs = errors.WrapMultiple(errs, key)
+				errs = errors.WrapKeyMultiple(errs, key)
 			}
 
 		} else {
@@ -335,7 +440,15 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 		} else {
 			err = setField(val, dest, structFieldValidation.StructField)
 		}
-		if allErrs, ok = errors.AddError(allErrs, err, key); ok {
+		if allErrs, ok = errors.AddError(allErrs, errors.WrapKey(err, key)); ok {
+			if v.ShortCircuit {
+				return allErrs
+			}
+		}
+	}
+
+	if v.PostValidate != nil && !errors.HasErrors(allErrs) {
+		if allErrs, ok = errors.AddError(allErrs, v.PostValidate(dest)); ok {
 			if v.ShortCircuit {
 				return allErrs
 			}
@@ -343,9 +456,9 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 	}
 
 	if !v.AllowExtraFields {
-		extraFields := util.SubtractStrSlice(util.InterfaceMapKeys(interMap), allowedFields)
+		extraFields := util.SubtractStrSlice(util.InterfaceMapSortedKeys(interMap), allowedFields)
 		for _, extraField := range extraFields {
-			allErrs = append(allErrs, errors.New(s.ErrUnsupportedKey(extraField)))
+			allErrs = append(allErrs, errors.NewUserCoded(s.MsgUnsupportedKey, s.ErrUnsupportedKey(extraField)+s.DidYouMean(s.SuggestClosest(extraField, allowedFields))))
 		}
 	}
 	if errors.HasErrors(allErrs) {
@@ -357,14 +470,14 @@ func Struct(dest interface{}, inter interface{}, v *StructValidation) []error {
 func StructList(dest interface{}, inter interface{}, v *StructListValidation) (interface{}, []error) {
 	if inter == nil {
 		if !v.AllowNull {
-			return nil, []error{errors.New(s.ErrCannotBeNull)}
+			return nil, []error{errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)}
 		}
 		return nil, nil
 	}
 
 	interSlice, ok := cast.InterfaceToInterfaceSlice(inter)
 	if !ok {
-		return nil, []error{errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeList))}
+		return nil, []error{errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeList}})}
 	}
 
 	errs := []error{}
@@ -372,7 +485,7 @@ func StructList(dest interface{}, inter interface{}, v *StructListValidation) (i
 		val := reflect.New(reflect.ValueOf(dest).Type().Elem().Elem()).Interface()
 		subErrs := Struct(val, interItem, v.StructValidation)
 		var ok bool
-		if errs, ok = errors.AddErrors(errs, subErrs, s.Index(i)); ok {
+		if errs, ok = errors.AddErrors(errs, errors.WrapIndexMultiple(subErrs, i)); ok {
 			if v.ShortCircuit {
 				return nil, errs
 			} else {
@@ -388,14 +501,14 @@ func StructList(dest interface{}, inter interface{}, v *StructListValidation) (i
 func InterfaceStruct(inter interface{}, v *InterfaceStructValidation) (interface{}, []error) {
 	if inter == nil {
 		if !v.AllowNull {
-			return nil, []error{errors.New(s.ErrCannotBeNull)}
+			return nil, []error{errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)}
 		}
 		return nil, nil
 	}
 
 	interMap, ok := cast.InterfaceToStrInterfaceMap(inter)
 	if !ok {
-		return nil, []error{errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeMap))}
+		return nil, []error{errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeMap}})}
 	}
 
 	validTypeStrs := make([]string, len(v.InterfaceStructTypes))
@@ -435,6 +548,7 @@ func InterfaceStruct(inter interface{}, v *InterfaceStructValidation) (interface
 		AllowNull:              v.AllowNull,
 		ShortCircuit:           v.ShortCircuit,
 		AllowExtraFields:       v.AllowExtraFields,
+		PostValidate:           structType.PostValidate,
 	}
 	errs := Struct(val, inter, structValidation)
 	return val, errs
@@ -443,21 +557,21 @@ func InterfaceStruct(inter interface{}, v *InterfaceStructValidation) (interface
 func InterfaceStructList(dest interface{}, inter interface{}, v *InterfaceStructListValidation) (interface{}, []error) {
 	if inter == nil {
 		if !v.AllowNull {
-			return nil, []error{errors.New(s.ErrCannotBeNull)}
+			return nil, []error{errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)}
 		}
 		return nil, nil
 	}
 
 	interSlice, ok := cast.InterfaceToInterfaceSlice(inter)
 	if !ok {
-		return nil, []error{errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeList))}
+		return nil, []error{errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeList}})}
 	}
 
 	errs := []error{}
 	for i, interItem := range interSlice {
 		val, subErrs := InterfaceStruct(interItem, v.InterfaceStructValidation)
 		var ok bool
-		if errs, ok = errors.AddErrors(errs, subErrs, s.Index(i)); ok {
+		if errs, ok = errors.AddErrors(errs, errors.WrapIndexMultiple(subErrs, i)); ok {
 			if v.ShortCircuit {
 				return nil, errs
 			} else {
@@ -480,6 +594,7 @@ func updateValidation(validation interface{}, dest interface{}, structFieldValid
 	}
 }
 
+// ReadInterfaceMapValue returns nil for nil pointers (e.g. from a partially decoded struct), so that they're treated like null
 func ReadInterfaceMapValue(name string, interMap map[string]interface{}) (interface{}, bool) {
 	if interMap == nil {
 		return nil, false
@@ -488,10 +603,50 @@ func ReadInterfaceMapValue(name string, interMap map[string]interface{}) (interf
 		if !ok {
 			return nil, false
 		}
+		if cast.Deref(val) == nil {
+			return nil, true
+		}
 		return val, true
 	}
 }
 
+var fieldRefRegex = regexp.MustCompile(`^\$\{\s*([^{}\s]+)\s*\}$`)
+
+// FieldRef returns the name of the field which inter refers to (e.g. "default_timeout" for "${default_timeout}")
+func FieldRef(inter interface{}) (string, bool) {
+	str, ok := inter.(string)
+	if !ok {
+		return "", false
+	}
+	match := fieldRefRegex.FindStringSubmatch(strings.TrimSpace(str))
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// ReadInterfaceMapValueWithRefs is like ReadInterfaceMapValue(), but if the value refers to another field in the map
+// (e.g. "${default_timeout}"), that field's value is returned instead. References are followed recursively
+func ReadInterfaceMapValueWithRefs(name string, interMap map[string]interface{}) (interface{}, bool, error) {
+	inter, ok := ReadInterfaceMapValue(name, interMap)
+	chain := []string{name}
+	for ok {
+		ref, isRef := FieldRef(inter)
+		if !isRef {
+			return inter, true, nil
+		}
+		if util.IsStrInSlice(ref, chain) {
+			return nil, false, errors.NewUserCoded(s.MsgFieldRefCycle, s.ErrFieldRefCycle(append(chain, ref)))
+		}
+		chain = append(chain, ref)
+		inter, ok = ReadInterfaceMapValue(ref, interMap)
+		if !ok {
+			return nil, false, errors.NewUserCoded(s.MsgUndefinedFieldRef, s.ErrUndefinedFieldRef(ref))
+		}
+	}
+	return nil, false, nil
+}
+
 //
 // Prompt
 //
@@ -524,21 +679,28 @@ func ReadPrompt(dest interface{}, promptValidation *PromptValidation) error {
 	var err error
 
 	for _, promptItemValidation := range promptValidation.PromptItemValidations {
+		// unless MaxAttempts is set, keep prompting until the value is valid
+		promptOpts := *promptItemValidation.PromptOpts
+		retryForever := promptOpts.MaxAttempts <= 0
+		if retryForever {
+			promptOpts.MaxAttempts = 1
+		}
+
 		for {
 			if promptItemValidation.StringValidation != nil {
-				val, err = StringFromPrompt(promptItemValidation.PromptOpts, promptItemValidation.StringValidation)
+				val, err = StringFromPrompt(&promptOpts, promptItemValidation.StringValidation)
 			} else if promptItemValidation.BoolValidation != nil {
-				val, err = BoolFromPrompt(promptItemValidation.PromptOpts, promptItemValidation.BoolValidation)
+				val, err = BoolFromPrompt(&promptOpts, promptItemValidation.BoolValidation)
 			} else if promptItemValidation.IntValidation != nil {
-				val, err = IntFromPrompt(promptItemValidation.PromptOpts, promptItemValidation.IntValidation)
+				val, err = IntFromPrompt(&promptOpts, promptItemValidation.IntValidation)
 			} else if promptItemValidation.Int32Validation != nil {
-				val, err = Int32FromPrompt(promptItemValidation.PromptOpts, promptItemValidation.Int32Validation)
+				val, err = Int32FromPrompt(&promptOpts, promptItemValidation.Int32Validation)
 			} else if promptItemValidation.Int64Validation != nil {
-				val, err = Int64FromPrompt(promptItemValidation.PromptOpts, promptItemValidation.Int64Validation)
+				val, err = Int64FromPrompt(&promptOpts, promptItemValidation.Int64Validation)
 			} else if promptItemValidation.Float32Validation != nil {
-				val, err = Float32FromPrompt(promptItemValidation.PromptOpts, promptItemValidation.Float32Validation)
+				val, err = Float32FromPrompt(&promptOpts, promptItemValidation.Float32Validation)
 			} else if promptItemValidation.Float64Validation != nil {
-				val, err = Float64FromPrompt(promptItemValidation.PromptOpts, promptItemValidation.Float64Validation)
+				val, err = Float64FromPrompt(&promptOpts, promptItemValidation.Float64Validation)
 			} else {
 				errors.Panic("Undefined or unsupported validation type for ReadPrompt")
 			}
@@ -546,7 +708,10 @@ func ReadPrompt(dest interface{}, promptValidation *PromptValidation) error {
 			if err == nil {
 				break
 			}
-			fmt.Println(err.Error())
+			if !retryForever || errors.Cause(err) == ErrPromptTimeout || IsNonInteractive() {
+				return err
+			}
+			fmt.Fprintln(promptOpts.out(), err.Error())
 		}
 
 		err = setField(val, dest, promptItemValidation.StructField)
@@ -558,42 +723,228 @@ func ReadPrompt(dest interface{}, promptValidation *PromptValidation) error {
 	return nil
 }
 
+// DefaultPromptMaxAttempts is the number of times an invalid value is re-prompted for if MaxAttempts is not set
+const DefaultPromptMaxAttempts = 3
+
+// DefaultPromptEndSentinel ends the input to a MultiLine prompt when it's entered on its own line
+const DefaultPromptEndSentinel = "EOF"
+
 type PromptOptions struct {
-	Prompt        string
-	MaskDefault   bool
-	HideTyping    bool
-	MaskTyping    bool
-	TypingMaskVal string
-	defaultStr    string
+	Prompt                     string
+	MaskDefault                bool
+	HideTyping                 bool
+	MaskTyping                 bool
+	TypingMaskVal              string
+	MaxAttempts                int                                   // defaults to DefaultPromptMaxAttempts
+	Timeout                    time.Duration                         // if set, ErrPromptTimeout is returned if no value is entered in time
+	Ctx                        context.Context                       // if set, ErrPromptTimeout is returned if it's canceled before a value is entered
+	FallBackToDefaultOnTimeout bool                                  // treat a timeout as a missing value instead of returning ErrPromptTimeout
+	NonInteractiveHint         string                                // added to errors when running non-interactively, e.g. "set CORTEX_API_KEY or pass --api-key"
+	MultiLine                  bool                                  // read lines until EndSentinel or an EOF (e.g. ctrl-d); HideTyping and MaskTyping are ignored
+	EndSentinel                string                                // for MultiLine prompts, defaults to DefaultPromptEndSentinel
+	PreserveTrailingNewline    bool                                  // for MultiLine prompts, end the value with a newline
+	In                         io.Reader                             // defaults to stdin (if set, prompts are shown even when running non-interactively)
+	Out                        io.Writer                             // defaults to stdout
+	HideConstraints            bool                                  // don't add a hint like "(1–64)" to the prompt (see ConstraintHint())
+	RetryMessageFunc           func(err error, remaining int) string // the message shown before re-prompting, defaults to DefaultRetryMessage
+	PromptStyler               func(string) string                   // applied to the prompt text (e.g. to add colors); see SetPromptColors()
+	DefaultStyler              func(string) string                   // applied to the rendered default value
+	ErrorStyler                func(string) string                   // applied to the message shown before re-prompting
+	LineEditing                bool                                  // support arrow keys, backspace, etc. when stdin and stdout are terminals
+	KeepHistory                bool                                  // for LineEditing prompts, entries can be recalled by other prompts with KeepHistory set
+	SingleLine                 bool                                  // for list prompts, read a comma-separated list instead of one element per line
+	defaultStr                 string
+	constraintHint             string
+}
+
+// withHints returns a copy of opts with the constraint hint and the default which are shown with the prompt, so that the
+// caller's options aren't modified
+func (opts *PromptOptions) withHints(constraintHint string, defaultStr string) *PromptOptions {
+	optsCopy := *opts
+	optsCopy.constraintHint = constraintHint
+	optsCopy.defaultStr = defaultStr
+	return &optsCopy
+}
+
+// DefaultRetryMessage is the validation error followed by the number of remaining attempts
+func DefaultRetryMessage(err error, remaining int) string {
+	return err.Error() + "\n" + s.PromptAttemptsRemaining(remaining)
 }
 
-func prompt(opts *PromptOptions) string {
+func (opts *PromptOptions) out() io.Writer {
+	if opts.Out != nil {
+		return opts.Out
+	}
+	return ui.Writer
+}
+
+// styling is skipped if styler is nil, NO_COLOR is set, or the output isn't a terminal
+func (opts *PromptOptions) style(styler func(string) string, str string) string {
+	if styler == nil || !promptColorsEnabled(opts.out()) {
+		return str
+	}
+	return styler(str)
+}
+
+// ErrPromptTimeout is returned by the FromPrompt readers if PromptOptions.Timeout elapses or PromptOptions.Ctx is canceled
+var ErrPromptTimeout = errors.NewUserCoded(s.MsgPromptTimeout, s.ErrPromptTimeout)
+
+// errPromptAgain can be returned by a prompt's parse function to prompt again without using up an attempt
+var errPromptAgain = errors.New("prompt again")
+
+// promptWithRetries prompts until parse() accepts the value, printing each error, or until the attempts are exhausted.
+// When running non-interactively, the default is used as if it had been entered
+func promptWithRetries(opts *PromptOptions, parse func(string) error) error {
+	switch GetPromptPolicy() {
+	case PromptAcceptDefaults:
+		if err := parse(opts.defaultStr); err != nil {
+			return errors.Wrap(err, opts.Prompt)
+		}
+		return nil
+	case PromptFailIfNeeded:
+		return errors.NewUserCoded(s.MsgPromptNeeded, s.ErrPromptNeeded(opts.Prompt, opts.NonInteractiveHint))
+	}
+
+	if opts.In == nil && IsNonInteractive() {
+		if err := parse(opts.defaultStr); err != nil {
+			return errors.Wrap(err, s.ErrPromptNonInteractive(opts.NonInteractiveHint))
+		}
+		return nil
+	}
+
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultPromptMaxAttempts
+	}
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		valStr, promptErr := prompt(opts)
+		if promptErr != nil {
+			if promptErr == ErrPromptTimeout && opts.FallBackToDefaultOnTimeout {
+				fmt.Fprintln(opts.out())
+				return parse("")
+			}
+			return promptErr
+		}
+
+		err = parse(valStr)
+		if err == nil {
+			return nil
+		}
+		if err == errPromptAgain {
+			attempt--
+			continue
+		}
+		if attempt < maxAttempts {
+			retryMessageFunc := opts.RetryMessageFunc
+			if retryMessageFunc == nil {
+				retryMessageFunc = DefaultRetryMessage
+			}
+			fmt.Fprintln(opts.out(), opts.style(opts.ErrorStyler, retryMessageFunc(err, maxAttempts-attempt)))
+		}
+	}
+
+	if maxAttempts == 1 {
+		return err
+	}
+	return errors.WrapCoded(err, s.MsgPromptAttemptsExhausted, s.ErrPromptAttemptsExhausted(maxAttempts))
+}
+
+func prompt(opts *PromptOptions) (string, error) {
 	prompt := opts.Prompt
+	hidden := (opts.HideTyping || opts.MaskTyping) && !opts.MultiLine
 
-	if opts.defaultStr != "" {
+	if opts.constraintHint != "" && !opts.HideConstraints {
+		prompt = fmt.Sprintf("%s (%s)", prompt, opts.constraintHint)
+	}
+	prompt = opts.style(opts.PromptStyler, prompt)
+
+	// the default value of hidden prompts is only shown if it's masked
+	if opts.defaultStr != "" && (!hidden || opts.MaskDefault) {
 		defualtStr := opts.defaultStr
 		if opts.MaskDefault {
 			defualtStr = s.MaskString(defualtStr, 4)
 		}
-		prompt = fmt.Sprintf("%s [%s]", opts.Prompt, defualtStr)
+		if newlineIndex := strings.Index(defualtStr, "\n"); newlineIndex >= 0 {
+			defualtStr = defualtStr[:newlineIndex] + "…"
+		}
+		prompt = fmt.Sprintf("%s %s", prompt, opts.style(opts.DefaultStyler, "["+defualtStr+"]"))
 	}
 
-	val, err := ui.Ask(prompt, &input.Options{
-		Default:     opts.defaultStr,
-		Hide:        opts.HideTyping,
-		Mask:        opts.MaskTyping,
-		MaskVal:     opts.TypingMaskVal,
-		Required:    false,
-		HideDefault: true,
-		HideOrder:   true,
-		Loop:        false,
-	})
+	ctx := opts.Ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
 
-	if err != nil {
-		errors.Panic(err)
+	out := opts.out()
+
+	// once stdin has been read in the background, all prompts must read from there
+	useStdinReader := opts.Timeout > 0 || opts.Ctx != nil || stdin.isStarted()
+
+	var val string
+	var err error
+	if opts.MultiLine {
+		endSentinel := opts.EndSentinel
+		if endSentinel == "" {
+			endSentinel = DefaultPromptEndSentinel
+		}
+		fmt.Fprintf(out, "%s\n\nEnter a value (end with %s on its own line, or ctrl-d):\n", prompt, endSentinel)
+		readLine := func() (string, error) { return stdin.readLine(ctx) }
+		if opts.In != nil {
+			readLine = func() (string, error) { return readLineFromIn(ctx, opts.In) }
+		}
+		val, err = readLines(readLine, endSentinel, opts.PreserveTrailingNewline)
+	} else if opts.In != nil {
+		fmt.Fprintf(out, "%s\n\nEnter a value: ", prompt)
+		val, err = readLineFromIn(ctx, opts.In)
+	} else if hidden && isTerminal(os.Stdin) {
+		fmt.Fprintf(out, "%s\n\nEnter a value: ", prompt)
+		readByte := readStdinByte
+		if useStdinReader {
+			readByte = func() (byte, error) { return stdin.readByte(ctx) }
+		}
+		val, err = readHiddenLine(int(os.Stdin.Fd()), readByte, out, opts.MaskTyping, opts.TypingMaskVal)
+	} else if opts.LineEditing && isTerminal(os.Stdin) && isTerminalWriter(out) {
+		fmt.Fprintf(out, "%s\n\n", prompt)
+		readByte := readStdinByte
+		if useStdinReader {
+			readByte = func() (byte, error) { return stdin.readByte(ctx) }
+		}
+		val, err = readEditedLine(int(os.Stdin.Fd()), readByte, out, "Enter a value: ", opts.KeepHistory)
+	} else {
+		if hidden {
+			warnNonTerminal(os.Stderr)
+		}
+		if useStdinReader || opts.Out != nil {
+			fmt.Fprintf(out, "%s\n\nEnter a value: ", prompt)
+			val, err = stdin.readLine(ctx)
+		} else {
+			val, err = ui.Ask(prompt, &input.Options{
+				Default:     opts.defaultStr,
+				Required:    false,
+				HideDefault: true,
+				HideOrder:   true,
+				Loop:        false,
+			})
+		}
 	}
 
-	return val
+	if err != nil {
+		if err == ErrPromptTimeout {
+			return "", err
+		}
+		return "", errors.MarkInternal(errors.Wrap(err))
+	}
+	if val == "" {
+		return opts.defaultStr, nil
+	}
+	return val, nil
 }
 
 //
@@ -601,12 +952,111 @@ func prompt(opts *PromptOptions) string {
 //
 
 func ReadEnvVar(envVarName string) *string {
-	envVar, envVarIsSet := os.LookupEnv(envVarName)
-	if envVarIsSet {
-		return &envVar
-	} else {
-		return nil
+	return DefaultEnv().ReadEnvVar(envVarName)
+}
+
+//
+// File
+//
+
+// DefaultMaxFileBytes is the largest file the FromFile readers will read if MaxFileBytes is not set
+const DefaultMaxFileBytes = 1 << 20 // 1 MiB
+
+// readValueFile reads a file which holds a single value. nil is returned if the file does not exist
+func readValueFile(filePath string, maxFileBytes int64, allowNonRegularFiles bool, trimWhitespace bool) (*string, error) {
+	fileInfo, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fileError(err)
 	}
+
+	// checked before opening, since opening a pipe blocks until there is a writer
+	if !allowNonRegularFiles && !fileInfo.Mode().IsRegular() {
+		return nil, errors.NewUserCoded(s.MsgNotRegularFile, s.ErrNotRegularFile)
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fileError(err)
+	}
+	defer file.Close()
+
+	if maxFileBytes <= 0 {
+		maxFileBytes = DefaultMaxFileBytes
+	}
+	valBytes, err := ioutil.ReadAll(io.LimitReader(file, maxFileBytes+1))
+	if err != nil {
+		return nil, fileError(err)
+	}
+	if int64(len(valBytes)) > maxFileBytes {
+		return nil, errors.NewUserCoded(s.MsgFileTooLarge, s.ErrFileTooLarge(maxFileBytes))
+	}
+
+	valStr := string(valBytes)
+	if trimWhitespace {
+		valStr = strings.TrimSpace(valStr)
+	}
+	return &valStr, nil
+}
+
+// checkSingleLine returns an error if a file which should hold a single scalar value has more than one non-empty line
+func checkSingleLine(valStr string) error {
+	numLines := 0
+	for _, line := range strings.Split(valStr, "\n") {
+		if strings.TrimSpace(line) != "" {
+			numLines++
+		}
+	}
+	if numLines > 1 {
+		return errors.NewUserCoded(s.MsgMultipleLines, s.ErrMultipleLines)
+	}
+	return nil
+}
+
+// the path is dropped from *os.PathErrors, since callers wrap errors with the path
+func fileError(err error) error {
+	if pathErr, ok := err.(*os.PathError); ok {
+		return errors.MarkInternal(errors.New(pathErr.Err.Error()))
+	}
+	return errors.MarkInternal(errors.Wrap(err))
+}
+
+// ReadFileLine returns the requested line of a file (1-based, or negative to count from the end).
+// nil is returned if the file does not exist
+func ReadFileLine(filePath string, line int) (*string, error) {
+	return readFileLine(filePath, line, 0, false)
+}
+
+func readFileLine(filePath string, line int, maxFileBytes int64, allowNonRegularFiles bool) (*string, error) {
+	if line == 0 {
+		return nil, errors.NewUserCoded(s.MsgInvalidFileLine, s.ErrInvalidFileLine(line))
+	}
+
+	contents, err := readValueFile(filePath, maxFileBytes, allowNonRegularFiles, false)
+	if err != nil || contents == nil {
+		return nil, err
+	}
+
+	var lines []string
+	if trimmed := strings.TrimSuffix(*contents, "\n"); trimmed != "" {
+		lines = strings.Split(trimmed, "\n")
+	}
+
+	index := line - 1
+	if line < 0 {
+		index = len(lines) + line
+	}
+	if index < 0 || index >= len(lines) {
+		return nil, errors.NewUserCoded(s.MsgFileLineOutOfRange, s.ErrFileLineOutOfRange(line, len(lines)))
+	}
+
+	lineStr := strings.TrimSuffix(lines[index], "\r")
+	return &lineStr, nil
 }
 
 //
@@ -620,7 +1070,7 @@ func ReadYAMLBytes(yamlBytes []byte) (interface{}, error) {
 	var parsed interface{}
 	err := yaml.Unmarshal(yamlBytes, &parsed)
 	if err != nil {
-		return nil, errors.New(s.ErrUnmarshalYaml, s.CleanYAMLError(err))
+		return nil, errors.NewUserCoded(s.MsgUnmarshalYaml, s.ErrUnmarshalYaml, s.CleanYAMLError(err))
 	}
 	return parsed, nil
 }
@@ -634,7 +1084,7 @@ func ReadJSONBytes(jsonBytes []byte) (interface{}, error) {
 	d.UseNumber()
 	err := d.Decode(&parsed)
 	if err != nil {
-		return nil, errors.Wrap(err, s.ErrUnmarshalJson)
+		return nil, errors.MarkUser(errors.WrapCoded(err, s.MsgUnmarshalJson, s.ErrUnmarshalJson))
 	}
 	return parsed, nil
 }
@@ -642,7 +1092,7 @@ func ReadJSONBytes(jsonBytes []byte) (interface{}, error) {
 func MustReadYAMLStr(yamlStr string) interface{} {
 	parsed, err := ReadYAMLBytes([]byte(yamlStr))
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return parsed
 }
@@ -650,11 +1100,11 @@ func MustReadYAMLStr(yamlStr string) interface{} {
 func MustReadYAMLStrMap(yamlStr string) map[string]interface{} {
 	parsed, err := ReadYAMLBytes([]byte(yamlStr))
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	casted, ok := cast.InterfaceToStrInterfaceMap(parsed)
 	if !ok {
-		errors.Panic(s.ErrInvalidPrimitiveType(parsed, s.PrimTypeMap))
+		Fatal(errors.Wrap(&InvalidTypeError{Provided: parsed, Expected: []s.PrimitiveType{s.PrimTypeMap}}))
 	}
 	return casted
 }
@@ -662,7 +1112,7 @@ func MustReadYAMLStrMap(yamlStr string) map[string]interface{} {
 func MustReadJSONStr(jsonStr string) interface{} {
 	parsed, err := ReadJSONBytes([]byte(jsonStr))
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return parsed
 }
@@ -681,14 +1131,15 @@ func setField(val interface{}, destStruct interface{}, fieldName string) error {
 	if !v.IsValid() || !v.CanSet() {
 		util.Pp(val)
 		util.Pp(destStruct)
-		return errors.New(fieldName, s.ErrCannotSetStructField)
+		return errors.MarkInternal(errors.NewCoded(s.MsgCannotSetStructField, fieldName, s.ErrCannotSetStructField))
 	}
-	if !reflect.ValueOf(val).Type().AssignableTo(v.Type()) {
+	converted, ok := cast.Convert(val, v.Type()) // e.g. for fields with a type which has a registered converter
+	if !ok {
 		util.Pp(val)
 		util.Pp(destStruct)
-		return errors.New(fieldName, s.ErrCannotSetStructField)
+		return errors.MarkInternal(errors.NewCoded(s.MsgCannotSetStructField, fieldName, s.ErrCannotSetStructField))
 	}
-	v.Set(reflect.ValueOf(val))
+	v.Set(reflect.ValueOf(converted))
 	return nil
 }
 
@@ -698,7 +1149,7 @@ func setFirstField(val interface{}, destStruct interface{}) error {
 	if !v.IsValid() || !v.CanSet() {
 		util.Pp(val)
 		util.Pp(destStruct)
-		return errors.New("first field", s.ErrCannotSetStructField)
+		return errors.MarkInternal(errors.NewCoded(s.MsgCannotSetStructField, "first field", s.ErrCannotSetStructField))
 	}
 	v.Set(reflect.ValueOf(val))
 	return nil
@@ -709,7 +1160,7 @@ func setFieldNil(destStruct interface{}, fieldName string) error {
 	v := reflect.ValueOf(destStruct).Elem().FieldByName(fieldName)
 	if !v.IsValid() || !v.CanSet() {
 		util.Pp(destStruct)
-		return errors.New(fieldName, s.ErrCannotSetStructField)
+		return errors.MarkInternal(errors.NewCoded(s.MsgCannotSetStructField, fieldName, s.ErrCannotSetStructField))
 	}
 	v.Set(reflect.Zero(v.Type()))
 	return nil
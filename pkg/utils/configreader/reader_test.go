@@ -17,13 +17,24 @@ limitations under the License.
 package configreader_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/configreader/prompttest"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
 )
 
 type SimpleConfig struct {
@@ -818,6 +829,282 @@ func TestDefaultField(t *testing.T) {
 	testConfig(structValidation, configData, expected, t)
 }
 
+type TLSConfig struct {
+	Enabled  bool   `json:"enabled"`
+	CertPath string `json:"cert_path"`
+	KeyPath  string `json:"key_path"`
+}
+
+type ServerConfig struct {
+	TLS *TLSConfig `json:"tls"`
+}
+
+func TestPostValidate(t *testing.T) {
+	postValidateCalls := 0
+	tlsValidation := &cr.StructValidation{
+		StructFieldValidations: []*cr.StructFieldValidation{
+			&cr.StructFieldValidation{
+				StructField:    "Enabled",
+				BoolValidation: &cr.BoolValidation{},
+			},
+			&cr.StructFieldValidation{
+				StructField:      "CertPath",
+				StringValidation: &cr.StringValidation{AllowEmpty: true},
+			},
+			&cr.StructFieldValidation{
+				StructField:      "KeyPath",
+				StringValidation: &cr.StringValidation{AllowEmpty: true},
+			},
+		},
+		PostValidate: func(dest interface{}) error {
+			postValidateCalls++
+			tls := dest.(*TLSConfig)
+			if tls.Enabled && (tls.CertPath == "" || tls.KeyPath == "") {
+				return errors.New("cert_path and key_path must be specified when tls is enabled")
+			}
+			return nil
+		},
+	}
+	structValidation := &cr.StructValidation{
+		StructFieldValidations: []*cr.StructFieldValidation{
+			&cr.StructFieldValidation{
+				StructField:      "TLS",
+				StructValidation: tlsValidation,
+			},
+		},
+	}
+
+	configData := cr.MustReadYAMLStr(
+		`
+    tls:
+      enabled: true
+      cert_path: /certs/tls.crt
+      key_path: /certs/tls.key
+    `)
+	expected := &ServerConfig{
+		TLS: &TLSConfig{
+			Enabled:  true,
+			CertPath: "/certs/tls.crt",
+			KeyPath:  "/certs/tls.key",
+		},
+	}
+	testConfig(structValidation, configData, expected, t)
+	require.Equal(t, 1, postValidateCalls)
+
+	configData = cr.MustReadYAMLStr(
+		`
+    tls:
+      enabled: true
+      cert_path: /certs/tls.crt
+    `)
+	errs := cr.Struct(&ServerConfig{}, configData, structValidation)
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "tls: cert_path and key_path must be specified when tls is enabled")
+
+	// not called if any field has an error
+	postValidateCalls = 0
+	configData = cr.MustReadYAMLStr(
+		`
+    tls:
+      enabled: 1
+    `)
+	errs = cr.Struct(&ServerConfig{}, configData, structValidation)
+	require.Len(t, errs, 1)
+	require.Equal(t, 0, postValidateCalls)
+}
+
+func TestFromFileLine(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	filePath := filepath.Join(tmpDir, "status")
+	err = ioutil.WriteFile(filePath, []byte("v1.2.3\r\n8080\nready\n"), 0644)
+	require.NoError(t, err)
+
+	str, err := cr.StringFromFileLine(filePath, 1, &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "v1.2.3", str)
+
+	str, err = cr.StringFromFileLine(filePath, -1, &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "ready", str)
+
+	num, err := cr.IntFromFileLine(filePath, 2, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 8080, num)
+
+	num, err = cr.IntFromFileLine(filePath, -2, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 8080, num)
+
+	_, err = cr.IntFromFileLine(filePath, 4, &cr.IntValidation{})
+	require.EqualError(t, err, filePath+": line 4 is out of range (file has 3 lines)")
+
+	_, err = cr.IntFromFileLine(filePath, -4, &cr.IntValidation{})
+	require.Error(t, err)
+
+	_, err = cr.IntFromFileLine(filePath, 0, &cr.IntValidation{})
+	require.Error(t, err)
+
+	num, err = cr.IntFromFileLine(filepath.Join(tmpDir, "missing"), 1, &cr.IntValidation{Default: 7})
+	require.NoError(t, err)
+	require.Equal(t, 7, num)
+
+	_, err = cr.IntFromFileLine(filepath.Join(tmpDir, "missing"), 1, &cr.IntValidation{Required: true})
+	require.Error(t, err)
+}
+
+func TestFromFileWhitespace(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	writeFile := func(name string, contents string) string {
+		filePath := filepath.Join(tmpDir, name)
+		err := ioutil.WriteFile(filePath, []byte(contents), 0644)
+		require.NoError(t, err)
+		return filePath
+	}
+
+	echoFile := writeFile("echo", "8080\n")   // echo 8080 > echo
+	printfFile := writeFile("printf", "8080") // printf 8080 > printf
+	crlfFile := writeFile("crlf", "8080\r\n") // written on windows
+	paddedFile := writeFile("padded", " 8080\t\n")
+	blankFile := writeFile("blank", "\n")
+
+	for _, filePath := range []string{echoFile, printfFile, crlfFile, paddedFile} {
+		num, err := cr.IntFromFile(filePath, &cr.IntValidation{})
+		require.NoError(t, err)
+		require.Equal(t, 8080, num)
+
+		num64, err := cr.Float64FromFile(filePath, &cr.Float64Validation{})
+		require.NoError(t, err)
+		require.Equal(t, float64(8080), num64)
+
+		str, err := cr.StringFromFile(filePath, &cr.StringValidation{})
+		require.NoError(t, err)
+		require.Equal(t, "8080", str)
+	}
+
+	boolVal, err := cr.BoolFromFile(writeFile("bool", "true\r\n"), &cr.BoolValidation{})
+	require.NoError(t, err)
+	require.Equal(t, true, boolVal)
+
+	num, err := cr.IntFromFile(blankFile, &cr.IntValidation{Default: 7})
+	require.NoError(t, err)
+	require.Equal(t, 7, num)
+
+	_, err = cr.IntFromFile(blankFile, &cr.IntValidation{Required: true})
+	require.Error(t, err)
+
+	str, err := cr.StringFromFile(paddedFile, &cr.StringValidation{PreserveWhitespace: true})
+	require.NoError(t, err)
+	require.Equal(t, " 8080\t\n", str)
+
+	strPtr, err := cr.StringPtrFromFile(crlfFile, &cr.StringPtrValidation{PreserveWhitespace: true})
+	require.NoError(t, err)
+	require.Equal(t, "8080\r\n", *strPtr)
+
+	_, err = cr.IntFromFile(echoFile, &cr.IntValidation{PreserveWhitespace: true})
+	require.EqualError(t, err, echoFile+":1: "+s.ErrInvalidPrimitiveType("8080\n", s.PrimTypeInt))
+
+	num, err = cr.IntFromFile(printfFile, &cr.IntValidation{PreserveWhitespace: true})
+	require.NoError(t, err)
+	require.Equal(t, 8080, num)
+
+	_, err = cr.BoolFromFile(writeFile("bool_padded", " true"), &cr.BoolValidation{PreserveWhitespace: true})
+	require.Error(t, err)
+}
+
+func TestFromFileMultipleLines(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	writeFile := func(name string, contents string) string {
+		filePath := filepath.Join(tmpDir, name)
+		err := ioutil.WriteFile(filePath, []byte(contents), 0644)
+		require.NoError(t, err)
+		return filePath
+	}
+
+	multiLineFile := writeFile("multi", "8080\n8081\n")
+	blankLinesFile := writeFile("blank_lines", "\n8080\n\n")
+
+	_, err = cr.IntFromFile(multiLineFile, &cr.IntValidation{})
+	require.EqualError(t, err, multiLineFile+": "+s.ErrMultipleLines)
+
+	_, err = cr.Int64FromFile(multiLineFile, &cr.Int64Validation{})
+	require.EqualError(t, err, multiLineFile+": "+s.ErrMultipleLines)
+
+	_, err = cr.Float64FromFile(multiLineFile, &cr.Float64Validation{})
+	require.EqualError(t, err, multiLineFile+": "+s.ErrMultipleLines)
+
+	_, err = cr.Float32PtrFromFile(multiLineFile, &cr.Float32PtrValidation{})
+	require.EqualError(t, err, multiLineFile+": "+s.ErrMultipleLines)
+
+	_, err = cr.BoolFromFile(writeFile("bools", "true\nfalse"), &cr.BoolValidation{})
+	require.EqualError(t, err, filepath.Join(tmpDir, "bools")+": "+s.ErrMultipleLines)
+
+	_, _, err = cr.IntFromFileWithRaw(multiLineFile, &cr.IntValidation{})
+	require.EqualError(t, err, multiLineFile+": "+s.ErrMultipleLines)
+
+	num, err := cr.IntFromFile(blankLinesFile, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 8080, num)
+
+	// strings can span multiple lines (e.g. certificates)
+	str, err := cr.StringFromFile(multiLineFile, &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "8080\n8081", str)
+}
+
+func TestFromFileErrors(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	filePath := filepath.Join(tmpDir, "port")
+	err = ioutil.WriteFile(filePath, []byte("8080\n"), 0644)
+	require.NoError(t, err)
+
+	num, err := cr.IntFromFile(filePath, &cr.IntValidation{MaxFileBytes: 5})
+	require.NoError(t, err)
+	require.Equal(t, 8080, num)
+
+	_, err = cr.IntFromFile(filePath, &cr.IntValidation{MaxFileBytes: 4})
+	require.EqualError(t, err, filePath+": "+s.ErrFileTooLarge(4))
+
+	_, err = cr.StringFromFileLine(filePath, 1, &cr.StringValidation{MaxFileBytes: 4})
+	require.EqualError(t, err, filePath+": "+s.ErrFileTooLarge(4))
+
+	// directories are not regular files, and are not treated as missing
+	_, err = cr.IntFromFile(tmpDir, &cr.IntValidation{Default: 7})
+	require.EqualError(t, err, tmpDir+": "+s.ErrNotRegularFile)
+
+	_, err = cr.StringFromFile(tmpDir, &cr.StringValidation{AllowNonRegularFiles: true})
+	require.Error(t, err)
+
+	if _, err := os.Stat(os.DevNull); err == nil {
+		str, err := cr.StringFromFile(os.DevNull, &cr.StringValidation{AllowNonRegularFiles: true, AllowEmpty: true})
+		require.NoError(t, err)
+		require.Equal(t, "", str)
+	}
+
+	// file permissions are not enforced for root
+	if os.Geteuid() > 0 {
+		err = os.Chmod(filePath, 0000)
+		require.NoError(t, err)
+		_, err = cr.IntFromFile(filePath, &cr.IntValidation{Default: 7})
+		require.EqualError(t, err, filePath+": permission denied")
+	}
+
+	num, err = cr.IntFromFile(filepath.Join(tmpDir, "missing"), &cr.IntValidation{Default: 7})
+	require.NoError(t, err)
+	require.Equal(t, 7, num)
+}
+
 func testConfig(structValidation *cr.StructValidation, configData interface{}, expected interface{}, t *testing.T) {
 	config := reflect.New(reflect.TypeOf(expected).Elem()).Interface()
 
@@ -832,3 +1119,2194 @@ func testConfig(structValidation *cr.StructValidation, configData interface{}, e
 
 	require.Equal(t, expected, config)
 }
+
+func TestEnvVarNameValidation(t *testing.T) {
+	v := cr.GetEnvVarNameValidation(&cr.EnvVarNameValidation{
+		ReservedPrefixes: []string{"CORTEX_"},
+	})
+
+	for _, name := range []string{"PATH", "_private", "my_var2", "a"} {
+		val, err := cr.StringFromStr(name, v)
+		require.NoError(t, err)
+		require.Equal(t, name, val)
+	}
+
+	for _, name := range []string{"2FAST", "1", "MY-VAR", "-x", "my var", "my.var", "VAR="} {
+		_, err := cr.StringFromStr(name, v)
+		require.EqualError(t, err, s.ErrInvalidEnvVarName(name))
+	}
+
+	_, err := cr.StringFromStr("CORTEX_VERSION", v)
+	require.EqualError(t, err, s.ErrReservedEnvVarPrefix("CORTEX_VERSION", "CORTEX_"))
+
+	val, err := cr.StringFromStr("CORTEX_VERSION", cr.GetEnvVarNameValidation(&cr.EnvVarNameValidation{}))
+	require.NoError(t, err)
+	require.Equal(t, "CORTEX_VERSION", val)
+
+	_, err = cr.StringFromStr("", v)
+	require.Error(t, err)
+}
+
+func TestPresets(t *testing.T) {
+	port, err := cr.IntFromStr("8080", cr.Presets.Port())
+	require.NoError(t, err)
+	require.Equal(t, 8080, port)
+
+	_, err = cr.IntFromStr("0", cr.Presets.Port())
+	require.Error(t, err)
+
+	_, err = cr.IntFromStr("65536", cr.Presets.Port())
+	require.Error(t, err)
+
+	percentage, err := cr.Float64FromStr("12.5", cr.Presets.Percentage())
+	require.NoError(t, err)
+	require.Equal(t, 12.5, percentage)
+
+	_, err = cr.Float64FromStr("100.1", cr.Presets.Percentage())
+	require.Error(t, err)
+
+	name, err := cr.StringFromStr("my-api", cr.Presets.K8sName())
+	require.NoError(t, err)
+	require.Equal(t, "my-api", name)
+
+	_, err = cr.StringFromStr("My_API", cr.Presets.K8sName())
+	require.Error(t, err)
+
+	longName := strings.Repeat("a", 64)
+	_, err = cr.StringFromStr(longName, cr.Presets.K8sName())
+	require.EqualError(t, err, s.ErrStrTooLong(longName, 63))
+
+	// the length limit still applies when the caller adds a Validator
+	withValidator := cr.Presets.K8sName()
+	withValidator.Validator = func(val string) (string, error) {
+		return val, nil
+	}
+	_, err = cr.StringFromStr(longName, withValidator)
+	require.EqualError(t, err, s.ErrStrTooLong(longName, 63))
+
+	// presets are independent of each other
+	withDefault := cr.Presets.Port()
+	withDefault.Default = 8888
+	port, err = cr.ValidateIntMissing(withDefault)
+	require.NoError(t, err)
+	require.Equal(t, 8888, port)
+	_, err = cr.ValidateIntMissing(cr.Presets.Port())
+	require.Error(t, err)
+}
+
+// cases from kubernetes' TestIsDNS1123Label and TestIsDNS1123Subdomain
+func TestK8sLabelAndSubdomain(t *testing.T) {
+	for _, val := range []string{"a", "ab", "abc", "a1", "a-1", "a--1--2--b", "0", "01", "012", "1a", "1-a", "1--a--b--2", strings.Repeat("a", 63)} {
+		_, err := cr.StringFromStr(val, cr.Presets.K8sLabel())
+		require.NoError(t, err, val)
+		_, err = cr.StringFromStr(val, cr.Presets.K8sSubdomain())
+		require.NoError(t, err, val)
+	}
+
+	for _, val := range []string{"a.a", "ab.ab", "0.0", "a.b.c", "a-1.b-2", "1-a.2-b", strings.Repeat("a", 63) + "." + strings.Repeat("b", 64), strings.Repeat("a", 253)} {
+		_, err := cr.StringFromStr(val, cr.Presets.K8sSubdomain())
+		require.NoError(t, err, val)
+	}
+
+	for val, expected := range map[string]string{
+		"A":                     s.ErrDNS1123Chars("A", false),
+		"a_b":                   s.ErrDNS1123Chars("a_b", false),
+		"a.b":                   s.ErrDNS1123Chars("a.b", false),
+		"a@b":                   s.ErrDNS1123Chars("a@b", false),
+		"-a":                    s.ErrDNS1123Boundary("-a"),
+		"a-":                    s.ErrDNS1123Boundary("a-"),
+		strings.Repeat("a", 64): s.ErrStrTooLong(strings.Repeat("a", 64), 63),
+	} {
+		_, err := cr.StringFromStr(val, cr.Presets.K8sLabel())
+		require.EqualError(t, err, expected, val)
+	}
+
+	for val, expected := range map[string]string{
+		"A.a":                    s.ErrDNS1123Chars("A.a", true),
+		"a_b":                    s.ErrDNS1123Chars("a_b", true),
+		"-a":                     s.ErrDNS1123Boundary("-a"),
+		"a..b":                   s.ErrDNS1123SubdomainParts("a..b"),
+		".a":                     s.ErrDNS1123SubdomainParts(".a"),
+		"a.":                     s.ErrDNS1123SubdomainParts("a."),
+		"a-.b":                   s.ErrDNS1123SubdomainParts("a-.b"),
+		"a.-b":                   s.ErrDNS1123SubdomainParts("a.-b"),
+		strings.Repeat("a", 254): s.ErrStrTooLong(strings.Repeat("a", 254), 253),
+	} {
+		_, err := cr.StringFromStr(val, cr.Presets.K8sSubdomain())
+		require.EqualError(t, err, expected, val)
+	}
+
+	_, err := cr.StringFromStr("A", &cr.StringValidation{Dns1123Label: true, Sensitive: true})
+	require.EqualError(t, err, s.ErrDNS1123Chars(s.Redacted("A"), false))
+}
+
+func TestFromRef(t *testing.T) {
+	os.Setenv("CR_TEST_REF_WORKERS", "4")
+	defer os.Unsetenv("CR_TEST_REF_WORKERS")
+
+	num, err := cr.IntFromRef("env://CR_TEST_REF_WORKERS", &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 4, num)
+
+	num, err = cr.IntFromRef("literal://8", &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 8, num)
+
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+	filePath := filepath.Join(tmpDir, "workers")
+	err = ioutil.WriteFile(filePath, []byte("16\n"), 0644)
+	require.NoError(t, err)
+
+	num, err = cr.IntFromRef("file://"+filePath, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 16, num)
+
+	num, err = cr.IntFromRef("env://CR_TEST_REF_MISSING", &cr.IntValidation{Default: 2})
+	require.NoError(t, err)
+	require.Equal(t, 2, num)
+
+	_, err = cr.IntFromRef("literal://abc", &cr.IntValidation{})
+	require.EqualError(t, err, "literal://abc: "+s.ErrInvalidPrimitiveType("abc", s.PrimTypeInt))
+
+	err = cr.RegisterSourceScheme("crtest", func(ref string) (*string, error) {
+		switch ref {
+		case "app/max_workers":
+			val := "32"
+			return &val, nil
+		case "app/broken":
+			return nil, errors.New("connection refused")
+		}
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	num, err = cr.IntFromRef("crtest://app/max_workers", &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 32, num)
+
+	_, err = cr.StringFromRef("crtest://app/broken", &cr.StringValidation{})
+	require.EqualError(t, err, "crtest://app/broken: connection refused")
+
+	_, err = cr.StringFromRef("crtest://app/name", &cr.StringValidation{Required: true})
+	require.EqualError(t, err, "crtest://app/name: "+s.ErrMustBeDefined)
+
+	err = cr.RegisterSourceScheme("crtest", func(ref string) (*string, error) { return nil, nil })
+	require.EqualError(t, err, s.ErrDuplicateSourceScheme("crtest"))
+
+	err = cr.RegisterSourceScheme("env", func(ref string) (*string, error) { return nil, nil })
+	require.Error(t, err)
+
+	_, err = cr.IntFromRef("unknown://abc", &cr.IntValidation{})
+	require.EqualError(t, err, "unknown://abc: "+s.ErrUnknownSourceScheme("unknown", []string{"crtest", "env", "file", "literal"}))
+
+	_, err = cr.IntFromRef("abc", &cr.IntValidation{})
+	require.EqualError(t, err, "abc: "+s.ErrInvalidSourceRef("abc"))
+}
+
+func TestFromArg(t *testing.T) {
+	args := []string{"my-api", "3", "0.5", "a", "b"}
+
+	name, err := cr.StringFromArg(args, 0, "deployment name", cr.Presets.K8sName())
+	require.NoError(t, err)
+	require.Equal(t, "my-api", name)
+
+	replicas, err := cr.IntFromArg(args, 1, "replicas", &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 3, replicas)
+
+	require.Equal(t, 0.5, cr.MustFloat64FromArg(args, 2, "ratio", &cr.Float64Validation{}))
+
+	_, err = cr.StringFromArg([]string{"My_API"}, 0, "deployment name", cr.Presets.K8sName())
+	require.EqualError(t, err, "deployment name: "+s.ErrDNS1035("My_API"))
+
+	_, err = cr.IntFromArg(args, 0, "replicas", &cr.IntValidation{})
+	require.EqualError(t, err, "replicas: "+s.ErrInvalidPrimitiveType("my-api", s.PrimTypeInt))
+
+	_, err = cr.StringFromArg(args, 10, "deployment name", &cr.StringValidation{Required: true})
+	require.EqualError(t, err, "deployment name: "+s.ErrMustBeDefined)
+
+	replicas, err = cr.IntFromArg(args, 10, "replicas", &cr.IntValidation{Default: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, replicas)
+
+	remaining, err := cr.RemainingArgsAsStringList(args, 3, &cr.StringListValidation{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, remaining)
+
+	remaining, err = cr.RemainingArgsAsStringList(args, 5, &cr.StringListValidation{Default: []string{}, AllowEmpty: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{}, remaining)
+
+	_, err = cr.RemainingArgsAsStringList(args, 5, &cr.StringListValidation{Required: true})
+	require.EqualError(t, err, s.ErrMustBeDefined)
+}
+
+func TestWithRaw(t *testing.T) {
+	num, raw, err := cr.IntFromStrWithRaw("8080", &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 8080, num)
+	require.Equal(t, "8080", raw)
+
+	_, raw, err = cr.IntFromStrWithRaw("80.5", &cr.IntValidation{})
+	require.Error(t, err)
+	require.Equal(t, "80.5", raw)
+
+	os.Setenv("CR_TEST_RAW", "0.25")
+	defer os.Unsetenv("CR_TEST_RAW")
+	ratio, raw, err := cr.Float64FromEnvWithRaw("CR_TEST_RAW", &cr.Float64Validation{})
+	require.NoError(t, err)
+	require.Equal(t, 0.25, ratio)
+	require.Equal(t, "0.25", raw)
+
+	num, raw, err = cr.IntFromEnvWithRaw("CR_TEST_RAW_MISSING", &cr.IntValidation{Default: 3})
+	require.NoError(t, err)
+	require.Equal(t, 3, num)
+	require.Equal(t, "", raw)
+
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+	filePath := filepath.Join(tmpDir, "port")
+	err = ioutil.WriteFile(filePath, []byte(" 8080\r\n"), 0644)
+	require.NoError(t, err)
+
+	num, raw, err = cr.IntFromFileWithRaw(filePath, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 8080, num)
+	require.Equal(t, " 8080\r\n", raw)
+
+	str, raw, err := cr.StringFromFileWithRaw(filePath, &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "8080", str)
+	require.Equal(t, " 8080\r\n", raw)
+
+	iMap := map[string]interface{}{"replicas": 2, "debug": true}
+	num, raw, err = cr.IntFromInterfaceMapWithRaw("replicas", iMap, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 2, num)
+	require.Equal(t, "2", raw)
+
+	debug, raw, err := cr.BoolFromInterfaceMapWithRaw("debug", iMap, &cr.BoolValidation{})
+	require.NoError(t, err)
+	require.Equal(t, true, debug)
+	require.Equal(t, "true", raw)
+
+	_, raw, err = cr.StringFromInterfaceMapWithRaw("replicas", iMap, &cr.StringValidation{})
+	require.Error(t, err)
+	require.Equal(t, "2", raw)
+
+	_, raw, err = cr.IntFromInterfaceMapWithRaw("missing", iMap, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "", raw)
+}
+
+func TestSensitive(t *testing.T) {
+	v := &cr.StringValidation{
+		Prefix:    "sk-",
+		Sensitive: true,
+	}
+
+	_, err := cr.StringFromStr("secret-key-123", v)
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "secret-key-123")
+	require.EqualError(t, err, s.ErrMustHavePrefix(s.Redacted("secret-key-123"), "sk-"))
+
+	_, err = cr.StringFromInterfaceMap("api_key", map[string]interface{}{"api_key": 123456}, v)
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "123456")
+
+	_, err = cr.StringPtrFromStr("secret", &cr.StringPtrValidation{AllowedValues: []string{"a"}, Sensitive: true})
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "secret")
+
+	// numbers (e.g. PINs) can be sensitive too
+	const secretNum = "731942"
+	var errs []error
+	_, err = cr.IntFromStr(secretNum, &cr.IntValidation{LessThan: util.IntPtr(1000), Sensitive: true})
+	require.EqualError(t, err, s.ErrMustBeLessThan(s.Redacted(731942), 1000))
+	errs = append(errs, err)
+	_, err = cr.IntFromStr(secretNum+"x", &cr.IntValidation{Sensitive: true})
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType("<redacted (len 7)>", s.PrimTypeInt))
+	errs = append(errs, err)
+	_, err = cr.Int64FromStr(secretNum, &cr.Int64Validation{MustBePowerOfTwo: true, Sensitive: true})
+	errs = append(errs, err)
+	_, err = cr.Int32PtrFromStr(secretNum, &cr.Int32PtrValidation{AllowedValues: []int32{1, 2}, Sensitive: true})
+	errs = append(errs, err)
+	_, err = cr.Float64FromInterfaceMap("pin", map[string]interface{}{"pin": 731942}, &cr.Float64Validation{GreaterThan: util.Float64Ptr(1e6), Sensitive: true})
+	errs = append(errs, err)
+	_, err = cr.Float32FromStr(secretNum+"e40", &cr.Float32Validation{Sensitive: true})
+	errs = append(errs, err)
+	_, err = cr.BoolFromStr(secretNum, &cr.BoolValidation{AllowNumeric: true, Sensitive: true})
+	errs = append(errs, err)
+	for _, err := range errs {
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), secretNum)
+		errJSON, jsonErr := cr.ErrorToJSON(err)
+		require.NoError(t, jsonErr)
+		require.NotContains(t, string(errJSON), secretNum)
+	}
+
+	report := &cr.Report{}
+	_, err = cr.IntFromStr(secretNum, &cr.IntValidation{Sensitive: true, Report: report})
+	require.NoError(t, err)
+	_, err = cr.IntFromInterfaceMap("pin", map[string]interface{}{"pin": 731942}, &cr.IntValidation{Sensitive: true, Report: report})
+	require.NoError(t, err)
+	entry, ok := report.Entry("pin")
+	require.True(t, ok)
+	require.Equal(t, s.Redacted(731942), entry.Value)
+
+	val, err := cr.StringFromStr("sk-123", v)
+	require.NoError(t, err)
+	require.Equal(t, "sk-123", val)
+}
+
+func TestFloat32Range(t *testing.T) {
+	v := &cr.Float32Validation{}
+
+	val, err := cr.Float32FromStr("3.4028235e38", v)
+	require.NoError(t, err)
+	require.Equal(t, float32(math.MaxFloat32), val)
+
+	val, err = cr.Float32FromStr("-3.4028235e38", v)
+	require.NoError(t, err)
+	require.Equal(t, float32(-math.MaxFloat32), val)
+
+	_, err = cr.Float32FromStr("3.5e38", v)
+	require.EqualError(t, err, s.ErrFloat32OutOfRange("3.5e38"))
+
+	_, err = cr.Float32FromStr("-1e39", v)
+	require.EqualError(t, err, s.ErrFloat32OutOfRange("-1e39"))
+
+	val, err = cr.Float32(float64(math.MaxFloat32), v)
+	require.NoError(t, err)
+	require.Equal(t, float32(math.MaxFloat32), val)
+
+	_, err = cr.Float32(float64(3.5e38), v)
+	require.EqualError(t, err, s.ErrFloat32OutOfRange(float64(3.5e38)))
+
+	_, err = cr.Float32FromInterfaceMap("scale", map[string]interface{}{"scale": 1e39}, v)
+	require.EqualError(t, err, "scale: "+s.ErrFloat32OutOfRange(1e39))
+
+	_, err = cr.Float32Ptr(float64(-3.5e38), &cr.Float32PtrValidation{})
+	require.EqualError(t, err, s.ErrFloat32OutOfRange(float64(-3.5e38)))
+
+	_, err = cr.Float32PtrFromStr("3.5e38", &cr.Float32PtrValidation{})
+	require.EqualError(t, err, s.ErrFloat32OutOfRange("3.5e38"))
+
+	_, err = cr.Float32List([]interface{}{1.5, 3.5e38}, &cr.Float32ListValidation{})
+	require.Error(t, err)
+
+	// values beyond float32's 24 bits of precision are rounded, and the warning is opt-in
+	val, err = cr.Float32FromStr("16777217", &cr.Float32Validation{WarnPrecisionLoss: true})
+	require.NoError(t, err)
+	require.Equal(t, float32(16777216), val)
+
+	val, err = cr.Float32(0.1, &cr.Float32Validation{WarnPrecisionLoss: true})
+	require.NoError(t, err)
+	require.Equal(t, float32(0.1), val)
+
+	// the warning is added to the report
+	report := &cr.Report{}
+	_, err = cr.Float32FromInterfaceMap("scale", map[string]interface{}{"scale": 16777217.0}, &cr.Float32Validation{WarnPrecisionLoss: true, Report: report})
+	require.NoError(t, err)
+	entry, ok := report.Entry("scale")
+	require.True(t, ok)
+	require.Equal(t, []string{s.WarnFloat32PrecisionLoss(16777217.0, float32(16777216))}, entry.Warnings)
+}
+
+func TestInterfaceListElementValidator(t *testing.T) {
+	tolerationValidator := func(elem interface{}) (interface{}, error) {
+		switch casted := elem.(type) {
+		case string:
+			return map[string]interface{}{"key": casted}, nil
+		case map[interface{}]interface{}:
+			return cr.InterfaceMap(casted, &cr.InterfaceMapValidation{})
+		}
+		return nil, errors.New(s.ErrInvalidPrimitiveType(elem, s.PrimTypeString, s.PrimTypeMap))
+	}
+
+	v := &cr.InterfaceListValidation{
+		Required:         true,
+		MinLength:        1,
+		MaxLength:        3,
+		ElementValidator: tolerationValidator,
+	}
+
+	configData := cr.MustReadYAMLStrMap(
+		`
+    tolerations:
+      - gpu
+      - key: spot
+        effect: NoSchedule
+    `)
+	val, err := cr.InterfaceListFromInterfaceMap("tolerations", configData, v)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"key": "gpu"},
+		map[string]interface{}{"key": "spot", "effect": "NoSchedule"},
+	}, val)
+
+	_, err = cr.InterfaceList([]interface{}{"gpu", 2}, v)
+	require.EqualError(t, err, "index 1: "+s.ErrInvalidPrimitiveType(2, s.PrimTypeString, s.PrimTypeMap))
+
+	_, err = cr.InterfaceList([]interface{}{"a", "b", "c", "d"}, v)
+	require.EqualError(t, err, s.ErrTooManyElements(4, 3))
+
+	_, err = cr.InterfaceList([]interface{}{"a"}, &cr.InterfaceListValidation{MinLength: 2})
+	require.EqualError(t, err, s.ErrTooFewElements(1, 2))
+
+	_, err = cr.InterfaceList("gpu", v)
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType("gpu", s.PrimTypeList))
+
+	v.CoerceToList = true
+	val, err = cr.InterfaceList("gpu", v)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{map[string]interface{}{"key": "gpu"}}, val)
+
+	_, err = cr.InterfaceListFromInterfaceMap("tolerations", map[string]interface{}{}, v)
+	require.EqualError(t, err, "tolerations: "+s.ErrMustBeDefined)
+}
+
+func TestIntFromInterfaceMapWithFallback(t *testing.T) {
+	v := &cr.IntValidation{
+		Required:    true,
+		GreaterThan: util.IntPtr(0),
+	}
+
+	val, err := cr.IntFromInterfaceMapWithFallback("workers", map[string]interface{}{}, v, util.IntPtr(4))
+	require.NoError(t, err)
+	require.Equal(t, 4, val)
+
+	val, err = cr.IntFromInterfaceMapWithFallback("workers", map[string]interface{}{"workers": 2}, v, util.IntPtr(4))
+	require.NoError(t, err)
+	require.Equal(t, 2, val)
+
+	_, err = cr.IntFromInterfaceMapWithFallback("workers", map[string]interface{}{}, v, nil)
+	require.EqualError(t, err, "workers: "+s.ErrMustBeDefined)
+
+	_, err = cr.IntFromInterfaceMapWithFallback("workers", map[string]interface{}{}, v, util.IntPtr(0))
+	require.EqualError(t, err, "workers: "+s.ErrMustBeGreaterThan(0, 0))
+
+	val, err = cr.IntFromInterfaceMapWithFallback("workers", map[string]interface{}{}, &cr.IntValidation{Default: 1}, util.IntPtr(4))
+	require.NoError(t, err)
+	require.Equal(t, 4, val)
+}
+
+func TestURLEncodedString(t *testing.T) {
+	val, err := cr.StringFromStr("a%20b%2Fc", &cr.StringValidation{RequireURLEncoded: true})
+	require.NoError(t, err)
+	require.Equal(t, "a%20b%2Fc", val)
+
+	val, err = cr.StringFromStr("a+b%2Fc", &cr.StringValidation{AutoDecode: true})
+	require.NoError(t, err)
+	require.Equal(t, "a b/c", val)
+
+	val, err = cr.StringFromStr("a+b%2Fc", &cr.StringValidation{AutoDecode: true, URLPathEncoding: true})
+	require.NoError(t, err)
+	require.Equal(t, "a+b/c", val)
+
+	_, err = cr.StringFromStr("100%", &cr.StringValidation{RequireURLEncoded: true})
+	require.EqualError(t, err, s.ErrInvalidURLEscape("100%", "%"))
+
+	_, err = cr.StringFromStr("a%zzb", &cr.StringValidation{AutoDecode: true})
+	require.EqualError(t, err, s.ErrInvalidURLEscape("a%zzb", "%zz"))
+
+	_, err = cr.StringFromStr("a%zzb", &cr.StringValidation{AutoDecode: true, Sensitive: true})
+	require.EqualError(t, err, s.ErrInvalidURLEncoding(s.Redacted("a%zzb")))
+
+	val, err = cr.StringFromStr("a b/c?d", &cr.StringValidation{Encode: true})
+	require.NoError(t, err)
+	require.Equal(t, "a+b%2Fc%3Fd", val)
+
+	val, err = cr.StringFromStr("a b/c?d", &cr.StringValidation{Encode: true, URLPathEncoding: true})
+	require.NoError(t, err)
+	require.Equal(t, "a%20b%2Fc%3Fd", val)
+}
+
+func TestFloat64ListSumTo(t *testing.T) {
+	v := &cr.Float64ListValidation{SumTo: util.Float64Ptr(1)}
+
+	weights, err := cr.Float64List([]interface{}{0.1, 0.2, 0.7}, v)
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.1, 0.2, 0.7}, weights)
+
+	_, err = cr.Float64List([]interface{}{0.5, 0.75}, v)
+	require.EqualError(t, err, s.ErrWrongSum(1.25, 1))
+
+	_, err = cr.Float64List([]interface{}{0.5, 0.495}, &cr.Float64ListValidation{SumTo: util.Float64Ptr(1), SumTolerance: 0.01})
+	require.NoError(t, err)
+
+	input := []float64{1, 1, 2}
+	weights, err = cr.ValidateFloat64List(input, &cr.Float64ListValidation{SumTo: util.Float64Ptr(1), Normalize: true})
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.25, 0.25, 0.5}, weights)
+	require.Equal(t, []float64{1, 1, 2}, input)
+
+	_, err = cr.Float64List([]interface{}{0, 0}, &cr.Float64ListValidation{SumTo: util.Float64Ptr(1), Normalize: true})
+	require.EqualError(t, err, s.ErrCannotNormalizeZeroSum(1))
+}
+
+func TestValidateOneTrue(t *testing.T) {
+	keys := []string{"s3", "gcs", "local"}
+
+	require.NoError(t, cr.ValidateAtMostOneTrue(map[string]interface{}{}, keys...))
+	require.NoError(t, cr.ValidateAtMostOneTrue(map[string]interface{}{"s3": true, "gcs": false}, keys...))
+	err := cr.ValidateAtMostOneTrue(map[string]interface{}{"s3": true, "gcs": false, "local": true}, keys...)
+	require.EqualError(t, err, s.ErrAtMostOneTrue(keys, []string{"s3", "local"}))
+
+	require.NoError(t, cr.ValidateExactlyOneTrue(map[string]interface{}{"local": true}, keys...))
+	err = cr.ValidateExactlyOneTrue(map[string]interface{}{"s3": false}, keys...)
+	require.EqualError(t, err, s.ErrExactlyOneTrue(keys))
+	err = cr.ValidateExactlyOneTrue(map[string]interface{}{"s3": true, "gcs": true}, keys...)
+	require.EqualError(t, err, s.ErrAtMostOneTrue(keys, []string{"s3", "gcs"}))
+
+	err = cr.ValidateExactlyOneTrue(map[string]interface{}{"s3": "yes please"}, keys...)
+	require.EqualError(t, err, "s3: "+s.ErrInvalidPrimitiveType("yes please", s.PrimTypeBool))
+}
+
+func TestStringChecksum(t *testing.T) {
+	v := &cr.StringValidation{ChecksumValidator: util.CheckLuhn, ChecksumDescription: "the last digit is a Luhn check digit"}
+
+	val, err := cr.StringFromStr("79927398713", v)
+	require.NoError(t, err)
+	require.Equal(t, "79927398713", val)
+
+	_, err = cr.StringFromStr("79927398731", v)
+	require.EqualError(t, err, s.ErrInvalidChecksum("79927398731", "the last digit is a Luhn check digit"))
+
+	_, err = cr.StringFromStr("79927398731", &cr.StringValidation{ChecksumValidator: util.CheckLuhn, Sensitive: true})
+	require.EqualError(t, err, s.ErrInvalidChecksum(s.Redacted("79927398731"), ""))
+}
+
+func TestStructCollectErrors(t *testing.T) {
+	structValidation := &cr.StructValidation{
+		StructFieldValidations: []*cr.StructFieldValidation{
+			{
+				StructField:       "Key0",
+				Float64Validation: &cr.Float64Validation{},
+			},
+			{
+				StructField: "Key1",
+				StructValidation: &cr.StructValidation{
+					StructFieldValidations: []*cr.StructFieldValidation{
+						{
+							StructField:     "Key11",
+							Int32Validation: &cr.Int32Validation{},
+						},
+					},
+				},
+			},
+			{
+				StructField: "Key2",
+				StructValidation: &cr.StructValidation{
+					StructFieldValidations: []*cr.StructFieldValidation{
+						{
+							StructField:      "Key21",
+							StringValidation: &cr.StringValidation{},
+						},
+						{
+							StructField: "Key22",
+							StructValidation: &cr.StructValidation{
+								StructFieldValidations: []*cr.StructFieldValidation{
+									{
+										StructField:   "Key31",
+										IntValidation: &cr.IntValidation{},
+									},
+								},
+								CollectErrors: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		ShortCircuit:  true,
+		CollectErrors: true,
+	}
+
+	configData := cr.MustReadYAMLStr(`
+    key0: abc
+    key1:
+      key11: x
+    key2:
+      key21: 5
+      key22:
+        key31: z
+    zz_extra: 1
+    aa_extra: 2
+    `)
+
+	errs := cr.Struct(&NestedConfig{}, configData, structValidation)
+	require.Len(t, errs, 1)
+
+	ec, ok := errs[0].(*cr.ErrorCollector)
+	require.True(t, ok)
+	require.Len(t, ec.Errors(), 6)
+
+	expected := []string{
+		s.ErrUnsupportedKey("aa_extra"),
+		s.ErrUnsupportedKey("zz_extra"),
+		"key0: " + s.ErrInvalidPrimitiveType("abc", s.PrimTypeFloat),
+		"key1.key11: " + s.ErrInvalidPrimitiveType("x", s.PrimTypeInt),
+		"key2.key21: " + s.ErrInvalidPrimitiveType(5, s.PrimTypeString),
+		"key2.key22.key31: " + s.ErrInvalidPrimitiveType("z", s.PrimTypeInt),
+	}
+	require.EqualError(t, errs[0], "6 validation errors:\n  - "+strings.Join(expected, "\n  - "))
+
+	ec = &cr.ErrorCollector{}
+	require.NoError(t, ec.Err())
+	require.False(t, ec.Add(nil, "replicas"))
+	_, err := cr.IntFromInterfaceMap("replicas", map[string]interface{}{"replicas": "a"}, &cr.IntValidation{})
+	require.True(t, ec.Add(err))
+	ec.AddAll(errs, "api")
+	require.Len(t, ec.Errors(), 7)
+	require.EqualError(t, ec.Errors()[6], "api: "+s.ErrUnsupportedKey("zz_extra"))
+}
+
+func TestBoolAllowNumeric(t *testing.T) {
+	v := &cr.BoolValidation{AllowNumeric: true}
+
+	for inter, expected := range map[interface{}]bool{1: true, 0: false, 1.0: true, int64(0): false, "1": true, "0": false, true: true, false: false} {
+		val, err := cr.Bool(inter, v)
+		require.NoError(t, err)
+		require.Equal(t, expected, val, inter)
+	}
+
+	_, err := cr.Bool(2, v)
+	require.EqualError(t, err, s.ErrInvalidNumericBool(2))
+	_, err = cr.Bool("true", v)
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType("true", s.PrimTypeBool))
+
+	// numbers are rejected by default
+	_, err = cr.Bool(1, &cr.BoolValidation{})
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType(1, s.PrimTypeBool))
+
+	val, err := cr.BoolPtr(0, &cr.BoolPtrValidation{AllowNumeric: true})
+	require.NoError(t, err)
+	require.False(t, *val)
+}
+
+func TestTypedErrors(t *testing.T) {
+	_, err := cr.IntFromInterfaceMap("replicas", map[string]interface{}{}, &cr.IntValidation{Required: true})
+	require.True(t, errors.Is(err, cr.ErrRequired))
+	require.EqualError(t, err, "replicas: "+s.ErrMustBeDefined)
+
+	_, err = cr.IntFromInterfaceMap("replicas", map[string]interface{}{"replicas": 0}, &cr.IntValidation{GreaterThan: util.IntPtr(0)})
+	var outOfRangeErr *cr.OutOfRangeError
+	require.True(t, errors.As(err, &outOfRangeErr))
+	require.Equal(t, &cr.OutOfRangeError{Val: 0, Bound: 0, Op: ">"}, outOfRangeErr)
+	require.EqualError(t, err, "replicas: "+s.ErrMustBeGreaterThan(0, 0))
+	require.False(t, errors.Is(err, cr.ErrRequired))
+
+	os.Setenv("CR_TEST_TYPED_ERRORS", "abc")
+	defer os.Unsetenv("CR_TEST_TYPED_ERRORS")
+	_, err = cr.Float64FromEnv("CR_TEST_TYPED_ERRORS", &cr.Float64Validation{})
+	var invalidTypeErr *cr.InvalidTypeError
+	require.True(t, errors.As(err, &invalidTypeErr))
+	require.Equal(t, "abc", invalidTypeErr.Provided)
+	require.Equal(t, []s.PrimitiveType{s.PrimTypeFloat}, invalidTypeErr.Expected)
+
+	os.Setenv("CR_TEST_TYPED_ERRORS", "8000")
+	_, err = cr.IntFromEnv("CR_TEST_TYPED_ERRORS", &cr.IntValidation{AllowedValues: []int{80, 443}})
+	var notAllowedErr *cr.NotAllowedValueError
+	require.True(t, errors.As(err, &notAllowedErr))
+	require.Equal(t, 8000, notAllowedErr.Val)
+	require.Equal(t, []int{80, 443}, notAllowedErr.Allowed)
+	require.Contains(t, err.Error(), s.ErrInvalidInt(8000, 80, 443))
+
+	// the original error is still the cause
+	require.Equal(t, cr.ErrRequired, errors.Cause(errors.Wrap(errors.Wrap(cr.ErrRequired, "a"), "b")))
+}
+
+func TestFilePathBaseDir(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	configDir := filepath.Join(tmpDir, "config")
+	require.NoError(t, os.MkdirAll(filepath.Join(configDir, "models"), 0755))
+	modelPath := filepath.Join(configDir, "models", "model.zip")
+	require.NoError(t, ioutil.WriteFile(modelPath, []byte("model"), 0644))
+
+	v := cr.GetFilePathValidation(&cr.PathValidation{BaseDir: configDir})
+
+	val, err := cr.StringFromStr("models/model.zip", v)
+	require.NoError(t, err)
+	require.Equal(t, modelPath, val)
+
+	val, err = cr.StringFromStr("./models/../models/model.zip", v)
+	require.NoError(t, err)
+	require.Equal(t, modelPath, val)
+
+	// absolute paths ignore BaseDir
+	val, err = cr.StringFromStr(modelPath, cr.GetFilePathValidation(&cr.PathValidation{BaseDir: "/does/not/exist"}))
+	require.NoError(t, err)
+	require.Equal(t, modelPath, val)
+
+	_, err = cr.StringFromStr("model.zip", v)
+	require.EqualError(t, err, s.ErrFileDoesNotExist(filepath.Join(configDir, "model.zip")))
+
+	// without BaseDir, relative paths are resolved against the working directory
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(configDir))
+	defer os.Chdir(cwd)
+	val, err = cr.StringFromStr("models/model.zip", cr.GetFilePathValidation(&cr.PathValidation{}))
+	require.NoError(t, err)
+	require.True(t, filepath.IsAbs(val))
+	require.True(t, util.IsFile(val))
+}
+
+type PodsConfig struct {
+	Pods []*PodConfig `json:"pods"`
+}
+type PodConfig struct {
+	CPU string `json:"cpu"`
+}
+
+func TestErrorKeyPath(t *testing.T) {
+	structValidation := &cr.StructValidation{
+		StructFieldValidations: []*cr.StructFieldValidation{
+			{
+				StructField: "Pods",
+				StructListValidation: &cr.StructListValidation{
+					StructValidation: &cr.StructValidation{
+						StructFieldValidations: []*cr.StructFieldValidation{
+							{
+								StructField:      "CPU",
+								StringValidation: &cr.StringValidation{Required: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	configData := cr.MustReadYAMLStr(`
+    pods:
+      - cpu: "1"
+      - cpu: "2"
+      - cpu: "3"
+      - memory: 1Gi
+    `)
+
+	errs := cr.Struct(&PodsConfig{}, configData, structValidation)
+	require.Len(t, errs, 2)
+	require.EqualError(t, errs[0], "pods: "+s.Index(3)+": cpu: "+s.ErrMustBeDefined)
+	require.True(t, errors.Is(errs[0], cr.ErrRequired))
+
+	path := errors.KeyPath(errs[0])
+	require.Equal(t, "pods[3].cpu", path.String())
+	require.Equal(t, []string{"pods", "3", "cpu"}, path.Strings())
+	require.Equal(t, errors.PathElement{Index: 3, IsIndex: true}, path[1])
+
+	require.Equal(t, "pods[3]", errors.KeyPath(errs[1]).String())
+	require.EqualError(t, errs[1], "pods: "+s.Index(3)+": "+s.ErrUnsupportedKey("memory"))
+
+	// keys can contain any character
+	_, err := cr.IntFromInterfaceMap("a: b", map[string]interface{}{"a: b": "x"}, &cr.IntValidation{})
+	require.Equal(t, []string{"a: b"}, errors.KeyPath(err).Strings())
+	require.Empty(t, errors.KeyPath(errors.New("abc")))
+}
+
+func TestIntMapSum(t *testing.T) {
+	v := &cr.IntMapValidation{
+		AllowEmpty: true,
+		MaxSum:     util.IntPtr(10),
+		ElementValidator: func(val int) (int, error) {
+			return cr.ValidateInt(val, &cr.IntValidation{GreaterThanOrEqualTo: util.IntPtr(0)})
+		},
+	}
+
+	val, err := cr.IntMapFromInterfaceMap("replicas", cr.MustReadYAMLStrMap("replicas: {a: 4, b: 6}"), v)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"a": 4, "b": 6}, val)
+
+	_, err = cr.IntMapFromInterfaceMap("replicas", cr.MustReadYAMLStrMap("replicas: {a: 4, b: 7}"), v)
+	require.EqualError(t, err, "replicas: "+s.ErrSumTooLarge(11, 10))
+
+	_, err = cr.IntMapFromInterfaceMap("replicas", cr.MustReadYAMLStrMap("replicas: {a: 4, b: -1}"), v)
+	require.EqualError(t, err, "replicas: b: "+s.ErrMustBeGreaterThanOrEqualTo(-1, 0))
+	require.Equal(t, "replicas.b", errors.KeyPath(err).String())
+
+	val, err = cr.IntMap(map[string]int{}, v)
+	require.NoError(t, err)
+	require.Empty(t, val)
+
+	v.MinSum = util.IntPtr(1)
+	_, err = cr.IntMap(map[string]int{}, v)
+	require.EqualError(t, err, s.ErrSumTooSmall(0, 1))
+
+	_, err = cr.IntMap(map[string]interface{}{"a": "b"}, v)
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType(map[string]interface{}{"a": "b"}, s.PrimTypeStringToIntMap))
+}
+
+func TestListElementTypeErrors(t *testing.T) {
+	config := cr.MustReadYAMLStrMap(`
+ports: [80, 443, 8080, http]
+hosts: [a, b, 3]
+weights: [0.5, [1]]
+`)
+
+	_, err := cr.IntListFromInterfaceMap("ports", config, &cr.IntListValidation{})
+	require.Equal(t, "ports[3]", errors.KeyPath(err).String())
+	require.EqualError(t, err, "ports: index 3: "+s.ErrInvalidPrimitiveType("http", s.PrimTypeInt))
+
+	_, err = cr.StringListFromInterfaceMap("hosts", config, &cr.StringListValidation{})
+	require.Equal(t, "hosts[2]", errors.KeyPath(err).String())
+
+	_, err = cr.Float64ListFromInterfaceMap("weights", config, &cr.Float64ListValidation{})
+	require.Equal(t, "weights[1]", errors.KeyPath(err).String())
+
+	_, err = cr.IntList("80", &cr.IntListValidation{})
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType("80", s.PrimTypeIntList))
+}
+
+func TestIntListSum(t *testing.T) {
+	v := &cr.IntListValidation{MinSum: util.IntPtr(2), MaxSum: util.IntPtr(4)}
+
+	_, err := cr.ValidateIntList([]int{1, 1}, v)
+	require.NoError(t, err)
+	_, err = cr.ValidateIntList([]int{2, 2}, v)
+	require.NoError(t, err)
+	_, err = cr.ValidateIntList([]int{1}, v)
+	require.EqualError(t, err, s.ErrSumTooSmall(1, 2))
+	_, err = cr.ValidateIntList([]int{2, 3}, v)
+	require.EqualError(t, err, s.ErrSumTooLarge(5, 4))
+
+	_, err = cr.ValidateInt32List([]int32{2, 3}, &cr.Int32ListValidation{MaxSum: util.Int32Ptr(4)})
+	require.EqualError(t, err, s.ErrSumTooLarge(5, 4))
+	_, err = cr.ValidateInt64List([]int64{}, &cr.Int64ListValidation{AllowEmpty: true, MinSum: util.Int64Ptr(1)})
+	require.EqualError(t, err, s.ErrSumTooSmall(0, 1))
+}
+
+func TestWrapRepeatedKey(t *testing.T) {
+	os.Setenv("MAX_WORKERS", "0")
+	defer os.Unsetenv("MAX_WORKERS")
+
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+	filePath := filepath.Join(tmpDir, "max_workers")
+	require.NoError(t, ioutil.WriteFile(filePath, []byte("0"), 0644))
+
+	v := &cr.IntValidation{GreaterThan: util.IntPtr(0)}
+
+	_, err = cr.IntFromEnvOrFile("MAX_WORKERS", filePath, v)
+	require.EqualError(t, err, "MAX_WORKERS: "+s.ErrMustBeGreaterThan(0, 0)+s.FromSource(s.EnvVar("MAX_WORKERS")))
+
+	_, err = cr.IntFromEnv("MAX_WORKERS", v)
+	require.EqualError(t, errors.Wrap(err, "MAX_WORKERS"), "MAX_WORKERS: "+s.ErrMustBeGreaterThan(0, 0)+s.FromSource(s.EnvVar("MAX_WORKERS")))
+
+	os.Unsetenv("MAX_WORKERS")
+	_, err = cr.IntFromEnvOrFile("MAX_WORKERS", filePath, v)
+	require.EqualError(t, err, filePath+":1: "+s.ErrMustBeGreaterThan(0, 0))
+	require.EqualError(t, errors.Wrap(errors.Wrap(err), filePath), filePath+":1: "+s.ErrMustBeGreaterThan(0, 0))
+
+	_, err = cr.IntFromSource(cr.MapSource{"MAX_WORKERS": "0"}, "MAX_WORKERS", v)
+	require.EqualError(t, errors.Wrap(err, "MAX_WORKERS"), "MAX_WORKERS: "+s.ErrMustBeGreaterThan(0, 0))
+
+	// only the most recent key is compared
+	require.EqualError(t, errors.Wrap(errors.Wrap(errors.Wrap(cr.ErrRequired, "a"), "b"), "a"), "a: b: a: "+s.ErrMustBeDefined)
+
+	// nested keys in a config are kept
+	_, err = cr.IntFromInterfaceMap("a", map[string]interface{}{}, &cr.IntValidation{Required: true})
+	err = errors.WrapKey(err, "a")
+	require.EqualError(t, err, "a: a: "+s.ErrMustBeDefined)
+	require.Equal(t, "a.a", errors.KeyPath(err).String())
+}
+
+func TestStringFirstOf(t *testing.T) {
+	keys := []string{"image", "docker_image"}
+	v := &cr.StringValidation{Default: "cortexlabs/api", Prefix: "cortexlabs/"}
+
+	val, err := cr.StringFirstOf(cr.MustReadYAMLStrMap("image: cortexlabs/a\ndocker_image: cortexlabs/b"), keys, v)
+	require.NoError(t, err)
+	require.Equal(t, "cortexlabs/a", val)
+
+	val, err = cr.StringFirstOf(cr.MustReadYAMLStrMap("image: ' '\ndocker_image: cortexlabs/b"), keys, v)
+	require.NoError(t, err)
+	require.Equal(t, "cortexlabs/b", val)
+
+	val, err = cr.StringFirstOf(cr.MustReadYAMLStrMap("image: null"), keys, v)
+	require.NoError(t, err)
+	require.Equal(t, "cortexlabs/api", val)
+
+	// only the value which is used is validated
+	val, err = cr.StringFirstOf(cr.MustReadYAMLStrMap("image: cortexlabs/a\ndocker_image: latest"), keys, v)
+	require.NoError(t, err)
+	require.Equal(t, "cortexlabs/a", val)
+
+	_, err = cr.StringFirstOf(cr.MustReadYAMLStrMap("image: ''\ndocker_image: latest"), keys, v)
+	require.Equal(t, "docker_image", errors.KeyPath(err).String())
+
+	v.Required = true
+	_, err = cr.StringFirstOf(map[string]interface{}{}, keys, v)
+	require.EqualError(t, err, "image or docker_image: "+s.ErrMustBeDefined)
+}
+
+func TestUserErrors(t *testing.T) {
+	_, err := cr.IntFromInterfaceMap("replicas", map[string]interface{}{"replicas": 0}, &cr.IntValidation{GreaterThan: util.IntPtr(0)})
+	require.True(t, errors.IsUserError(err))
+
+	_, err = cr.IntFromInterfaceMap("replicas", map[string]interface{}{}, &cr.IntValidation{Required: true})
+	require.True(t, errors.IsUserError(errors.Wrap(err, "api")))
+
+	_, err = cr.StringFromInterfaceMap("name", map[string]interface{}{"name": ""}, &cr.StringValidation{})
+	require.True(t, errors.IsUserError(err))
+
+	_, err = cr.StringFromInterfaceMap("name", map[string]interface{}{"name": "a"}, &cr.StringValidation{
+		Validator: func(val string) (string, error) {
+			return "", errors.New("invalid name")
+		},
+	})
+	require.True(t, errors.IsUserError(err))
+
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+	_, err = cr.IntFromFile(tmpDir, &cr.IntValidation{AllowNonRegularFiles: true})
+	require.Error(t, err)
+	require.False(t, errors.IsUserError(err))
+
+	require.NoError(t, cr.RegisterSourceScheme("cr-test-user-errors", func(key string) (*string, error) {
+		if key == "timeout" {
+			return nil, errors.New("timed out")
+		}
+		return nil, errors.NewUser("bad key")
+	}))
+	_, err = cr.IntFromRef("cr-test-user-errors://timeout", &cr.IntValidation{})
+	require.EqualError(t, err, "cr-test-user-errors://timeout: timed out")
+	require.False(t, errors.IsUserError(err))
+	_, err = cr.IntFromRef("cr-test-user-errors://key", &cr.IntValidation{})
+	require.True(t, errors.IsUserError(err))
+
+	// the first classification is kept
+	require.False(t, errors.IsUserError(errors.MarkUser(errors.Wrap(errors.MarkInternal(errors.New("a"))))))
+	require.False(t, errors.IsUserError(errors.New("a")))
+	require.Nil(t, errors.MarkUser(nil))
+
+	ec := &cr.ErrorCollector{}
+	ec.Add(errors.NewUser("a"))
+	require.True(t, errors.IsUserError(ec.Err()))
+	ec.Add(errors.MarkInternal(errors.New("b")))
+	require.False(t, errors.IsUserError(errors.Wrap(ec.Err(), "api")))
+}
+
+func TestDidYouMean(t *testing.T) {
+	v := &cr.StringValidation{
+		AllowedValues:       []string{"us-east-1", "us-west-2"},
+		HiddenAllowedValues: []string{"us-east-1-internal"},
+	}
+
+	_, err := cr.StringFromStr("us-esat-1", v)
+	require.EqualError(t, err, s.ErrInvalidStr("us-esat-1", "us-east-1", "us-west-2")+`; did you mean "us-east-1"?`)
+	var notAllowedErr *cr.NotAllowedValueError
+	require.True(t, errors.As(err, &notAllowedErr))
+	require.Equal(t, "us-east-1", notAllowedErr.Suggestion)
+
+	_, err = cr.StringFromStr("ap-south-1", v)
+	require.EqualError(t, err, s.ErrInvalidStr("ap-south-1", "us-east-1", "us-west-2"))
+
+	// hidden values are allowed, but aren't suggested
+	val, err := cr.StringFromStr("us-east-1-internal", v)
+	require.NoError(t, err)
+	require.Equal(t, "us-east-1-internal", val)
+	_, err = cr.StringFromStr("us-east-1-internl", v)
+	require.EqualError(t, err, s.ErrInvalidStr("us-east-1-internl", "us-east-1", "us-west-2"))
+
+	v.Sensitive = true
+	_, err = cr.StringFromStr("us-esat-1", v)
+	require.EqualError(t, err, s.ErrInvalidStr(s.Redacted("us-esat-1"), "us-east-1", "us-west-2"))
+
+	_, err = cr.LevelFromStr("wrn", &cr.LevelValidation{Levels: logLevels})
+	require.EqualError(t, err, s.ErrInvalidStr("wrn", logLevels...)+`; did you mean "warn"?`)
+
+	errs := cr.Struct(&LevelConfig{}, cr.MustReadYAMLStrMap("log_levl: info"), &cr.StructValidation{
+		StructFieldValidations: []*cr.StructFieldValidation{
+			{
+				StructField:     "LogLevel",
+				LevelValidation: &cr.LevelValidation{Default: "info", Levels: logLevels},
+			},
+		},
+	})
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], s.ErrUnsupportedKey("log_levl")+`; did you mean "log_level"?`)
+}
+
+func TestStringMapFromStr(t *testing.T) {
+	v := &cr.StringMapValidation{
+		KeyValidation:   &cr.StringValidation{AlphaNumericDashUnderscore: true},
+		ValueValidation: &cr.StringValidation{AllowEmpty: true},
+	}
+
+	val, err := cr.StringMapFromStr(" a = 1, b=2,c=x=y,d=,", v)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "1", "b": "2", "c": "x=y", "d": ""}, val)
+
+	_, err = cr.StringMapFromStr("a=1,b", v)
+	require.EqualError(t, err, s.ErrMalformedKVPair("b", "="))
+
+	_, err = cr.StringMapFromStr("a=1, =2", v)
+	require.EqualError(t, err, s.ErrEmptyKVPairKey("=2"))
+
+	_, err = cr.StringMapFromStr("a=1,a=2", v)
+	require.EqualError(t, err, s.ErrDuplicateKey("a"))
+
+	_, err = cr.StringMapFromStr("a.b=1", v)
+	require.EqualError(t, err, "a.b: "+s.ErrAlphaNumericDashUnderscore("a.b"))
+
+	v.AllowDuplicateKeys = true
+	v.PairDelimiter = ";"
+	v.KVDelimiter = ":"
+	val, err = cr.StringMapFromStr("a:1;a:2", v)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"a": "2"}, val)
+
+	_, err = cr.StringMapFromStr("", v)
+	require.EqualError(t, err, s.ErrCannotBeNull)
+
+	os.Setenv("CR_TEST_STRING_MAP", "a:1;b:2")
+	defer os.Unsetenv("CR_TEST_STRING_MAP")
+	require.Equal(t, map[string]string{"a": "1", "b": "2"}, cr.MustStringMapFromEnv("CR_TEST_STRING_MAP", v))
+
+	os.Setenv("CR_TEST_STRING_MAP", "a")
+	_, err = cr.StringMapFromEnv("CR_TEST_STRING_MAP", v)
+	require.EqualError(t, err, "CR_TEST_STRING_MAP: "+s.ErrMalformedKVPair("a", ":")+s.FromSource(s.EnvVar("CR_TEST_STRING_MAP")))
+}
+
+func TestFloat64SignNormalization(t *testing.T) {
+	v := &cr.Float64Validation{}
+
+	val, err := cr.Float64FromStr("-0.0", v)
+	require.NoError(t, err)
+	require.True(t, math.Signbit(val))
+	val, err = cr.Float64FromStr("+1.5", v)
+	require.NoError(t, err)
+	require.Equal(t, 1.5, val)
+
+	v.NormalizeNegativeZero = true
+	v.DisallowExplicitPlus = true
+
+	val, err = cr.Float64FromStr("-0.0", v)
+	require.NoError(t, err)
+	require.Equal(t, float64(0), val)
+	require.False(t, math.Signbit(val))
+
+	val, err = cr.Float64(math.Copysign(0, -1), v)
+	require.NoError(t, err)
+	require.False(t, math.Signbit(val))
+
+	val, err = cr.Float64FromStr("0.0", v)
+	require.NoError(t, err)
+	require.False(t, math.Signbit(val))
+
+	_, err = cr.Float64FromStr("+1.5", v)
+	require.EqualError(t, err, s.ErrExplicitPlus("+1.5"))
+
+	val, err = cr.Float64FromStr("-1.5", v)
+	require.NoError(t, err)
+	require.Equal(t, -1.5, val)
+}
+
+func TestIntCommaGrouping(t *testing.T) {
+	_, err := cr.IntFromStr("1,000", &cr.IntValidation{})
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType("1,000", s.PrimTypeInt))
+
+	v := &cr.IntValidation{AllowCommaGrouping: true}
+	for str, expected := range map[string]int{"1,000": 1000, "-12,345,678": -12345678, "999": 999, "1000": 1000} {
+		val, err := cr.IntFromStr(str, v)
+		require.NoError(t, err)
+		require.Equal(t, expected, val)
+	}
+	for _, str := range []string{"1,00,0", ",100", "100,", "1,,000", "1234,567", "1,0a0"} {
+		_, err = cr.IntFromStr(str, v)
+		require.EqualError(t, err, s.ErrMisplacedGroupingComma(str))
+	}
+
+	val64, err := cr.Int64FromStr("9,000,000,000", &cr.Int64Validation{AllowCommaGrouping: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(9000000000), val64)
+	_, err = cr.Int64FromStr("9,000,000,000", &cr.Int64Validation{})
+	require.Error(t, err)
+}
+
+func TestMessageRenderer(t *testing.T) {
+	s.SetMessageRenderer(func(code string, args ...interface{}) string {
+		switch code {
+		case s.MsgMustBeDefined:
+			return strings.ToUpper(s.ErrMustBeDefined)
+		case s.MsgMustBeGreaterThan:
+			return strings.ToUpper(fmt.Sprintf("%v must be greater than %v", args...))
+		case s.MsgInvalidPrimitiveType:
+			return strings.ToUpper(fmt.Sprintf("%v: invalid type", args[0]))
+		}
+		return ""
+	})
+	defer s.SetMessageRenderer(nil)
+
+	_, err := cr.IntFromStrMap("replicas", map[string]string{}, &cr.IntValidation{Required: true})
+	require.EqualError(t, err, "replicas: MUST BE DEFINED")
+	require.True(t, errors.Is(err, cr.ErrRequired))
+
+	_, err = cr.IntFromStrMap("replicas", map[string]string{"replicas": "0"}, &cr.IntValidation{GreaterThan: util.IntPtr(0)})
+	require.EqualError(t, err, "replicas: 0 MUST BE GREATER THAN 0")
+	var outOfRangeErr *cr.OutOfRangeError
+	require.True(t, errors.As(err, &outOfRangeErr))
+	require.Equal(t, s.MsgMustBeGreaterThan, outOfRangeErr.Code())
+
+	_, err = cr.IntFromStrMap("replicas", map[string]string{"replicas": "abc"}, &cr.IntValidation{})
+	require.EqualError(t, err, "replicas: ABC: INVALID TYPE")
+
+	_, err = cr.IntFromStrMap("replicas", map[string]string{"replicas": "5"}, &cr.IntValidation{AllowedValues: []int{1, 2}})
+	require.EqualError(t, err, "replicas: "+s.ErrInvalidInt(5, 1, 2))
+
+	// the renderer is consulted when the message is rendered, not when the error is created
+	s.SetMessageRenderer(nil)
+	require.EqualError(t, err, "replicas: invalid value (got 5, must be 1 or 2)")
+}
+
+func TestCaptureStacks(t *testing.T) {
+	_, err := cr.IntFromStr("0", &cr.IntValidation{GreaterThan: util.IntPtr(0)})
+	require.Nil(t, errors.Stack(err))
+	require.Nil(t, errors.Stack(errors.New("no stack")))
+
+	errors.SetCaptureStacks(true)
+	defer errors.SetCaptureStacks(false)
+
+	err = errors.Wrap(errors.New("must be defined"), "replicas")
+	require.EqualError(t, err, "replicas: must be defined")
+	stack := errors.Stack(err)
+	require.NotEmpty(t, stack)
+	require.Contains(t, stack[0].Function, "TestCaptureStacks")
+	for _, frame := range stack {
+		require.NotContains(t, frame.Function, "pkg/utils/errors.")
+	}
+
+	verbose := fmt.Sprintf("%+v", err)
+	require.True(t, strings.HasPrefix(verbose, "must be defined"))
+	require.Contains(t, verbose, "TestCaptureStacks")
+	require.NotContains(t, fmt.Sprintf("%v", err), "TestCaptureStacks")
+
+	// foreign errors get a stack when they're first wrapped
+	err = errors.Wrap(fmt.Errorf("connection refused"), "ssm")
+	require.Contains(t, errors.Stack(err)[0].Function, "TestCaptureStacks")
+}
+
+func BenchmarkValidationError(b *testing.B) {
+	v := &cr.IntValidation{GreaterThan: util.IntPtr(0)}
+	run := func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			cr.IntFromStrMap("replicas", map[string]string{"replicas": "0"}, v)
+		}
+	}
+
+	b.Run("without stacks", run)
+
+	errors.SetCaptureStacks(true)
+	defer errors.SetCaptureStacks(false)
+	b.Run("with stacks", run)
+}
+
+func TestPowerOfTwo(t *testing.T) {
+	v := &cr.IntValidation{MustBePowerOfTwo: true}
+	for _, val := range []int{1, 2, 1024} {
+		_, err := cr.ValidateInt(val, v)
+		require.NoError(t, err)
+	}
+
+	_, err := cr.ValidateInt(3, v)
+	require.EqualError(t, err, "3 must be a power of two (e.g. 2, 4, 8)")
+	_, err = cr.ValidateInt(300, v)
+	require.EqualError(t, err, "300 must be a power of two (e.g. 256, 512, 1024)")
+	_, err = cr.ValidateInt(0, v)
+	require.EqualError(t, err, "0 must be a power of two (e.g. 1, 2, 4)")
+	_, err = cr.ValidateInt(-4, v)
+	require.EqualError(t, err, "-4 must be a power of two (e.g. 1, 2, 4)")
+
+	val64, err := cr.Int64FromStr("4294967296", &cr.Int64Validation{MustBePowerOfTwo: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(1<<32), val64)
+	_, err = cr.Int64FromStr("-1", &cr.Int64Validation{MustBePowerOfTwo: true})
+	require.EqualError(t, err, s.ErrMustBePowerOfTwo(int64(-1), []int64{1, 2, 4}))
+}
+
+func TestDisallowLeadingZeros(t *testing.T) {
+	v := &cr.IntValidation{DisallowLeadingZeros: true}
+
+	val, err := cr.IntFromStr("0", v)
+	require.NoError(t, err)
+	require.Equal(t, 0, val)
+	val, err = cr.IntFromStr("-70", v)
+	require.NoError(t, err)
+	require.Equal(t, -70, val)
+
+	_, err = cr.IntFromStr("00", v)
+	require.EqualError(t, err, s.ErrLeadingZeros("00"))
+	_, err = cr.IntFromStr("007", v)
+	require.EqualError(t, err, s.ErrLeadingZeros("007"))
+	_, err = cr.IntFromStr("-007", v)
+	require.EqualError(t, err, s.ErrLeadingZeros("-007"))
+
+	val, err = cr.IntFromStr("007", &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 7, val)
+}
+
+func TestFormatList(t *testing.T) {
+	inner := &cr.ErrorCollector{}
+	inner.Add(errors.WrapKey(errors.New("must be defined"), "cpu"))
+	inner.Add(errors.WrapIndex(errors.WrapKey(errors.New("invalid"), "port"), 1))
+
+	outer := &cr.ErrorCollector{}
+	outer.Add(errors.WrapKey(errors.New("must be positive"), "replicas"))
+	outer.Add(errors.WrapKey(inner.Err(), "compute"))
+
+	require.EqualError(t, outer, "3 validation errors:\n  - compute.cpu: must be defined\n  - compute[1].port: invalid\n  - replicas: must be positive")
+	require.Equal(t, "3 validation errors: compute.cpu: must be defined; compute[1].port: invalid; replicas: must be positive", errors.FormatListCompact(outer.Errors()))
+
+	errors.SetCompactLists(true)
+	require.Equal(t, "3 validation errors: compute.cpu: must be defined; compute[1].port: invalid; replicas: must be positive", outer.Error())
+	errors.SetCompactLists(false)
+
+	// a single error is shown as-is
+	require.Equal(t, "replicas: must be positive", errors.FormatList(outer.Errors()[:1]))
+}
+
+type PoolConfig struct {
+	Name string
+	Size int
+	Type string
+}
+
+func readPool(iMap map[string]interface{}) (interface{}, error) {
+	ec := &cr.ErrorCollector{}
+	pool := PoolConfig{}
+	var err error
+	pool.Name, err = cr.StringFromInterfaceMap("name", iMap, &cr.StringValidation{Required: true})
+	ec.Add(err)
+	pool.Size, err = cr.IntFromInterfaceMap("size", iMap, &cr.IntValidation{GreaterThan: util.IntPtr(0)})
+	ec.Add(err)
+	pool.Type, err = cr.StringFromInterfaceMap("type", iMap, &cr.StringValidation{Default: "cpu", AllowedValues: []string{"cpu", "gpu"}})
+	ec.Add(err)
+	return pool, ec.Err()
+}
+
+func TestSectionList(t *testing.T) {
+	config := cr.MustReadYAMLStrMap(`
+pools:
+  - name: a
+    size: 2
+  - name: b
+    size: 4
+    type: gpu
+`)
+	var pools []PoolConfig
+	err := cr.SectionListFromInterfaceMap("pools", config, &pools, readPool)
+	require.NoError(t, err)
+	require.Equal(t, []PoolConfig{{Name: "a", Size: 2, Type: "cpu"}, {Name: "b", Size: 4, Type: "gpu"}}, pools)
+
+	config = cr.MustReadYAMLStrMap(`
+pools:
+  - size: 0
+  - name: b
+    size: 1
+  - 3
+`)
+	pools = nil
+	err = cr.SectionListFromInterfaceMap("pools", config, &pools, readPool)
+	var ec *cr.ErrorCollector
+	require.True(t, errors.As(err, &ec))
+	require.Len(t, ec.Errors(), 3)
+	require.Equal(t, "pools[0].name", errors.KeyPath(ec.Errors()[0]).String())
+	require.Equal(t, "pools[0].size", errors.KeyPath(ec.Errors()[1]).String())
+	require.EqualError(t, ec.Errors()[2], "pools: index 2: "+s.ErrInvalidPrimitiveType(3, s.PrimTypeMap))
+	require.Equal(t, []PoolConfig{{Name: "b", Size: 1, Type: "cpu"}}, pools)
+}
+
+func TestSetFatalHandler(t *testing.T) {
+	var handled error
+	cr.SetFatalHandler(func(err error) {
+		handled = err
+	})
+	defer cr.SetFatalHandler(nil)
+
+	os.Setenv("CR_TEST_FATAL_WORKERS", "0")
+	defer os.Unsetenv("CR_TEST_FATAL_WORKERS")
+
+	require.Panics(t, func() {
+		cr.MustIntFromEnv("CR_TEST_FATAL_WORKERS", &cr.IntValidation{GreaterThan: util.IntPtr(0)})
+	})
+	require.EqualError(t, handled, "CR_TEST_FATAL_WORKERS: "+s.ErrMustBeGreaterThan(0, 0)+s.FromSource(s.EnvVar("CR_TEST_FATAL_WORKERS")))
+
+	handled = nil
+	require.Equal(t, 1, cr.MustIntFromSource(cr.MapSource{"workers": "1"}, "workers", &cr.IntValidation{}))
+	require.Nil(t, handled)
+
+	cr.SetFatalHandler(nil)
+	require.Panics(t, func() {
+		cr.MustIntFromSource(cr.MapSource{"workers": "a"}, "workers", &cr.IntValidation{})
+	})
+	require.Nil(t, handled)
+}
+
+func TestReport(t *testing.T) {
+	report := &cr.Report{}
+
+	os.Setenv("CR_TEST_REPORT_WORKERS", "4")
+	defer os.Unsetenv("CR_TEST_REPORT_WORKERS")
+
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+	filePath := filepath.Join(tmpDir, "token")
+	err = ioutil.WriteFile(filePath, []byte("secret\n"), 0644)
+	require.NoError(t, err)
+
+	_, err = cr.IntFromEnv("CR_TEST_REPORT_WORKERS", &cr.IntValidation{Report: report})
+	require.NoError(t, err)
+	_, err = cr.IntFromEnv("CR_TEST_REPORT_MISSING", &cr.IntValidation{Default: 2, Report: report})
+	require.NoError(t, err)
+	_, err = cr.StringFromFile(filePath, &cr.StringValidation{Sensitive: true, Report: report})
+	require.NoError(t, err)
+	_, err = cr.Float32FromInterfaceMap("ratio", map[string]interface{}{"ratio": 16777217.0}, &cr.Float32Validation{WarnPrecisionLoss: true, Report: report})
+	require.NoError(t, err)
+	_, err = cr.BoolFromPrompt(&cr.PromptOptions{
+		Prompt: "Enabled",
+		In:     prompttest.ScriptedInput([]string{""}),
+		Out:    prompttest.NewOutput(),
+	}, &cr.BoolValidation{Default: true, Report: report})
+	require.NoError(t, err)
+
+	// values which fail validation aren't recorded
+	_, err = cr.IntFromEnv("CR_TEST_REPORT_WORKERS", &cr.IntValidation{LessThan: util.IntPtr(4), Report: report})
+	require.Error(t, err)
+
+	require.Equal(t, []cr.ReportEntry{
+		{Key: "CR_TEST_REPORT_WORKERS", Source: cr.ValueSourceEnv, Location: s.EnvVar("CR_TEST_REPORT_WORKERS"), Value: 4},
+		{Key: "CR_TEST_REPORT_MISSING", Source: cr.ValueSourceDefault, Location: s.EnvVar("CR_TEST_REPORT_MISSING"), Value: 2},
+		{Key: filePath, Source: cr.ValueSourceFile, Location: filePath, Value: s.Redacted("secret")},
+		{Key: "ratio", Source: cr.ValueSourceConfig, Value: float32(16777216), Warnings: []string{s.WarnFloat32PrecisionLoss(16777217.0, float32(16777216))}},
+		{Key: "Enabled", Source: cr.ValueSourceDefault, Value: true},
+	}, report.Entries())
+
+	entry, ok := report.Entry("ratio")
+	require.True(t, ok)
+	require.Equal(t, cr.ValueSourceConfig, entry.Source)
+
+	// a nil report records nothing
+	_, err = cr.IntFromEnv("CR_TEST_REPORT_WORKERS", &cr.IntValidation{})
+	require.NoError(t, err)
+}
+
+func TestReportWarnings(t *testing.T) {
+	report := &cr.Report{}
+
+	os.Setenv("CR_TEST_REPORT_WORKERS", "4")
+	defer os.Unsetenv("CR_TEST_REPORT_WORKERS")
+
+	// a warning for a value which fails validation isn't added to the next entry
+	_, err := cr.Float32FromInterfaceMap("ratio", map[string]interface{}{"ratio": 16777217.0}, &cr.Float32Validation{WarnPrecisionLoss: true, LessThan: util.Float32Ptr(1), Report: report})
+	require.Error(t, err)
+	_, err = cr.IntFromEnv("CR_TEST_REPORT_WORKERS", &cr.IntValidation{Report: report})
+	require.NoError(t, err)
+
+	// a warning from a read which doesn't record (i.e. has no report) isn't added to another report's entry
+	_, err = cr.Float32FromStr("16777217", &cr.Float32Validation{WarnPrecisionLoss: true})
+	require.NoError(t, err)
+	_, err = cr.Float32PtrFromStrMap("ratio", map[string]string{"ratio": "16777217"}, &cr.Float32PtrValidation{WarnPrecisionLoss: true, Report: report})
+	require.NoError(t, err)
+	_, err = cr.IntFromEnv("CR_TEST_REPORT_WORKERS", &cr.IntValidation{Report: report})
+	require.NoError(t, err)
+
+	require.Equal(t, []cr.ReportEntry{
+		{Key: "CR_TEST_REPORT_WORKERS", Source: cr.ValueSourceEnv, Location: s.EnvVar("CR_TEST_REPORT_WORKERS"), Value: 4},
+		{Key: "ratio", Source: cr.ValueSourceConfig, Value: float32(16777216), Warnings: []string{s.WarnFloat32PrecisionLoss("16777217", float32(16777216))}},
+		{Key: "CR_TEST_REPORT_WORKERS", Source: cr.ValueSourceEnv, Location: s.EnvVar("CR_TEST_REPORT_WORKERS"), Value: 4},
+	}, report.Entries())
+}
+
+func TestReportReaders(t *testing.T) {
+	report := &cr.Report{}
+
+	os.Setenv("CR_TEST_REPORT_PORTS", "80,443")
+	defer os.Unsetenv("CR_TEST_REPORT_PORTS")
+
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+	filePath := filepath.Join(tmpDir, "workers")
+	err = ioutil.WriteFile(filePath, []byte("1\n2\n"), 0644)
+	require.NoError(t, err)
+
+	_, err = cr.IntFromFileLine(filePath, 2, &cr.IntValidation{Report: report})
+	require.NoError(t, err)
+	_, err = cr.IntFromRef("literal://3", &cr.IntValidation{Report: report})
+	require.NoError(t, err)
+	_, err = cr.IntFromSource(cr.MapSource{"workers": "5"}, "workers", &cr.IntValidation{Report: report})
+	require.NoError(t, err)
+	_, err = cr.IntFromArg([]string{"6"}, 0, "workers", &cr.IntValidation{Report: report})
+	require.NoError(t, err)
+	_, err = cr.IntFromArg([]string{}, 1, "replicas", &cr.IntValidation{Default: 1, Report: report})
+	require.NoError(t, err)
+	_, err = cr.StringPtrFromPrompt(&cr.PromptOptions{
+		Prompt: "Token",
+		In:     prompttest.ScriptedInput([]string{"abc"}),
+		Out:    prompttest.NewOutput(),
+	}, &cr.StringPtrValidation{Sensitive: true, Report: report})
+	require.NoError(t, err)
+	_, err = cr.StringPtrFromInterfaceMap("region", map[string]interface{}{}, &cr.StringPtrValidation{Report: report})
+	require.NoError(t, err)
+	_, err = cr.IntListFromEnv("CR_TEST_REPORT_PORTS", &cr.IntListValidation{Report: report})
+	require.NoError(t, err)
+
+	require.Equal(t, []cr.ReportEntry{
+		{Key: filePath, Source: cr.ValueSourceFile, Location: filePath + ":2", Value: 2},
+		{Key: "literal://3", Source: cr.ValueSourceRef, Location: "literal://3", Value: 3},
+		{Key: "workers", Source: cr.ValueSourceLookup, Value: 5},
+		{Key: "workers", Source: cr.ValueSourceArg, Value: 6},
+		{Key: "replicas", Source: cr.ValueSourceDefault, Value: 1},
+		{Key: "Token", Source: cr.ValueSourcePrompt, Value: s.Redacted("abc")},
+		{Key: "region", Source: cr.ValueSourceDefault, Value: nil},
+		{Key: "CR_TEST_REPORT_PORTS", Source: cr.ValueSourceEnv, Location: s.EnvVar("CR_TEST_REPORT_PORTS"), Value: []int{80, 443}},
+	}, report.Entries())
+}
+
+type ClusterConfig struct {
+	Name        string `json:"name"`
+	MaxReplicas int    `json:"max_replicas"`
+}
+
+var clusterValidation = &cr.StructValidation{
+	StructFieldValidations: []*cr.StructFieldValidation{
+		{
+			StructField:      "Name",
+			StringValidation: &cr.StringValidation{Required: true},
+		},
+		{
+			StructField:   "MaxReplicas",
+			IntValidation: &cr.IntValidation{LessThanOrEqualTo: util.IntPtr(100)},
+		},
+	},
+}
+
+func TestErrorPositions(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+
+	yamlPath := filepath.Join(tmpDir, "cluster.yaml")
+	err = ioutil.WriteFile(yamlPath, []byte("# cluster\nname: test\n\nmax_replicas: 200\n"), 0644)
+	require.NoError(t, err)
+
+	var cluster ClusterConfig
+	errs := cr.StructFromConfigFile(&cluster, yamlPath, clusterValidation)
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], yamlPath+":4:1: max_replicas: "+s.ErrMustBeLessThanOrEqualTo(200, 100))
+
+	pos, ok := errors.GetPosition(errors.Wrap(errs[0], "cluster"))
+	require.True(t, ok)
+	require.Equal(t, errors.Position{File: yamlPath, Line: 4, Col: 1}, pos)
+
+	errJSON := cr.NewErrorJSON(errs[0])
+	require.Equal(t, yamlPath, errJSON.File)
+	require.Equal(t, 4, errJSON.Line)
+	require.Equal(t, 1, errJSON.Col)
+
+	jsonPath := filepath.Join(tmpDir, "cluster.json")
+	err = ioutil.WriteFile(jsonPath, []byte("{\n  \"name\": \"test\",\n  \"max_replicas\": 200\n}\n"), 0644)
+	require.NoError(t, err)
+
+	_, err = cr.IntFromConfigFile(jsonPath, "max_replicas", &cr.IntValidation{LessThanOrEqualTo: util.IntPtr(100)})
+	require.EqualError(t, err, jsonPath+":3:3: max_replicas: "+s.ErrMustBeLessThanOrEqualTo(200, 100))
+
+	// errors with no key are positioned at the file
+	err = ioutil.WriteFile(yamlPath, []byte("max_replicas: 10\n"), 0644)
+	require.NoError(t, err)
+	errs = cr.StructFromConfigFile(&cluster, yamlPath, clusterValidation)
+	require.Len(t, errs, 1)
+	pos, _ = errors.GetPosition(errs[0])
+	require.Equal(t, errors.Position{File: yamlPath, Line: 0}, pos)
+
+	// values read from a whole file are on line 1
+	replicasPath := filepath.Join(tmpDir, "max_replicas")
+	err = ioutil.WriteFile(replicasPath, []byte("200\n"), 0644)
+	require.NoError(t, err)
+	_, err = cr.IntFromFile(replicasPath, &cr.IntValidation{LessThanOrEqualTo: util.IntPtr(100)})
+	require.EqualError(t, err, replicasPath+":1: "+s.ErrMustBeLessThanOrEqualTo(200, 100))
+}
+
+type WorkersConfig struct {
+	NumWorkers     int    `json:"num_workers"`
+	MaxConnections int    `json:"max_connections"`
+	LogDir         string `json:"log_dir"`
+}
+
+func TestDefaultFunc(t *testing.T) {
+	config := &WorkersConfig{}
+	structValidation := &cr.StructValidation{
+		StructFieldValidations: []*cr.StructFieldValidation{
+			{
+				StructField:   "NumWorkers",
+				IntValidation: &cr.IntValidation{Default: 1},
+			},
+			{
+				StructField: "MaxConnections",
+				IntValidation: &cr.IntValidation{
+					LessThanOrEqualTo: util.IntPtr(64),
+					DefaultFunc: func() (int, error) {
+						if config.NumWorkers > 50 {
+							return 0, fmt.Errorf("must be set when num_workers is greater than 50")
+						}
+						return 2 * config.NumWorkers, nil
+					},
+				},
+			},
+		},
+	}
+
+	errs := cr.Struct(config, cr.MustReadYAMLStrMap("num_workers: 4"), structValidation)
+	require.Empty(t, errs)
+	require.Equal(t, 8, config.MaxConnections)
+
+	errs = cr.Struct(config, cr.MustReadYAMLStrMap("num_workers: 4\nmax_connections: 5"), structValidation)
+	require.Empty(t, errs)
+	require.Equal(t, 5, config.MaxConnections)
+
+	errs = cr.Struct(config, cr.MustReadYAMLStrMap("num_workers: 60"), structValidation)
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "max_connections: must be set when num_workers is greater than 50")
+	require.True(t, errors.IsUserError(errs[0]))
+
+	// the computed default is validated
+	errs = cr.Struct(config, cr.MustReadYAMLStrMap("num_workers: 40"), structValidation)
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "max_connections: "+s.ErrMustBeLessThanOrEqualTo(80, 64))
+
+	val, err := cr.Float64FromStr("", &cr.Float64Validation{DefaultFunc: func() (float64, error) { return 0.5, nil }})
+	require.NoError(t, err)
+	require.Equal(t, 0.5, val)
+
+	str, err := cr.StringFromInterfaceMap("log_dir", nil, &cr.StringValidation{DefaultFunc: func() (string, error) { return "/var/log/" + "api", nil }})
+	require.NoError(t, err)
+	require.Equal(t, "/var/log/api", str)
+
+	// the prompt shows the computed default
+	out := prompttest.NewOutput()
+	num, err := cr.IntFromPrompt(&cr.PromptOptions{
+		Prompt: "Max connections",
+		In:     prompttest.ScriptedInput([]string{""}),
+		Out:    out,
+	}, &cr.IntValidation{DefaultFunc: func() (int, error) { return 12, nil }})
+	require.NoError(t, err)
+	require.Equal(t, 12, num)
+	require.Contains(t, out.String(), "[12]")
+
+	// DefaultFunc is only called once per prompt, and the caller's options aren't modified
+	calls := 0
+	promptOpts := &cr.PromptOptions{
+		Prompt: "Log dir",
+		In:     prompttest.ScriptedInput([]string{"", ""}),
+		Out:    prompttest.NewOutput(),
+	}
+	promptOptsBefore := *promptOpts
+	str, err = cr.StringFromPrompt(promptOpts, &cr.StringValidation{AllowEmpty: true, DefaultFunc: func() (string, error) {
+		calls++
+		return "", nil
+	}})
+	require.NoError(t, err)
+	require.Equal(t, "", str)
+	require.Equal(t, 1, calls)
+	require.Equal(t, promptOptsBefore, *promptOpts)
+
+	num, err = cr.IntFromPrompt(promptOpts, &cr.IntValidation{DefaultFunc: func() (int, error) {
+		calls++
+		return 3, nil
+	}})
+	require.NoError(t, err)
+	require.Equal(t, 3, num)
+	require.Equal(t, 2, calls)
+	require.Equal(t, promptOptsBefore, *promptOpts)
+}
+
+func TestListFromStrDelimiters(t *testing.T) {
+	strs, err := cr.StringListFromStr(" a, b ,c", &cr.StringListValidation{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, strs)
+
+	strs, err = cr.StringListFromStr("--port 80\t --verbose ", &cr.StringListValidation{Delimiter: cr.WhitespaceDelimiter})
+	require.NoError(t, err)
+	require.Equal(t, []string{"--port", "80", "--verbose"}, strs)
+
+	v := &cr.IntListValidation{Delimiter: cr.WhitespaceDelimiter, DisallowDups: true}
+	for _, valStr := range []string{"0 1 2 3", "  0 1\t2\n3  ", "0   1 2 3"} {
+		ints, err := cr.IntListFromStr(valStr, v)
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 1, 2, 3}, ints)
+	}
+
+	_, err = cr.IntListFromStr("0 1 x", v)
+	require.EqualError(t, err, "index 2: "+s.ErrInvalidPrimitiveType("x", s.PrimTypeInt))
+
+	_, err = cr.IntListFromStr("   ", v)
+	require.EqualError(t, err, s.ErrCannotBeEmpty)
+
+	floats, err := cr.Float64ListFromStr("0.5;0.25; 0.25", &cr.Float64ListValidation{Delimiter: ";", SumTo: util.Float64Ptr(1)})
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.5, 0.25, 0.25}, floats)
+
+	os.Setenv("CR_TEST_CPU_AFFINITY", "0 2 4")
+	defer os.Unsetenv("CR_TEST_CPU_AFFINITY")
+	require.Equal(t, []int{0, 2, 4}, cr.MustIntListFromEnv("CR_TEST_CPU_AFFINITY", &cr.IntListValidation{Delimiter: cr.WhitespaceDelimiter}))
+
+	_, err = cr.StringListFromEnv("CR_TEST_MISSING_LIST", &cr.StringListValidation{Required: true})
+	require.EqualError(t, err, "CR_TEST_MISSING_LIST: "+s.MustBeDefined()+s.FromSource(s.EnvVar("CR_TEST_MISSING_LIST")))
+}
+
+func TestIntFromFloat(t *testing.T) {
+	num, err := cr.Int(2.0, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 2, num)
+
+	_, err = cr.Int(2.5, &cr.IntValidation{})
+	require.EqualError(t, err, s.ErrExpectedInteger(2.5))
+
+	_, err = cr.IntFromInterfaceMap("replicas", map[string]interface{}{"replicas": 2.7}, &cr.IntValidation{})
+	require.EqualError(t, err, "replicas: expected an integer; got 2.7")
+
+	_, err = cr.Int(1e20, &cr.IntValidation{})
+	require.EqualError(t, err, s.ErrIntOutOfRange(1e20, 64))
+
+	_, err = cr.Int(math.NaN(), &cr.IntValidation{})
+	require.EqualError(t, err, s.ErrExpectedInteger(math.NaN()))
+
+	num, err = cr.Int(2.7, &cr.IntValidation{AllowTruncation: true})
+	require.NoError(t, err)
+	require.Equal(t, 2, num)
+
+	num, err = cr.Int(-2.7, &cr.IntValidation{AllowTruncation: true})
+	require.NoError(t, err)
+	require.Equal(t, -2, num)
+
+	_, err = cr.Int(1e20, &cr.IntValidation{AllowTruncation: true})
+	require.EqualError(t, err, s.ErrIntOutOfRange(1e20, 64))
+
+	num32, err := cr.Int32(float32(3), &cr.Int32Validation{})
+	require.NoError(t, err)
+	require.Equal(t, int32(3), num32)
+
+	_, err = cr.Int32(3e9, &cr.Int32Validation{})
+	require.EqualError(t, err, s.ErrIntOutOfRange(3e9, 32))
+
+	num64, err := cr.Int64(json.Number("9007199254740993.0"), &cr.Int64Validation{})
+	require.NoError(t, err)
+	require.Equal(t, int64(9007199254740993), num64)
+
+	_, err = cr.Int64(json.Number("2.5"), &cr.Int64Validation{})
+	require.EqualError(t, err, s.ErrExpectedInteger(json.Number("2.5")))
+
+	ptr, err := cr.IntPtr(4.0, &cr.IntPtrValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 4, *ptr)
+
+	_, err = cr.Int("2", &cr.IntValidation{})
+	var invalidTypeErr *cr.InvalidTypeError
+	require.True(t, errors.As(err, &invalidTypeErr))
+}
+
+func TestBoolExtended(t *testing.T) {
+	iMap := map[string]interface{}{"str": "TRUE", "one": 1, "zero": 0, "two": 2}
+
+	_, err := cr.BoolFromInterfaceMap("str", iMap, &cr.BoolValidation{})
+	require.Error(t, err)
+	_, err = cr.BoolFromInterfaceMap("one", iMap, &cr.BoolValidation{})
+	require.Error(t, err)
+
+	val, err := cr.BoolFromInterfaceMap("str", iMap, &cr.BoolValidation{Extended: true})
+	require.NoError(t, err)
+	require.True(t, val)
+	val, err = cr.BoolFromInterfaceMap("one", iMap, &cr.BoolValidation{Extended: true})
+	require.NoError(t, err)
+	require.True(t, val)
+	val, err = cr.BoolFromInterfaceMap("zero", iMap, &cr.BoolValidation{Extended: true})
+	require.NoError(t, err)
+	require.False(t, val)
+
+	_, err = cr.BoolFromInterfaceMap("two", iMap, &cr.BoolValidation{Extended: true})
+	var invalidTypeErr *cr.InvalidTypeError
+	require.True(t, errors.As(err, &invalidTypeErr))
+
+	ptr, err := cr.BoolPtrFromInterfaceMap("str", iMap, &cr.BoolPtrValidation{Extended: true})
+	require.NoError(t, err)
+	require.True(t, *ptr)
+}
+
+func TestStringEncodingChecks(t *testing.T) {
+	v := &cr.StringValidation{RequireValidUTF8: true}
+
+	val, err := cr.StringFromStr("héllo, 世界", v)
+	require.NoError(t, err)
+	require.Equal(t, "héllo, 世界", val)
+
+	_, err = cr.StringFromStr("ab\xffcd", v)
+	require.EqualError(t, err, s.ErrInvalidUTF8(2))
+
+	_, err = cr.StringFromStr("é\xc3", v) // truncated multi-byte sequence
+	require.EqualError(t, err, s.ErrInvalidUTF8(2))
+
+	// an encoded U+FFFD is valid
+	_, err = cr.StringFromStr("a�b", v)
+	require.NoError(t, err)
+
+	v = &cr.StringValidation{DisallowControlChars: true}
+
+	_, err = cr.StringFromStr("abc\x00def", v)
+	require.EqualError(t, err, s.ErrControlChar(0, 3))
+
+	_, err = cr.StringFromStr("a\x1bb", v)
+	require.EqualError(t, err, s.ErrControlChar(0x1b, 1))
+
+	_, err = cr.StringFromStr("line 1\tline 2", v)
+	require.EqualError(t, err, s.ErrControlChar('\t', 6))
+
+	v.AllowedControlChars = []rune{'\t', '\n'}
+	_, err = cr.StringFromStr("line 1\tcol 2\nline 2", v)
+	require.NoError(t, err)
+
+	_, err = cr.StringFromStr("line 1\r\nline 2", v)
+	require.EqualError(t, err, s.ErrControlChar('\r', 6))
+}
+
+func TestFromInterfaceMapWithRefs(t *testing.T) {
+	iMap := map[string]interface{}{
+		"default_timeout": 30,
+		"timeout":         "${default_timeout}",
+		"retry_timeout":   "${ timeout }",
+		"negative":        "${minus_one}",
+		"minus_one":       -1,
+		"a":               "${b}",
+		"b":               "${c}",
+		"c":               "${a}",
+		"self":            "${self}",
+		"undefined":       "${nope}",
+		"name":            "api",
+		"full_name":       "${name}",
+		"literal":         "prefix ${name}",
+		"enabled":         "${debug}",
+		"debug":           true,
+		"ratio":           "${half}",
+		"half":            0.5,
+	}
+
+	val, err := cr.IntFromInterfaceMapWithRefs("timeout", iMap, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 30, val)
+
+	val, err = cr.IntFromInterfaceMapWithRefs("retry_timeout", iMap, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 30, val)
+
+	val, err = cr.IntFromInterfaceMapWithRefs("missing", iMap, &cr.IntValidation{Default: 5})
+	require.NoError(t, err)
+	require.Equal(t, 5, val)
+
+	// the resolved value is validated
+	_, err = cr.IntFromInterfaceMapWithRefs("negative", iMap, &cr.IntValidation{GreaterThan: util.IntPtr(0)})
+	require.Error(t, err)
+
+	_, err = cr.IntFromInterfaceMapWithRefs("a", iMap, &cr.IntValidation{})
+	require.EqualError(t, err, "a: "+s.ErrFieldRefCycle([]string{"a", "b", "c", "a"}))
+
+	_, err = cr.IntFromInterfaceMapWithRefs("self", iMap, &cr.IntValidation{})
+	require.EqualError(t, err, "self: "+s.ErrFieldRefCycle([]string{"self", "self"}))
+
+	_, err = cr.IntFromInterfaceMapWithRefs("undefined", iMap, &cr.IntValidation{})
+	require.EqualError(t, err, "undefined: "+s.ErrUndefinedFieldRef("nope"))
+
+	str, err := cr.StringFromInterfaceMapWithRefs("full_name", iMap, &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "api", str)
+
+	// only whole values are references
+	str, err = cr.StringFromInterfaceMapWithRefs("literal", iMap, &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "prefix ${name}", str)
+
+	enabled, err := cr.BoolFromInterfaceMapWithRefs("enabled", iMap, &cr.BoolValidation{})
+	require.NoError(t, err)
+	require.True(t, enabled)
+
+	ratio, err := cr.Float64FromInterfaceMapWithRefs("ratio", iMap, &cr.Float64Validation{})
+	require.NoError(t, err)
+	require.Equal(t, 0.5, ratio)
+}
+
+func TestIntExponentNotation(t *testing.T) {
+	v := &cr.IntValidation{AllowExponentNotation: true}
+
+	for valStr, expected := range map[string]int{
+		"1e6":                      1000000,
+		"1E6":                      1000000,
+		"2.5e3":                    2500,
+		"-2.5e3":                   -2500,
+		"+1e0":                     1,
+		".5e1":                     5,
+		"12500e-2":                 125,
+		"0e999999999":              0,
+		"42":                       42,
+		"9.223372036854775807e18":  math.MaxInt64,
+		"-9.223372036854775808e18": math.MinInt64,
+	} {
+		val, err := cr.IntFromStr(valStr, v)
+		require.NoError(t, err, valStr)
+		require.Equal(t, expected, val, valStr)
+	}
+
+	_, err := cr.IntFromStr("1e19", v)
+	require.EqualError(t, err, s.ErrIntOutOfRange("1e19", 64))
+	_, err = cr.IntFromStr("9.223372036854775808e18", v)
+	require.EqualError(t, err, s.ErrIntOutOfRange("9.223372036854775808e18", 64))
+	_, err = cr.IntFromStr("1e999999999", v)
+	require.EqualError(t, err, s.ErrIntOutOfRange("1e999999999", 64))
+
+	_, err = cr.IntFromStr("2.5e0", v)
+	require.EqualError(t, err, s.ErrExpectedInteger("2.5e0"))
+	_, err = cr.IntFromStr("1e-999999999", v)
+	require.EqualError(t, err, s.ErrExpectedInteger("1e-999999999"))
+
+	_, err = cr.IntFromStr("1e", v)
+	require.EqualError(t, err, s.ErrInvalidIntOrExponent("1e"))
+	_, err = cr.IntFromStr("2.5", v)
+	require.EqualError(t, err, s.ErrInvalidIntOrExponent("2.5"))
+
+	// off by default
+	_, err = cr.IntFromStr("1e6", &cr.IntValidation{})
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType("1e6", s.PrimTypeInt))
+
+	// validations apply to the result
+	_, err = cr.IntFromStr("1e6", &cr.IntValidation{AllowExponentNotation: true, LessThan: util.IntPtr(1000)})
+	require.Error(t, err)
+}
+
+func TestListFromInterfaceMapWithCount(t *testing.T) {
+	iMap := map[string]interface{}{
+		"num_shards":    4,
+		"shard_weights": []interface{}{0.1, 0.2, 0.3, 0.4},
+		"shard_names":   []interface{}{"a", "b", "c"},
+		"shard_sizes":   []interface{}{1, 2, 3, 4},
+		"bad_count":     "four",
+	}
+
+	weights, err := cr.Float64ListFromInterfaceMapWithCount("shard_weights", "num_shards", iMap, &cr.Float64ListValidation{}, &cr.IntPtrValidation{})
+	require.NoError(t, err)
+	require.Equal(t, []float64{0.1, 0.2, 0.3, 0.4}, weights)
+
+	sizes, err := cr.IntListFromInterfaceMapWithCount("shard_sizes", "num_shards", iMap, &cr.IntListValidation{}, &cr.IntPtrValidation{})
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3, 4}, sizes)
+
+	_, err = cr.StringListFromInterfaceMapWithCount("shard_names", "num_shards", iMap, &cr.StringListValidation{}, &cr.IntPtrValidation{})
+	require.EqualError(t, err, "shard_names: "+s.ErrListLengthMismatch(3, "num_shards", 4))
+	require.EqualError(t, err, "shard_names: must contain 4 elements, to match num_shards (got 3)")
+
+	// a defaulted count
+	names, err := cr.StringListFromInterfaceMapWithCount("shard_names", "num_replicas", iMap, &cr.StringListValidation{}, &cr.IntPtrValidation{Default: util.IntPtr(3)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, names)
+
+	_, err = cr.IntListFromInterfaceMapWithCount("shard_sizes", "num_replicas", iMap, &cr.IntListValidation{}, &cr.IntPtrValidation{Default: util.IntPtr(1)})
+	require.EqualError(t, err, "shard_sizes: "+s.ErrListLengthMismatch(4, "num_replicas", 1))
+
+	// an absent count isn't checked, unless it's required
+	names, err = cr.StringListFromInterfaceMapWithCount("shard_names", "num_replicas", iMap, &cr.StringListValidation{}, &cr.IntPtrValidation{})
+	require.NoError(t, err)
+	require.Len(t, names, 3)
+
+	_, err = cr.StringListFromInterfaceMapWithCount("shard_names", "num_replicas", iMap, &cr.StringListValidation{}, &cr.IntPtrValidation{Required: true})
+	require.Error(t, err)
+
+	_, err = cr.IntListFromInterfaceMapWithCount("shard_sizes", "bad_count", iMap, &cr.IntListValidation{}, &cr.IntPtrValidation{})
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(err.Error(), "bad_count: "))
+}
+
+type ShardID string
+
+type ShardConfig struct {
+	ID ShardID `json:"id"`
+}
+
+func TestStructWithRegisteredConverter(t *testing.T) {
+	defer cast.ResetConvertersForTest()
+	require.NoError(t, cast.RegisterConverter(reflect.TypeOf(ShardID("")), func(in interface{}) (interface{}, bool) {
+		if str, ok := in.(string); ok {
+			return ShardID(str), true
+		}
+		return nil, false
+	}))
+
+	validation := &cr.StructValidation{
+		StructFieldValidations: []*cr.StructFieldValidation{
+			{StructField: "ID", StringValidation: &cr.StringValidation{}},
+		},
+	}
+	config := &ShardConfig{}
+	errs := cr.Struct(config, map[string]interface{}{"id": "shard-1"}, validation)
+	require.Empty(t, errs)
+	require.Equal(t, ShardID("shard-1"), config.ID)
+}
+
+func TestGlob(t *testing.T) {
+	for _, pattern := range []string{"data/*.csv", "data/[a-c]?.csv", `data/\[1\].csv`, "[^a-z]*", "a]b", `[\]]`} {
+		val, err := cr.GlobFromStr(pattern, &cr.GlobValidation{})
+		require.NoError(t, err, pattern)
+		require.Equal(t, pattern, val)
+	}
+
+	for pattern, expected := range map[string]string{
+		"data/[a-c.csv": s.ErrInvalidGlob("data/[a-c.csv", 5, "unterminated ["),
+		"data/*.csv[":   s.ErrInvalidGlob("data/*.csv[", 10, "unterminated ["),
+		"[]a]":          s.ErrInvalidGlob("[]a]", 1, "unexpected ]"),
+		"[a-]":          s.ErrInvalidGlob("[a-]", 3, "unexpected ]"),
+		"[-a]":          s.ErrInvalidGlob("[-a]", 1, "unexpected -"),
+		`data\`:         s.ErrInvalidGlob(`data\`, 4, `trailing \`),
+		`[a\`:           s.ErrInvalidGlob(`[a\`, 2, `trailing \`),
+	} {
+		_, err := cr.GlobFromStr(pattern, &cr.GlobValidation{})
+		require.EqualError(t, err, expected, pattern)
+	}
+
+	dir, err := ioutil.TempDir("", "cr-test-glob")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.csv"), []byte("a"), 0644))
+
+	pattern := filepath.Join(dir, "*.csv")
+	val, err := cr.GlobFromInterfaceMap("files", map[string]interface{}{"files": pattern}, &cr.GlobValidation{RequireMatch: true})
+	require.NoError(t, err)
+	require.Equal(t, pattern, val)
+
+	pattern = filepath.Join(dir, "*.json")
+	_, err = cr.GlobFromInterfaceMap("files", map[string]interface{}{"files": pattern}, &cr.GlobValidation{RequireMatch: true})
+	require.EqualError(t, err, "files: "+s.ErrGlobNoMatches(pattern))
+
+	os.Setenv("CR_TEST_GLOB", "data/[")
+	defer os.Unsetenv("CR_TEST_GLOB")
+	_, err = cr.GlobFromEnv("CR_TEST_GLOB", &cr.GlobValidation{})
+	require.EqualError(t, err, "CR_TEST_GLOB: "+s.ErrInvalidGlob("data/[", 5, "unterminated [")+s.FromSource(s.EnvVar("CR_TEST_GLOB")))
+}
+
+func TestNilPointers(t *testing.T) {
+	i := 4
+	var nilInt *int
+	iMap := map[string]interface{}{"nil": nilInt, "ptr": &i}
+
+	val, err := cr.IntFromInterfaceMap("ptr", iMap, &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 4, val)
+
+	_, err = cr.IntFromInterfaceMap("nil", iMap, &cr.IntValidation{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), s.ErrCannotBeNull)
+
+	ptrVal, err := cr.IntPtrFromInterfaceMap("nil", iMap, &cr.IntPtrValidation{})
+	require.NoError(t, err)
+	require.Nil(t, ptrVal)
+
+	ptrVal, err = cr.IntPtrFromInterfaceMap("ptr", iMap, &cr.IntPtrValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 4, *ptrVal)
+
+	_, err = cr.IntPtrFromInterfaceMap("nil", iMap, &cr.IntPtrValidation{DisallowNull: true})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), s.ErrCannotBeNull)
+}
+
+func TestStringListCSVQuoting(t *testing.T) {
+	v := &cr.StringListValidation{CSVQuoting: true}
+
+	strs, err := cr.StringListFromStr(`"a,b","c"`, v)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a,b", "c"}, strs)
+
+	strs, err = cr.StringListFromStr(` a ,  "b, c",d  `, v)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b, c", "d"}, strs)
+
+	strs, err = cr.StringListFromStr(`"say ""hi""",plain`, v)
+	require.NoError(t, err)
+	require.Equal(t, []string{`say "hi"`, "plain"}, strs)
+
+	strs, err = cr.StringListFromStr(`a,,b`, v)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "", "b"}, strs)
+
+	strs, err = cr.StringListFromStr("   ", &cr.StringListValidation{CSVQuoting: true, AllowEmpty: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{}, strs)
+
+	strs, err = cr.StringListFromStr(`"a;b";c`, &cr.StringListValidation{CSVQuoting: true, Delimiter: ";"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a;b", "c"}, strs)
+
+	strs, err = cr.StringListFromStr(`"a,b","c"`, &cr.StringListValidation{})
+	require.NoError(t, err)
+	require.Equal(t, []string{`"a`, `b"`, `"c"`}, strs)
+
+	_, err = cr.StringListFromStr(`"a,b`, v)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not a valid list")
+
+	_, err = cr.StringListFromStr(`a"b,c`, v)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not a valid list")
+
+	_, err = cr.StringListFromStr(`"a" ,b`, v)
+	require.Error(t, err)
+
+	_, err = cr.StringListFromStr("a,b\nc", v)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "single line")
+
+	_, err = cr.StringListFromStr("a b", &cr.StringListValidation{CSVQuoting: true, Delimiter: cr.WhitespaceDelimiter})
+	require.Error(t, err)
+
+	_, err = cr.StringListFromStr(`"a,b",a,b`, &cr.StringListValidation{CSVQuoting: true, DisallowDups: true})
+	require.NoError(t, err)
+	_, err = cr.StringListFromStr(`"a",a`, &cr.StringListValidation{CSVQuoting: true, DisallowDups: true})
+	require.Error(t, err)
+
+	os.Setenv("CR_TEST_ALLOWLIST", `"x,y", z`)
+	defer os.Unsetenv("CR_TEST_ALLOWLIST")
+	strs, err = cr.StringListFromEnv("CR_TEST_ALLOWLIST", v)
+	require.NoError(t, err)
+	require.Equal(t, []string{"x,y", "z"}, strs)
+}
+
+func TestLenientNumbers(t *testing.T) {
+	v := &cr.IntValidation{Lenient: true}
+	for str, expected := range map[string]int{"+5": 5, " 42 ": 42, "1,000": 1000, "1_000_000": 1000000, " -2,000 ": -2000} {
+		val, err := cr.IntFromStr(str, v)
+		require.NoError(t, err)
+		require.Equal(t, expected, val)
+	}
+	for _, str := range []string{"1,00", "1_00", "1,000_000"} {
+		_, err := cr.IntFromStr(str, v)
+		require.EqualError(t, err, s.ErrMisplacedGroupingSeparator(str))
+	}
+	_, err := cr.IntFromStr("4 2", v)
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType("4 2", s.PrimTypeInt))
+	_, err = cr.IntFromStr(" 42 ", &cr.IntValidation{})
+	require.Error(t, err)
+
+	val, err := cr.IntFromStr("  ", &cr.IntValidation{Lenient: true, Default: 3})
+	require.NoError(t, err)
+	require.Equal(t, 3, val)
+
+	val64, err := cr.Int64FromStr(" 9_000_000_000 ", &cr.Int64Validation{Lenient: true})
+	require.NoError(t, err)
+	require.Equal(t, int64(9000000000), val64)
+	_, err = cr.Int64FromStr(" 9_000_000_000 ", &cr.Int64Validation{})
+	require.Error(t, err)
+
+	floatVal, err := cr.Float64FromStr(" 1.5\n", &cr.Float64Validation{Lenient: true})
+	require.NoError(t, err)
+	require.Equal(t, 1.5, floatVal)
+	_, err = cr.Float64FromStr(" 1.5\n", &cr.Float64Validation{})
+	require.Error(t, err)
+	_, err = cr.Float64FromStr(" +1.5 ", &cr.Float64Validation{Lenient: true, DisallowExplicitPlus: true})
+	require.Error(t, err)
+}
+
+func TestIntUnlimited(t *testing.T) {
+	v := &cr.IntValidation{
+		UnlimitedTokens:      []string{"unlimited", "-1"},
+		UnlimitedValue:       math.MaxInt32,
+		GreaterThanOrEqualTo: util.IntPtr(1),
+		LessThanOrEqualTo:    util.IntPtr(100),
+	}
+
+	for _, inter := range []interface{}{"unlimited", "Unlimited", " UNLIMITED ", "-1", -1, int64(-1)} {
+		val, err := cr.Int(inter, v)
+		require.NoError(t, err)
+		require.Equal(t, math.MaxInt32, val)
+	}
+	for _, str := range []string{"unlimited", "-1", " -1 "} {
+		val, err := cr.IntFromStr(str, v)
+		require.NoError(t, err)
+		require.Equal(t, math.MaxInt32, val)
+	}
+
+	val, err := cr.IntFromInterfaceMap("max_replicas", map[string]interface{}{"max_replicas": 5}, v)
+	require.NoError(t, err)
+	require.Equal(t, 5, val)
+
+	val, err = cr.IntFromInterfaceMap("max_replicas", map[string]interface{}{}, &cr.IntValidation{UnlimitedTokens: []string{"unlimited"}, UnlimitedValue: -1, Default: -1, GreaterThan: util.IntPtr(0)})
+	require.NoError(t, err)
+	require.Equal(t, -1, val)
+
+	_, err = cr.Int(-2, v)
+	require.Error(t, err)
+	_, err = cr.Int(101, v)
+	require.Error(t, err)
+
+	_, err = cr.Int("infinite", v)
+	require.EqualError(t, err, s.ErrInvalidIntOrKeyword("infinite", v.UnlimitedTokens))
+	_, err = cr.IntFromStr("infinite", v)
+	require.EqualError(t, err, s.ErrInvalidIntOrKeyword("infinite", v.UnlimitedTokens))
+	require.Contains(t, err.Error(), "integer")
+	require.Contains(t, err.Error(), "unlimited")
+
+	_, err = cr.IntFromStr("unlimited", &cr.IntValidation{})
+	require.Error(t, err)
+	_, err = cr.Int(-1, &cr.IntValidation{UnlimitedTokens: []string{"unlimited"}, GreaterThanOrEqualTo: util.IntPtr(0)})
+	require.Error(t, err)
+
+	// only the tokens skip validation, not numbers which are equal to UnlimitedValue
+	_, err = cr.Int(math.MaxInt32, v)
+	require.Error(t, err)
+	_, err = cr.IntFromStr("2147483647", v)
+	require.Error(t, err)
+	_, err = cr.IntFromInterfaceMap("max_replicas", map[string]interface{}{"max_replicas": math.MaxInt32}, v)
+	require.Error(t, err)
+	_, err = cr.IntFromStr("0", &cr.IntValidation{UnlimitedTokens: []string{"unlimited"}, GreaterThan: util.IntPtr(0)})
+	require.Error(t, err)
+	_, err = cr.Int(0, &cr.IntValidation{UnlimitedTokens: []string{"unlimited"}, GreaterThan: util.IntPtr(0)})
+	require.Error(t, err)
+	val, err = cr.IntFromStr("unlimited", &cr.IntValidation{UnlimitedTokens: []string{"unlimited"}, GreaterThan: util.IntPtr(0)})
+	require.NoError(t, err)
+	require.Equal(t, 0, val)
+}
+
+func TestGoIdentAndK8sResourceName(t *testing.T) {
+	for _, val := range []string{"a", "_", "_private", "MyType", "snake_case_2", "x1", "string", "nil"} {
+		validated, err := cr.StringFromStr(val, cr.Presets.GoIdent())
+		require.NoError(t, err, val)
+		require.Equal(t, val, validated)
+	}
+	for _, val := range []string{"1abc", "my-name", "my.name", "has space", "ünïcode", "a$b"} {
+		_, err := cr.StringFromStr(val, cr.Presets.GoIdent())
+		require.EqualError(t, err, s.ErrInvalidGoIdent(val), val)
+		require.Contains(t, err.Error(), "must be a valid identifier: ")
+	}
+	for _, val := range []string{"func", "type", "range", "package"} {
+		_, err := cr.StringFromStr(val, cr.Presets.GoIdent())
+		require.EqualError(t, err, s.ErrGoKeyword(val), val)
+	}
+
+	// K8sResourceName is an alias for K8sSubdomain
+	require.Equal(t, cr.Presets.K8sSubdomain(), cr.Presets.K8sResourceName())
+	for _, val := range []string{"my-api", "my.config.map", "0abc"} {
+		_, err := cr.StringFromStr(val, cr.Presets.K8sResourceName())
+		require.NoError(t, err, val)
+	}
+	for _, val := range []string{"My-API", "my_api", "-api", "api-", strings.Repeat("a", 254)} {
+		_, err := cr.StringFromStr(val, cr.Presets.K8sResourceName())
+		require.Error(t, err, val)
+		_, subdomainErr := cr.StringFromStr(val, cr.Presets.K8sSubdomain())
+		require.EqualError(t, err, subdomainErr.Error(), val)
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"sync"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+)
+
+// ValueSource is where a value in a Report came from
+type ValueSource string
+
+const (
+	ValueSourceEnv     ValueSource = "env"
+	ValueSourceFile    ValueSource = "file"
+	ValueSourcePrompt  ValueSource = "prompt"
+	ValueSourceConfig  ValueSource = "config" // a key in a config map (e.g. from a YAML file)
+	ValueSourceRef     ValueSource = "ref"    // read with ReadRef
+	ValueSourceLookup  ValueSource = "lookup" // a key from a Source
+	ValueSourceArg     ValueSource = "arg"    // a command line argument
+	ValueSourceDefault ValueSource = "default"
+)
+
+// ReportEntry is one value which was read
+type ReportEntry struct {
+	Key      string      `json:"key"`                // the env var name, file path, prompt, or config key
+	Source   ValueSource `json:"source"`             // ValueSourceDefault if the value was missing
+	Location string      `json:"location,omitempty"` // e.g. `environment variable "MAX_WORKERS"` or the file path
+	Value    interface{} `json:"value"`              // the final (validated) value; s.Redacted() for sensitive values
+	Warnings []string    `json:"warnings,omitempty"`
+}
+
+// Report records the values which are read by readers whose validation has Report set (e.g. for an "effective config" command).
+// A nil *Report records nothing
+type Report struct {
+	mutex   sync.Mutex
+	entries []ReportEntry
+}
+
+// Entries returns the recorded values, in the order in which they were read
+func (report *Report) Entries() []ReportEntry {
+	report.mutex.Lock()
+	defer report.mutex.Unlock()
+	entries := make([]ReportEntry, len(report.entries))
+	copy(entries, report.entries)
+	return entries
+}
+
+// Entry returns the most recent entry for key
+func (report *Report) Entry(key string) (ReportEntry, bool) {
+	report.mutex.Lock()
+	defer report.mutex.Unlock()
+	for i := len(report.entries) - 1; i >= 0; i-- {
+		if report.entries[i].Key == key {
+			return report.entries[i], true
+		}
+	}
+	return ReportEntry{}, false
+}
+
+func (report *Report) record(key string, source ValueSource, location string, val interface{}, warnings ...string) {
+	if report == nil {
+		return
+	}
+	report.mutex.Lock()
+	defer report.mutex.Unlock()
+	report.entries = append(report.entries, ReportEntry{
+		Key:      key,
+		Source:   source,
+		Location: location,
+		Value:    val,
+		Warnings: warnings,
+	})
+}
+
+// redactIf returns val, or a placeholder which only includes its length if it's sensitive (nil is returned as-is)
+func redactIf(val interface{}, sensitive bool) interface{} {
+	if sensitive && val != nil {
+		return s.Redacted(val)
+	}
+	return val
+}
+
+// promptSource returns ValueSourceDefault if valStr is empty or is the default (which is used when nothing is entered)
+func promptSource(valStr string, opts *PromptOptions) ValueSource {
+	if valStr == "" || valStr == opts.defaultStr {
+		return ValueSourceDefault
+	}
+	return ValueSourcePrompt
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"reflect"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// SectionReader reads one section of a config (e.g. a node pool) into a typed value (e.g. a PoolConfig)
+type SectionReader func(iMap map[string]interface{}) (interface{}, error)
+
+// SectionList reads each element of inter (a list of maps) with readSection, and appends the results to dest, which must be
+// a pointer to a slice of the type returned by readSection (e.g. *[]PoolConfig). Every element is read, and any errors are
+// returned together as an *ErrorCollector, with each one wrapped with its element's index
+func SectionList(dest interface{}, inter interface{}, readSection SectionReader) error {
+	interSlice, ok := cast.InterfaceToInterfaceSlice(inter)
+	if !ok {
+		return errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeMapList}})
+	}
+
+	destSlice := reflect.ValueOf(dest).Elem()
+	ec := &ErrorCollector{}
+	for i, interItem := range interSlice {
+		iMap, ok := cast.InterfaceToStrInterfaceMap(interItem)
+		if !ok {
+			ec.Add(errors.WrapIndex(errors.Wrap(&InvalidTypeError{Provided: interItem, Expected: []s.PrimitiveType{s.PrimTypeMap}}), i))
+			continue
+		}
+
+		val, err := readSection(iMap)
+		if collected, ok := err.(*ErrorCollector); ok {
+			ec.AddAll(errors.WrapIndexMultiple(collected.Errors(), i))
+			continue
+		}
+		if ec.Add(errors.WrapIndex(err, i)) {
+			continue
+		}
+		destSlice.Set(reflect.Append(destSlice, reflect.ValueOf(val)))
+	}
+
+	return ec.Err()
+}
+
+// SectionListFromInterfaceMap is the same as SectionList(dest, iMap[key], readSection), but with errors wrapped with key.
+// If key is missing, dest isn't modified
+func SectionListFromInterfaceMap(key string, iMap map[string]interface{}, dest interface{}, readSection SectionReader) error {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		return nil
+	}
+	err := SectionList(dest, inter, readSection)
+	if collected, ok := err.(*ErrorCollector); ok {
+		ec := &ErrorCollector{}
+		ec.AddAll(errors.WrapKeyMultiple(collected.Errors(), key))
+		return ec.Err()
+	}
+	return errors.WrapKey(err, key)
+}
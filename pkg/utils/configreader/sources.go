@@ -0,0 +1,203 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// SourceFunc looks up the value for a ref (the part after "<scheme>://"). nil is returned if the value does not exist
+type SourceFunc func(ref string) (*string, error)
+
+var (
+	sourceSchemesMutex sync.RWMutex
+	sourceSchemes      = map[string]SourceFunc{
+		"env":     readEnvSource,
+		"file":    readFileSource,
+		"literal": readLiteralSource,
+	}
+)
+
+func readEnvSource(envVarName string) (*string, error) {
+	return ReadEnvVar(envVarName), nil
+}
+
+func readFileSource(filePath string) (*string, error) {
+	return readValueFile(filePath, 0, false, true)
+}
+
+func readLiteralSource(val string) (*string, error) {
+	return &val, nil
+}
+
+// RegisterSourceScheme makes values from fn available to the *FromRef readers via "<scheme>://<ref>"
+func RegisterSourceScheme(scheme string, fn SourceFunc) error {
+	if scheme == "" || strings.Contains(scheme, "://") {
+		return errors.MarkInternal(errors.NewCoded(s.MsgInvalidSourceScheme, s.ErrInvalidSourceScheme(scheme)))
+	}
+
+	sourceSchemesMutex.Lock()
+	defer sourceSchemesMutex.Unlock()
+
+	if _, ok := sourceSchemes[scheme]; ok {
+		return errors.MarkInternal(errors.NewCoded(s.MsgDuplicateSourceScheme, s.ErrDuplicateSourceScheme(scheme)))
+	}
+	sourceSchemes[scheme] = fn
+	return nil
+}
+
+func RegisteredSourceSchemes() []string {
+	sourceSchemesMutex.RLock()
+	defer sourceSchemesMutex.RUnlock()
+
+	schemes := make([]string, 0, len(sourceSchemes))
+	for scheme := range sourceSchemes {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// ReadRef looks up a value from a registered source (e.g. "env://MAX_WORKERS").
+// nil is returned if the source does not have the value
+func ReadRef(ref string) (*string, error) {
+	split := strings.SplitN(ref, "://", 2)
+	if len(split) != 2 {
+		return nil, errors.NewUserCoded(s.MsgInvalidSourceRef, s.ErrInvalidSourceRef(ref))
+	}
+	scheme, key := split[0], split[1]
+
+	sourceSchemesMutex.RLock()
+	fn, ok := sourceSchemes[scheme]
+	sourceSchemesMutex.RUnlock()
+	if !ok {
+		return nil, errors.NewUserCoded(s.MsgUnknownSourceScheme, s.ErrUnknownSourceScheme(scheme, RegisteredSourceSchemes()))
+	}
+
+	// e.g. a timeout from a remote source (errors which the source marked as user errors are kept as-is)
+	valStr, err := fn(key)
+	if err != nil {
+		return nil, errors.MarkInternal(err)
+	}
+	return valStr, nil
+}
+
+// Source is a backend which values can be looked up from by key (see e.g. IntFromSource())
+type Source interface {
+	// Lookup returns the value for key, and whether it was found
+	Lookup(key string) (string, bool, error)
+}
+
+// SourceDescriber can be implemented by a Source to say where a key's value comes from in errors (see errors.WithSource())
+type SourceDescriber interface {
+	Describe(key string) string
+}
+
+func describeSource(source Source, key string) string {
+	if describer, ok := source.(SourceDescriber); ok {
+		return describer.Describe(key)
+	}
+	return ""
+}
+
+// envVarError adds the environment variable which a value came from to err,
+// e.g. `MAX_WORKERS: must be greater than 0 (from environment variable "MAX_WORKERS")`
+func envVarError(err error, envVarName string) error {
+	return errors.WrapKey(errors.WithSource(err, s.EnvVar(envVarName)), envVarName)
+}
+
+// valueFileError adds the file which a value came from to err. The file is shown as err's position
+// (e.g. "workers.txt:1: must be greater than 0"), so it isn't repeated at the end
+func valueFileError(err error, filePath string, line int) error {
+	return errors.WithSource(errors.WithPosition(err, errors.Position{File: filePath, Line: line}), filePath)
+}
+
+func (env *Env) Lookup(key string) (string, bool, error) {
+	valStr := env.ReadEnvVar(key)
+	if valStr == nil {
+		return "", false, nil
+	}
+	return *valStr, true, nil
+}
+
+func (env *Env) Describe(key string) string {
+	return s.EnvVar(key)
+}
+
+// EnvSource returns a Source which reads from DefaultEnv()
+func EnvSource() Source {
+	return DefaultEnv()
+}
+
+// FileSource reads each key from the file of that name in Dir (e.g. a mounted config map)
+type FileSource struct {
+	Dir string
+}
+
+func (source *FileSource) Lookup(key string) (string, bool, error) {
+	valStr, err := readValueFile(filepath.Join(source.Dir, key), 0, false, true)
+	if err != nil || valStr == nil {
+		return "", false, err
+	}
+	return *valStr, true, nil
+}
+
+func (source *FileSource) Describe(key string) string {
+	return filepath.Join(source.Dir, key)
+}
+
+type MapSource map[string]string
+
+func (source MapSource) Lookup(key string) (string, bool, error) {
+	val, ok := source[key]
+	return val, ok, nil
+}
+
+type chainedSource []Source
+
+// ChainSources returns a Source which returns the value from the first source which has the key
+func ChainSources(sources ...Source) Source {
+	return chainedSource(sources)
+}
+
+func (sources chainedSource) Lookup(key string) (string, bool, error) {
+	for _, source := range sources {
+		val, ok, err := source.Lookup(key)
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			return val, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+func (sources chainedSource) Describe(key string) string {
+	for _, source := range sources {
+		if _, ok, err := source.Lookup(key); err == nil && ok {
+			return describeSource(source, key)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+type failingSource struct{}
+
+func (failingSource) Lookup(key string) (string, bool, error) {
+	return "", false, errors.New("connection refused")
+}
+
+func TestFromSource(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(tmpDir, "workers"), []byte("4\n"), 0644)
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(tmpDir, "name"), []byte("from-file\n"), 0644)
+	require.NoError(t, err)
+
+	os.Setenv("CR_TEST_SOURCE_NAME", "from-env")
+	defer os.Unsetenv("CR_TEST_SOURCE_NAME")
+
+	fileSource := &cr.FileSource{Dir: tmpDir}
+	mapSource := cr.MapSource{"workers": "8", "ratio": "0.5"}
+
+	num, err := cr.IntFromSource(fileSource, "workers", &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 4, num)
+
+	num, err = cr.IntFromSource(mapSource, "workers", &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 8, num)
+
+	str, err := cr.StringFromSource(cr.EnvSource(), "CR_TEST_SOURCE_NAME", &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "from-env", str)
+
+	num, err = cr.IntFromSource(mapSource, "missing", &cr.IntValidation{Default: 2})
+	require.NoError(t, err)
+	require.Equal(t, 2, num)
+
+	_, err = cr.IntFromSource(mapSource, "missing", &cr.IntValidation{Required: true})
+	require.EqualError(t, err, "missing: "+s.ErrMustBeDefined)
+
+	_, err = cr.IntFromSource(mapSource, "ratio", &cr.IntValidation{})
+	require.EqualError(t, err, "ratio: "+s.ErrInvalidPrimitiveType("0.5", s.PrimTypeInt))
+
+	_, err = cr.IntFromSource(failingSource{}, "workers", &cr.IntValidation{})
+	require.EqualError(t, err, "workers: connection refused")
+
+	chained := cr.ChainSources(cr.MapSource{"name": "from-map"}, fileSource, mapSource)
+	require.Equal(t, "from-map", cr.MustStringFromSource(chained, "name", &cr.StringValidation{}))
+	require.Equal(t, 4, cr.MustIntFromSource(chained, "workers", &cr.IntValidation{}))
+	require.Equal(t, 0.5, cr.MustFloat64FromSource(chained, "ratio", &cr.Float64Validation{}))
+
+	_, err = cr.IntFromSource(cr.ChainSources(mapSource, failingSource{}), "missing", &cr.IntValidation{})
+	require.EqualError(t, err, "missing: connection refused")
+}
+
+func TestFromSourceErrorSources(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(tmpDir, "workers"), []byte("0\n"), 0644)
+	require.NoError(t, err)
+
+	os.Setenv("CR_TEST_MAX_WORKERS", "0")
+	defer os.Unsetenv("CR_TEST_MAX_WORKERS")
+
+	intValidation := &cr.IntValidation{GreaterThan: util.IntPtr(0)}
+
+	_, err = cr.IntFromSource(cr.EnvSource(), "CR_TEST_MAX_WORKERS", intValidation)
+	require.EqualError(t, err, "CR_TEST_MAX_WORKERS: "+s.ErrMustBeGreaterThan(0, 0)+s.FromSource(s.EnvVar("CR_TEST_MAX_WORKERS")))
+	require.Equal(t, s.EnvVar("CR_TEST_MAX_WORKERS"), errors.GetSource(err))
+
+	chained := cr.ChainSources(cr.MapSource{}, &cr.FileSource{Dir: tmpDir})
+	_, err = cr.IntFromSource(chained, "workers", intValidation)
+	require.EqualError(t, err, "workers: "+s.ErrMustBeGreaterThan(0, 0)+s.FromSource(filepath.Join(tmpDir, "workers")))
+
+	_, err = cr.IntFromSource(cr.MapSource{"workers": "0"}, "workers", intValidation)
+	require.EqualError(t, err, "workers: "+s.ErrMustBeGreaterThan(0, 0))
+
+	errors.SetShowSources(false)
+	_, err = cr.IntFromSource(chained, "workers", intValidation)
+	require.EqualError(t, err, "workers: "+s.ErrMustBeGreaterThan(0, 0))
+	errors.SetShowSources(true)
+
+	errs := errors.WithSourceMultiple([]error{errors.New("a"), errors.WithSource(errors.New("b"), "flag --b")}, "cluster.yaml")
+	require.EqualError(t, errs[0], "a (from cluster.yaml)")
+	require.EqualError(t, errs[1], "b (from flag --b)")
+	require.EqualError(t, errors.WrapKey(errs[0], "key"), "key: a (from cluster.yaml)")
+}
+
+func TestEnvAndFileErrorSources(t *testing.T) {
+	tmpDir, err := util.TmpDir()
+	defer os.RemoveAll(tmpDir)
+	require.NoError(t, err)
+	filePath := filepath.Join(tmpDir, "workers")
+	require.NoError(t, ioutil.WriteFile(filePath, []byte("0\n"), 0644))
+	configPath := filepath.Join(tmpDir, "cluster.yaml")
+	require.NoError(t, ioutil.WriteFile(configPath, []byte("workers: 0\n"), 0644))
+
+	os.Setenv("CR_TEST_SOURCE_WORKERS", "0")
+	defer os.Unsetenv("CR_TEST_SOURCE_WORKERS")
+
+	intValidation := &cr.IntValidation{GreaterThan: util.IntPtr(0)}
+
+	_, err = cr.IntFromEnv("CR_TEST_SOURCE_WORKERS", intValidation)
+	require.EqualError(t, err, "CR_TEST_SOURCE_WORKERS: "+s.ErrMustBeGreaterThan(0, 0)+s.FromSource(s.EnvVar("CR_TEST_SOURCE_WORKERS")))
+	require.Equal(t, s.EnvVar("CR_TEST_SOURCE_WORKERS"), errors.GetSource(err))
+
+	_, err = cr.StringFromEnv("CR_TEST_SOURCE_MISSING", &cr.StringValidation{Required: true})
+	require.EqualError(t, err, "CR_TEST_SOURCE_MISSING: "+s.ErrMustBeDefined+s.FromSource(s.EnvVar("CR_TEST_SOURCE_MISSING")))
+
+	// the file is the error's position, so it isn't repeated at the end
+	_, err = cr.IntFromFile(filePath, intValidation)
+	require.EqualError(t, err, filePath+":1: "+s.ErrMustBeGreaterThan(0, 0))
+	require.Equal(t, filePath, errors.GetSource(err))
+
+	missingPath := filepath.Join(tmpDir, "missing")
+	_, err = cr.IntFromFile(missingPath, &cr.IntValidation{Required: true})
+	require.EqualError(t, err, missingPath+": "+s.ErrMustBeDefined)
+	require.Equal(t, missingPath, errors.GetSource(err))
+
+	_, err = cr.IntFromConfigFile(configPath, "workers", intValidation)
+	require.EqualError(t, err, configPath+":1:1: workers: "+s.ErrMustBeGreaterThan(0, 0))
+	require.Equal(t, configPath, errors.GetSource(err))
+
+	errors.SetShowSources(false)
+	_, err = cr.IntFromEnv("CR_TEST_SOURCE_WORKERS", intValidation)
+	require.EqualError(t, err, "CR_TEST_SOURCE_WORKERS: "+s.ErrMustBeGreaterThan(0, 0))
+	errors.SetShowSources(true)
+}
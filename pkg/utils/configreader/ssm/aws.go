@@ -0,0 +1,52 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssm
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsssm "github.com/aws/aws-sdk-go/service/ssm"
+	"github.com/aws/aws-sdk-go/service/ssm/ssmiface"
+
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+type awsClient struct {
+	api ssmiface.SSMAPI
+}
+
+// NewClient wraps an AWS SSM client (e.g. ssm.New(session))
+func NewClient(api ssmiface.SSMAPI) Client {
+	return &awsClient{api: api}
+}
+
+func (client *awsClient) GetParameter(paramName string) (*string, error) {
+	output, err := client.api.GetParameter(&awsssm.GetParameterInput{
+		Name:           aws.String(paramName),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == awsssm.ErrCodeParameterNotFound {
+			return nil, nil
+		}
+		return nil, errors.MarkInternal(err)
+	}
+	if output.Parameter == nil {
+		return nil, nil
+	}
+	return output.Parameter.Value, nil
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssm
+
+import (
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// Client fetches parameters from SSM Parameter Store (use NewClient() to wrap an AWS SSM client)
+type Client interface {
+	// GetParameter returns the value of a parameter (decrypted if it is a SecureString), or nil if it does not exist
+	GetParameter(paramName string) (*string, error)
+}
+
+// Source returns a source which can be registered with configreader.RegisterSourceScheme()
+func Source(client Client) cr.SourceFunc {
+	return client.GetParameter
+}
+
+func StringFromSSM(client Client, paramName string, v *cr.StringValidation) (string, error) {
+	valStr, err := client.GetParameter(paramName)
+	if err != nil {
+		return "", errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	if valStr == nil {
+		val, err := cr.ValidateStringMissing(v)
+		if err != nil {
+			return "", errors.Wrap(err, s.SSMParameter(paramName))
+		}
+		return val, nil
+	}
+	val, err := cr.StringFromStr(*valStr, v)
+	if err != nil {
+		return "", errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	return val, nil
+}
+
+func BoolFromSSM(client Client, paramName string, v *cr.BoolValidation) (bool, error) {
+	valStr, err := client.GetParameter(paramName)
+	if err != nil {
+		return false, errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	if valStr == nil || *valStr == "" {
+		val, err := cr.ValidateBoolMissing(v)
+		if err != nil {
+			return false, errors.Wrap(err, s.SSMParameter(paramName))
+		}
+		return val, nil
+	}
+	val, err := cr.BoolFromStr(*valStr, v)
+	if err != nil {
+		return false, errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	return val, nil
+}
+
+func IntFromSSM(client Client, paramName string, v *cr.IntValidation) (int, error) {
+	valStr, err := client.GetParameter(paramName)
+	if err != nil {
+		return 0, errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	if valStr == nil || *valStr == "" {
+		val, err := cr.ValidateIntMissing(v)
+		if err != nil {
+			return 0, errors.Wrap(err, s.SSMParameter(paramName))
+		}
+		return val, nil
+	}
+	val, err := cr.IntFromStr(*valStr, v)
+	if err != nil {
+		return 0, errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	return val, nil
+}
+
+func Int32FromSSM(client Client, paramName string, v *cr.Int32Validation) (int32, error) {
+	valStr, err := client.GetParameter(paramName)
+	if err != nil {
+		return 0, errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	if valStr == nil || *valStr == "" {
+		val, err := cr.ValidateInt32Missing(v)
+		if err != nil {
+			return 0, errors.Wrap(err, s.SSMParameter(paramName))
+		}
+		return val, nil
+	}
+	val, err := cr.Int32FromStr(*valStr, v)
+	if err != nil {
+		return 0, errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	return val, nil
+}
+
+func Int64FromSSM(client Client, paramName string, v *cr.Int64Validation) (int64, error) {
+	valStr, err := client.GetParameter(paramName)
+	if err != nil {
+		return 0, errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	if valStr == nil || *valStr == "" {
+		val, err := cr.ValidateInt64Missing(v)
+		if err != nil {
+			return 0, errors.Wrap(err, s.SSMParameter(paramName))
+		}
+		return val, nil
+	}
+	val, err := cr.Int64FromStr(*valStr, v)
+	if err != nil {
+		return 0, errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	return val, nil
+}
+
+func Float32FromSSM(client Client, paramName string, v *cr.Float32Validation) (float32, error) {
+	valStr, err := client.GetParameter(paramName)
+	if err != nil {
+		return 0, errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	if valStr == nil || *valStr == "" {
+		val, err := cr.ValidateFloat32Missing(v)
+		if err != nil {
+			return 0, errors.Wrap(err, s.SSMParameter(paramName))
+		}
+		return val, nil
+	}
+	val, err := cr.Float32FromStr(*valStr, v)
+	if err != nil {
+		return 0, errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	return val, nil
+}
+
+func Float64FromSSM(client Client, paramName string, v *cr.Float64Validation) (float64, error) {
+	valStr, err := client.GetParameter(paramName)
+	if err != nil {
+		return 0, errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	if valStr == nil || *valStr == "" {
+		val, err := cr.ValidateFloat64Missing(v)
+		if err != nil {
+			return 0, errors.Wrap(err, s.SSMParameter(paramName))
+		}
+		return val, nil
+	}
+	val, err := cr.Float64FromStr(*valStr, v)
+	if err != nil {
+		return 0, errors.Wrap(err, s.SSMParameter(paramName))
+	}
+	return val, nil
+}
+
+//
+// Musts
+//
+
+func MustStringFromSSM(client Client, paramName string, v *cr.StringValidation) string {
+	val, err := StringFromSSM(client, paramName, v)
+	if err != nil {
+		cr.Fatal(err)
+	}
+	return val
+}
+
+func MustBoolFromSSM(client Client, paramName string, v *cr.BoolValidation) bool {
+	val, err := BoolFromSSM(client, paramName, v)
+	if err != nil {
+		cr.Fatal(err)
+	}
+	return val
+}
+
+func MustIntFromSSM(client Client, paramName string, v *cr.IntValidation) int {
+	val, err := IntFromSSM(client, paramName, v)
+	if err != nil {
+		cr.Fatal(err)
+	}
+	return val
+}
+
+func MustInt32FromSSM(client Client, paramName string, v *cr.Int32Validation) int32 {
+	val, err := Int32FromSSM(client, paramName, v)
+	if err != nil {
+		cr.Fatal(err)
+	}
+	return val
+}
+
+func MustInt64FromSSM(client Client, paramName string, v *cr.Int64Validation) int64 {
+	val, err := Int64FromSSM(client, paramName, v)
+	if err != nil {
+		cr.Fatal(err)
+	}
+	return val
+}
+
+func MustFloat32FromSSM(client Client, paramName string, v *cr.Float32Validation) float32 {
+	val, err := Float32FromSSM(client, paramName, v)
+	if err != nil {
+		cr.Fatal(err)
+	}
+	return val
+}
+
+func MustFloat64FromSSM(client Client, paramName string, v *cr.Float64Validation) float64 {
+	val, err := Float64FromSSM(client, paramName, v)
+	if err != nil {
+		cr.Fatal(err)
+	}
+	return val
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssm_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/configreader/ssm"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+type fakeClient map[string]string
+
+func (client fakeClient) GetParameter(paramName string) (*string, error) {
+	if paramName == "/app/unreachable" {
+		return nil, errors.New("request timed out")
+	}
+	val, ok := client[paramName]
+	if !ok {
+		return nil, nil
+	}
+	return &val, nil
+}
+
+func TestFromSSM(t *testing.T) {
+	client := fakeClient{
+		"/app/name":        "my-api",
+		"/app/max_workers": "8",
+		"/app/ratio":       "0.5",
+		"/app/debug":       "true",
+	}
+
+	str, err := ssm.StringFromSSM(client, "/app/name", &cr.StringValidation{})
+	require.NoError(t, err)
+	require.Equal(t, "my-api", str)
+
+	num, err := ssm.IntFromSSM(client, "/app/max_workers", &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 8, num)
+
+	ratio, err := ssm.Float64FromSSM(client, "/app/ratio", &cr.Float64Validation{})
+	require.NoError(t, err)
+	require.Equal(t, 0.5, ratio)
+
+	require.True(t, ssm.MustBoolFromSSM(client, "/app/debug", &cr.BoolValidation{}))
+
+	num, err = ssm.IntFromSSM(client, "/app/missing", &cr.IntValidation{Default: 4})
+	require.NoError(t, err)
+	require.Equal(t, 4, num)
+
+	_, err = ssm.IntFromSSM(client, "/app/missing", &cr.IntValidation{Required: true})
+	require.EqualError(t, err, s.SSMParameter("/app/missing")+": "+s.ErrMustBeDefined)
+
+	_, err = ssm.IntFromSSM(client, "/app/name", &cr.IntValidation{})
+	require.EqualError(t, err, s.SSMParameter("/app/name")+": "+s.ErrInvalidPrimitiveType("my-api", s.PrimTypeInt))
+
+	_, err = ssm.StringFromSSM(client, "/app/unreachable", &cr.StringValidation{})
+	require.EqualError(t, err, s.SSMParameter("/app/unreachable")+": request timed out")
+}
+
+func TestSource(t *testing.T) {
+	err := cr.RegisterSourceScheme("ssmtest", ssm.Source(fakeClient{"/app/max_workers": "8"}))
+	require.NoError(t, err)
+
+	num, err := cr.IntFromRef("ssmtest:///app/max_workers", &cr.IntValidation{})
+	require.NoError(t, err)
+	require.Equal(t, 8, num)
+}
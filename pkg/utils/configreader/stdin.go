@@ -0,0 +1,226 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"context"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// backgroundReader reads from a reader on a single background goroutine, so that a read can be abandoned (e.g. when a
+// prompt times out) without leaking a goroutine per read. Anything which is read after a read is abandoned is kept for the
+// next read
+type backgroundReader struct {
+	reader    io.Reader // defaults to os.Stdin
+	chunkSize int       // defaults to 1024
+	mutex     sync.Mutex
+	started   int32 // accessed atomically, so that it can be checked while a read is in progress
+	chunks    chan readChunk
+	buf       []byte
+	err       error
+
+	terminalEOF bool // set if an EOF from the reader isn't final
+}
+
+type readChunk struct {
+	data []byte
+	err  error
+}
+
+var stdin = &backgroundReader{}
+
+// inReaders holds a backgroundReader for each PromptOptions.In which has been read with a timeout or context, so that later
+// prompts read what an abandoned read consumed (instead of the abandoned read consuming their input)
+var inReaders sync.Map // io.Reader -> *backgroundReader
+
+func (r *backgroundReader) isStarted() bool {
+	return atomic.LoadInt32(&r.started) == 1
+}
+
+// start must be called with r.mutex held
+func (r *backgroundReader) start() {
+	if r.isStarted() {
+		return
+	}
+	r.chunks = make(chan readChunk)
+	atomic.StoreInt32(&r.started, 1)
+
+	reader := r.reader
+	if reader == nil {
+		reader = os.Stdin
+	}
+	chunkSize := r.chunkSize
+	if chunkSize <= 0 {
+		chunkSize = 1024
+	}
+	// ctrl-d on a terminal is an EOF, but the terminal can still be read from afterwards
+	terminalEOF := false
+	if file, ok := reader.(*os.File); ok {
+		terminalEOF = isTerminal(file)
+	}
+	r.terminalEOF = terminalEOF
+	go func() {
+		for {
+			data := make([]byte, chunkSize)
+			n, err := reader.Read(data)
+			r.chunks <- readChunk{data: data[:n], err: err}
+			if err != nil && !(err == io.EOF && terminalEOF) {
+				return
+			}
+		}
+	}()
+}
+
+// readByte returns ErrPromptTimeout if ctx is done before a byte is available
+func (r *backgroundReader) readByte(ctx context.Context) (byte, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.start()
+
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		select {
+		case chunk := <-r.chunks:
+			r.buf = chunk.data
+			if chunk.err == io.EOF && r.terminalEOF {
+				if len(chunk.data) == 0 {
+					return 0, io.EOF
+				}
+				continue
+			}
+			r.err = chunk.err
+		case <-ctx.Done():
+			return 0, ErrPromptTimeout
+		}
+	}
+
+	char := r.buf[0]
+	r.buf = r.buf[1:]
+	return char, nil
+}
+
+func (r *backgroundReader) readLine(ctx context.Context) (string, error) {
+	return readLineBytes(func() (byte, error) { return r.readByte(ctx) })
+}
+
+// readLineFromIn reads a line from in. If ctx can be done, in is read by a backgroundReader (which is kept for later reads
+// from in), and ErrPromptTimeout is returned if ctx is done first
+func readLineFromIn(ctx context.Context, in io.Reader) (string, error) {
+	if !reflect.TypeOf(in).Comparable() {
+		if ctx.Done() == nil {
+			return readLine(in)
+		}
+		return (&backgroundReader{reader: in, chunkSize: 1}).readLine(ctx)
+	}
+
+	r, ok := inReaders.Load(in)
+	if !ok {
+		if ctx.Done() == nil {
+			return readLine(in)
+		}
+		// one byte at a time, so that at most one byte after the line is consumed from in
+		r, _ = inReaders.LoadOrStore(in, &backgroundReader{reader: in, chunkSize: 1})
+	}
+
+	line, err := r.(*backgroundReader).readLine(ctx)
+	if err != nil && err != ErrPromptTimeout {
+		// in is done (e.g. it's at EOF), so its background reader has exited
+		inReaders.Delete(in)
+	}
+	return line, err
+}
+
+// readLine reads one byte at a time, so that nothing after the line is consumed from in
+func readLine(in io.Reader) (string, error) {
+	buf := make([]byte, 1)
+	return readLineBytes(func() (byte, error) {
+		for {
+			n, err := in.Read(buf)
+			if n == 1 {
+				return buf[0], nil
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+	})
+}
+
+func readLineBytes(readByte func() (byte, error)) (string, error) {
+	var line []byte
+	for {
+		char, err := readByte()
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				break
+			}
+			return "", err
+		}
+		if char == '\n' {
+			break
+		}
+		line = append(line, char)
+	}
+	return strings.TrimSuffix(string(line), "\r"), nil
+}
+
+func readStdinByte() (byte, error) {
+	buf := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			return 0, err
+		}
+		if n == 1 {
+			return buf[0], nil
+		}
+	}
+}
+
+// readLines reads lines until endSentinel or an EOF, and joins them
+func readLines(readLine func() (string, error), endSentinel string, trailingNewline bool) (string, error) {
+	var lines []string
+	for {
+		line, err := readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(line) == endSentinel {
+			break
+		}
+		lines = append(lines, line)
+	}
+
+	if len(lines) == 0 {
+		return "", nil
+	}
+	val := strings.Join(lines, "\n")
+	if trailingNewline {
+		val += "\n"
+	}
+	return val, nil
+}
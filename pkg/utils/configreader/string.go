@@ -17,10 +17,13 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
+	"net/url"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
 	"github.com/cortexlabs/cortex/pkg/utils/util"
 )
@@ -28,22 +31,42 @@ import (
 type StringValidation struct {
 	Required                      bool
 	Default                       string
+	DefaultFunc                   func() (string, error) // if set, it's called to compute the default (e.g. from values which have already been read)
 	AllowEmpty                    bool
 	AllowedValues                 []string
+	HiddenAllowedValues           []string // also allowed, but not listed in errors or suggested for typos (e.g. internal values)
 	Prefix                        string
 	AlphaNumericDashDotUnderscore bool
 	AlphaNumericDashUnderscore    bool
 	Dns1035                       bool
+	MaxLength                     int               // maximum length in bytes (0 means no limit)
+	RequireValidUTF8              bool              // reject values which aren't valid UTF-8
+	DisallowControlChars          bool              // reject values which contain control characters, other than those in AllowedControlChars
+	AllowedControlChars           []rune            // for DisallowControlChars, e.g. []rune{'\t', '\n'}
+	Dns1123Label                  bool              // kubernetes' rules for DNS-1123 labels (e.g. namespaces); see Presets.K8sLabel()
+	Dns1123Subdomain              bool              // kubernetes' rules for DNS-1123 subdomains (e.g. most resource names); see Presets.K8sSubdomain()
+	PreserveWhitespace            bool              // don't trim surrounding whitespace from values read from files
+	MaxFileBytes                  int64             // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles          bool              // allow FromFile readers to read from e.g. pipes and device files
+	Sensitive                     bool              // replace the value with its length in errors and reports (errors from Validator are shown as-is)
+	RequireURLEncoded             bool              // the value must be a valid percent-encoded URL component
+	AutoDecode                    bool              // return the percent-decoded value (implies RequireURLEncoded)
+	Encode                        bool              // return the percent-encoded value, for interpolating into URLs
+	URLPathEncoding               bool              // for RequireURLEncoded, AutoDecode, and Encode, use path segment encoding instead of query encoding
+	ChecksumValidator             func(string) bool // e.g. util.CheckLuhn; errors with ErrInvalidChecksum if it returns false
+	ChecksumDescription           string            // added to the invalid checksum error, e.g. "the last digit is a Luhn check digit"
+	Report                        *Report           // if set, each value which is read is recorded, along with where it came from
 	Validator                     func(string) (string, error)
+	allowedValuesSet              allowedValuesSet
 }
 
 func String(inter interface{}, v *StringValidation) (string, error) {
 	if inter == nil {
-		return "", errors.New(s.ErrCannotBeNull)
+		return "", errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 	}
-	casted, castOk := inter.(string)
+	casted, castOk := cast.InterfaceToStr(inter)
 	if !castOk {
-		return "", errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeString))
+		return "", errors.Wrap(&InvalidTypeError{Provided: redactIf(inter, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeString}})
 	}
 	return ValidateString(casted, v)
 }
@@ -53,14 +76,38 @@ func StringFromInterfaceMap(key string, iMap map[string]interface{}, v *StringVa
 	if !ok {
 		val, err := ValidateStringMissing(v)
 		if err != nil {
-			return "", errors.Wrap(err, key)
+			return "", errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := String(inter, v)
 	if err != nil {
-		return "", errors.Wrap(err, key)
+		return "", errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
+	return val, nil
+}
+
+// StringFromInterfaceMapWithRefs is like StringFromInterfaceMap(), but the value can refer to another field in the map (e.g. "${default_timeout}")
+func StringFromInterfaceMapWithRefs(key string, iMap map[string]interface{}, v *StringValidation) (string, error) {
+	inter, ok, err := ReadInterfaceMapValueWithRefs(key, iMap)
+	if err != nil {
+		return "", errors.WrapKey(err, key)
+	}
+	if !ok {
+		val, err := ValidateStringMissing(v)
+		if err != nil {
+			return "", errors.WrapKey(err, key)
+		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := String(inter, v)
+	if err != nil {
+		return "", errors.WrapKey(err, key)
+	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
 	return val, nil
 }
 
@@ -69,13 +116,40 @@ func StringFromStrMap(key string, sMap map[string]string, v *StringValidation) (
 	if !ok {
 		val, err := ValidateStringMissing(v)
 		if err != nil {
-			return "", errors.Wrap(err, key)
+			return "", errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := StringFromStr(valStr, v)
 	if err != nil {
-		return "", errors.Wrap(err, key)
+		return "", errors.WrapKey(err, key)
+	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive))
+	return val, nil
+}
+
+// StringFirstOf reads the first of keys whose value is present and not blank (e.g. a key and its aliases).
+// Only that value is validated; if none of the keys have a value, v.Required and v.Default are applied
+func StringFirstOf(iMap map[string]interface{}, keys []string, v *StringValidation) (string, error) {
+	for _, key := range keys {
+		inter, ok := ReadInterfaceMapValue(key, iMap)
+		if !ok || inter == nil {
+			continue
+		}
+		if casted, castOk := inter.(string); castOk && strings.TrimSpace(casted) == "" {
+			continue
+		}
+		val, err := String(inter, v)
+		if err != nil {
+			return "", errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+
+	val, err := ValidateStringMissing(v)
+	if err != nil {
+		return "", errors.Wrap(err, s.StrsOr(keys))
 	}
 	return val, nil
 }
@@ -85,35 +159,70 @@ func StringFromStr(valStr string, v *StringValidation) (string, error) {
 }
 
 func StringFromEnv(envVarName string, v *StringValidation) (string, error) {
-	valStr := ReadEnvVar(envVarName)
+	return DefaultEnv().StringFromEnv(envVarName, v)
+}
+
+func (env *Env) StringFromEnv(envVarName string, v *StringValidation) (string, error) {
+	valStr := env.ReadEnvVar(envVarName)
 	if valStr == nil {
 		val, err := ValidateStringMissing(v)
 		if err != nil {
-			return "", errors.Wrap(err, s.EnvVar(envVarName))
+			return "", envVarError(err, envVarName)
 		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
 		return val, nil
 	}
 	val, err := StringFromStr(*valStr, v)
 	if err != nil {
-		return "", errors.Wrap(err, s.EnvVar(envVarName))
+		return "", envVarError(err, envVarName)
 	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
 	return val, nil
 }
 
 func StringFromFile(filePath string, v *StringValidation) (string, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
+	return uncachedFiles.StringFromFile(filePath, v)
+}
+
+func (cache *FileCache) StringFromFile(filePath string, v *StringValidation) (string, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
 	if err != nil {
+		return "", errors.Wrap(err, filePath)
+	}
+	if valStr == nil {
 		val, err := ValidateStringMissing(v)
 		if err != nil {
-			return "", errors.Wrap(err, filePath)
+			return "", valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
-	val, err := StringFromStr(valStr, v)
+	val, err := StringFromStr(*valStr, v)
+	if err != nil {
+		return "", valueFileError(err, filePath, 1)
+	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive))
+	return val, nil
+}
+
+func StringFromFileLine(filePath string, line int, v *StringValidation) (string, error) {
+	valStr, err := readFileLine(filePath, line, v.MaxFileBytes, v.AllowNonRegularFiles)
 	if err != nil {
 		return "", errors.Wrap(err, filePath)
 	}
+	if valStr == nil {
+		val, err := ValidateStringMissing(v)
+		if err != nil {
+			return "", valueFileError(err, filePath, 0)
+		}
+		v.Report.record(filePath, ValueSourceDefault, errors.Position{File: filePath, Line: line}.String(), redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := StringFromStr(*valStr, v)
+	if err != nil {
+		return "", valueFileError(err, filePath, line)
+	}
+	v.Report.record(filePath, ValueSourceFile, errors.Position{File: filePath, Line: line}.String(), redactIf(val, v.Sensitive))
 	return val, nil
 }
 
@@ -125,74 +234,352 @@ func StringFromEnvOrFile(envVarName string, filePath string, v *StringValidation
 	return StringFromFile(filePath, v)
 }
 
+func StringFromRef(ref string, v *StringValidation) (string, error) {
+	valStr, err := ReadRef(ref)
+	if err != nil {
+		return "", errors.Wrap(err, ref)
+	}
+	if valStr == nil {
+		val, err := ValidateStringMissing(v)
+		if err != nil {
+			return "", errors.Wrap(err, ref)
+		}
+		v.Report.record(ref, ValueSourceDefault, ref, redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := StringFromStr(*valStr, v)
+	if err != nil {
+		return "", errors.Wrap(err, ref)
+	}
+	v.Report.record(ref, ValueSourceRef, ref, redactIf(val, v.Sensitive))
+	return val, nil
+}
+
+func StringFromSource(source Source, key string, v *StringValidation) (string, error) {
+	valStr, found, err := source.Lookup(key)
+	if err != nil {
+		return "", errors.WrapKey(err, key)
+	}
+	if !found {
+		val, err := ValidateStringMissing(v)
+		if err != nil {
+			return "", errors.WrapKey(err, key)
+		}
+		v.Report.record(key, ValueSourceDefault, describeSource(source, key), redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := StringFromStr(valStr, v)
+	if err != nil {
+		return "", errors.WrapKey(errors.WithSource(err, describeSource(source, key)), key)
+	}
+	v.Report.record(key, ValueSourceLookup, describeSource(source, key), redactIf(val, v.Sensitive))
+	return val, nil
+}
+
+// StringFromArg reads a positional argument; argName is used in error messages (e.g. "deployment name")
+func StringFromArg(args []string, index int, argName string, v *StringValidation) (string, error) {
+	if index < 0 || index >= len(args) {
+		val, err := ValidateStringMissing(v)
+		if err != nil {
+			return "", errors.Wrap(err, argName)
+		}
+		v.Report.record(argName, ValueSourceDefault, "", redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := StringFromStr(args[index], v)
+	if err != nil {
+		return "", errors.Wrap(err, argName)
+	}
+	v.Report.record(argName, ValueSourceArg, "", redactIf(val, v.Sensitive))
+	return val, nil
+}
+
 func StringFromPrompt(promptOpts *PromptOptions, v *StringValidation) (string, error) {
-	promptOpts.defaultStr = v.Default
-	valStr := prompt(promptOpts)
-	if valStr == "" { // Treat empty prompt value as missing
-		return ValidateStringMissing(v)
+	defaultVal, defaultErr := v.Default, error(nil)
+	if v.DefaultFunc != nil {
+		// DefaultFunc is only called once, for both the default which is shown and an empty response
+		defaultVal, defaultErr = v.DefaultFunc()
+	}
+	defaultStr := ""
+	if defaultErr == nil {
+		defaultStr = defaultVal
+	}
+	promptOpts = promptOpts.withHints(ConstraintHint(v), defaultStr)
+	var val string
+	var source ValueSource
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		if valStr == "" { // Treat empty prompt value as missing
+			val, err = validateStringMissing(v, defaultVal, defaultErr)
+		} else {
+			val, err = StringFromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(val, v.Sensitive))
 	}
-	return StringFromStr(valStr, v)
+	return val, err
 }
 
 func ValidateStringMissing(v *StringValidation) (string, error) {
 	if v.Required {
-		return "", errors.New(s.ErrMustBeDefined)
+		return "", errors.Wrap(ErrRequired)
+	}
+	if v.DefaultFunc != nil {
+		val, err := v.DefaultFunc()
+		return validateStringMissing(v, val, err)
 	}
 	return ValidateString(v.Default, v)
 }
 
+// validateStringMissing is the same as ValidateStringMissing, with the result of v.DefaultFunc instead of calling it
+func validateStringMissing(v *StringValidation, defaultVal string, defaultErr error) (string, error) {
+	if v.Required {
+		return "", errors.Wrap(ErrRequired)
+	}
+	if defaultErr != nil {
+		return "", errors.MarkUser(defaultErr)
+	}
+	return ValidateString(defaultVal, v)
+}
+
 func ValidateString(val string, v *StringValidation) (string, error) {
 	err := ValidateStringVal(val, v)
 	if err != nil {
 		return "", err
 	}
 
+	if v.AutoDecode {
+		val, _ = urlUnescape(val, v.URLPathEncoding)
+	} else if v.Encode {
+		val = urlEscape(val, v.URLPathEncoding)
+	}
+
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
 
 func ValidateStringVal(val string, v *StringValidation) error {
+	errVal := val
+	if v.Sensitive {
+		errVal = s.Redacted(val)
+	}
+
 	if !v.AllowEmpty {
 		if len(val) == 0 {
-			return errors.New(s.ErrCannotBeEmpty)
+			return errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
+		}
+	}
+
+	if v.MaxLength > 0 && len(val) > v.MaxLength {
+		return errors.NewUserCoded(s.MsgStrTooLong, s.ErrStrTooLong(errVal, v.MaxLength))
+	}
+
+	if v.RequireValidUTF8 {
+		if offset := invalidUTF8Offset(val); offset != -1 {
+			return errors.NewUserCoded(s.MsgInvalidUTF8, s.ErrInvalidUTF8(offset))
+		}
+	}
+
+	if v.DisallowControlChars {
+		for offset, char := range val {
+			if unicode.IsControl(char) && !runeInSlice(char, v.AllowedControlChars) {
+				return errors.NewUserCoded(s.MsgControlChar, s.ErrControlChar(char, offset))
+			}
 		}
 	}
 
 	if v.AllowedValues != nil {
-		if !util.IsStrInSlice(val, v.AllowedValues) {
-			return errors.New(s.ErrInvalidStr(val, v.AllowedValues...))
+		if !isAllowed(val, v.AllowedValues, &v.allowedValuesSet) && !util.IsStrInSlice(val, v.HiddenAllowedValues) {
+			notAllowedErr := &NotAllowedValueError{Val: errVal, Allowed: v.AllowedValues, File: allowedValuesFile(v.AllowedValues)}
+			if !v.Sensitive {
+				notAllowedErr.Suggestion = s.SuggestClosest(val, v.AllowedValues)
+			}
+			return errors.Wrap(notAllowedErr)
 		}
 	}
 
 	if v.Prefix != "" {
 		if !strings.HasPrefix(val, v.Prefix) {
-			return errors.New(s.ErrMustHavePrefix(val, v.Prefix))
+			return errors.NewUserCoded(s.MsgMustHavePrefix, s.ErrMustHavePrefix(errVal, v.Prefix))
 		}
 	}
 
 	if v.AlphaNumericDashDotUnderscore {
 		if !util.CheckAlphaNumericDashDotUnderscore(val) {
-			return errors.New(s.ErrAlphaNumericDashDotUnderscore(val))
+			return errors.NewUserCoded(s.MsgAlphaNumericDashDotUnderscore, s.ErrAlphaNumericDashDotUnderscore(errVal))
 		}
 	}
 
 	if v.AlphaNumericDashUnderscore {
 		if !util.CheckAlphaNumericDashUnderscore(val) {
-			return errors.New(s.ErrAlphaNumericDashUnderscore(val))
+			return errors.NewUserCoded(s.MsgAlphaNumericDashUnderscore, s.ErrAlphaNumericDashUnderscore(errVal))
 		}
 	}
 
 	if v.Dns1035 {
 		if !util.CheckDns1035(val) {
-			return errors.New(s.ErrDNS1035(val))
+			return errors.NewUserCoded(s.MsgDNS1035, s.ErrDNS1035(errVal))
+		}
+	}
+
+	if v.Dns1123Label {
+		if !util.CheckDns1123Label(val) {
+			return dns1123Error(val, errVal, false)
+		}
+	}
+
+	if v.Dns1123Subdomain {
+		if !util.CheckDns1123Subdomain(val) {
+			return dns1123Error(val, errVal, true)
+		}
+	}
+
+	if v.RequireURLEncoded || v.AutoDecode {
+		if _, err := urlUnescape(val, v.URLPathEncoding); err != nil {
+			if escapeErr, ok := err.(url.EscapeError); ok && !v.Sensitive {
+				return errors.NewUserCoded(s.MsgInvalidURLEscape, s.ErrInvalidURLEscape(errVal, string(escapeErr)))
+			}
+			return errors.NewUserCoded(s.MsgInvalidURLEncoding, s.ErrInvalidURLEncoding(errVal))
+		}
+	}
+
+	if v.ChecksumValidator != nil {
+		if !v.ChecksumValidator(val) {
+			return errors.NewUserCoded(s.MsgInvalidChecksum, s.ErrInvalidChecksum(errVal, v.ChecksumDescription))
 		}
 	}
 
 	return nil
 }
 
+// invalidUTF8Offset returns the byte offset of the first invalid UTF-8 sequence, or -1 if val is valid
+func invalidUTF8Offset(val string) int {
+	for offset, char := range val {
+		if char == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(val[offset:]); size == 1 {
+				return offset
+			}
+		}
+	}
+	return -1
+}
+
+func runeInSlice(char rune, chars []rune) bool {
+	for _, candidate := range chars {
+		if candidate == char {
+			return true
+		}
+	}
+	return false
+}
+
+// dns1123Error describes the first rule which val breaks
+func dns1123Error(val string, errVal string, subdomain bool) error {
+	maxLength := util.Dns1123LabelMaxLength
+	if subdomain {
+		maxLength = util.Dns1123SubdomainMaxLength
+	}
+	if len(val) > maxLength {
+		return errors.NewUserCoded(s.MsgStrTooLong, s.ErrStrTooLong(errVal, maxLength))
+	}
+
+	for _, char := range val {
+		if !(char >= 'a' && char <= 'z' || char >= '0' && char <= '9' || char == '-' || subdomain && char == '.') {
+			return errors.NewUserCoded(s.MsgDNS1123Chars, s.ErrDNS1123Chars(errVal, subdomain))
+		}
+	}
+
+	if subdomain && strings.Contains(val, ".") {
+		return errors.NewUserCoded(s.MsgDNS1123SubdomainParts, s.ErrDNS1123SubdomainParts(errVal))
+	}
+	return errors.NewUserCoded(s.MsgDNS1123Boundary, s.ErrDNS1123Boundary(errVal))
+}
+
+func urlUnescape(val string, pathEncoding bool) (string, error) {
+	if pathEncoding {
+		return url.PathUnescape(val)
+	}
+	return url.QueryUnescape(val)
+}
+
+func urlEscape(val string, pathEncoding bool) string {
+	if pathEncoding {
+		return url.PathEscape(val)
+	}
+	return url.QueryEscape(val)
+}
+
+//
+// With raw values
+//
+
+func StringFromInterfaceMapWithRaw(key string, iMap map[string]interface{}, v *StringValidation) (string, string, error) {
+	val, err := StringFromInterfaceMap(key, iMap, v)
+	raw := ""
+	if inter, ok := ReadInterfaceMapValue(key, iMap); ok {
+		raw = s.UserStrStripped(inter)
+	}
+	return val, raw, err
+}
+
+func StringFromStrWithRaw(valStr string, v *StringValidation) (string, string, error) {
+	val, err := StringFromStr(valStr, v)
+	return val, valStr, err
+}
+
+func StringFromEnvWithRaw(envVarName string, v *StringValidation) (string, string, error) {
+	return DefaultEnv().StringFromEnvWithRaw(envVarName, v)
+}
+
+func (env *Env) StringFromEnvWithRaw(envVarName string, v *StringValidation) (string, string, error) {
+	valStr := env.ReadEnvVar(envVarName)
+	if valStr == nil {
+		val, err := ValidateStringMissing(v)
+		if err != nil {
+			return "", "", envVarError(err, envVarName)
+		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+		return val, "", nil
+	}
+	val, err := StringFromStr(*valStr, v)
+	if err != nil {
+		return "", *valStr, envVarError(err, envVarName)
+	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+	return val, *valStr, nil
+}
+
+func StringFromFileWithRaw(filePath string, v *StringValidation) (string, string, error) {
+	valStr, err := readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, false)
+	if err != nil {
+		return "", "", errors.Wrap(err, filePath)
+	}
+	if valStr == nil {
+		val, err := ValidateStringMissing(v)
+		if err != nil {
+			return "", "", valueFileError(err, filePath, 0)
+		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(val, v.Sensitive))
+		return val, "", nil
+	}
+	trimmed := *valStr
+	if !v.PreserveWhitespace {
+		trimmed = strings.TrimSpace(trimmed)
+	}
+	val, err := StringFromStr(trimmed, v)
+	if err != nil {
+		return "", *valStr, valueFileError(err, filePath, 1)
+	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(val, v.Sensitive))
+	return val, *valStr, nil
+}
+
 //
 // Musts
 //
@@ -200,7 +587,7 @@ func ValidateStringVal(val string, v *StringValidation) error {
 func MustStringFromEnv(envVarName string, v *StringValidation) string {
 	val, err := StringFromEnv(envVarName, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
 	}
 	return val
 }
@@ -208,7 +595,15 @@ func MustStringFromEnv(envVarName string, v *StringValidation) string {
 func MustStringFromFile(filePath string, v *StringValidation) string {
 	val, err := StringFromFile(filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
+	}
+	return val
+}
+
+func MustStringFromFileLine(filePath string, line int, v *StringValidation) string {
+	val, err := StringFromFileLine(filePath, line, v)
+	if err != nil {
+		Fatal(err)
 	}
 	return val
 }
@@ -216,7 +611,31 @@ func MustStringFromFile(filePath string, v *StringValidation) string {
 func MustStringFromEnvOrFile(envVarName string, filePath string, v *StringValidation) string {
 	val, err := StringFromEnvOrFile(envVarName, filePath, v)
 	if err != nil {
-		errors.Panic(err)
+		Fatal(err)
+	}
+	return val
+}
+
+func MustStringFromRef(ref string, v *StringValidation) string {
+	val, err := StringFromRef(ref, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustStringFromSource(source Source, key string, v *StringValidation) string {
+	val, err := StringFromSource(source, key, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustStringFromArg(args []string, index int, argName string, v *StringValidation) string {
+	val, err := StringFromArg(args, index, argName, v)
+	if err != nil {
+		Fatal(err)
 	}
 	return val
 }
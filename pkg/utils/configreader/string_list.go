@@ -17,6 +17,12 @@ limitations under the License.
 package configreader
 
 import (
+	"encoding/csv"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
 	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
@@ -24,18 +30,27 @@ import (
 )
 
 type StringListValidation struct {
-	Required     bool
-	Default      []string
-	AllowNull    bool
-	AllowEmpty   bool
-	DisallowDups bool
-	Validator    func([]string) ([]string, error)
+	Required         bool
+	Default          []string
+	AllowNull        bool
+	AllowEmpty       bool
+	DisallowDups     bool
+	MinLength        int
+	MaxLength        int    // 0 means there is no limit
+	Delimiter        string // for FromStr and FromEnv readers, defaults to DefaultListDelimiter (see WhitespaceDelimiter)
+	CSVQuoting       bool   // for FromStr and FromEnv readers, allow elements to be quoted like in CSV files, e.g. `"a,b",c` (see splitCSVList())
+	ElementValidator func(string) (string, error)
+	Report           *Report // if set, each value which is read is recorded, along with where it came from
+	Validator        func([]string) ([]string, error)
 }
 
 func StringList(inter interface{}, v *StringListValidation) ([]string, error) {
-	casted, castOk := cast.InterfaceToStrSlice(inter)
+	casted, invalidIndex, castOk := cast.InterfaceToStrSliceWithInvalidIndex(inter, false)
+	if invalidIndex >= 0 {
+		return nil, invalidElemTypeError(inter, invalidIndex, s.PrimTypeString)
+	}
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeStringList))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeStringList}})
 	}
 	return ValidateStringList(casted, v)
 }
@@ -45,20 +60,146 @@ func StringListFromInterfaceMap(key string, iMap map[string]interface{}, v *Stri
 	if !ok {
 		val, err := ValidateStringListMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", val)
 		return val, nil
 	}
 	val, err := StringList(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
+	}
+	v.Report.record(key, ValueSourceConfig, "", val)
+	return val, nil
+}
+
+// StringListFromInterfaceMapWithCount is like StringListFromInterfaceMap(), but the list must have as many elements as the
+// integer at countKey (e.g. "num_shards"). If countKey is missing and countV has no default, the length isn't checked
+func StringListFromInterfaceMapWithCount(key string, countKey string, iMap map[string]interface{}, v *StringListValidation, countV *IntPtrValidation) ([]string, error) {
+	count, err := IntPtrFromInterfaceMap(countKey, iMap, countV)
+	if err != nil {
+		return nil, err
+	}
+	val, err := StringListFromInterfaceMap(key, iMap, v)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkListCount(len(val), countKey, count); err != nil {
+		return nil, errors.WrapKey(err, key)
 	}
 	return val, nil
 }
 
+func checkListCount(length int, countKey string, count *int) error {
+	if count != nil && length != *count {
+		return errors.NewUserCoded(s.MsgListLengthMismatch, s.ErrListLengthMismatch(length, countKey, *count))
+	}
+	return nil
+}
+
+const DefaultListDelimiter = ","
+
+// WhitespaceDelimiter splits lists on any run of whitespace (e.g. "0 1  2\t3"), like shell arguments
+const WhitespaceDelimiter = " "
+
+// splitList splits a list which was read from a string. Elements are trimmed, and an all-whitespace string is an empty list
+func splitList(valStr string, delimiter string) []string {
+	if delimiter == "" {
+		delimiter = DefaultListDelimiter
+	}
+	if delimiter == WhitespaceDelimiter {
+		return strings.Fields(valStr)
+	}
+	if strings.TrimSpace(valStr) == "" {
+		return []string{}
+	}
+	elems := strings.Split(valStr, delimiter)
+	for i, elem := range elems {
+		elems[i] = strings.TrimSpace(elem)
+	}
+	return elems
+}
+
+// splitCSVList splits a single line with encoding/csv, so that elements can contain the delimiter or quotes if they're
+// quoted (quotes are escaped by doubling them, e.g. `"say ""hi"""`). Like with splitList(), elements are trimmed
+func splitCSVList(valStr string, delimiter string) ([]string, error) {
+	if delimiter == "" {
+		delimiter = DefaultListDelimiter
+	}
+	delimiterRunes := []rune(delimiter)
+	if len(delimiterRunes) != 1 || unicode.IsSpace(delimiterRunes[0]) || delimiterRunes[0] == '"' || !utf8.ValidString(delimiter) {
+		return nil, errors.NewUserCoded(s.MsgCSVQuotingDelimiter, s.ErrCSVQuotingDelimiter(delimiter))
+	}
+
+	reader := csv.NewReader(strings.NewReader(strings.TrimSpace(valStr)))
+	reader.Comma = delimiterRunes[0]
+	reader.TrimLeadingSpace = true
+
+	elems, err := reader.Read()
+	if err == io.EOF {
+		return []string{}, nil
+	}
+	if err != nil {
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			err = parseErr.Err
+		}
+		return nil, errors.NewUserCoded(s.MsgInvalidCSVList, s.ErrInvalidCSVList(valStr, err.Error()))
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		return nil, errors.NewUserCoded(s.MsgCSVListMultipleLines, s.ErrCSVListMultipleLines(valStr))
+	}
+
+	for i, elem := range elems {
+		elems[i] = strings.TrimSpace(elem)
+	}
+	return elems, nil
+}
+
+func StringListFromStr(valStr string, v *StringListValidation) ([]string, error) {
+	if valStr == "" {
+		return ValidateStringListMissing(v)
+	}
+	if v.CSVQuoting {
+		elems, err := splitCSVList(valStr, v.Delimiter)
+		if err != nil {
+			return nil, err
+		}
+		return ValidateStringList(elems, v)
+	}
+	return ValidateStringList(splitList(valStr, v.Delimiter), v)
+}
+
+func StringListFromEnv(envVarName string, v *StringListValidation) ([]string, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateStringListMissing(v)
+		if err != nil {
+			return nil, envVarError(err, envVarName)
+		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), val)
+		return val, nil
+	}
+	val, err := StringListFromStr(*valStr, v)
+	if err != nil {
+		return nil, envVarError(err, envVarName)
+	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), val)
+	return val, nil
+}
+
+// RemainingArgsAsStringList reads all positional arguments starting at fromIndex
+func RemainingArgsAsStringList(args []string, fromIndex int, v *StringListValidation) ([]string, error) {
+	if fromIndex < 0 || fromIndex >= len(args) {
+		return ValidateStringListMissing(v)
+	}
+	remaining := make([]string, len(args)-fromIndex)
+	copy(remaining, args[fromIndex:])
+	return ValidateStringList(remaining, v)
+}
+
 func ValidateStringListMissing(v *StringListValidation) ([]string, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateStringList(v.Default, v)
 }
@@ -66,24 +207,74 @@ func ValidateStringListMissing(v *StringListValidation) ([]string, error) {
 func ValidateStringList(val []string, v *StringListValidation) ([]string, error) {
 	if !v.AllowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
 	if !v.AllowEmpty {
 		if val != nil && len(val) == 0 {
-			return nil, errors.New(s.ErrCannotBeEmpty)
+			return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
+		}
+	}
+
+	if val != nil {
+		if len(val) < v.MinLength {
+			return nil, errors.NewUserCoded(s.MsgTooFewElements, s.ErrTooFewElements(len(val), v.MinLength))
+		}
+		if v.MaxLength > 0 && len(val) > v.MaxLength {
+			return nil, errors.NewUserCoded(s.MsgTooManyElements, s.ErrTooManyElements(len(val), v.MaxLength))
+		}
+	}
+
+	if v.ElementValidator != nil && val != nil {
+		validated := make([]string, len(val))
+		for i, elem := range val {
+			validatedElem, err := v.ElementValidator(elem)
+			if err != nil {
+				return nil, errors.WrapIndex(errors.MarkUser(err), i)
+			}
+			validated[i] = validatedElem
 		}
+		val = validated
 	}
 
 	if v.DisallowDups {
 		if dups := util.FindDuplicateStrs(val); len(dups) > 0 {
-			return nil, errors.New(s.ErrDuplicatedValue(dups[0]))
+			return nil, errors.NewUserCoded(s.MsgDuplicatedValue, s.ErrDuplicatedValue(dups[0]))
 		}
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
+
+//
+// Musts
+//
+
+func MustStringListFromStr(valStr string, v *StringListValidation) []string {
+	val, err := StringListFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustStringListFromEnv(envVarName string, v *StringListValidation) []string {
+	val, err := StringListFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustRemainingArgsAsStringList(args []string, fromIndex int, v *StringListValidation) []string {
+	val, err := RemainingArgsAsStringList(args, fromIndex, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
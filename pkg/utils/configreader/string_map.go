@@ -17,23 +17,32 @@ limitations under the License.
 package configreader
 
 import (
+	"sort"
+	"strings"
+
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
 	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
 )
 
 type StringMapValidation struct {
-	Required   bool
-	Default    map[string]string
-	AllowNull  bool
-	AllowEmpty bool
-	Validator  func(map[string]string) (map[string]string, error)
+	Required           bool
+	Default            map[string]string
+	AllowNull          bool
+	AllowEmpty         bool
+	PairDelimiter      string            // for FromStr and FromEnv (e.g. "a=1,b=2"), defaults to ","
+	KVDelimiter        string            // for FromStr and FromEnv, defaults to "="
+	AllowDuplicateKeys bool              // for FromStr and FromEnv, the last value of a duplicated key is used (otherwise it's an error)
+	KeyValidation      *StringValidation // optional
+	ValueValidation    *StringValidation // optional
+	Validator          func(map[string]string) (map[string]string, error)
 }
 
 func StringMap(inter interface{}, v *StringMapValidation) (map[string]string, error) {
 	casted, castOk := cast.InterfaceToStrStrMap(inter)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeStringToStringMap))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeStringToStringMap}})
 	}
 	return ValidateStringMap(casted, v)
 }
@@ -43,20 +52,79 @@ func StringMapFromInterfaceMap(key string, iMap map[string]interface{}, v *Strin
 	if !ok {
 		val, err := ValidateStringMapMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
 		return val, nil
 	}
 	val, err := StringMap(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func StringMapFromStr(valStr string, v *StringMapValidation) (map[string]string, error) {
+	if strings.TrimSpace(valStr) == "" {
+		return ValidateStringMapMissing(v)
+	}
+	casted, err := parseStringMap(valStr, v)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateStringMap(casted, v)
+}
+
+func StringMapFromEnv(envVarName string, v *StringMapValidation) (map[string]string, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateStringMapMissing(v)
+		if err != nil {
+			return nil, envVarError(err, envVarName)
+		}
+		return val, nil
+	}
+	val, err := StringMapFromStr(*valStr, v)
+	if err != nil {
+		return nil, envVarError(err, envVarName)
+	}
+	return val, nil
+}
+
+func parseStringMap(valStr string, v *StringMapValidation) (map[string]string, error) {
+	pairDelimiter := v.PairDelimiter
+	if pairDelimiter == "" {
+		pairDelimiter = ","
+	}
+	kvDelimiter := v.KVDelimiter
+	if kvDelimiter == "" {
+		kvDelimiter = "="
+	}
+
+	val := map[string]string{}
+	for _, pair := range strings.Split(valStr, pairDelimiter) {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		split := strings.SplitN(pair, kvDelimiter, 2)
+		if len(split) != 2 {
+			return nil, errors.NewUserCoded(s.MsgMalformedKVPair, s.ErrMalformedKVPair(pair, kvDelimiter))
+		}
+		key := strings.TrimSpace(split[0])
+		if key == "" {
+			return nil, errors.NewUserCoded(s.MsgEmptyKVPairKey, s.ErrEmptyKVPairKey(pair))
+		}
+		if _, ok := val[key]; ok && !v.AllowDuplicateKeys {
+			return nil, errors.NewUserCoded(s.MsgDuplicateKey, s.ErrDuplicateKey(key))
+		}
+		val[key] = strings.TrimSpace(split[1])
 	}
 	return val, nil
 }
 
 func ValidateStringMapMissing(v *StringMapValidation) (map[string]string, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateStringMap(v.Default, v)
 }
@@ -64,18 +132,63 @@ func ValidateStringMapMissing(v *StringMapValidation) (map[string]string, error)
 func ValidateStringMap(val map[string]string, v *StringMapValidation) (map[string]string, error) {
 	if !v.AllowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
 	if !v.AllowEmpty {
 		if val != nil && len(val) == 0 {
-			return nil, errors.New(s.ErrCannotBeEmpty)
+			return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
+		}
+	}
+
+	if (v.KeyValidation != nil || v.ValueValidation != nil) && val != nil {
+		keys := util.StrMapKeys(val)
+		sort.Strings(keys)
+		validated := make(map[string]string, len(val))
+		for _, key := range keys {
+			validatedKey := key
+			if v.KeyValidation != nil {
+				var err error
+				if validatedKey, err = ValidateString(key, v.KeyValidation); err != nil {
+					return nil, errors.WrapKey(err, key)
+				}
+			}
+			validatedVal := val[key]
+			if v.ValueValidation != nil {
+				var err error
+				if validatedVal, err = ValidateString(validatedVal, v.ValueValidation); err != nil {
+					return nil, errors.WrapKey(err, key)
+				}
+			}
+			validated[validatedKey] = validatedVal
 		}
+		val = validated
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
+
+//
+// Musts
+//
+
+func MustStringMapFromStr(valStr string, v *StringMapValidation) map[string]string {
+	val, err := StringMapFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustStringMapFromEnv(envVarName string, v *StringMapValidation) map[string]string {
+	val, err := StringMapFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
@@ -17,9 +17,8 @@ limitations under the License.
 package configreader
 
 import (
-	"io/ioutil"
-
 	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
 	"github.com/cortexlabs/cortex/pkg/utils/errors"
 )
 
@@ -29,21 +28,32 @@ type StringPtrValidation struct {
 	DisallowNull                  bool
 	AllowEmpty                    bool
 	AllowedValues                 []string
+	HiddenAllowedValues           []string // also allowed, but not listed in errors or suggested for typos (e.g. internal values)
 	Prefix                        string
 	AlphaNumericDashDotUnderscore bool
 	AlphaNumericDashUnderscore    bool
 	Dns1035                       bool
+	PreserveWhitespace            bool    // don't trim surrounding whitespace from values read from files
+	MaxFileBytes                  int64   // for FromFile readers, defaults to DefaultMaxFileBytes
+	AllowNonRegularFiles          bool    // allow FromFile readers to read from e.g. pipes and device files
+	Sensitive                     bool    // replace the value with its length in errors (errors from Validator are shown as-is)
+	Report                        *Report // if set, each value which is read is recorded, along with where it came from
 	Validator                     func(*string) (*string, error)
+	allowedValuesSet              allowedValuesSet
 }
 
 func makeStringValValidation(v *StringPtrValidation) *StringValidation {
 	return &StringValidation{
 		AllowEmpty:                    v.AllowEmpty,
 		AllowedValues:                 v.AllowedValues,
+		HiddenAllowedValues:           v.HiddenAllowedValues,
 		Prefix:                        v.Prefix,
 		AlphaNumericDashDotUnderscore: v.AlphaNumericDashDotUnderscore,
 		AlphaNumericDashUnderscore:    v.AlphaNumericDashUnderscore,
 		Dns1035:                       v.Dns1035,
+		PreserveWhitespace:            v.PreserveWhitespace,
+		Sensitive:                     v.Sensitive,
+		allowedValuesSet:              allowedValuesSet{shared: &v.allowedValuesSet},
 	}
 }
 
@@ -53,7 +63,7 @@ func StringPtr(inter interface{}, v *StringPtrValidation) (*string, error) {
 	}
 	casted, castOk := inter.(string)
 	if !castOk {
-		return nil, errors.New(s.ErrInvalidPrimitiveType(inter, s.PrimTypeString))
+		return nil, errors.Wrap(&InvalidTypeError{Provided: redactIf(inter, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeString}})
 	}
 	return ValidateStringPtr(&casted, v)
 }
@@ -63,14 +73,16 @@ func StringPtrFromInterfaceMap(key string, iMap map[string]interface{}, v *Strin
 	if !ok {
 		val, err := ValidateStringPtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := StringPtr(inter, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -79,14 +91,16 @@ func StringPtrFromStrMap(key string, sMap map[string]string, v *StringPtrValidat
 	if !ok {
 		val, err := ValidateStringPtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, key)
+			return nil, errors.WrapKey(err, key)
 		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := StringPtrFromStr(valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, key)
+		return nil, errors.WrapKey(err, key)
 	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -95,35 +109,49 @@ func StringPtrFromStr(str string, v *StringPtrValidation) (*string, error) {
 }
 
 func StringPtrFromEnv(envVarName string, v *StringPtrValidation) (*string, error) {
-	valStr := ReadEnvVar(envVarName)
+	return DefaultEnv().StringPtrFromEnv(envVarName, v)
+}
+
+func (env *Env) StringPtrFromEnv(envVarName string, v *StringPtrValidation) (*string, error) {
+	valStr := env.ReadEnvVar(envVarName)
 	if valStr == nil {
 		val, err := ValidateStringPtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, s.EnvVar(envVarName))
+			return nil, envVarError(err, envVarName)
 		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
 	val, err := StringPtrFromStr(*valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, s.EnvVar(envVarName))
+		return nil, envVarError(err, envVarName)
 	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
 func StringPtrFromFile(filePath string, v *StringPtrValidation) (*string, error) {
-	valBytes, err := ioutil.ReadFile(filePath)
+	return uncachedFiles.StringPtrFromFile(filePath, v)
+}
+
+func (cache *FileCache) StringPtrFromFile(filePath string, v *StringPtrValidation) (*string, error) {
+	valStr, err := cache.readValueFile(filePath, v.MaxFileBytes, v.AllowNonRegularFiles, !v.PreserveWhitespace)
 	if err != nil {
+		return nil, errors.Wrap(err, filePath)
+	}
+	if valStr == nil {
 		val, err := ValidateStringPtrMissing(v)
 		if err != nil {
-			return nil, errors.Wrap(err, filePath)
+			return nil, valueFileError(err, filePath, 0)
 		}
+		v.Report.record(filePath, ValueSourceDefault, filePath, redactIf(cast.Deref(val), v.Sensitive))
 		return val, nil
 	}
-	valStr := string(valBytes)
-	val, err := StringPtrFromStr(valStr, v)
+	val, err := StringPtrFromStr(*valStr, v)
 	if err != nil {
-		return nil, errors.Wrap(err, filePath)
+		return nil, valueFileError(err, filePath, 1)
 	}
+	v.Report.record(filePath, ValueSourceFile, filePath, redactIf(cast.Deref(val), v.Sensitive))
 	return val, nil
 }
 
@@ -136,16 +164,28 @@ func StringPtrFromEnvOrFile(envVarName string, filePath string, v *StringPtrVali
 }
 
 func StringPtrFromPrompt(promptOpts *PromptOptions, v *StringPtrValidation) (*string, error) {
-	valStr := prompt(promptOpts)
-	if valStr == "" { // Treat empty prompt value as missing
-		ValidateStringPtrMissing(v)
+	promptOpts = promptOpts.withHints(ConstraintHint(v), "")
+	var val *string
+	var source ValueSource
+	err := promptWithRetries(promptOpts, func(valStr string) error {
+		var err error
+		source = promptSource(valStr, promptOpts)
+		if valStr == "" { // Treat empty prompt value as missing
+			val, err = ValidateStringPtrMissing(v)
+		} else {
+			val, err = StringPtrFromStr(valStr, v)
+		}
+		return err
+	})
+	if err == nil {
+		v.Report.record(promptOpts.Prompt, source, "", redactIf(cast.Deref(val), v.Sensitive))
 	}
-	return StringPtrFromStr(valStr, v)
+	return val, err
 }
 
 func ValidateStringPtrMissing(v *StringPtrValidation) (*string, error) {
 	if v.Required {
-		return nil, errors.New(s.ErrMustBeDefined)
+		return nil, errors.Wrap(ErrRequired)
 	}
 	return ValidateStringPtr(v.Default, v)
 }
@@ -153,7 +193,7 @@ func ValidateStringPtrMissing(v *StringPtrValidation) (*string, error) {
 func ValidateStringPtr(val *string, v *StringPtrValidation) (*string, error) {
 	if v.DisallowNull {
 		if val == nil {
-			return nil, errors.New(s.ErrCannotBeNull)
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
 		}
 	}
 
@@ -165,7 +205,8 @@ func ValidateStringPtr(val *string, v *StringPtrValidation) (*string, error) {
 	}
 
 	if v.Validator != nil {
-		return v.Validator(val)
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
 	}
 	return val, nil
 }
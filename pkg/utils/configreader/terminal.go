@@ -0,0 +1,204 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/ssh/terminal"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// NonInteractiveEnvVar can be set to "true" or "false" to override the detection of whether prompts can be shown
+const NonInteractiveEnvVar = "CORTEX_NON_INTERACTIVE"
+
+var warnNonTerminalOnce sync.Once
+
+var (
+	nonInteractiveMutex    sync.Mutex
+	nonInteractiveOverride *bool
+)
+
+var (
+	promptColorsMutex    sync.Mutex
+	promptColorsOverride *bool
+)
+
+func isTerminal(file *os.File) bool {
+	return terminal.IsTerminal(int(file.Fd()))
+}
+
+func isTerminalWriter(out io.Writer) bool {
+	file, ok := out.(*os.File)
+	return ok && isTerminal(file)
+}
+
+// SetNonInteractive controls whether the FromPrompt readers skip prompting (and use the default value instead).
+// It takes precedence over NonInteractiveEnvVar and the detection of whether stdin and stdout are terminals
+func SetNonInteractive(nonInteractive bool) {
+	nonInteractiveMutex.Lock()
+	defer nonInteractiveMutex.Unlock()
+	nonInteractiveOverride = &nonInteractive
+}
+
+// ResetNonInteractive undoes SetNonInteractive
+func ResetNonInteractive() {
+	nonInteractiveMutex.Lock()
+	defer nonInteractiveMutex.Unlock()
+	nonInteractiveOverride = nil
+}
+
+func IsNonInteractive() bool {
+	nonInteractiveMutex.Lock()
+	defer nonInteractiveMutex.Unlock()
+	if nonInteractiveOverride != nil {
+		return *nonInteractiveOverride
+	}
+	if nonInteractive, ok := s.ParseBool(os.Getenv(NonInteractiveEnvVar)); ok {
+		return nonInteractive
+	}
+	return !isTerminal(os.Stdin) || !isTerminal(os.Stdout)
+}
+
+// SetPromptColors controls whether the stylers in PromptOptions are applied.
+// It takes precedence over NO_COLOR and the detection of whether the prompt's output is a terminal
+func SetPromptColors(enabled bool) {
+	promptColorsMutex.Lock()
+	defer promptColorsMutex.Unlock()
+	promptColorsOverride = &enabled
+}
+
+// ResetPromptColors undoes SetPromptColors
+func ResetPromptColors() {
+	promptColorsMutex.Lock()
+	defer promptColorsMutex.Unlock()
+	promptColorsOverride = nil
+}
+
+// see https://no-color.org
+func promptColorsEnabled(out io.Writer) bool {
+	promptColorsMutex.Lock()
+	defer promptColorsMutex.Unlock()
+	if promptColorsOverride != nil {
+		return *promptColorsOverride
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminalWriter(out)
+}
+
+func warnNonTerminal(out io.Writer) {
+	warnNonTerminalOnce.Do(func() {
+		fmt.Fprintln(out, "warning: input is not a terminal, so hidden prompts will be read as plain input")
+	})
+}
+
+// readHiddenLine reads a line from a terminal without echoing it (if mask is true, maskVal is echoed for each character).
+// The terminal state is restored before returning, and if the process is interrupted
+func readHiddenLine(fd int, readByte func() (byte, error), out io.Writer, mask bool, maskVal string) (string, error) {
+	if maskVal == "" {
+		maskVal = "*"
+	}
+
+	restore, err := makeRaw(fd, out)
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+
+	var line []byte
+	for {
+		char, err := readByte()
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				fmt.Fprint(out, "\r\n")
+				return string(line), nil
+			}
+			if err == ErrPromptTimeout {
+				fmt.Fprint(out, "\r\n")
+				return "", err
+			}
+			return "", errors.MarkInternal(errors.Wrap(err))
+		}
+
+		switch {
+		case char == '\r' || char == '\n':
+			fmt.Fprint(out, "\r\n")
+			return string(line), nil
+		case char == 3: // ctrl-c
+			fmt.Fprint(out, "\r\n")
+			return "", errors.NewUserCoded(s.MsgPromptInterrupted, s.ErrPromptInterrupted)
+		case char == 4 && len(line) == 0: // ctrl-d
+			fmt.Fprint(out, "\r\n")
+			return "", errors.Wrap(io.EOF)
+		case char == 127 || char == '\b':
+			if len(line) > 0 {
+				_, size := utf8.DecodeLastRune(line)
+				line = line[:len(line)-size]
+				if mask {
+					fmt.Fprint(out, "\b \b")
+				}
+			}
+		case char < 32: // ignore other control characters
+		default:
+			line = append(line, char)
+			if mask && !isUTF8ContinuationByte(char) {
+				fmt.Fprint(out, maskVal)
+			}
+		}
+	}
+}
+
+// makeRaw puts the terminal into raw mode until the returned function is called
+func makeRaw(fd int, out io.Writer) (func(), error) {
+	oldState, err := terminal.MakeRaw(fd)
+	if err != nil {
+		return nil, errors.MarkInternal(errors.Wrap(err))
+	}
+
+	// in raw mode, ctrl-c is read as a character; this handles interrupts sent by other processes
+	interrupts := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(interrupts, os.Interrupt)
+	go func() {
+		select {
+		case <-interrupts:
+			terminal.Restore(fd, oldState)
+			fmt.Fprint(out, "\r\n")
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(interrupts)
+		close(done)
+		terminal.Restore(fd, oldState)
+	}, nil
+}
+
+func isUTF8ContinuationByte(char byte) bool {
+	return char&0xC0 == 0x80
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"time"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// DefaultTimestampLayouts are used when no layouts are configured
+var DefaultTimestampLayouts = []string{time.RFC3339, "2006-01-02"}
+
+type TimestampValidation struct {
+	Required  bool
+	Default   time.Time
+	Layouts   []string       // tried in order, defaults to DefaultTimestampLayouts
+	Location  *time.Location // for layouts without a time zone, defaults to UTC
+	Validator func(time.Time) (time.Time, error)
+}
+
+func Timestamp(inter interface{}, v *TimestampValidation) (time.Time, error) {
+	if inter == nil {
+		return time.Time{}, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+	}
+	if casted, ok := inter.(string); ok {
+		return TimestampFromStr(casted, v)
+	}
+	casted, ok := cast.InterfaceToTime(inter, nil, v.Location)
+	if ok {
+		return ValidateTimestamp(casted, v)
+	}
+	return time.Time{}, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeTimestamp}})
+}
+
+func TimestampFromInterfaceMap(key string, iMap map[string]interface{}, v *TimestampValidation) (time.Time, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateTimestampMissing(v)
+		if err != nil {
+			return time.Time{}, errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := Timestamp(inter, v)
+	if err != nil {
+		return time.Time{}, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func TimestampFromStr(valStr string, v *TimestampValidation) (time.Time, error) {
+	if valStr == "" {
+		return ValidateTimestampMissing(v)
+	}
+	casted, err := parseTimestamp(valStr, v.Layouts, v.Location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ValidateTimestamp(casted, v)
+}
+
+func TimestampFromEnv(envVarName string, v *TimestampValidation) (time.Time, error) {
+	valStr := ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := ValidateTimestampMissing(v)
+		if err != nil {
+			return time.Time{}, envVarError(err, envVarName)
+		}
+		return val, nil
+	}
+	val, err := TimestampFromStr(*valStr, v)
+	if err != nil {
+		return time.Time{}, envVarError(err, envVarName)
+	}
+	return val, nil
+}
+
+func ValidateTimestampMissing(v *TimestampValidation) (time.Time, error) {
+	if v.Required {
+		return time.Time{}, errors.Wrap(ErrRequired)
+	}
+	return ValidateTimestamp(v.Default, v)
+}
+
+func ValidateTimestamp(val time.Time, v *TimestampValidation) (time.Time, error) {
+	if v.Validator != nil {
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
+	}
+	return val, nil
+}
+
+func parseTimestamp(valStr string, layouts []string, loc *time.Location) (time.Time, error) {
+	if len(layouts) == 0 {
+		layouts = DefaultTimestampLayouts
+	}
+	if parsed, ok := cast.InterfaceToTime(valStr, layouts, loc); ok {
+		return parsed, nil
+	}
+	return time.Time{}, errors.NewUserCoded(s.MsgInvalidTimestamp, s.ErrInvalidTimestamp(valStr, layouts))
+}
+
+//
+// Musts
+//
+
+func MustTimestampFromStr(valStr string, v *TimestampValidation) time.Time {
+	val, err := TimestampFromStr(valStr, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
+
+func MustTimestampFromEnv(envVarName string, v *TimestampValidation) time.Time {
+	val, err := TimestampFromEnv(envVarName, v)
+	if err != nil {
+		Fatal(err)
+	}
+	return val
+}
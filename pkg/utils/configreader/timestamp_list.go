@@ -0,0 +1,120 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"time"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+type TimestampListValidation struct {
+	Required           bool
+	Default            []time.Time
+	AllowNull          bool
+	AllowEmpty         bool
+	MinLength          int
+	MaxLength          int      // 0 means there is no limit
+	Layouts            []string // used to parse each element, defaults to DefaultTimestampLayouts
+	StrictlyIncreasing bool     // each timestamp must be after the previous one
+	NonDecreasing      bool     // each timestamp must not be before the previous one
+	Validator          func([]time.Time) ([]time.Time, error)
+}
+
+func TimestampList(inter interface{}, v *TimestampListValidation) ([]time.Time, error) {
+	if inter == nil {
+		return ValidateTimestampList(nil, v)
+	}
+	casted, castOk := cast.InterfaceToInterfaceSlice(inter)
+	if !castOk {
+		return nil, errors.Wrap(&InvalidTypeError{Provided: inter, Expected: []s.PrimitiveType{s.PrimTypeTimestampList}})
+	}
+	elemValidation := &TimestampValidation{Required: true, Layouts: v.Layouts}
+	timestamps := make([]time.Time, len(casted))
+	for i, elem := range casted {
+		timestamp, err := Timestamp(elem, elemValidation)
+		if err != nil {
+			return nil, errors.WrapIndex(err, i)
+		}
+		timestamps[i] = timestamp
+	}
+	return ValidateTimestampList(timestamps, v)
+}
+
+func TimestampListFromInterfaceMap(key string, iMap map[string]interface{}, v *TimestampListValidation) ([]time.Time, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := ValidateTimestampListMissing(v)
+		if err != nil {
+			return nil, errors.WrapKey(err, key)
+		}
+		return val, nil
+	}
+	val, err := TimestampList(inter, v)
+	if err != nil {
+		return nil, errors.WrapKey(err, key)
+	}
+	return val, nil
+}
+
+func ValidateTimestampListMissing(v *TimestampListValidation) ([]time.Time, error) {
+	if v.Required {
+		return nil, errors.Wrap(ErrRequired)
+	}
+	return ValidateTimestampList(v.Default, v)
+}
+
+func ValidateTimestampList(val []time.Time, v *TimestampListValidation) ([]time.Time, error) {
+	if !v.AllowNull {
+		if val == nil {
+			return nil, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+		}
+	}
+
+	if !v.AllowEmpty {
+		if val != nil && len(val) == 0 {
+			return nil, errors.NewUserCoded(s.MsgCannotBeEmpty, s.ErrCannotBeEmpty)
+		}
+	}
+
+	if val != nil {
+		if len(val) < v.MinLength {
+			return nil, errors.NewUserCoded(s.MsgTooFewElements, s.ErrTooFewElements(len(val), v.MinLength))
+		}
+		if v.MaxLength > 0 && len(val) > v.MaxLength {
+			return nil, errors.NewUserCoded(s.MsgTooManyElements, s.ErrTooManyElements(len(val), v.MaxLength))
+		}
+	}
+
+	if v.StrictlyIncreasing || v.NonDecreasing {
+		for i := 1; i < len(val); i++ {
+			if val[i].Before(val[i-1]) || (v.StrictlyIncreasing && val[i].Equal(val[i-1])) {
+				prev := val[i-1].Format(time.RFC3339Nano)
+				curr := val[i].Format(time.RFC3339Nano)
+				return nil, errors.WrapIndex(errors.NewUserCoded(s.MsgTimestampsOutOfOrder, s.ErrTimestampsOutOfOrder(i-1, prev, curr, v.StrictlyIncreasing)), i)
+			}
+		}
+	}
+
+	if v.Validator != nil {
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
+	}
+	return val, nil
+}
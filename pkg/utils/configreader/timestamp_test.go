@@ -0,0 +1,121 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v3"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+)
+
+func TestTimestamp(t *testing.T) {
+	v := &cr.TimestampValidation{}
+
+	val, err := cr.TimestampFromStr("2020-01-02T03:04:05Z", v)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), val)
+
+	val, err = cr.TimestampFromStr("2020-01-02", v)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), val)
+
+	_, err = cr.TimestampFromStr("01/02/2020", v)
+	require.EqualError(t, err, s.ErrInvalidTimestamp("01/02/2020", cr.DefaultTimestampLayouts))
+
+	v.Layouts = []string{"01/02/2006"}
+	val, err = cr.TimestampFromStr("01/02/2020", v)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), val)
+
+	_, err = cr.Timestamp(2020, v)
+	require.EqualError(t, err, s.ErrInvalidPrimitiveType(2020, s.PrimTypeTimestamp))
+}
+
+func TestTimestampFromYAML(t *testing.T) {
+	var iMap map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte("start: 2020-01-02T03:04:05Z\nend: \"2020-02-01\"\nday: 2020-03-04\n"), &iMap))
+	require.IsType(t, time.Time{}, iMap["start"]) // YAML-native timestamps are decoded to time.Time
+
+	v := &cr.TimestampValidation{}
+	val, err := cr.TimestampFromInterfaceMap("start", iMap, v)
+	require.NoError(t, err)
+	require.True(t, time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC).Equal(val))
+
+	val, err = cr.TimestampFromInterfaceMap("end", iMap, v)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2020, 2, 1, 0, 0, 0, 0, time.UTC), val)
+
+	val, err = cr.TimestampFromInterfaceMap("day", iMap, v)
+	require.NoError(t, err)
+	require.True(t, time.Date(2020, 3, 4, 0, 0, 0, 0, time.UTC).Equal(val))
+
+	loc := time.FixedZone("UTC-8", -8*60*60)
+	val, err = cr.TimestampFromStr("2020-01-02 03:04", &cr.TimestampValidation{Layouts: []string{"2006-01-02 15:04"}, Location: loc})
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2020, 1, 2, 3, 4, 0, 0, loc), val)
+}
+
+type BackfillConfig struct {
+	Timestamps []time.Time `json:"timestamps"`
+}
+
+func TestTimestampList(t *testing.T) {
+	structValidation := &cr.StructValidation{
+		StructFieldValidations: []*cr.StructFieldValidation{
+			{
+				StructField: "Timestamps",
+				TimestampListValidation: &cr.TimestampListValidation{
+					MinLength:          2,
+					StrictlyIncreasing: true,
+				},
+			},
+		},
+	}
+
+	config := &BackfillConfig{}
+	errs := cr.Struct(config, cr.MustReadYAMLStrMap(`timestamps: ["2020-01-01", "2020-01-02T12:00:00Z"]`), structValidation)
+	require.Empty(t, errs)
+	require.Equal(t, []time.Time{
+		time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC),
+	}, config.Timestamps)
+
+	v := &cr.TimestampListValidation{StrictlyIncreasing: true}
+	equal := []interface{}{"2020-01-01", "2020-01-02", "2020-01-02"}
+
+	_, err := cr.TimestampList(equal, v)
+	require.EqualError(t, err, "index 2: "+s.ErrTimestampsOutOfOrder(1, "2020-01-02T00:00:00Z", "2020-01-02T00:00:00Z", true))
+
+	v = &cr.TimestampListValidation{NonDecreasing: true}
+	val, err := cr.TimestampList(equal, v)
+	require.NoError(t, err)
+	require.Len(t, val, 3)
+
+	_, err = cr.TimestampList([]interface{}{"2020-01-02", "2020-01-01"}, v)
+	require.EqualError(t, err, "index 1: "+s.ErrTimestampsOutOfOrder(0, "2020-01-02T00:00:00Z", "2020-01-01T00:00:00Z", false))
+
+	_, err = cr.TimestampList([]interface{}{"2020-01-01", "yesterday"}, v)
+	require.EqualError(t, err, "index 1: "+s.ErrInvalidTimestamp("yesterday", cr.DefaultTimestampLayouts))
+
+	_, err = cr.TimestampList([]interface{}{"2020-01-01"}, &cr.TimestampListValidation{MinLength: 2})
+	require.EqualError(t, err, s.ErrTooFewElements(1, 2))
+}
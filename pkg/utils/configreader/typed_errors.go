@@ -0,0 +1,125 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// The readers return these errors wrapped with context (e.g. the key), so check for them with errors.Is() and errors.As().
+// They are all user errors (see errors.IsUserError())
+
+// ErrRequired is returned when a required value is missing
+var ErrRequired error = &requiredError{}
+
+type requiredError struct{}
+
+func (err *requiredError) Error() string {
+	return s.MustBeDefined()
+}
+
+func (err *requiredError) Code() string {
+	return s.MsgMustBeDefined
+}
+
+func (err *requiredError) UserError() bool {
+	return true
+}
+
+// OutOfRangeError is returned when a value is outside of its GreaterThan, GreaterThanOrEqualTo, LessThan, or LessThanOrEqualTo bound
+type OutOfRangeError struct {
+	Val   interface{}
+	Bound interface{}
+	Op    string // ">", ">=", "<", or "<=" (the comparison which Val failed to satisfy)
+}
+
+func (err *OutOfRangeError) Error() string {
+	switch err.Op {
+	case ">":
+		return s.ErrMustBeGreaterThan(err.Val, err.Bound)
+	case ">=":
+		return s.ErrMustBeGreaterThanOrEqualTo(err.Val, err.Bound)
+	case "<":
+		return s.ErrMustBeLessThan(err.Val, err.Bound)
+	}
+	return s.ErrMustBeLessThanOrEqualTo(err.Val, err.Bound)
+}
+
+func (err *OutOfRangeError) Code() string {
+	switch err.Op {
+	case ">":
+		return s.MsgMustBeGreaterThan
+	case ">=":
+		return s.MsgMustBeGreaterThanOrEqualTo
+	case "<":
+		return s.MsgMustBeLessThan
+	}
+	return s.MsgMustBeLessThanOrEqualTo
+}
+
+func (err *OutOfRangeError) UserError() bool {
+	return true
+}
+
+// InvalidTypeError is returned when a value can't be cast to the expected type
+type InvalidTypeError struct {
+	Provided interface{}
+	Expected []s.PrimitiveType
+}
+
+func (err *InvalidTypeError) Error() string {
+	return s.ErrInvalidPrimitiveType(err.Provided, err.Expected...)
+}
+
+func (err *InvalidTypeError) Code() string {
+	return s.MsgInvalidPrimitiveType
+}
+
+func (err *InvalidTypeError) UserError() bool {
+	return true
+}
+
+// invalidElemTypeError is returned when the element at index of a list has the wrong type (it's wrapped with the index)
+func invalidElemTypeError(list interface{}, index int, expected s.PrimitiveType) error {
+	elems, _ := cast.InterfaceToInterfaceSlice(list)
+	return errors.WrapIndex(errors.Wrap(&InvalidTypeError{Provided: elems[index], Expected: []s.PrimitiveType{expected}}), index)
+}
+
+// NotAllowedValueError is returned when a value isn't one of its AllowedValues
+type NotAllowedValueError struct {
+	Val        interface{}
+	Allowed    interface{} // the AllowedValues slice
+	Suggestion string      // the allowed value which Val is likely a typo of, if any
+	File       string      // the file which Allowed was read from by AllowedValuesFromFile(), if any
+}
+
+func (err *NotAllowedValueError) Error() string {
+	if err.File != "" {
+		return s.ErrNotAllowedValueInFile(err.Val, err.Allowed, err.File) + s.DidYouMean(err.Suggestion)
+	}
+	return s.ErrNotAllowedValue(err.Val, err.Allowed) + s.DidYouMean(err.Suggestion)
+}
+
+func (err *NotAllowedValueError) Code() string {
+	return s.MsgNotAllowedValue
+}
+
+func (err *NotAllowedValueError) UserError() bool {
+	return true
+}
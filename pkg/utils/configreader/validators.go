@@ -18,6 +18,7 @@ package configreader
 
 import (
 	"net/url"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -35,14 +36,18 @@ func init() {
 type PathValidation struct {
 	Required bool
 	Default  string
-	BaseDir  string
+	BaseDir  string // relative paths are resolved against this directory (e.g. the directory of the config file) instead of the working directory
 }
 
+// GetFilePathValidation validates that the path is a file, and returns its cleaned absolute path
 func GetFilePathValidation(v *PathValidation) *StringValidation {
 	validator := func(val string) (string, error) {
 		val = util.RelPath(val, v.BaseDir)
+		if absVal, err := filepath.Abs(val); err == nil {
+			val = absVal
+		}
 		if !util.IsFile(val) {
-			return "", errors.New(s.ErrFileDoesNotExist(val))
+			return "", errors.NewUserCoded(s.MsgFileDoesNotExist, s.ErrFileDoesNotExist(val))
 		}
 		return val, nil
 	}
@@ -62,7 +67,7 @@ type S3aPathValidation struct {
 func GetS3aPathValidation(v *S3aPathValidation) *StringValidation {
 	validator := func(val string) (string, error) {
 		if !util.IsValidS3aPath(val) {
-			return "", errors.New(s.ErrInvalidS3aPath(val))
+			return "", errors.NewUserCoded(s.MsgInvalidS3aPath, s.ErrInvalidS3aPath(val))
 		}
 		return val, nil
 	}
@@ -105,7 +110,7 @@ func GetURLValidation(v *URLValidation) *StringValidation {
 
 		_, err := url.Parse(urlStr)
 		if err != nil {
-			return "", errors.New(s.ErrInvalidUrl(urlStr))
+			return "", errors.NewUserCoded(s.MsgInvalidUrl, s.ErrInvalidUrl(urlStr))
 		}
 
 		return urlStr, nil
@@ -117,3 +122,29 @@ func GetURLValidation(v *URLValidation) *StringValidation {
 		Validator: validator,
 	}
 }
+
+type EnvVarNameValidation struct {
+	Required         bool
+	Default          string
+	ReservedPrefixes []string // e.g. "CORTEX_"
+}
+
+func GetEnvVarNameValidation(v *EnvVarNameValidation) *StringValidation {
+	validator := func(val string) (string, error) {
+		if !util.CheckEnvVarName(val) {
+			return "", errors.NewUserCoded(s.MsgInvalidEnvVarName, s.ErrInvalidEnvVarName(val))
+		}
+		for _, prefix := range v.ReservedPrefixes {
+			if strings.HasPrefix(val, prefix) {
+				return "", errors.NewUserCoded(s.MsgReservedEnvVarPrefix, s.ErrReservedEnvVarPrefix(val, prefix))
+			}
+		}
+		return val, nil
+	}
+
+	return &StringValidation{
+		Required:  v.Required,
+		Default:   v.Default,
+		Validator: validator,
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader
+
+import (
+	"reflect"
+	"strconv"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	"github.com/cortexlabs/cortex/pkg/utils/cast"
+	"github.com/cortexlabs/cortex/pkg/utils/errors"
+)
+
+// Value validates numbers of any int, uint, or float type (e.g. Value[uint]). The numeric validations (e.g. IntValidation)
+// read their values through it, and it shares their range and allowed value checks
+type Value[T cast.Numeric] struct {
+	Required             bool
+	Default              T
+	AllowedValues        []T
+	GreaterThan          *T
+	GreaterThanOrEqualTo *T
+	LessThan             *T
+	LessThanOrEqualTo    *T
+	Sensitive            bool    // replace the value with its length in errors and reports (errors from Validator are shown as-is)
+	Report               *Report // if set, each value which is read is recorded, along with where it came from
+	Validator            func(T) (T, error)
+	allowedValuesSet     allowedValuesSet
+
+	// set by the numeric validations for their type-specific options (nil funcs are skipped)
+	defaultFunc      func() (T, error)
+	defaultUnchecked bool                                 // Default is returned without validation (e.g. IntValidation.UnlimitedValue)
+	keyword          func(inter interface{}) (T, bool)    // values which are returned without validation (e.g. IntValidation.UnlimitedTokens)
+	castFunc         func(inter interface{}) (T, error)   // replaces the conversion in From
+	parseFunc        func(valStr string) (T, bool, error) // replaces the parsing in FromStr; false if nothing is left once valStr is normalized
+	normalize        func(val T) T                        // runs before validation (e.g. Float64Validation.NormalizeNegativeZero)
+	check            func(val T) error                    // runs after the range and allowed value checks (e.g. IntValidation.MustBePowerOfTwo)
+	warnings         func(provided interface{}) []string  // added to the report entry (e.g. Float32Validation.WarnPrecisionLoss)
+	allowTruncation  bool                                 // for integer types, accept floats with fractional parts by truncating them
+}
+
+func (v *Value[T]) From(inter interface{}) (T, error) {
+	if inter == nil {
+		return 0, errors.NewUserCoded(s.MsgCannotBeNull, s.ErrCannotBeNull)
+	}
+	if v.keyword != nil {
+		if val, ok := v.keyword(inter); ok {
+			return val, nil
+		}
+	}
+
+	var casted T
+	var err error
+	if v.castFunc != nil {
+		casted, err = v.castFunc(inter)
+	} else {
+		casted, err = v.cast(inter, inter)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return v.Validate(casted)
+}
+
+func (v *Value[T]) FromInterfaceMap(key string, iMap map[string]interface{}) (T, error) {
+	inter, ok := ReadInterfaceMapValue(key, iMap)
+	if !ok {
+		val, err := v.ValidateMissing()
+		if err != nil {
+			return 0, errors.WrapKey(err, key)
+		}
+		v.Report.record(key, ValueSourceDefault, "", redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := v.From(inter)
+	if err != nil {
+		return 0, errors.WrapKey(err, key)
+	}
+	v.Report.record(key, ValueSourceConfig, "", redactIf(val, v.Sensitive), v.warningsFor(inter)...)
+	return val, nil
+}
+
+func (v *Value[T]) FromStr(valStr string) (T, error) {
+	if valStr == "" {
+		return v.ValidateMissing()
+	}
+	if v.keyword != nil {
+		if val, ok := v.keyword(valStr); ok {
+			return val, nil
+		}
+	}
+
+	if v.parseFunc != nil {
+		casted, ok, err := v.parseFunc(valStr)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return v.ValidateMissing()
+		}
+		return v.Validate(casted)
+	}
+
+	casted, err := v.cast(valStr, v.parse(valStr))
+	if err != nil {
+		return 0, err
+	}
+	return v.Validate(casted)
+}
+
+func (v *Value[T]) FromEnv(envVarName string) (T, error) {
+	return v.fromEnv(DefaultEnv(), envVarName)
+}
+
+func (v *Value[T]) fromEnv(env *Env, envVarName string) (T, error) {
+	valStr := env.ReadEnvVar(envVarName)
+	if valStr == nil || *valStr == "" {
+		val, err := v.ValidateMissing()
+		if err != nil {
+			return 0, envVarError(err, envVarName)
+		}
+		v.Report.record(envVarName, ValueSourceDefault, s.EnvVar(envVarName), redactIf(val, v.Sensitive))
+		return val, nil
+	}
+	val, err := v.FromStr(*valStr)
+	if err != nil {
+		return 0, envVarError(err, envVarName)
+	}
+	v.Report.record(envVarName, ValueSourceEnv, s.EnvVar(envVarName), redactIf(val, v.Sensitive), v.warningsFor(*valStr)...)
+	return val, nil
+}
+
+func (v *Value[T]) ValidateMissing() (T, error) {
+	if v.Required {
+		return 0, errors.Wrap(ErrRequired)
+	}
+	if v.defaultFunc != nil {
+		val, err := v.defaultFunc()
+		if err != nil {
+			return 0, errors.MarkUser(err)
+		}
+		return v.Validate(val)
+	}
+	if v.defaultUnchecked {
+		return v.Default, nil
+	}
+	return v.Validate(v.Default)
+}
+
+func (v *Value[T]) Validate(val T) (T, error) {
+	if v.normalize != nil {
+		val = v.normalize(val)
+	}
+
+	err := v.validateVal(val)
+	if err != nil {
+		return 0, err
+	}
+
+	if v.Validator != nil {
+		validated, err := v.Validator(val)
+		return validated, errors.MarkUser(err)
+	}
+	return val, nil
+}
+
+func (v *Value[T]) validateVal(val T) error {
+	err := validateNumber(val, v.GreaterThan, v.GreaterThanOrEqualTo, v.LessThan, v.LessThanOrEqualTo, v.AllowedValues, &v.allowedValuesSet, v.Sensitive)
+	if err != nil {
+		return err
+	}
+	if v.check != nil {
+		return v.check(val)
+	}
+	return nil
+}
+
+func (v *Value[T]) warningsFor(provided interface{}) []string {
+	if v.warnings == nil {
+		return nil
+	}
+	return v.warnings(provided)
+}
+
+// parse returns valStr as a number which can be converted to T, or valStr itself if it isn't one
+func (v *Value[T]) parse(valStr string) interface{} {
+	switch reflect.TypeOf(T(0)).Kind() {
+	case reflect.Float32:
+		// parsed as a float32 directly, to avoid rounding twice
+		if casted, ok := s.ParseFloat32(valStr); ok {
+			return casted
+		}
+		if casted, ok := s.ParseFloat64(valStr); ok {
+			return casted // out of range
+		}
+	case reflect.Float64:
+		if casted, ok := s.ParseFloat64(valStr); ok {
+			return casted
+		}
+	default:
+		if casted, ok := s.ParseInt64(valStr); ok {
+			return casted
+		}
+		if casted, err := strconv.ParseUint(valStr, 10, 64); err == nil {
+			return casted
+		}
+	}
+	return valStr
+}
+
+// cast converts parsed (which is provided, or the number which was parsed from it) to T
+func (v *Value[T]) cast(provided interface{}, parsed interface{}) (T, error) {
+	if casted, ok := cast.To[T](parsed); ok {
+		return casted, nil
+	}
+
+	numberType := reflect.TypeOf(T(0))
+	parsed = cast.Deref(parsed)
+	switch numberType.Kind() {
+	case reflect.Float32:
+		if cast.IsFloatOrIntType(parsed) {
+			return 0, errors.NewUserCoded(s.MsgFloat32OutOfRange, s.ErrFloat32OutOfRange(redactIf(provided, v.Sensitive)))
+		}
+		return 0, errors.Wrap(&InvalidTypeError{Provided: redactIf(provided, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeFloat}})
+	case reflect.Float64:
+		return 0, errors.Wrap(&InvalidTypeError{Provided: redactIf(provided, v.Sensitive), Expected: []s.PrimitiveType{s.PrimTypeFloat}})
+	}
+
+	_, isInt := cast.To[int64](parsed)
+	_, isUint := cast.To[uint64](parsed)
+	if !isInt && !isUint {
+		// floats (e.g. from JSON) are accepted if they're integral; intFromFloat() rejects everything else
+		bits := numberType.Bits()
+		switch numberType.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			bits = 64 // the sign is checked by cast.To
+		}
+		fromFloat, err := intFromFloat(parsed, bits, v.allowTruncation, v.Sensitive)
+		if err != nil {
+			return 0, err
+		}
+		if casted, ok := cast.To[T](fromFloat); ok {
+			return casted, nil
+		}
+	}
+	return 0, errors.NewUserCoded(s.MsgIntOutOfRange, s.ErrIntOutOfRange(redactIf(provided, v.Sensitive), numberType.Bits()))
+}
+
+// validateNumber checks the range and allowed values which the numeric validations have in common
+func validateNumber[T cast.Numeric](val T, greaterThan, greaterThanOrEqualTo, lessThan, lessThanOrEqualTo *T, allowedValues []T, cache *allowedValuesSet, sensitive bool) error {
+	if greaterThan != nil {
+		if val <= *greaterThan {
+			return errors.Wrap(&OutOfRangeError{Val: redactIf(val, sensitive), Bound: *greaterThan, Op: ">"})
+		}
+	}
+	if greaterThanOrEqualTo != nil {
+		if val < *greaterThanOrEqualTo {
+			return errors.Wrap(&OutOfRangeError{Val: redactIf(val, sensitive), Bound: *greaterThanOrEqualTo, Op: ">="})
+		}
+	}
+	if lessThan != nil {
+		if val >= *lessThan {
+			return errors.Wrap(&OutOfRangeError{Val: redactIf(val, sensitive), Bound: *lessThan, Op: "<"})
+		}
+	}
+	if lessThanOrEqualTo != nil {
+		if val > *lessThanOrEqualTo {
+			return errors.Wrap(&OutOfRangeError{Val: redactIf(val, sensitive), Bound: *lessThanOrEqualTo, Op: "<="})
+		}
+	}
+
+	if allowedValues != nil {
+		if !isAllowed(val, allowedValues, cache) {
+			return errors.Wrap(&NotAllowedValueError{Val: redactIf(val, sensitive), Allowed: allowedValues})
+		}
+	}
+
+	return nil
+}
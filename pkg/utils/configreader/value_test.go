@@ -0,0 +1,89 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configreader_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+	cr "github.com/cortexlabs/cortex/pkg/utils/configreader"
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+func TestValue(t *testing.T) {
+	os.Setenv("CR_TEST_VALUE_REPLICAS", "3")
+	defer os.Unsetenv("CR_TEST_VALUE_REPLICAS")
+
+	// uint has no concrete validation, so it's only readable through Value
+	report := &cr.Report{}
+	minReplicas := uint(0)
+	replicas := &cr.Value[uint]{Required: true, GreaterThan: &minReplicas, Report: report}
+	val, err := replicas.FromEnv("CR_TEST_VALUE_REPLICAS")
+	require.NoError(t, err)
+	require.Equal(t, uint(3), val)
+	entry, ok := report.Entry("CR_TEST_VALUE_REPLICAS")
+	require.True(t, ok)
+	require.Equal(t, cr.ValueSourceEnv, entry.Source)
+
+	_, err = replicas.FromEnv("CR_TEST_VALUE_MISSING")
+	require.Error(t, err)
+
+	_, err = replicas.FromStr("0")
+	require.Error(t, err)
+
+	_, err = replicas.FromStr("-1")
+	require.EqualError(t, err, s.ErrIntOutOfRange("-1", 64))
+	require.Equal(t, s.MsgIntOutOfRange, s.Code(err))
+
+	_, err = (&cr.Value[uint8]{}).FromStr("300")
+	require.EqualError(t, err, s.ErrIntOutOfRange("300", 8))
+
+	_, err = replicas.FromStr("abc")
+	require.Error(t, err)
+
+	// the bounds and allowed values are checked the same way as the concrete validations check them
+	workers := &cr.Value[int64]{Default: 1, AllowedValues: []int64{1, 2, 4}, LessThanOrEqualTo: util.Int64Ptr(2)}
+	val64, err := workers.FromInterfaceMap("workers", map[string]interface{}{"workers": 2})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), val64)
+
+	val64, err = workers.FromInterfaceMap("workers", map[string]interface{}{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), val64)
+
+	_, err = workers.FromInterfaceMap("workers", map[string]interface{}{"workers": 4})
+	_, concreteErr := cr.Int64FromInterfaceMap("workers", map[string]interface{}{"workers": 4}, &cr.Int64Validation{LessThanOrEqualTo: util.Int64Ptr(2)})
+	require.Error(t, err)
+	require.EqualError(t, err, concreteErr.Error())
+
+	_, err = workers.FromInterfaceMap("workers", map[string]interface{}{"workers": 3})
+	require.Error(t, err)
+
+	_, err = workers.FromInterfaceMap("workers", map[string]interface{}{"workers": "2"})
+	require.Error(t, err)
+
+	scale := &cr.Value[float32]{}
+	valFloat, err := scale.FromStr("0.5")
+	require.NoError(t, err)
+	require.Equal(t, float32(0.5), valFloat)
+
+	_, err = scale.From(3.5e38)
+	require.EqualError(t, err, s.ErrFloat32OutOfRange(3.5e38))
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"fmt"
+)
+
+// codeError is returned by WithCode()
+type codeError struct {
+	error
+	code string
+}
+
+func (err *codeError) Code() string {
+	return err.code
+}
+
+func (err *codeError) Unwrap() error {
+	return err.error
+}
+
+func (err *codeError) Format(state fmt.State, verb rune) {
+	if verb == 'v' && state.Flag('+') {
+		if formatter, ok := err.error.(fmt.Formatter); ok {
+			formatter.Format(state, verb)
+			return
+		}
+	}
+	fmt.Fprint(state, err.Error())
+}
+
+// WithCode attaches the code of the message which err was built from (e.g. s.MsgStrTooLong for s.ErrStrTooLong()),
+// which is returned by s.Code(). It doesn't change err's message
+func WithCode(err error, code string) error {
+	if err == nil || code == "" {
+		return err
+	}
+	return &codeError{err, code}
+}
+
+// NewCoded is the same as WithCode(New(strs...), code)
+func NewCoded(code string, strs ...string) error {
+	return WithCode(New(strs...), code)
+}
+
+// NewUserCoded is the same as WithCode(NewUser(strs...), code)
+func NewUserCoded(code string, strs ...string) error {
+	return WithCode(NewUser(strs...), code)
+}
+
+// WrapCoded is the same as WithCode(Wrap(err, strs...), code)
+func WrapCoded(err error, code string, strs ...string) error {
+	return WithCode(Wrap(err, strs...), code)
+}
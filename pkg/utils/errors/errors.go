@@ -17,6 +17,7 @@ limitations under the License.
 package errors
 
 import (
+	goerrors "errors"
 	"fmt"
 	"os"
 	"strings"
@@ -28,7 +29,10 @@ import (
 
 func New(strs ...string) error {
 	errStr := strings.Join(strs, ": ")
-	return pkgerrors.New(errStr)
+	if shouldCaptureStacks() {
+		return &stackError{goerrors.New(errStr), callers()}
+	}
+	return goerrors.New(errStr)
 }
 
 func Wrap(err error, strs ...string) error {
@@ -36,16 +40,94 @@ func Wrap(err error, strs ...string) error {
 		return nil
 	}
 	if len(strs) == 0 {
-		return pkgerrors.WithStack(err)
+		return &wrappedError{error: err, cause: err, stack: wrapStack(err)}
 	}
 	errStr := strings.Join(strs, ": ")
-	return pkgerrors.Wrap(err, errStr)
+	// avoid messages like "MAX_WORKERS: MAX_WORKERS: must be greater than 0" when several layers wrap with the same key
+	if errStr == lastWrapStr(err) {
+		return err
+	}
+	return wrap(err, errStr)
+}
+
+func wrap(err error, errStr string) *wrappedError {
+	return &wrappedError{error: pkgerrors.WithMessage(err, errStr), cause: err, str: errStr, stack: wrapStack(err)}
+}
+
+// wrapStack captures the stack if stacks are being captured and err doesn't already have one
+func wrapStack(err error) []Frame {
+	if !shouldCaptureStacks() || Stack(err) != nil {
+		return nil
+	}
+	return callers()
+}
+
+// wrappedError is returned by Wrap(). Unlike the errors from pkgerrors, it can be unwrapped by Is() and As()
+type wrappedError struct {
+	error         // the pkgerrors error, for the message
+	cause error   // the error which was wrapped
+	str   string  // the strs passed to Wrap(), joined by ": "
+	stack []Frame // only set if stacks are being captured, and cause doesn't have one
+}
+
+// lastWrapStr returns the most recent non-empty strs which err was wrapped with by Wrap()
+func lastWrapStr(err error) string {
+	for err != nil {
+		switch casted := err.(type) {
+		case *wrappedError:
+			if casted.str != "" {
+				return casted.str
+			}
+			err = casted.cause
+		case *pathError:
+			err = casted.error
+		case *sourceError:
+			err = casted.error
+		case *codeError:
+			err = casted.error
+		case *positionError:
+			// the file is already at the start of the message
+			return casted.pos.File
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+func (err *wrappedError) Unwrap() error {
+	return err.cause
+}
+
+func (err *wrappedError) Cause() error {
+	return err.cause
+}
+
+func (err *wrappedError) Format(state fmt.State, verb rune) {
+	if formatter, ok := err.error.(fmt.Formatter); ok {
+		formatter.Format(state, verb)
+	} else {
+		fmt.Fprint(state, err.Error())
+	}
+	if verb == 'v' && state.Flag('+') {
+		fmt.Fprint(state, FormatStack(err.stack))
+	}
 }
 
 func Cause(err error) error {
 	return pkgerrors.Cause(err)
 }
 
+// Is reports whether any error in err's chain (including errors wrapped by Wrap()) matches target
+func Is(err error, target error) bool {
+	return goerrors.Is(err, target)
+}
+
+// As finds the first error in err's chain (including errors wrapped by Wrap()) which matches target, and sets target to it
+func As(err error, target interface{}) bool {
+	return goerrors.As(err, target)
+}
+
 func AddError(errs []error, err error, strs ...string) ([]error, bool) {
 	ok := false
 	if err != nil {
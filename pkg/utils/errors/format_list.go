@@ -0,0 +1,136 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	goerrors "errors"
+	"sort"
+	"strings"
+	"sync"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+)
+
+var (
+	compactListsMutex sync.RWMutex
+	compactLists      bool
+)
+
+// SetCompactLists controls whether FormatList() renders errors on a single line (e.g. for logs), instead of as a bulleted list
+func SetCompactLists(compact bool) {
+	compactListsMutex.Lock()
+	defer compactListsMutex.Unlock()
+	compactLists = compact
+}
+
+func shouldCompactLists() bool {
+	compactListsMutex.RLock()
+	defer compactListsMutex.RUnlock()
+	return compactLists
+}
+
+type listEntry struct {
+	path Path
+	msg  string
+}
+
+// FormatList renders errs as a header (e.g. "3 validation errors:") followed by one indented bullet per error, sorted by key path.
+// Errors which hold several errors (i.e. have an Errors() []error method) are flattened. A single error is rendered as-is.
+// See SetCompactLists() and FormatListCompact()
+func FormatList(errs []error) string {
+	return formatList(errs, shouldCompactLists())
+}
+
+// FormatListCompact is like FormatList(), but renders the errors on a single line, separated by "; "
+func FormatListCompact(errs []error) string {
+	return formatList(errs, true)
+}
+
+func formatList(errs []error, compact bool) string {
+	var entries []listEntry
+	for _, err := range errs {
+		if err != nil {
+			entries = append(entries, flattenError(err)...)
+		}
+	}
+
+	if len(entries) == 1 {
+		return entries[0].String()
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].path.String() < entries[j].path.String()
+	})
+
+	strs := make([]string, len(entries))
+	for i, entry := range entries {
+		strs[i] = entry.String()
+	}
+	if compact {
+		return s.ValidationErrorsHeader(len(entries)) + " " + strings.Join(strs, "; ")
+	}
+	return s.ValidationErrorsHeader(len(entries)) + "\n  - " + strings.Join(strs, "\n  - ")
+}
+
+func (entry listEntry) String() string {
+	if len(entry.path) == 0 {
+		return entry.msg
+	}
+	return entry.path.String() + ": " + entry.msg
+}
+
+func flattenError(err error) []listEntry {
+	var outerPath Path
+	for inner := err; inner != nil; inner = goerrors.Unwrap(inner) {
+		if pathErr, ok := inner.(*pathError); ok {
+			outerPath = append(outerPath, pathErr.elem)
+			continue
+		}
+		if multi, ok := inner.(interface{ Errors() []error }); ok {
+			var entries []listEntry
+			for _, multiErr := range multi.Errors() {
+				for _, entry := range flattenError(multiErr) {
+					path := append(append(Path{}, outerPath...), entry.path...)
+					entries = append(entries, listEntry{path, entry.msg})
+				}
+			}
+			return entries
+		}
+	}
+
+	path := KeyPath(err)
+	msg := err.Error()
+	// the path is rendered separately, so remove it from the message if it's at the start (e.g. "pods: index 3: cpu: ...")
+	prefix := strings.Join(pathMessageStrs(path), ": ") + ": "
+	if len(path) == 0 || !strings.HasPrefix(msg, prefix) {
+		return []listEntry{{nil, msg}}
+	}
+	return []listEntry{{path, strings.TrimPrefix(msg, prefix)}}
+}
+
+// pathMessageStrs returns each element of path the way WrapKey() and WrapIndex() add it to the message
+func pathMessageStrs(path Path) []string {
+	strs := make([]string, len(path))
+	for i, elem := range path {
+		if elem.IsIndex {
+			strs[i] = s.Index(elem.Index)
+		} else {
+			strs[i] = elem.Key
+		}
+	}
+	return strs
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+)
+
+// PathElement is a key in a map, or an index in a list
+type PathElement struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+// Path is the location of an error in a config, outermost element first
+type Path []PathElement
+
+// String renders the path like "pods[3].cpu"
+func (path Path) String() string {
+	var str strings.Builder
+	for _, elem := range path {
+		if elem.IsIndex {
+			str.WriteString("[" + strconv.Itoa(elem.Index) + "]")
+			continue
+		}
+		if str.Len() > 0 {
+			str.WriteString(".")
+		}
+		str.WriteString(elem.Key)
+	}
+	return str.String()
+}
+
+// Strings returns each element of the path (indexes are formatted as integers)
+func (path Path) Strings() []string {
+	strs := make([]string, len(path))
+	for i, elem := range path {
+		if elem.IsIndex {
+			strs[i] = strconv.Itoa(elem.Index)
+		} else {
+			strs[i] = elem.Key
+		}
+	}
+	return strs
+}
+
+// pathError is returned by WrapKey() and WrapIndex(). Its message is the same as Wrap(err, key), but repeated keys
+// are kept (e.g. for {"a": {"a": 1}}), since each one is a level of the config
+type pathError struct {
+	error // the error from Wrap()
+	elem  PathElement
+}
+
+func (err *pathError) Unwrap() error {
+	return err.error
+}
+
+func (err *pathError) Cause() error {
+	return Cause(err.error)
+}
+
+func (err *pathError) Format(state fmt.State, verb rune) {
+	err.error.(fmt.Formatter).Format(state, verb)
+}
+
+// WrapKey is the same as Wrap(err, key), but also adds key to the error's Path (see KeyPath())
+func WrapKey(err error, key string) error {
+	if err == nil {
+		return nil
+	}
+	return &pathError{wrap(err, key), PathElement{Key: key}}
+}
+
+// WrapIndex is the same as Wrap(err, s.Index(index)), but also adds index to the error's Path (see KeyPath())
+func WrapIndex(err error, index int) error {
+	if err == nil {
+		return nil
+	}
+	return &pathError{wrap(err, s.Index(index)), PathElement{Index: index, IsIndex: true}}
+}
+
+func WrapKeyMultiple(errs []error, key string) []error {
+	if !HasErrors(errs) {
+		return nil
+	}
+	wrappedErrs := make([]error, len(errs))
+	for i, err := range errs {
+		wrappedErrs[i] = WrapKey(err, key)
+	}
+	return wrappedErrs
+}
+
+func WrapIndexMultiple(errs []error, index int) []error {
+	if !HasErrors(errs) {
+		return nil
+	}
+	wrappedErrs := make([]error, len(errs))
+	for i, err := range errs {
+		wrappedErrs[i] = WrapIndex(err, index)
+	}
+	return wrappedErrs
+}
+
+// KeyPath returns the keys and indexes which err was wrapped with by WrapKey() and WrapIndex()
+func KeyPath(err error) Path {
+	var path Path
+	for err != nil {
+		if pathErr, ok := err.(*pathError); ok {
+			path = append(path, pathErr.elem)
+		}
+		err = goerrors.Unwrap(err)
+	}
+	return path
+}
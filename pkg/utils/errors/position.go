@@ -0,0 +1,91 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"strconv"
+)
+
+// Position is the location of a value in a file. Line and Col are 1-based, and 0 if unknown
+type Position struct {
+	File string
+	Line int
+	Col  int
+}
+
+// String renders the position like "cluster.yaml:14:3"
+func (pos Position) String() string {
+	str := pos.File
+	if pos.Line > 0 {
+		str += ":" + strconv.Itoa(pos.Line)
+		if pos.Col > 0 {
+			str += ":" + strconv.Itoa(pos.Col)
+		}
+	}
+	return str
+}
+
+// positionError is returned by WithPosition()
+type positionError struct {
+	error
+	pos Position
+}
+
+func (err *positionError) Error() string {
+	return err.pos.String() + ": " + err.error.Error()
+}
+
+func (err *positionError) Unwrap() error {
+	return err.error
+}
+
+func (err *positionError) Format(state fmt.State, verb rune) {
+	if verb == 'v' && state.Flag('+') {
+		if formatter, ok := err.error.(fmt.Formatter); ok {
+			fmt.Fprint(state, err.pos.String()+": ")
+			formatter.Format(state, verb)
+			return
+		}
+	}
+	fmt.Fprint(state, err.Error())
+}
+
+// WithPosition records where in a file the value which caused err is, so that e.g. editors can jump to it.
+// It's shown at the start of the message (e.g. "cluster.yaml:14:3: max_replicas: ..."), and is kept when err is wrapped.
+// If err already has a position, it's returned as-is
+func WithPosition(err error, pos Position) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := GetPosition(err); ok {
+		return err
+	}
+	return &positionError{err, pos}
+}
+
+// GetPosition returns the position which was added to err by WithPosition()
+func GetPosition(err error) (Position, bool) {
+	for err != nil {
+		if posErr, ok := err.(*positionError); ok {
+			return posErr.pos, true
+		}
+		err = goerrors.Unwrap(err)
+	}
+	return Position{}, false
+}
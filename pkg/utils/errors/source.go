@@ -0,0 +1,107 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"sync"
+
+	s "github.com/cortexlabs/cortex/pkg/api/strings"
+)
+
+var (
+	showSourcesMutex sync.RWMutex
+	showSources      = true
+)
+
+// SetShowSources controls whether the sources added by WithSource() are included in error messages (they are by default)
+func SetShowSources(show bool) {
+	showSourcesMutex.Lock()
+	defer showSourcesMutex.Unlock()
+	showSources = show
+}
+
+func shouldShowSources() bool {
+	showSourcesMutex.RLock()
+	defer showSourcesMutex.RUnlock()
+	return showSources
+}
+
+// sourceError is returned by WithSource()
+type sourceError struct {
+	error
+	source string
+}
+
+func (err *sourceError) Error() string {
+	if !shouldShowSources() {
+		return err.error.Error()
+	}
+	// the file is already shown at the start of the message
+	if pos, ok := GetPosition(err.error); ok && pos.File == err.source {
+		return err.error.Error()
+	}
+	return err.error.Error() + s.FromSource(err.source)
+}
+
+func (err *sourceError) Unwrap() error {
+	return err.error
+}
+
+func (err *sourceError) Format(state fmt.State, verb rune) {
+	if verb == 'v' && state.Flag('+') {
+		if formatter, ok := err.error.(fmt.Formatter); ok {
+			formatter.Format(state, verb)
+			return
+		}
+	}
+	fmt.Fprint(state, err.Error())
+}
+
+// WithSource records where the value which caused err came from (e.g. `environment variable "MAX_WORKERS"` or "cluster.yaml").
+// It's shown at the end of the message (see SetShowSources()) unless err's position (see WithPosition()) is in the same file,
+// and is kept when err is wrapped.
+// If err already has a source, it's returned as-is
+func WithSource(err error, source string) error {
+	if err == nil || source == "" || GetSource(err) != "" {
+		return err
+	}
+	return &sourceError{err, source}
+}
+
+func WithSourceMultiple(errs []error, source string) []error {
+	if !HasErrors(errs) {
+		return nil
+	}
+	sourcedErrs := make([]error, len(errs))
+	for i, err := range errs {
+		sourcedErrs[i] = WithSource(err, source)
+	}
+	return sourcedErrs
+}
+
+// GetSource returns the source which was added to err by WithSource(), or "" if there isn't one
+func GetSource(err error) string {
+	for err != nil {
+		if sourceErr, ok := err.(*sourceError); ok {
+			return sourceErr.source
+		}
+		err = goerrors.Unwrap(err)
+	}
+	return ""
+}
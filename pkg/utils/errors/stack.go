@@ -0,0 +1,121 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+const maxStackDepth = 32
+
+var captureStacks int32
+
+// SetCaptureStacks controls whether New() and Wrap() record the caller's stack (see Stack()). It's off by default.
+// Stacks are only shown when an error is formatted with %+v (e.g. by PrintStacktrace()), never in its message
+func SetCaptureStacks(capture bool) {
+	var val int32
+	if capture {
+		val = 1
+	}
+	atomic.StoreInt32(&captureStacks, val)
+}
+
+func shouldCaptureStacks() bool {
+	return atomic.LoadInt32(&captureStacks) == 1
+}
+
+// Frame is one function call in a stack captured by New() or Wrap()
+type Frame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (frame Frame) String() string {
+	return fmt.Sprintf("%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+}
+
+var errorsPkgPrefix = reflect.TypeOf(Frame{}).PkgPath() + "."
+
+// callers returns the current stack, starting from the first caller outside of this package
+func callers() []Frame {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(2, pcs)
+	runtimeFrames := runtime.CallersFrames(pcs[:n])
+
+	var frames []Frame
+	for {
+		runtimeFrame, more := runtimeFrames.Next()
+		if !strings.HasPrefix(runtimeFrame.Function, errorsPkgPrefix) {
+			frames = append(frames, Frame{Function: runtimeFrame.Function, File: runtimeFrame.File, Line: runtimeFrame.Line})
+		}
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// stackError is returned by New() when stacks are being captured
+type stackError struct {
+	error
+	stack []Frame
+}
+
+func (err *stackError) Unwrap() error {
+	return err.error
+}
+
+func (err *stackError) Format(state fmt.State, verb rune) {
+	fmt.Fprint(state, err.Error())
+	if verb == 'v' && state.Flag('+') {
+		fmt.Fprint(state, FormatStack(err.stack))
+	}
+}
+
+// Stack returns the stack which was captured when err (or the innermost error it wraps which has a stack) was created,
+// or nil if stacks weren't being captured (see SetCaptureStacks())
+func Stack(err error) []Frame {
+	var stack []Frame
+	for err != nil {
+		switch casted := err.(type) {
+		case *stackError:
+			stack = casted.stack
+		case *wrappedError:
+			if casted.stack != nil {
+				stack = casted.stack
+			}
+		}
+		err = goerrors.Unwrap(err)
+	}
+	return stack
+}
+
+// FormatStack renders frames like a panic's stack trace (one "function\n\tfile:line" per frame, each on a new line)
+func FormatStack(frames []Frame) string {
+	var sb strings.Builder
+	for _, frame := range frames {
+		sb.WriteString("\n")
+		sb.WriteString(frame.String())
+	}
+	return sb.String()
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	goerrors "errors"
+	"fmt"
+)
+
+// UserErrorClassifier is implemented by errors which know whether they are user errors (see IsUserError())
+type UserErrorClassifier interface {
+	UserError() bool
+}
+
+// markedError is returned by MarkUser() and MarkInternal()
+type markedError struct {
+	error
+	user bool
+}
+
+func (err *markedError) UserError() bool {
+	return err.user
+}
+
+func (err *markedError) Unwrap() error {
+	return err.error
+}
+
+func (err *markedError) Format(state fmt.State, verb rune) {
+	if formatter, ok := err.error.(fmt.Formatter); ok {
+		formatter.Format(state, verb)
+		return
+	}
+	fmt.Fprint(state, err.Error())
+}
+
+// MarkUser marks err as caused by the user (e.g. an invalid config value), so its message can be shown as-is.
+// If err has already been marked, it keeps its classification
+func MarkUser(err error) error {
+	return mark(err, true)
+}
+
+// MarkInternal marks err as an operational problem (e.g. a failure to read a file which exists).
+// If err has already been marked, it keeps its classification
+func MarkInternal(err error) error {
+	return mark(err, false)
+}
+
+func mark(err error, user bool) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := classifyError(err); ok {
+		return err
+	}
+	return &markedError{err, user}
+}
+
+// NewUser is the same as MarkUser(New(strs...))
+func NewUser(strs ...string) error {
+	return MarkUser(New(strs...))
+}
+
+// IsUserError returns whether err (or an error it wraps) was marked with MarkUser(). Errors which haven't been marked are not
+// user errors. An error which holds several errors (i.e. has an Errors() []error method) is a user error if all of them are
+func IsUserError(err error) bool {
+	user, _ := classifyError(err)
+	return user
+}
+
+func classifyError(err error) (user bool, ok bool) {
+	for err != nil {
+		if classifier, ok := err.(UserErrorClassifier); ok {
+			return classifier.UserError(), true
+		}
+		if multi, ok := err.(interface{ Errors() []error }); ok {
+			return classifyErrors(multi.Errors())
+		}
+		err = goerrors.Unwrap(err)
+	}
+	return false, false
+}
+
+func classifyErrors(errs []error) (user bool, ok bool) {
+	if len(errs) == 0 {
+		return false, false
+	}
+	for _, err := range errs {
+		user, ok := classifyError(err)
+		if !ok || !user {
+			return false, ok
+		}
+	}
+	return true, true
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2019 Cortex Labs, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cortexlabs/cortex/pkg/utils/util"
+)
+
+func TestCheckLuhn(t *testing.T) {
+	require.True(t, util.CheckLuhn("79927398713"))
+	require.True(t, util.CheckLuhn("4111111111111111"))
+	require.True(t, util.CheckLuhn("00"))
+	require.False(t, util.CheckLuhn("79927398710"))
+	require.False(t, util.CheckLuhn("4111111111111112"))
+	require.False(t, util.CheckLuhn("4111 1111 1111 1111"))
+	require.False(t, util.CheckLuhn("0"))
+	require.False(t, util.CheckLuhn(""))
+}
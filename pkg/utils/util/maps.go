@@ -91,3 +91,21 @@ func MergeStrMaps(maps ...map[string]string) map[string]string {
 	}
 	return merged
 }
+
+// int
+
+func IntMapKeys(myMap map[string]int) []string {
+	keys := make([]string, len(myMap))
+	i := 0
+	for key := range myMap {
+		keys[i] = key
+		i++
+	}
+	return keys
+}
+
+func IntMapSortedKeys(myMap map[string]int) []string {
+	keys := IntMapKeys(myMap)
+	sort.Strings(keys)
+	return keys
+}
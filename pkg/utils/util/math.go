@@ -57,3 +57,20 @@ func MaxInt(vals ...int) int {
 	}
 	return max
 }
+
+func IsPowerOfTwo(val int64) bool {
+	return val > 0 && val&(val-1) == 0
+}
+
+// PowersOfTwoNear returns the largest power of two which is <= val, followed by the next two (e.g. 256, 512, 1024 for 300)
+func PowersOfTwoNear(val int64) []int64 {
+	lower := int64(1)
+	for lower <= val/2 {
+		lower *= 2
+	}
+	powers := []int64{lower}
+	for len(powers) < 3 && powers[len(powers)-1] <= math.MaxInt64/2 {
+		powers = append(powers, powers[len(powers)-1]*2)
+	}
+	return powers
+}
@@ -51,3 +51,17 @@ func TestRound(t *testing.T) {
 	require.Equal(t, util.Round(1, 2, false), "1")
 	require.Equal(t, util.Round(20, 3, false), "20")
 }
+
+func TestPowersOfTwo(t *testing.T) {
+	for _, val := range []int64{1, 2, 4, 1024, 1 << 62} {
+		require.True(t, util.IsPowerOfTwo(val))
+	}
+	for _, val := range []int64{0, -2, 3, 1000, -1 << 63} {
+		require.False(t, util.IsPowerOfTwo(val))
+	}
+
+	require.Equal(t, []int64{256, 512, 1024}, util.PowersOfTwoNear(300))
+	require.Equal(t, []int64{2, 4, 8}, util.PowersOfTwoNear(3))
+	require.Equal(t, []int64{1, 2, 4}, util.PowersOfTwoNear(-5))
+	require.Equal(t, []int64{1 << 62}, util.PowersOfTwoNear(1<<62+1))
+}
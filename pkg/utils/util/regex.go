@@ -17,7 +17,9 @@ limitations under the License.
 package util
 
 import (
+	"go/token"
 	"regexp"
+	"strings"
 )
 
 func MatchAnyRegex(s string, regexes []*regexp.Regexp) bool {
@@ -46,3 +48,50 @@ var dns1035Regex = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
 func CheckDns1035(s string) bool {
 	return dns1035Regex.MatchString(s)
 }
+
+// Kubernetes limits
+const (
+	Dns1123LabelMaxLength     = 63
+	Dns1123SubdomainMaxLength = 253
+)
+
+var dns1123LabelRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// CheckDns1123Label matches kubernetes' IsDNS1123Label()
+func CheckDns1123Label(s string) bool {
+	return len(s) <= Dns1123LabelMaxLength && dns1123LabelRegex.MatchString(s)
+}
+
+var dns1123SubdomainRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// CheckDns1123Subdomain matches kubernetes' IsDNS1123Subdomain() (the dot-separated parts aren't limited to 63 characters)
+func CheckDns1123Subdomain(s string) bool {
+	return len(s) <= Dns1123SubdomainMaxLength && dns1123SubdomainRegex.MatchString(s)
+}
+
+// HostnameMaxLength is the maximum length of a hostname (RFC 1123)
+const HostnameMaxLength = 253
+
+var hostnameRegex = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([-a-zA-Z0-9]{0,61}[a-zA-Z0-9])?)*\.?$`)
+
+// CheckHostname checks RFC 1123 hostnames (e.g. "localhost" or "api.example.com"), which may have a trailing dot
+func CheckHostname(s string) bool {
+	return len(strings.TrimSuffix(s, ".")) <= HostnameMaxLength && hostnameRegex.MatchString(s)
+}
+
+var goIdentRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// CheckGoIdent checks ASCII Go identifiers (e.g. for generated code), which can't be keywords
+func CheckGoIdent(s string) bool {
+	return goIdentRegex.MatchString(s) && !IsGoKeyword(s)
+}
+
+func IsGoKeyword(s string) bool {
+	return token.Lookup(s).IsKeyword()
+}
+
+var envVarNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+func CheckEnvVarName(s string) bool {
+	return envVarNameRegex.MatchString(s)
+}
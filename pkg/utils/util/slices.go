@@ -191,6 +191,18 @@ func FindDuplicateStrs(in []string) []string {
 	return dups
 }
 
+func FindDuplicateInts(in []int) []int {
+	dups := []int{}
+	keys := map[int]bool{}
+	for _, elem := range in {
+		if _, ok := keys[elem]; ok {
+			dups = append(dups, elem)
+		}
+		keys[elem] = true
+	}
+	return dups
+}
+
 func SubtractStrSlice(slice1 []string, slice2 []string) []string {
 	result := []string{}
 	for _, elem := range slice1 {